@@ -0,0 +1,153 @@
+// Provides helpers that retrieve the citations the IDC site formats for repository objects, so that tests may verify the
+// citation of known objects in each CSL style.
+//
+// The url of the citation of a repository object is built from the pattern named by the environment variable
+// 'IDC_CITATION_PATTERN' (by default `/node/{nid}/citation?style={style}`), relative to the base url of Drupal.  The
+// pattern may carry the placeholders `{nid}`, the node id of the repository object, `{uuid}`, its UUID, and `{style}`,
+// the CSL style, e.g. `apa`.  The endpoint answers either the formatted citation as text or HTML, or a JSON document:
+//
+//	{"citation": "Adams, A. (1941). Moonrise."}
+//	{"error": "Unable to render citation", "missing_fields": ["issued"]}
+//
+// Requests are issued using the Config loaded by env.Load.
+package citation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/jhu-idc/idc-golang/drupal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	citationPattern        = "IDC_CITATION_PATTERN"
+	defaultCitationPattern = "/node/{nid}/citation?style={style}"
+)
+
+// Answered (wrapped) when the endpoint answers an error payload in place of a citation, e.g. because the object lacks
+// a field required by the style
+var ErrNotCitable = errors.New("citation: object cannot be cited")
+
+var (
+	// Matches the tags of a citation formatted as HTML
+	tags = regexp.MustCompile(`<[^>]*>`)
+	// Matches runs of whitespace
+	whitespace = regexp.MustCompile(`\s+`)
+)
+
+// GetCitation answers the citation of the repository object with the uuid in the CSL style (e.g. `apa`), as text with
+// its whitespace collapsed, failing the test immediately if it cannot be retrieved.  If the endpoint answers an error
+// payload (e.g. because the object lacks a field required by the style), the failure reports its message and the
+// missing fields.
+func GetCitation(t *testing.T, nodeUuid, style string) string {
+	citation, err := GetCitationErr(nodeUuid, style)
+	require.Nil(t, err, "%s", err)
+	return citation
+}
+
+// GetCitationErr behaves as GetCitation, but answers an error instead of failing the test.  The error wraps
+// ErrNotCitable if the endpoint answers an error payload.
+func GetCitationErr(nodeUuid, style string) (string, error) {
+	c, err := env.Load()
+	if err != nil {
+		return "", err
+	}
+	u, err := citationUrlErr(c, nodeUuid, style)
+	if err != nil {
+		return "", err
+	}
+
+	res, err := jsonapi.NewClient(c).Do(context.Background(), http.MethodGet, u, nil, nil)
+	var httpErr *jsonapi.HTTPError
+	if err != nil && !errors.As(err, &httpErr) {
+		return "", err
+	}
+	return parseCitationErr(u, res, err, nodeUuid, style)
+}
+
+// parseCitationErr answers the citation carried by the response from the url, or an error describing the error payload
+// it carries, or the error of its status, if any
+func parseCitationErr(u string, res *jsonapi.Response, statusErr error, nodeUuid, style string) (string, error) {
+	if strings.Contains(res.Header.Get("Content-Type"), "json") {
+		payload := struct {
+			Citation      string   `json:"citation"`
+			Error         string   `json:"error"`
+			MissingFields []string `json:"missing_fields"`
+		}{}
+		if err := json.Unmarshal(res.Body, &payload); err != nil {
+			return "", fmt.Errorf("citation: unable to unmarshal the response from %s: %w", env.RedactUrl(u), err)
+		}
+		if payload.Error != "" {
+			err := fmt.Errorf("%w: %s in style %s: %s", ErrNotCitable, nodeUuid, style, payload.Error)
+			if len(payload.MissingFields) > 0 {
+				err = fmt.Errorf("%w (missing fields: %s)", err, strings.Join(payload.MissingFields, ", "))
+			}
+			return "", err
+		}
+		if statusErr == nil && res.Status == http.StatusOK {
+			return normalize(payload.Citation), nil
+		}
+	}
+	if statusErr != nil {
+		return "", statusErr
+	}
+	return normalize(string(res.Body)), nil
+}
+
+// AssertCitationContains asserts that the citation of the repository object in the CSL style contains the substring,
+// ignoring differences in whitespace
+func AssertCitationContains(t *testing.T, nodeUuid, style, substring string) bool {
+	citation, err := GetCitationErr(nodeUuid, style)
+	if !assert.Nil(t, err, "%s", err) {
+		return false
+	}
+	return assert.Contains(t, citation, normalize(substring), "unexpected %s citation of %s", style, nodeUuid)
+}
+
+// AssertCitationMatches asserts that the citation of the repository object in the CSL style matches the regular
+// expression, e.g. to tolerate punctuation that varies between versions of a style:
+//
+//	citation.AssertCitationMatches(t, obj.JsonApiData[0].Id, "apa", `^Adams, A\. \(1941\)[.,] Moonrise`)
+func AssertCitationMatches(t *testing.T, nodeUuid, style, pattern string) bool {
+	citation, err := GetCitationErr(nodeUuid, style)
+	if !assert.Nil(t, err, "%s", err) {
+		return false
+	}
+	return assert.Regexp(t, pattern, citation, "unexpected %s citation of %s", style, nodeUuid)
+}
+
+// citationUrlErr answers the absolute url of the citation of the repository object in the style
+func citationUrlErr(c env.Config, nodeUuid, style string) (string, error) {
+	u := env.StringOr(citationPattern, defaultCitationPattern)
+	u = strings.NewReplacer("{uuid}", url.PathEscape(nodeUuid), "{style}", url.QueryEscape(style)).Replace(u)
+	if strings.Contains(u, "{nid}") {
+		nid, err := model.NodeIdErr(nodeUuid, model.WithConfig(&c))
+		if err != nil {
+			return "", err
+		}
+		u = strings.ReplaceAll(u, "{nid}", strconv.Itoa(nid))
+	}
+	if strings.HasPrefix(u, "/") {
+		u = strings.TrimSuffix(c.BaseUrl, "/") + u
+	}
+	return u, nil
+}
+
+// normalize answers the text of a citation, which may be formatted as HTML, with its whitespace collapsed
+func normalize(citation string) string {
+	text := html.UnescapeString(tags.ReplaceAllString(citation, ""))
+	return strings.TrimSpace(whitespace.ReplaceAllString(text, " "))
+}
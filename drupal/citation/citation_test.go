@@ -0,0 +1,73 @@
+package citation
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newCitationServer answers a server citing node 12 (uuid `1`), and failing to cite node 13 (uuid `2`), which lacks a
+// date, and configures the environment to use it
+func newCitationServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, style := r.URL.Query().Get("filter[id]"), r.URL.Query().Get("style")
+		switch {
+		case r.URL.Path == "/jsonapi/node/islandora_object" && (id == "1" || id == "2"):
+			_, _ = fmt.Fprintf(w, `{"data": [{"type": "node--islandora_object", "id": "%s",
+				"attributes": {"drupal_internal__nid": %d}}]}`, id, map[string]int{"1": 12, "2": 13}[id])
+		case r.URL.Path == "/jsonapi/node/collection_object" || r.URL.Path == "/jsonapi/node/islandora_object":
+			_, _ = w.Write([]byte(`{"data": []}`))
+		case r.URL.Path == "/node/12/citation" && style == "apa":
+			w.Header().Set("Content-Type", "text/html; charset=UTF-8")
+			_, _ = w.Write([]byte("<div class=\"csl-entry\">Adams, A. (1941).\n  <i>Moonrise</i> &amp; other works.</div>"))
+		case r.URL.Path == "/node/12/citation" && style == "chicago-fullnote-bibliography":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"citation": "Adams, Ansel. <i>Moonrise</i>. 1941."}`))
+		case r.URL.Path == "/node/13/citation":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = w.Write([]byte(`{"error": "Unable to render citation", "missing_fields": ["issued", "author"]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	t.Cleanup(func() { _, _ = env.Reload() })
+	t.Setenv("DRUPAL_BASE_URL", server.URL)
+	_, err := env.Reload()
+	require.Nil(t, err, "%s", err)
+	return server
+}
+
+func Test_GetCitation(t *testing.T) {
+	newCitationServer(t)
+
+	assert.Equal(t, "Adams, A. (1941). Moonrise & other works.", GetCitation(t, "1", "apa"))
+	assert.Equal(t, "Adams, Ansel. Moonrise. 1941.", GetCitation(t, "1", "chicago-fullnote-bibliography"))
+
+	assert.True(t, AssertCitationContains(t, "1", "apa", "(1941).\nMoonrise"))
+	assert.True(t, AssertCitationMatches(t, "1", "apa", `^Adams, A\. \(1941\)[.,] Moonrise`))
+}
+
+func Test_GetCitationErr(t *testing.T) {
+	newCitationServer(t)
+
+	_, err := GetCitationErr("2", "apa")
+	assert.True(t, errors.Is(err, ErrNotCitable), "expected ErrNotCitable, got %v", err)
+	assert.Equal(t, "citation: object cannot be cited: 2 in style apa: Unable to render citation "+
+		"(missing fields: issued, author)", fmt.Sprint(err))
+
+	_, err = GetCitationErr("1", "mla")
+	assert.Contains(t, fmt.Sprint(err), "404 status encountered when requesting")
+
+	t.Setenv("IDC_CITATION_PATTERN", "/citation/{uuid}/{style}")
+	_, err = GetCitationErr("1", "apa")
+	assert.Contains(t, fmt.Sprint(err), "/citation/1/apa")
+}
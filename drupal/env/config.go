@@ -0,0 +1,248 @@
+package env
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	httpTimeout    = "IDC_HTTP_TIMEOUT"
+	httpRetries    = "IDC_HTTP_RETRIES"
+	retryBackoff   = "IDC_HTTP_RETRY_BACKOFF"
+	tlsInsecure    = "IDC_TLS_INSECURE_SKIP_VERIFY"
+	tlsCaCert      = "IDC_TLS_CA_CERT"
+	rateLimit      = "IDC_RATE_LIMIT"
+	maxResponse    = "IDC_MAX_RESPONSE_SIZE"
+	defaultTimeout = 30 * time.Second
+	// The delay before the first retry of a failed request
+	defaultRetryBackoff = 250 * time.Millisecond
+	// The largest number of retries accepted from 'IDC_HTTP_RETRIES'
+	maxRetries = 10
+)
+
+// The base url of Drupal used when the environment variable 'DRUPAL_BASE_URL' is unset
+const DefaultBaseUrl = "https://islandora-idc.traefik.me"
+
+// The maximum size, in bytes, of a JSON API response body used when 'IDC_MAX_RESPONSE_SIZE' is unset: 50 MiB
+const DefaultMaxResponseSize = 50 << 20
+
+// Config carries the settings used to make requests of Drupal, read from the environment and validated as a whole by
+// Load, so that a suite can share one configuration, and fail fast (e.g. from TestMain) if it is invalid:
+//
+//	func TestMain(m *testing.M) {
+//		if _, err := env.Load(); err != nil {
+//			log.Fatalf("%s", err)
+//		}
+//		os.Exit(m.Run())
+//	}
+type Config struct {
+	// The name of the active profile, from 'IDC_PROFILE', e.g. `staging`; empty if no profile is active.  The settings
+	// of a profile are read from environment variables prefixed with its name (e.g. 'IDC_STAGING_BASEURL' or
+	// 'IDC_STAGING_TIMEOUT'), or from the profiles file named by 'IDC_PROFILES_FILE', and take precedence over the
+	// environment variables described below.
+	Profile string
+	// The base url of Drupal, from 'DRUPAL_BASE_URL'
+	BaseUrl string
+	// The credentials authenticating requests, from 'DRUPAL_USERNAME' and 'DRUPAL_PASSWORD'.  Requests are anonymous if
+	// the username is empty.
+	Credentials Credentials
+	// The time allowed for each request, from 'IDC_HTTP_TIMEOUT' (e.g. `45s`); defaults to 30 seconds
+	Timeout time.Duration
+	// The number of times a request is retried after a network error or a 5xx response, from 'IDC_HTTP_RETRIES'
+	Retries int
+	// The delay before the first retry of a failed request, from 'IDC_HTTP_RETRY_BACKOFF'; the delay grows with each
+	// subsequent retry.  Defaults to 250 milliseconds.
+	RetryBackoff time.Duration
+	// Whether the certificate presented by Drupal is verified, from 'IDC_TLS_INSECURE_SKIP_VERIFY'
+	InsecureSkipVerify bool
+	// The path of a PEM file carrying additional certificate authorities to trust, from 'IDC_TLS_CA_CERT'
+	CaCertFile string
+	// The maximum number of requests per second, from 'IDC_RATE_LIMIT'; zero is unlimited
+	RateLimit float64
+	// The maximum size, in bytes, of a JSON API response body, from 'IDC_MAX_RESPONSE_SIZE', so that a query missing
+	// its filters fails rather than exhausting memory.  Defaults to DefaultMaxResponseSize, as does zero in a Config
+	// composed by hand; a negative size is unlimited.
+	MaxResponseSize int64
+
+	// The client and throttle shared by copies of a validated Config
+	state *configState
+}
+
+// The state shared by copies of a validated Config
+type configState struct {
+	client *http.Client
+	mu     sync.Mutex
+	// the earliest time the next request may be issued
+	next time.Time
+}
+
+var (
+	loadMu sync.Mutex
+	loaded *Config
+)
+
+// Load answers the Config read from the environment, or an error describing every invalid setting.  The Config is read
+// and validated once, and the same Config is answered by subsequent invocations (see Reload).
+func Load() (Config, error) {
+	loadMu.Lock()
+	defer loadMu.Unlock()
+
+	if loaded != nil {
+		return *loaded, nil
+	}
+	c, err := readConfig()
+	if err != nil {
+		return Config{}, err
+	}
+	loaded = &c
+	return c, nil
+}
+
+// Reload discards the Config cached by Load and reads it from the environment again, e.g. after a test has changed
+// the environment
+func Reload() (Config, error) {
+	loadMu.Lock()
+	loaded = nil
+	loadMu.Unlock()
+	return Load()
+}
+
+// readConfig reads and validates a new Config from the environment, and the active profile
+func readConfig() (Config, error) {
+	p, err := activeProfile()
+	if err != nil {
+		return Config{}, err
+	}
+
+	str := func(envVar, defaultValue string) (string, string) {
+		v, source, ok := p.lookup(envVar)
+		if !ok {
+			v = defaultValue
+		}
+		return v, source
+	}
+
+	c := Config{Profile: p.name, Timeout: defaultTimeout, RetryBackoff: defaultRetryBackoff}
+	maxResponseSize := DefaultMaxResponseSize
+	c.BaseUrl, _ = str(drupalBaseUrl, DefaultBaseUrl)
+	c.Credentials.Username, c.Credentials.usernameVar = str(username, "")
+	c.Credentials.Password, c.Credentials.passwordVar = str(password, "")
+	c.CaCertFile, _ = str(tlsCaCert, "")
+
+	var problems []string
+	for _, err := range []error{
+		lookupParsed(p, httpTimeout, &c.Timeout, parseDuration),
+		lookupParsed(p, httpRetries, &c.Retries, parseInt),
+		lookupParsed(p, retryBackoff, &c.RetryBackoff, parseDuration),
+		lookupParsed(p, tlsInsecure, &c.InsecureSkipVerify, parseBool),
+		lookupParsed(p, rateLimit, &c.RateLimit, parseFloat),
+		lookupParsed(p, maxResponse, &maxResponseSize, parseInt),
+	} {
+		if err != nil {
+			problems = append(problems, strings.TrimPrefix(err.Error(), "env: "))
+		}
+	}
+
+	c.MaxResponseSize = int64(maxResponseSize)
+
+	if len(problems) == 0 {
+		if err := c.Validate(); err != nil {
+			return Config{}, err
+		}
+		return c, nil
+	}
+	return Config{}, fmt.Errorf("env: invalid configuration:\n\t%s", strings.Join(problems, "\n\t"))
+}
+
+// Validate answers an error describing every invalid setting of the Config, and prepares a valid Config for use.  A
+// Config answered by Load is already valid; a Config composed by hand should be validated before it is used.
+func (c *Config) Validate() error {
+	var problems []string
+	if u, err := url.Parse(c.BaseUrl); err != nil || u.Scheme == "" || u.Host == "" {
+		problems = append(problems, fmt.Sprintf("base url: '%s' is not an absolute url", c.BaseUrl))
+	}
+	if c.Timeout <= 0 {
+		problems = append(problems, fmt.Sprintf("timeout: %s must be positive", c.Timeout))
+	}
+	if c.Retries < 0 || c.Retries > maxRetries {
+		problems = append(problems, fmt.Sprintf("retries: %d must be between 0 and %d", c.Retries, maxRetries))
+	}
+	if c.RetryBackoff < 0 {
+		problems = append(problems, fmt.Sprintf("retry backoff: %s must not be negative", c.RetryBackoff))
+	}
+	if c.RateLimit < 0 {
+		problems = append(problems, fmt.Sprintf("rate limit: %g must not be negative", c.RateLimit))
+	}
+
+	client, err := c.newClient()
+	if err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("env: invalid configuration:\n\t%s", strings.Join(problems, "\n\t"))
+	}
+	c.state = &configState{client: client}
+	return nil
+}
+
+// Client answers the HTTP client honoring the timeout and TLS settings of the Config
+func (c Config) Client() *http.Client {
+	if c.state != nil {
+		return c.state.client
+	}
+	client, err := c.newClient()
+	if err != nil {
+		return &http.Client{Timeout: c.Timeout}
+	}
+	return client
+}
+
+// Throttle blocks until a request may be issued without exceeding the rate limit of the Config.  Requests are only
+// throttled by a validated Config (see Validate).
+func (c Config) Throttle() {
+	if c.RateLimit <= 0 || c.state == nil {
+		return
+	}
+
+	c.state.mu.Lock()
+	now := time.Now()
+	wait := c.state.next.Sub(now)
+	if wait < 0 {
+		wait = 0
+	}
+	c.state.next = now.Add(wait + time.Duration(float64(time.Second)/c.RateLimit))
+	c.state.mu.Unlock()
+
+	time.Sleep(wait)
+}
+
+// newClient answers a new HTTP client honoring the timeout and TLS settings of the Config
+func (c Config) newClient() (*http.Client, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: c.InsecureSkipVerify}
+	if c.CaCertFile != "" {
+		pem, err := os.ReadFile(c.CaCertFile)
+		if err != nil {
+			return nil, fmt.Errorf("ca cert: unable to read '%s': %s", c.CaCertFile, err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("ca cert: '%s' carries no PEM encoded certificates", c.CaCertFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	return &http.Client{Timeout: c.Timeout, Transport: transport}, nil
+}
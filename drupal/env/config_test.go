@@ -0,0 +1,80 @@
+package env
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// forgetConfig discards the Config cached by Load when the test completes
+func forgetConfig(t *testing.T) {
+	t.Cleanup(func() {
+		loadMu.Lock()
+		defer loadMu.Unlock()
+		loaded = nil
+	})
+}
+
+func Test_LoadDefaults(t *testing.T) {
+	forgetConfig(t)
+	t.Setenv(drupalBaseUrl, "https://idc.example.org")
+	t.Setenv(username, "admin")
+	t.Setenv(password, "moo")
+
+	c, err := Reload()
+	require.Nil(t, err, "%s", err)
+	assert.Equal(t, "https://idc.example.org", c.BaseUrl)
+	assert.Equal(t, "admin", c.Credentials.Username)
+	assert.Equal(t, defaultTimeout, c.Timeout)
+	assert.Equal(t, 0, c.Retries)
+	assert.Equal(t, defaultTimeout, c.Client().Timeout)
+	assert.Equal(t, int64(DefaultMaxResponseSize), c.MaxResponseSize)
+}
+
+func Test_LoadIsCached(t *testing.T) {
+	forgetConfig(t)
+	t.Setenv(httpRetries, "2")
+	_, err := Reload()
+	require.Nil(t, err, "%s", err)
+
+	t.Setenv(httpRetries, "3")
+	c, err := Load()
+	require.Nil(t, err, "%s", err)
+	assert.Equal(t, 2, c.Retries)
+
+	c, err = Reload()
+	require.Nil(t, err, "%s", err)
+	assert.Equal(t, 3, c.Retries)
+}
+
+func Test_LoadReportsEveryProblem(t *testing.T) {
+	forgetConfig(t)
+	t.Setenv(httpTimeout, "soon")
+	t.Setenv(httpRetries, "many")
+	t.Setenv(rateLimit, "fast")
+
+	_, err := Reload()
+	for _, v := range []string{httpTimeout, httpRetries, rateLimit} {
+		assert.Contains(t, fmt.Sprint(err), v)
+	}
+}
+
+func Test_ConfigValidate(t *testing.T) {
+	c := Config{BaseUrl: "islandora-idc", Timeout: -time.Second, Retries: maxRetries + 1, RateLimit: -1,
+		CaCertFile: "testdata/missing.pem"}
+	err := c.Validate()
+	for _, problem := range []string{"base url", "timeout", "retries", "rate limit", "ca cert"} {
+		assert.Contains(t, fmt.Sprint(err), problem)
+	}
+
+	c = Config{BaseUrl: DefaultBaseUrl, Timeout: time.Second, RateLimit: 20}
+	require.Nil(t, c.Validate())
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		c.Throttle()
+	}
+	assert.True(t, time.Since(start) >= 100*time.Millisecond, "expected requests to be throttled")
+}
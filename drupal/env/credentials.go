@@ -0,0 +1,57 @@
+package env
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Credentials authenticate requests to Drupal using HTTP Basic Auth.  Credentials read from the environment (see
+// AdminCredentials and CredentialsFor) remember the variables they were read from, so that incomplete credentials can
+// be reported with the variable that needs to be set, rather than surfacing as a 403 from Drupal.
+type Credentials struct {
+	Username string
+	Password string
+	// The environment variables the username and password were read from, if any
+	usernameVar, passwordVar string
+}
+
+// AdminCredentials answers the credentials of the Drupal administrator from the environment variables
+// 'IDC_ADMIN_USERNAME' and 'IDC_ADMIN_PASSWORD'.  The username defaults to `admin`.
+func AdminCredentials() Credentials {
+	return CredentialsFor("admin")
+}
+
+// CredentialsFor answers the credentials of the Drupal test user holding the supplied role (e.g. `collection_creator`)
+// from the environment variables 'IDC_<ROLE>_USERNAME' (see UsernameFor) and 'IDC_<ROLE>_PASSWORD'
+func CredentialsFor(role string) Credentials {
+	c := Credentials{usernameVar: roleVar(role, "USERNAME"), passwordVar: roleVar(role, "PASSWORD")}
+	c.Username = UsernameFor(role)
+	c.Password = StringOr(c.passwordVar, "")
+	return c
+}
+
+// Validate answers an error if the username or password of the credentials is missing, naming the environment
+// variable to set if the credentials were read from the environment
+func (c Credentials) Validate() error {
+	if c.Username == "" {
+		return fmt.Errorf("env: missing username for an authenticated request: %s", c.remedy(c.usernameVar))
+	}
+	if c.Password == "" {
+		return fmt.Errorf("env: missing password for user '%s': %s", c.Username, c.remedy(c.passwordVar))
+	}
+	return nil
+}
+
+// remedy answers how a missing value may be supplied
+func (c Credentials) remedy(envVar string) string {
+	if envVar == "" {
+		return "supply it explicitly"
+	}
+	return fmt.Sprintf("set %s", envVar)
+}
+
+// roleVar answers the name of the environment variable carrying the named value for the role, e.g.
+// `IDC_COLLECTION_CREATOR_PASSWORD`
+func roleVar(role, name string) string {
+	return fmt.Sprintf("IDC_%s_%s", strings.ToUpper(strings.NewReplacer("-", "_", " ", "_").Replace(role)), name)
+}
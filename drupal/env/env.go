@@ -4,13 +4,22 @@ package env
 import (
 	"fmt"
 	"os"
-	"strconv"
 )
 
 const (
 	drupalBaseUrl = "DRUPAL_BASE_URL"
 	testBasedir   = "DRUPAL_TEST_BASEDIR"
 	assetsBaseUrl = "BASE_ASSETS_URL"
+	username      = "DRUPAL_USERNAME"
+	password      = "DRUPAL_PASSWORD"
+	checkEmbeds   = "CHECK_EMBED_URLS"
+	updateExp     = "UPDATE_EXPECTED"
+	adminUsername = "IDC_ADMIN_USERNAME"
+	adminPassword = "IDC_ADMIN_PASSWORD"
+	recordFixture = "IDC_RECORD_FIXTURES"
+	destructive   = "IDC_ALLOW_DESTRUCTIVE"
+	userAgent     = "IDC_USER_AGENT"
+	testRunId     = "IDC_TEST_RUN_ID"
 )
 
 // Answers the base url of Drupal from the environment variable 'DRUPAL_BASE_URL', or panics
@@ -20,7 +29,7 @@ func BaseUrl() string {
 
 // Answers the base url of Drupal from the environment variable 'DRUPAL_BASE_URL', or returns the default value if unset
 func BaseUrlOr(defaultValue string) string {
-	return GetEnvOr(drupalBaseUrl, defaultValue)
+	return StringOr(drupalBaseUrl, defaultValue)
 }
 
 // Answers the name (not path) of the base directory for the test suite from the environment variable
@@ -32,7 +41,7 @@ func TestBasedir() string {
 // Answers the base URL to the test assets docker container from the environment variable
 // 'DRUPAL_TEST_BASEDIR', or returns the default value if unset
 func TestBasedirOr(defaultValue string) string {
-	return GetEnvOr(testBasedir, defaultValue)
+	return StringOr(testBasedir, defaultValue)
 }
 
 // Answers the base URL to the test assets docker container from the environment variable
@@ -44,44 +53,110 @@ func AssetsBaseUrl() string {
 // Answers the base URL to the test assets docker container from the environment variable
 // 'BASE_ASSETS_URL', or returns the default value if unset
 func AssetsBaseUrlOr(defaultValue string) string {
-	return GetEnvOr(assetsBaseUrl, defaultValue)
+	return StringOr(assetsBaseUrl, defaultValue)
 }
 
-// Answers the value of the supplied environment variable, or the default value if unset
+// Answers the username used to authenticate to Drupal from the environment variable 'DRUPAL_USERNAME', or returns the
+// default value if unset
+func UsernameOr(defaultValue string) string {
+	return StringOr(username, defaultValue)
+}
+
+// Answers the password used to authenticate to Drupal from the environment variable 'DRUPAL_PASSWORD', or returns the
+// default value if unset
+func PasswordOr(defaultValue string) string {
+	return StringOr(password, defaultValue)
+}
+
+// Answers the username of the Drupal administrator from the environment variable 'IDC_ADMIN_USERNAME', or returns the
+// default value if unset
+func AdminUsernameOr(defaultValue string) string {
+	return StringOr(adminUsername, defaultValue)
+}
+
+// Answers the password of the Drupal administrator from the environment variable 'IDC_ADMIN_PASSWORD', or returns the
+// default value if unset
+func AdminPasswordOr(defaultValue string) string {
+	return StringOr(adminPassword, defaultValue)
+}
+
+// Answers the username of the Drupal test user holding the supplied role (e.g. `admin` or `collection_creator`) from
+// the environment variable 'IDC_<ROLE>_USERNAME' (e.g. 'IDC_COLLECTION_CREATOR_USERNAME'), or returns the role itself
+// if unset
+func UsernameFor(role string) string {
+	return StringOr(roleVar(role, "USERNAME"), role)
+}
+
+// Answers whether remote video embed urls should be requested to confirm that they resolve, from the environment
+// variable 'CHECK_EMBED_URLS', or returns the default value if unset.  Panics if the value is not a bool.
+func CheckEmbedUrlsOr(defaultValue bool) bool {
+	return GetEnvOrBool(checkEmbeds, defaultValue)
+}
+
+// Answers whether the Compare* functions of the model package should write expected files from actual values instead of
+// making assertions, from the environment variable 'UPDATE_EXPECTED', or returns the default value if unset.  Panics if
+// the value is not a bool.
+func UpdateExpectedOr(defaultValue bool) bool {
+	return GetEnvOrBool(updateExp, defaultValue)
+}
+
+// Answers the path of the file recording the entities created by the fixtures of the model package, from the
+// environment variable 'IDC_RECORD_FIXTURES', or returns the default value if unset
+func RecordFixturesOr(defaultValue string) string {
+	return GetEnvOr(recordFixture, defaultValue)
+}
+
+// Answers whether tests may perform destructive operations on the site, e.g. rolling back a migration, from the
+// environment variable 'IDC_ALLOW_DESTRUCTIVE', or returns the default value if unset.  Answers an error if the value
+// is not a bool.
+func AllowDestructiveOr(defaultValue bool) (bool, error) {
+	return BoolOr(destructive, defaultValue)
+}
+
+// Answers the User-Agent sent with requests of the Drupal JSON API, from the environment variable 'IDC_USER_AGENT', or
+// returns the default value if unset
+func UserAgentOr(defaultValue string) string {
+	return StringOr(userAgent, defaultValue)
+}
+
+// Answers the id identifying the requests of a test run, e.g. one shared by the suites of a CI job, from the
+// environment variable 'IDC_TEST_RUN_ID', or returns the default value if unset
+func TestRunIdOr(defaultValue string) string {
+	return StringOr(testRunId, defaultValue)
+}
+
+// Answers the maximum size, in bytes, of a JSON API response body, from the environment variable
+// 'IDC_MAX_RESPONSE_SIZE', or returns the default value if unset.  Answers an error if the value is not an integer.
+func MaxResponseSizeOr(defaultValue int64) (int64, error) {
+	size, err := IntOr(maxResponse, int(defaultValue))
+	return int64(size), err
+}
+
+// Answers the value of the supplied environment variable, or the default value if unset.  Equivalent to StringOr.
 func GetEnvOr(envVar, defValue string) string {
-	if val, ok := getEnv(envVar, false); ok {
-		return val
-	} else {
-		return defValue
-	}
+	return StringOr(envVar, defValue)
 }
 
 // Answers the value of the supplied environment variable as an integer, or the default value if unset.  This function
-// will panic if the value of the environment variable cannot be parsed as an integer.
+// will panic if the value of the environment variable cannot be parsed as an integer; prefer IntOr, which answers an
+// error instead.
 func GetEnvOrInt(envVar string, defValue int) int {
-	if val, ok := getEnv(envVar, false); ok {
-		if intval, err := strconv.Atoi(val); err != nil {
-			panic(fmt.Errorf("env: error formatting the value of environment variable '%s' as an integer: %w", envVar, err))
-		} else {
-			return intval
-		}
-	} else {
-		return defValue
+	val, err := IntOr(envVar, defValue)
+	if err != nil {
+		panic(err)
 	}
+	return val
 }
 
 // Answers the value of the supplied environment variable, or the default value if unset.  This function
-// will panic if the value of the environment variable cannot be parsed as a bool.
+// will panic if the value of the environment variable cannot be parsed as a bool; prefer BoolOr, which answers an
+// error instead.
 func GetEnvOrBool(envVar string, defValue bool) bool {
-	if val, ok := getEnv(envVar, false); ok {
-		if boolval, err := strconv.ParseBool(val); err != nil {
-			panic(fmt.Errorf("env: error formatting the value of environment variable '%s' as a bool: %w", envVar, err))
-		} else {
-			return boolval
-		}
-	} else {
-		return defValue
+	val, err := BoolOr(envVar, defValue)
+	if err != nil {
+		panic(err)
 	}
+	return val
 }
 
 // Answers the value for the supplied environment variable, or panics
@@ -0,0 +1,129 @@
+package env
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+const (
+	profile      = "IDC_PROFILE"
+	profilesFile = "IDC_PROFILES_FILE"
+)
+
+// The key of each setting a profile may carry, by the environment variable it stands in for.  A profile named
+// `staging` may set its base url with the environment variable 'IDC_STAGING_BASEURL', or with the key `baseurl` of
+// the `staging` profile in the profiles file.
+var profileKeys = map[string]string{
+	drupalBaseUrl: "BASEURL",
+	username:      "USERNAME",
+	password:      "PASSWORD",
+	httpTimeout:   "TIMEOUT",
+	httpRetries:   "RETRIES",
+	retryBackoff:  "RETRY_BACKOFF",
+	tlsInsecure:   "TLS_INSECURE_SKIP_VERIFY",
+	tlsCaCert:     "TLS_CA_CERT",
+	rateLimit:     "RATE_LIMIT",
+	maxResponse:   "MAX_RESPONSE_SIZE",
+}
+
+// The settings of the profile selected by 'IDC_PROFILE'.  Settings of the profile take precedence over the environment
+// variables they stand in for.
+type profileSettings struct {
+	// the name of the profile, e.g. `staging`; empty if no profile is selected
+	name string
+	// the settings of the profile from the profiles file, by lower-cased key
+	file map[string]string
+}
+
+// ProfileOr answers the name of the active profile from the environment variable 'IDC_PROFILE', e.g. `local`,
+// `staging`, or `prod-readonly`, or returns the default value if unset
+func ProfileOr(defaultValue string) string {
+	return StringOr(profile, defaultValue)
+}
+
+// RequireProfile skips the test unless the active profile (see Config.Profile) is one of the supplied names, so that
+// tests which only make sense against a specific deployment are not run elsewhere.  The test fails if the Config
+// cannot be loaded.
+//
+//	env.RequireProfile(t, "staging")
+func RequireProfile(t *testing.T, names ...string) {
+	c, err := Load()
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	for _, name := range names {
+		if strings.EqualFold(name, c.Profile) {
+			return
+		}
+	}
+	t.Skipf("requires profile %s, but the active profile is '%s'", strings.Join(names, " or "), c.Profile)
+}
+
+// activeProfile answers the settings of the profile selected by 'IDC_PROFILE', reading them from the profiles file
+// named by 'IDC_PROFILES_FILE', if any.  The profiles file is a JSON object of profiles, each an object of settings:
+//
+//	{"staging": {"baseurl": "https://idc-stage.library.jhu.edu", "username": "verifier", "timeout": "1m"}}
+//
+// An error is answered if a profile is selected, but is defined neither by the environment nor by the profiles file.
+func activeProfile() (profileSettings, error) {
+	p := profileSettings{name: ProfileOr("")}
+	if p.name == "" {
+		return p, nil
+	}
+
+	if path := StringOr(profilesFile, ""); path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return p, fmt.Errorf("env: unable to read profiles file: %w", err)
+		}
+		profiles := map[string]map[string]interface{}{}
+		if err := json.Unmarshal(b, &profiles); err != nil {
+			return p, fmt.Errorf("env: unable to decode profiles file %s: %w", path, err)
+		}
+		for name, settings := range profiles {
+			if !strings.EqualFold(name, p.name) {
+				continue
+			}
+			p.file = map[string]string{}
+			for key, value := range settings {
+				p.file[strings.ToLower(key)] = fmt.Sprint(value)
+			}
+		}
+	}
+
+	for envVar := range profileKeys {
+		if _, _, ok := p.lookup(envVar); ok {
+			return p, nil
+		}
+	}
+	return p, fmt.Errorf("env: profile '%s' is not defined: set e.g. %s, or define it in the file named by %s",
+		p.name, p.envVar(drupalBaseUrl), profilesFile)
+}
+
+// lookup answers the value of the setting standing in for the environment variable, and where it was found, from the
+// profile if it carries the setting, otherwise from the environment variable itself.  If the setting is not found, the
+// source answered is where it may be set: the environment variable of the profile, if a profile is active.
+func (p profileSettings) lookup(envVar string) (value, source string, ok bool) {
+	if p.name != "" && profileKeys[envVar] != "" {
+		if value, ok = getEnv(p.envVar(envVar), false); ok {
+			return value, p.envVar(envVar), true
+		}
+		if value, ok = p.file[strings.ToLower(profileKeys[envVar])]; ok {
+			return value, fmt.Sprintf("%s (%s.%s)", StringOr(profilesFile, ""), p.name,
+				strings.ToLower(profileKeys[envVar])), true
+		}
+	}
+	if value, ok = getEnv(envVar, false); ok || p.name == "" || profileKeys[envVar] == "" {
+		return value, envVar, ok
+	}
+	return "", p.envVar(envVar), false
+}
+
+// envVar answers the environment variable carrying the profile's setting standing in for the environment variable,
+// e.g. `IDC_STAGING_BASEURL`
+func (p profileSettings) envVar(envVar string) string {
+	return roleVar(p.name, profileKeys[envVar])
+}
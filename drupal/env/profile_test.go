@@ -0,0 +1,74 @@
+package env
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_LoadProfileFromEnvironment(t *testing.T) {
+	forgetConfig(t)
+	t.Setenv(drupalBaseUrl, "https://islandora-idc.traefik.me")
+	t.Setenv(httpRetries, "1")
+	t.Setenv(profile, "staging")
+	t.Setenv("IDC_STAGING_BASEURL", "https://idc-stage.example.org")
+	t.Setenv("IDC_STAGING_USERNAME", "verifier")
+
+	c, err := Reload()
+	require.Nil(t, err, "%s", err)
+	assert.Equal(t, "staging", c.Profile)
+	assert.Equal(t, "https://idc-stage.example.org", c.BaseUrl)
+	assert.Equal(t, 1, c.Retries, "settings the profile does not carry are expected from the environment")
+	assert.Contains(t, fmt.Sprint(c.Credentials.Validate()), "set IDC_STAGING_PASSWORD")
+}
+
+func Test_LoadProfileFromFile(t *testing.T) {
+	forgetConfig(t)
+	path := filepath.Join(t.TempDir(), "profiles.json")
+	require.Nil(t, os.WriteFile(path, []byte(`{
+		"local": {"baseurl": "https://islandora-idc.traefik.me"},
+		"prod-readonly": {"baseurl": "https://idc.example.org", "timeout": "1m", "retries": 3, "rate_limit": "soon",
+			"max_response_size": 1024}
+	}`), 0644))
+	t.Setenv(profilesFile, path)
+	t.Setenv(profile, "prod-readonly")
+	t.Setenv("IDC_PROD_READONLY_RATE_LIMIT", "5")
+
+	c, err := Reload()
+	require.Nil(t, err, "%s", err)
+	assert.Equal(t, "https://idc.example.org", c.BaseUrl)
+	assert.Equal(t, time.Minute, c.Timeout)
+	assert.Equal(t, 3, c.Retries)
+	assert.Equal(t, int64(1024), c.MaxResponseSize)
+	assert.Equal(t, 5.0, c.RateLimit, "the environment is expected to take precedence over the profiles file")
+
+	t.Setenv(profile, "dev")
+	_, err = Reload()
+	assert.Contains(t, fmt.Sprint(err), "profile 'dev' is not defined")
+}
+
+func Test_RequireProfile(t *testing.T) {
+	forgetConfig(t)
+	t.Setenv(profile, "local")
+	t.Setenv("IDC_LOCAL_BASEURL", "https://islandora-idc.traefik.me")
+	_, err := Reload()
+	require.Nil(t, err, "%s", err)
+
+	skipped := t.Run("staging", func(t *testing.T) {
+		RequireProfile(t, "staging")
+		t.Fatal("expected the test to be skipped")
+	})
+	assert.True(t, skipped)
+
+	ran := false
+	t.Run("local", func(t *testing.T) {
+		RequireProfile(t, "staging", "local")
+		ran = true
+	})
+	assert.True(t, ran)
+}
@@ -0,0 +1,120 @@
+package env
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// StringOr answers the value of the environment variable, or the default value if unset
+func StringOr(key, defaultValue string) string {
+	if val, ok := getEnv(key, false); ok {
+		return val
+	}
+	return defaultValue
+}
+
+// BoolOr answers the value of the environment variable parsed as a bool, or the default value if unset.  An error is
+// answered if the variable is set, but is not a bool.
+func BoolOr(key string, defaultValue bool) (bool, error) {
+	return parsedOr(key, defaultValue, parseBool)
+}
+
+// IntOr answers the value of the environment variable parsed as an integer, or the default value if unset.  An error
+// is answered if the variable is set, but is not an integer.
+func IntOr(key string, defaultValue int) (int, error) {
+	return parsedOr(key, defaultValue, parseInt)
+}
+
+// DurationOr answers the value of the environment variable parsed as a duration (e.g. `30s` or `1m30s`), or the
+// default value if unset.  An error is answered if the variable is set, but is not a duration.
+func DurationOr(key string, defaultValue time.Duration) (time.Duration, error) {
+	return parsedOr(key, defaultValue, parseDuration)
+}
+
+// MustBoolOr behaves as BoolOr, but fails the test immediately if the variable is malformed
+func MustBoolOr(t *testing.T, key string, defaultValue bool) bool {
+	value, err := BoolOr(key, defaultValue)
+	return must(t, value, err)
+}
+
+// MustIntOr behaves as IntOr, but fails the test immediately if the variable is malformed
+func MustIntOr(t *testing.T, key string, defaultValue int) int {
+	value, err := IntOr(key, defaultValue)
+	return must(t, value, err)
+}
+
+// MustDurationOr behaves as DurationOr, but fails the test immediately if the variable is malformed
+func MustDurationOr(t *testing.T, key string, defaultValue time.Duration) time.Duration {
+	value, err := DurationOr(key, defaultValue)
+	return must(t, value, err)
+}
+
+// parsedOr answers the value of the environment variable converted by parse, or the default value if unset
+func parsedOr[T any](key string, defaultValue T, parse func(source, value string) (T, error)) (T, error) {
+	val, ok := getEnv(key, false)
+	if !ok {
+		return defaultValue, nil
+	}
+	return parse(key, val)
+}
+
+// lookupParsed assigns the value of the setting standing in for the environment variable (see profileSettings.lookup),
+// converted by parse, to value.  The value is left unchanged if the setting is not found.
+func lookupParsed[T any](p profileSettings, envVar string, value *T, parse func(source, value string) (T, error)) error {
+	val, source, ok := p.lookup(envVar)
+	if !ok {
+		return nil
+	}
+	parsed, err := parse(source, val)
+	if err == nil {
+		*value = parsed
+	}
+	return err
+}
+
+// must answers the value, failing the test if err is not nil
+func must[T any](t *testing.T, value T, err error) T {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	return value
+}
+
+// parseBool parses the value of the setting from the named source as a bool
+func parseBool(source, value string) (bool, error) {
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("env: %s: '%s' is not a bool", source, value)
+	}
+	return b, nil
+}
+
+// parseInt parses the value of the setting from the named source as an integer
+func parseInt(source, value string) (int, error) {
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("env: %s: '%s' is not an integer", source, value)
+	}
+	return i, nil
+}
+
+// parseFloat parses the value of the setting from the named source as a number
+func parseFloat(source, value string) (float64, error) {
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("env: %s: '%s' is not a number", source, value)
+	}
+	return f, nil
+}
+
+// parseDuration parses the value of the setting from the named source as a duration
+func parseDuration(source, value string) (time.Duration, error) {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("env: %s: '%s' is not a duration, e.g. `30s`", source, value)
+	}
+	return d, nil
+}
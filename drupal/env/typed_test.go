@@ -0,0 +1,40 @@
+package env
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TypedAccessors(t *testing.T) {
+	const key = "IDC_TEST_VALUE"
+
+	b, err := BoolOr(key, true)
+	assert.True(t, b)
+	assert.Nil(t, err)
+	i, err := IntOr(key, 3)
+	assert.Equal(t, 3, i)
+	assert.Nil(t, err)
+	d, err := DurationOr(key, time.Second)
+	assert.Equal(t, time.Second, d)
+	assert.Nil(t, err)
+	assert.Equal(t, "moo", StringOr(key, "moo"))
+
+	t.Setenv(key, "90s")
+	d, err = DurationOr(key, time.Second)
+	assert.Equal(t, 90*time.Second, d)
+	assert.Nil(t, err)
+	assert.Equal(t, 90*time.Second, MustDurationOr(t, key, time.Second))
+
+	_, err = BoolOr(key, false)
+	assert.Equal(t, "env: IDC_TEST_VALUE: '90s' is not a bool", fmt.Sprint(err))
+	_, err = IntOr(key, 0)
+	assert.Equal(t, "env: IDC_TEST_VALUE: '90s' is not an integer", fmt.Sprint(err))
+	assert.Panics(t, func() { GetEnvOrInt(key, 0) })
+
+	t.Setenv(key, "")
+	_, err = IntOr(key, 0)
+	assert.NotNil(t, err, "a variable that is set but empty is expected to be malformed")
+}
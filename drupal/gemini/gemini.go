@@ -0,0 +1,132 @@
+// Provides helpers that verify that migrated nodes and media are mirrored into Fedora, by consulting Gemini, which maps
+// the UUID of each Drupal entity to the Fedora resource mirroring it.
+//
+// Gemini is queried at the url named by the environment variable 'IDC_GEMINI_URL', e.g. `http://crayfish/gemini`:
+//
+//	GET {gemini url}/{uuid}  answers the mapping of the entity, e.g. {"drupal": "...", "fedora": "..."}, or a 404
+//	                         status if the entity is not mapped
+//
+// Fedora resources are requested using the credentials named by the environment variables 'IDC_FEDORA_USERNAME' and
+// 'IDC_FEDORA_PASSWORD', if set.  Requests use the timeout and TLS settings of the Config loaded by env.Load.
+package gemini
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	geminiUrl      = "IDC_GEMINI_URL"
+	fedoraUsername = "IDC_FEDORA_USERNAME"
+	fedoraPassword = "IDC_FEDORA_PASSWORD"
+)
+
+var (
+	// Answered (wrapped) when Gemini holds no mapping for an entity, e.g. because the entity was never indexed
+	ErrMappingMissing = errors.New("gemini: mapping missing")
+	// Answered (wrapped) when the Fedora resource mapped by Gemini does not exist
+	ErrFedoraNotFound = errors.New("gemini: fedora resource not found")
+)
+
+// Mapping is the mapping Gemini holds for a Drupal entity
+type Mapping struct {
+	// The url of the Drupal entity
+	DrupalUri string `json:"drupal"`
+	// The url of the Fedora resource mirroring the entity
+	FedoraUri string `json:"fedora"`
+}
+
+// AssertMirrored asserts that the node or media with the uuid is mirrored into Fedora: Gemini maps the uuid to a Fedora
+// resource, and that resource exists.  The failure distinguishes a missing mapping (Gemini, or the indexer that
+// registers mappings, is at fault) from a missing Fedora resource (Fedora, or the indexer that mirrors resources, is at
+// fault):
+//
+//	gemini.AssertMirrored(t, obj.JsonApiData[0].Id)
+func AssertMirrored(t *testing.T, uuid string) bool {
+	fedoraUri, err := MirroredErr(uuid)
+	switch {
+	case errors.Is(err, ErrMappingMissing):
+		return assert.Fail(t, fmt.Sprintf("%s is not mirrored: Gemini holds no mapping for it", uuid), err.Error())
+	case errors.Is(err, ErrFedoraNotFound):
+		return assert.Fail(t, fmt.Sprintf("%s is not mirrored: Gemini maps it to %s, which Fedora does not hold",
+			uuid, fedoraUri), err.Error())
+	case err != nil:
+		return assert.Fail(t, fmt.Sprintf("unable to determine whether %s is mirrored: %s", uuid, err))
+	}
+	return true
+}
+
+// MirroredErr answers the url of the Fedora resource mirroring the node or media with the uuid.  The error wraps
+// ErrMappingMissing if Gemini holds no mapping for the uuid, or ErrFedoraNotFound (along with the Fedora url) if the
+// mapped resource does not exist.
+func MirroredErr(uuid string) (string, error) {
+	c, err := env.Load()
+	if err != nil {
+		return "", err
+	}
+
+	m, err := mappingErr(c, uuid)
+	if err != nil {
+		return "", err
+	}
+	if m.FedoraUri == "" {
+		return "", fmt.Errorf("%w: Gemini maps %s to no Fedora resource", ErrMappingMissing, uuid)
+	}
+
+	fedora := &jsonapi.Client{Config: &c, Credentials: &env.Credentials{Username: env.StringOr(fedoraUsername, ""),
+		Password: env.StringOr(fedoraPassword, "")}}
+	_, err = fedora.Do(context.Background(), http.MethodHead, m.FedoraUri, nil, nil)
+	var httpErr *jsonapi.HTTPError
+	switch {
+	case errors.As(err, &httpErr) && (httpErr.Status == http.StatusNotFound || httpErr.Status == http.StatusGone):
+		return m.FedoraUri, fmt.Errorf("%w: %w", ErrFedoraNotFound, err)
+	case err != nil:
+		return m.FedoraUri, err
+	}
+	return m.FedoraUri, nil
+}
+
+// MappingErr answers the mapping Gemini holds for the node or media with the uuid.  The error wraps ErrMappingMissing
+// if Gemini holds no mapping.
+func MappingErr(uuid string) (Mapping, error) {
+	c, err := env.Load()
+	if err != nil {
+		return Mapping{}, err
+	}
+	return mappingErr(c, uuid)
+}
+
+// mappingErr retrieves the mapping of the uuid from Gemini
+func mappingErr(c env.Config, uuid string) (Mapping, error) {
+	base := env.StringOr(geminiUrl, "")
+	if base == "" {
+		return Mapping{}, fmt.Errorf("gemini: the Gemini endpoint is unknown: set %s", geminiUrl)
+	}
+	u := strings.TrimSuffix(base, "/") + "/" + url.PathEscape(uuid)
+
+	// Gemini is requested without credentials
+	res, err := (&jsonapi.Client{Config: &c, Anonymous: true}).Do(context.Background(), http.MethodGet, u, nil,
+		http.Header{"Accept": {"application/json"}})
+	switch {
+	case errors.Is(err, jsonapi.ErrNotFound):
+		return Mapping{}, fmt.Errorf("%w: %w", ErrMappingMissing, err)
+	case err != nil:
+		return Mapping{}, err
+	}
+
+	m := Mapping{}
+	if err := json.Unmarshal(res.Body, &m); err != nil {
+		return Mapping{}, fmt.Errorf("gemini: unable to unmarshal the mapping answered by %s: %w", env.RedactUrl(u), err)
+	}
+	return m, nil
+}
@@ -0,0 +1,73 @@
+package gemini
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFakeStack answers a server acting as Gemini, mapping the uuids `1` and `2`, and as Fedora, holding only the
+// resource mapped to `1`, and configures the environment to use it
+func newFakeStack(t *testing.T) *httptest.Server {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/gemini/1", "/gemini/2":
+			id := r.URL.Path[len("/gemini/"):]
+			_, _ = fmt.Fprintf(w, `{"drupal": "%[1]s/node/%[2]s", "fedora": "%[1]s/fcrepo/rest/%[2]s"}`, server.URL, id)
+		case "/fcrepo/rest/1":
+			if username, password, _ := r.BasicAuth(); username != "fedoraAdmin" || password != "moo" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			assert.Equal(t, http.MethodHead, r.Method)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	t.Cleanup(func() { _, _ = env.Reload() })
+	t.Setenv("IDC_GEMINI_URL", server.URL+"/gemini/")
+	t.Setenv("IDC_FEDORA_USERNAME", "fedoraAdmin")
+	t.Setenv("IDC_FEDORA_PASSWORD", "moo")
+	_, err := env.Reload()
+	require.Nil(t, err, "%s", err)
+	return server
+}
+
+func Test_AssertMirrored(t *testing.T) {
+	server := newFakeStack(t)
+
+	assert.True(t, AssertMirrored(t, "1"))
+
+	m, err := MappingErr("1")
+	assert.Nil(t, err, "%s", err)
+	assert.Equal(t, Mapping{DrupalUri: server.URL + "/node/1", FedoraUri: server.URL + "/fcrepo/rest/1"}, m)
+}
+
+func Test_MirroredErr(t *testing.T) {
+	server := newFakeStack(t)
+
+	fedoraUri, err := MirroredErr("2")
+	assert.True(t, errors.Is(err, ErrFedoraNotFound), "expected ErrFedoraNotFound, got %v", err)
+	assert.Equal(t, server.URL+"/fcrepo/rest/2", fedoraUri)
+
+	_, err = MirroredErr("3")
+	assert.True(t, errors.Is(err, ErrMappingMissing), "expected ErrMappingMissing, got %v", err)
+
+	t.Setenv("IDC_FEDORA_PASSWORD", "")
+	_, err = MirroredErr("1")
+	assert.Contains(t, fmt.Sprint(err), "401 status encountered sending HEAD")
+	assert.False(t, errors.Is(err, ErrFedoraNotFound))
+
+	t.Setenv("IDC_GEMINI_URL", "")
+	_, err = MirroredErr("1")
+	assert.Contains(t, fmt.Sprint(err), "set IDC_GEMINI_URL")
+}
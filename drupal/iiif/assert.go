@@ -0,0 +1,61 @@
+package iiif
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+// AssertCanvasCount asserts that the manifest carries the expected number of canvases, e.g. one for each page of a book
+func AssertCanvasCount(t *testing.T, m Manifest, expected int) bool {
+	return assert.Equal(t, expected, len(m.Canvases), "unexpected number of canvases in manifest %s", m.Id)
+}
+
+// AssertImageServiceReachable asserts that the info.json of each distinct image service of the manifest answers a
+// HEAD request with a 200 status.  Every unreachable image service is reported, with the canvas carrying it.
+func AssertImageServiceReachable(t *testing.T, m Manifest) bool {
+	c, err := env.Load()
+	if !assert.Nil(t, err, "%s", err) {
+		return false
+	}
+
+	checked := map[string]bool{}
+	var failures []string
+	for _, canvas := range m.Canvases {
+		for _, service := range canvas.ImageServices {
+			if checked[service] {
+				continue
+			}
+			checked[service] = true
+			if _, err := sendErr(c, http.MethodHead, strings.TrimSuffix(service, "/")+"/info.json"); err != nil {
+				failures = append(failures, fmt.Sprintf("canvas '%s': %s", canvas.Label, err))
+			}
+		}
+	}
+	if len(failures) > 0 {
+		return assert.Fail(t, fmt.Sprintf("%d image service(s) of manifest %s are unreachable:\n\t%s", len(failures),
+			m.Id, strings.Join(failures, "\n\t")))
+	}
+	return assert.NotEmpty(t, checked, "manifest %s carries no image services", m.Id)
+}
+
+// AssertCanvasOrder asserts that the labels of the canvases of the manifest are the titles of the children of the
+// repository object, ordered by their field_weight (see model.ChildrenInOrder), e.g. that the canvases of a book follow
+// the order of its pages
+func AssertCanvasOrder(t *testing.T, m Manifest, parentUuid string) bool {
+	var expected []string
+	for _, child := range model.ChildrenInOrder(t, parentUuid).JsonApiData {
+		expected = append(expected, child.JsonApiAttributes.Title)
+	}
+	var actual []string
+	for _, canvas := range m.Canvases {
+		actual = append(actual, canvas.Label)
+	}
+	return assert.Equal(t, expected, actual, "the canvases of manifest %s are not ordered by the weight of the "+
+		"children of %s", m.Id, parentUuid)
+}
@@ -0,0 +1,62 @@
+package iiif
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_AssertCanvasCount(t *testing.T) {
+	newManifestServer(t, manifestV3, "")
+
+	assert.True(t, AssertCanvasCount(t, FetchManifest(t, "1"), 2))
+}
+
+func Test_AssertImageServiceReachable(t *testing.T) {
+	server := newManifestServer(t, manifestV2, "")
+	m := FetchManifest(t, "1")
+
+	m.Canvases = m.Canvases[:1]
+	assert.True(t, AssertImageServiceReachable(t, m))
+
+	_, err := sendErr(mustConfig(t), "HEAD", server.URL+"/iiif/2/page2/info.json")
+	assert.Contains(t, fmt.Sprint(err), "404 status encountered sending HEAD")
+}
+
+// Drupal credentials are expected to be sent only to Drupal, and not to the host of an image service
+func Test_SendErrCredentials(t *testing.T) {
+	t.Setenv("DRUPAL_USERNAME", "admin")
+	t.Setenv("DRUPAL_PASSWORD", "moo")
+	newManifestServer(t, manifestV2, "")
+	var authorization []string
+	images := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = append(authorization, r.Header.Get("Authorization"))
+	}))
+	defer images.Close()
+
+	_, err := sendErr(mustConfig(t), http.MethodHead, images.URL+"/iiif/2/page1/info.json")
+	assert.Nil(t, err, "%s", err)
+	_, _ = FetchManifestErr(images.URL + "/node/12/manifest")
+	assert.Equal(t, []string{"", ""}, authorization, "no Authorization header is expected to be sent to %s",
+		images.URL)
+
+	c := mustConfig(t)
+	c.BaseUrl = images.URL
+	_, err = sendErr(c, http.MethodHead, images.URL+"/iiif/2/page1/info.json")
+	assert.Nil(t, err, "%s", err)
+	if assert.Equal(t, 3, len(authorization)) {
+		assert.NotEmpty(t, authorization[2], "the credentials are expected to be sent to Drupal")
+	}
+}
+
+func Test_AssertCanvasOrder(t *testing.T) {
+	// the children of the book are answered out of order
+	newManifestServer(t, manifestV2, `
+		{"type": "node--islandora_object", "id": "3", "attributes": {"title": "Page 2", "field_weight": 2}},
+		{"type": "node--islandora_object", "id": "2", "attributes": {"title": "Page 1", "field_weight": 1}}`)
+
+	assert.True(t, AssertCanvasOrder(t, FetchManifest(t, "1"), "1"))
+}
@@ -0,0 +1,260 @@
+// Provides helpers that retrieve the IIIF Presentation manifests of repository objects, and verify them, e.g. that a
+// book manifest carries a canvas for each page, in order, and that each image service is reachable.
+//
+// The url of the manifest of a repository object is built from the pattern named by the environment variable
+// 'IDC_IIIF_MANIFEST_PATTERN' (by default `/node/{nid}/manifest`), relative to the base url of Drupal.  The pattern may
+// carry the placeholders `{nid}`, the node id of the repository object, and `{uuid}`, its UUID.  Presentation 2 and 3
+// manifests are decoded into the same Manifest.
+package iiif
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/jhu-idc/idc-golang/drupal/model"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	manifestPattern        = "IDC_IIIF_MANIFEST_PATTERN"
+	defaultManifestPattern = "/node/{nid}/manifest"
+)
+
+// Manifest is a minimal IIIF Presentation manifest, decoded from either version 2 or version 3
+type Manifest struct {
+	// The major version of the Presentation API, 2 or 3
+	Version int
+	// The id of the manifest
+	Id string
+	// The label of the manifest; the first value of a version 3 language map
+	Label string
+	// The canvases of the manifest, in order: the canvases of the first sequence of version 2, or the items of version 3
+	Canvases []Canvas
+}
+
+// Canvas is a single view of a manifest, e.g. a page of a book
+type Canvas struct {
+	Id    string
+	Label string
+	// The ids of the image services of the images painted on the canvas, e.g. `https://iiif.example.edu/iiif/2/abc`
+	ImageServices []string
+}
+
+// FetchManifest retrieves and decodes the manifest of the repository object identified by ref, failing the test
+// immediately if it cannot.  The ref is a UUID, from which the url of the manifest is built (see the package
+// documentation), or the path (e.g. `/node/12/book-manifest`) or absolute url of a manifest:
+//
+//	manifest := iiif.FetchManifest(t, book.JsonApiData[0].Id)
+//	iiif.AssertCanvasCount(t, manifest, 12)
+func FetchManifest(t *testing.T, ref string) Manifest {
+	m, err := FetchManifestErr(ref)
+	require.Nil(t, err, "%s", err)
+	return m
+}
+
+// FetchManifestErr behaves as FetchManifest, but answers an error instead of failing the test
+func FetchManifestErr(ref string) (Manifest, error) {
+	c, err := env.Load()
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	u, err := manifestUrlErr(c, ref)
+	if err != nil {
+		return Manifest{}, err
+	}
+	body, err := sendErr(c, http.MethodGet, u)
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	m, err := decodeManifest(body)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("iiif: unable to decode the manifest at %s: %w", env.RedactUrl(u), err)
+	}
+	return m, nil
+}
+
+// manifestUrlErr answers the absolute url of the manifest identified by ref
+func manifestUrlErr(c env.Config, ref string) (string, error) {
+	base := strings.TrimSuffix(c.BaseUrl, "/")
+	switch {
+	case strings.HasPrefix(ref, "http://") || strings.HasPrefix(ref, "https://"):
+		return ref, nil
+	case strings.HasPrefix(ref, "/"):
+		return base + ref, nil
+	}
+
+	u := env.StringOr(manifestPattern, defaultManifestPattern)
+	u = strings.ReplaceAll(u, "{uuid}", ref)
+	if strings.Contains(u, "{nid}") {
+		nid, err := model.NodeIdErr(ref, model.WithConfig(&c))
+		if err != nil {
+			return "", err
+		}
+		u = strings.ReplaceAll(u, "{nid}", strconv.Itoa(nid))
+	}
+	if strings.HasPrefix(u, "/") {
+		u = base + u
+	}
+	return u, nil
+}
+
+// The members of version 2 and version 3 manifests decoded by decodeManifest
+type rawManifest struct {
+	Context   json.RawMessage `json:"@context"`
+	LegacyId  string          `json:"@id"`
+	Id        string          `json:"id"`
+	Label     json.RawMessage `json:"label"`
+	Sequences []struct {
+		Canvases []struct {
+			Id     string          `json:"@id"`
+			Label  json.RawMessage `json:"label"`
+			Images []struct {
+				Resource struct {
+					Service json.RawMessage `json:"service"`
+				} `json:"resource"`
+			} `json:"images"`
+		} `json:"canvases"`
+	} `json:"sequences"`
+	Items []struct {
+		Id    string          `json:"id"`
+		Label json.RawMessage `json:"label"`
+		Items []struct {
+			Items []struct {
+				Body struct {
+					Service json.RawMessage `json:"service"`
+				} `json:"body"`
+			} `json:"items"`
+		} `json:"items"`
+	} `json:"items"`
+}
+
+// decodeManifest decodes a version 2 or version 3 manifest
+func decodeManifest(body []byte) (Manifest, error) {
+	raw := rawManifest{}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return Manifest{}, err
+	}
+
+	m := Manifest{Version: 2, Id: raw.LegacyId, Label: label(raw.Label)}
+	if strings.Contains(string(raw.Context), "presentation/3") {
+		m.Version, m.Id = 3, raw.Id
+		for _, item := range raw.Items {
+			canvas := Canvas{Id: item.Id, Label: label(item.Label)}
+			for _, page := range item.Items {
+				for _, annotation := range page.Items {
+					canvas.ImageServices = append(canvas.ImageServices, serviceIds(annotation.Body.Service)...)
+				}
+			}
+			m.Canvases = append(m.Canvases, canvas)
+		}
+		return m, nil
+	}
+
+	if len(raw.Sequences) == 0 {
+		return m, nil
+	}
+	for _, c := range raw.Sequences[0].Canvases {
+		canvas := Canvas{Id: c.Id, Label: label(c.Label)}
+		for _, image := range c.Images {
+			canvas.ImageServices = append(canvas.ImageServices, serviceIds(image.Resource.Service)...)
+		}
+		m.Canvases = append(m.Canvases, canvas)
+	}
+	return m, nil
+}
+
+// label answers the value of a label: a string, the first of an array of strings or of `@value` objects (version 2),
+// or the first value of a language map (version 3), preferring the `none` and `en` languages
+func label(raw json.RawMessage) string {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	var values []json.RawMessage
+	if json.Unmarshal(raw, &values) == nil {
+		if len(values) == 0 {
+			return ""
+		}
+		return label(values[0])
+	}
+	var value struct {
+		Value string `json:"@value"`
+	}
+	if json.Unmarshal(raw, &value) == nil && value.Value != "" {
+		return value.Value
+	}
+	var languages map[string][]string
+	if json.Unmarshal(raw, &languages) == nil {
+		for _, lang := range []string{"none", "en"} {
+			if len(languages[lang]) > 0 {
+				return languages[lang][0]
+			}
+		}
+		for _, values := range languages {
+			if len(values) > 0 {
+				return values[0]
+			}
+		}
+	}
+	return ""
+}
+
+// serviceIds answers the ids of a service, which may be a single service or an array of services, identified by
+// `@id` (version 2) or `id` (version 3)
+func serviceIds(raw json.RawMessage) []string {
+	type service struct {
+		LegacyId string `json:"@id"`
+		Id       string `json:"id"`
+	}
+	var services []service
+	if json.Unmarshal(raw, &services) != nil {
+		var s service
+		if json.Unmarshal(raw, &s) != nil {
+			return nil
+		}
+		services = []service{s}
+	}
+
+	var ids []string
+	for _, s := range services {
+		if s.Id != "" {
+			ids = append(ids, s.Id)
+		} else if s.LegacyId != "" {
+			ids = append(ids, s.LegacyId)
+		}
+	}
+	return ids
+}
+
+// sendErr sends a request using the method, and answers the body of the response.  The request is authenticated by the
+// credentials of the Config (if any) only if it is sent to Drupal, i.e. the scheme and host of the url are those of the
+// base url of the Config, so that the credentials are not sent to the hosts of image services, or of manifests named by
+// an absolute url.
+func sendErr(c env.Config, method, u string) ([]byte, error) {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return nil, fmt.Errorf("iiif: error creating request for %s: %w", env.RedactUrl(u), err)
+	}
+	client := &jsonapi.Client{Config: &c, Anonymous: !sameOrigin(parsed, c.BaseUrl)}
+	res, err := client.Do(context.Background(), method, u, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return res.Body, nil
+}
+
+// sameOrigin answers whether the url has the scheme and host (including the port) of the base url
+func sameOrigin(u *url.URL, baseUrl string) bool {
+	base, err := url.Parse(baseUrl)
+	return err == nil && strings.EqualFold(u.Scheme, base.Scheme) && strings.EqualFold(u.Host, base.Host)
+}
@@ -0,0 +1,106 @@
+package iiif
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// A version 2 manifest of a book with two pages, whose image services are served by the site
+const manifestV2 = `{
+	"@context": "http://iiif.io/api/presentation/2/context.json",
+	"@id": "%[1]s/node/12/manifest",
+	"label": "Moonrise",
+	"sequences": [{"canvases": [
+		{"@id": "%[1]s/canvas/1", "label": "Page 1",
+			"images": [{"resource": {"service": {"@id": "%[1]s/iiif/2/page1"}}}]},
+		{"@id": "%[1]s/canvas/2", "label": [{"@value": "Page 2", "@language": "en"}],
+			"images": [{"resource": {"service": {"@id": "%[1]s/iiif/2/page2"}}}]}
+	]}]
+}`
+
+// The version 3 equivalent of manifestV2
+const manifestV3 = `{
+	"@context": "http://iiif.io/api/presentation/3/context.json",
+	"id": "%[1]s/node/12/manifest",
+	"label": {"en": ["Moonrise"]},
+	"items": [
+		{"id": "%[1]s/canvas/1", "label": {"none": ["Page 1"]},
+			"items": [{"items": [{"body": {"service": [{"id": "%[1]s/iiif/2/page1"}]}}]}]},
+		{"id": "%[1]s/canvas/2", "label": {"fr": ["Page 2"]},
+			"items": [{"items": [{"body": {"service": [{"@id": "%[1]s/iiif/2/page2"}]}}]}]}
+	]
+}`
+
+// newManifestServer answers a server serving the manifest of node 12, whose uuid is `1`, and its image services, and
+// configures the environment to use it
+func newManifestServer(t *testing.T, manifest string, children string) *httptest.Server {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/jsonapi/node/islandora_object" && r.URL.Query().Get("filter[id]") == "1":
+			_, _ = w.Write([]byte(`{"data": [{"type": "node--islandora_object", "id": "1",
+				"attributes": {"drupal_internal__nid": 12}}]}`))
+		case r.URL.Path == "/jsonapi/node/islandora_object":
+			_, _ = fmt.Fprintf(w, `{"data": [%s]}`, children)
+		case r.URL.Path == "/node/12/manifest":
+			_, _ = fmt.Fprintf(w, manifest, server.URL)
+		case r.URL.Path == "/iiif/2/page1/info.json" && r.Method == http.MethodHead:
+			w.WriteHeader(http.StatusOK)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	t.Cleanup(func() { _, _ = env.Reload() })
+	t.Setenv("DRUPAL_BASE_URL", server.URL)
+	_, err := env.Reload()
+	require.Nil(t, err, "%s", err)
+	return server
+}
+
+func Test_FetchManifest(t *testing.T) {
+	for version, manifest := range map[int]string{2: manifestV2, 3: manifestV3} {
+		t.Run(fmt.Sprintf("v%d", version), func(t *testing.T) {
+			server := newManifestServer(t, manifest, "")
+
+			m := FetchManifest(t, "1")
+			assert.Equal(t, version, m.Version)
+			assert.Equal(t, server.URL+"/node/12/manifest", m.Id)
+			assert.Equal(t, "Moonrise", m.Label)
+			require.Equal(t, 2, len(m.Canvases))
+			assert.Equal(t, Canvas{Id: server.URL + "/canvas/1", Label: "Page 1",
+				ImageServices: []string{server.URL + "/iiif/2/page1"}}, m.Canvases[0])
+			assert.Equal(t, "Page 2", m.Canvases[1].Label)
+			assert.Equal(t, []string{server.URL + "/iiif/2/page2"}, m.Canvases[1].ImageServices)
+
+			assert.Equal(t, m, FetchManifest(t, "/node/12/manifest"))
+			assert.Equal(t, m, FetchManifest(t, server.URL+"/node/12/manifest"))
+		})
+	}
+}
+
+func Test_FetchManifestErr(t *testing.T) {
+	newManifestServer(t, manifestV2, "")
+
+	_, err := FetchManifestErr("2")
+	assert.Contains(t, fmt.Sprint(err), "no repository object or collection 2")
+
+	t.Setenv("IDC_IIIF_MANIFEST_PATTERN", "/node/{uuid}/book-manifest")
+	_, err = FetchManifestErr("1")
+	assert.Contains(t, fmt.Sprint(err), "404 status encountered when requesting")
+	assert.Contains(t, fmt.Sprint(err), "/node/1/book-manifest")
+}
+
+// mustConfig answers the Config of the environment
+func mustConfig(t *testing.T) env.Config {
+	c, err := env.Load()
+	require.Nil(t, err, "%s", err)
+	return c
+}
@@ -0,0 +1,396 @@
+package jsonapi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+)
+
+// Client retrieves resources of the Drupal JSON API, answering errors rather than making assertions, so that it may be
+// used outside of tests, e.g. by a command line tool auditing content.  The requests of a JsonApiUrl are issued by a
+// Client carrying its settings; its testing.T methods (e.g. GetSingle) are adapters reporting the errors of the
+// Client.  The zero Client issues unauthenticated requests using a default HTTP client:
+//
+//	config, err := env.Load()
+//	...
+//	obj := model.JsonApiIslandoraObj{}
+//	err = jsonapi.NewClient(config).GetSingle(ctx, config.BaseUrl+"/jsonapi/node/islandora_object/"+id, &obj)
+type Client struct {
+	// If present, supplies the credentials of requests, and the timeout, TLS settings, retries, and rate limit of
+	// requests (see env.Load)
+	Config *env.Config
+	// If present, authenticates requests in place of the credentials of the Config
+	Credentials *env.Credentials
+	// If true, requests are sent without credentials, regardless of Credentials and Config
+	Anonymous bool
+	// If present, and requests are not Anonymous, answers a token sent as the bearer of each request in place of Basic
+	// authentication
+	BearerToken func() (string, error)
+	// If non-zero, bounds the time of each request, in place of the timeout of the Config
+	Timeout time.Duration
+	// If present, records the status and duration of each request, and its retries, in place of the Logger set by
+	// SetLogger
+	Logger Logger
+	// If non-zero, bounds the size, in bytes, of each response body in place of the maximum response size of the
+	// Config, or env.DefaultMaxResponseSize if there is no Config; a negative size is unlimited
+	MaxResponseSize int64
+	// If present, sent as the User-Agent of requests in place of 'IDC_USER_AGENT' or DefaultUserAgent
+	UserAgent string
+	// If present, sent as the TestRunHeader of requests in place of the id of the current test run (see TestRunId)
+	TestRunId string
+}
+
+// NewClient answers a Client issuing requests according to the Config, e.g. as answered by env.Load
+func NewClient(c env.Config) *Client {
+	return &Client{Config: &c}
+}
+
+// GetSingle retrieves the JSON API url, and unmarshals the response into the supplied interface (which must be a
+// pointer).  An error is answered if the request fails, the response cannot be unmarshaled, or the `data` element of
+// the response does not contain exactly one object.  If the response contains no objects, the error wraps ErrNotFound.
+func (c *Client) GetSingle(ctx context.Context, u string, v interface{}) error {
+	value, err := c.getResponseErr(ctx, u)
+	if err != nil {
+		return err
+	}
+	if len(value.Data) == 0 {
+		return fmt.Errorf("%w: no JSONAPI data elements in the response from %s", ErrNotFound, env.RedactUrl(u))
+	}
+	if len(value.Data) != 1 {
+		return fmt.Errorf("jsonapi: exactly one JSONAPI data element is expected in the response from %s, but found %d element(s)", env.RedactUrl(u), len(value.Data))
+	}
+	return value.toErr(v)
+}
+
+// GetByUuid retrieves the entity of the type with the UUID by its canonical path (see CanonicalUrl), and unmarshals
+// the response into the supplied interface (which must be a pointer).  Drupal answers the `data` element of the
+// canonical path as an object, which is unmarshaled as the sole element of a `data` slice, so the structs of the model
+// package may be supplied.  If the entity does not exist, the error wraps ErrNotFound.
+func (c *Client) GetByUuid(ctx context.Context, baseUrl string, t DrupalType, uuid string, v interface{}) error {
+	return c.GetSingle(ctx, CanonicalUrl(baseUrl, t, uuid), v)
+}
+
+// CanonicalUrl answers the canonical url of the entity of the type with the UUID, e.g.
+// `{base url}/jsonapi/node/islandora_object/{uuid}`
+func CanonicalUrl(baseUrl string, t DrupalType, uuid string) string {
+	return strings.Join([]string{strings.TrimSuffix(baseUrl, "/"), "jsonapi", url.PathEscape(t.Entity()),
+		url.PathEscape(t.Bundle()), url.PathEscape(uuid)}, "/")
+}
+
+// Get retrieves the JSON API url, and unmarshals the response into the supplied interface (which must be a pointer),
+// regardless of the number of objects in its `data` element
+func (c *Client) Get(ctx context.Context, u string, v interface{}) error {
+	value, err := c.getResponseErr(ctx, u)
+	if err != nil {
+		return err
+	}
+	return value.toErr(v)
+}
+
+// GetAll behaves as Get, but follows the `next` link of each paginated response until the last page is retrieved, and
+// unmarshals the `data` elements of every page into the supplied interface
+func (c *Client) GetAll(ctx context.Context, u string, v interface{}) error {
+	all := &JsonApiResponse{}
+	err := eachPage(u, func(u string) ([]byte, error) {
+		body, _, err := c.fetchErr(ctx, u, nil)
+		return body, err
+	}, func(page *JsonApiResponse) error {
+		all.Data = append(all.Data, page.Data...)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return all.toErr(v)
+}
+
+// EachPage behaves as GetAll, but unmarshals the `data` elements of each page in turn into the supplied interface, and
+// invokes f with each (see JsonApiUrl.EachPageErr)
+func (c *Client) EachPage(ctx context.Context, u string, v interface{}, f func() error) error {
+	return eachPage(u, func(u string) ([]byte, error) {
+		body, _, err := c.fetchErr(ctx, u, nil)
+		return body, err
+	}, func(page *JsonApiResponse) error {
+		// discard the elements of the previous page, rather than decoding over them
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer && !rv.IsNil() {
+			rv.Elem().Set(reflect.Zero(rv.Elem().Type()))
+		}
+		if err := page.toErr(v); err != nil {
+			return err
+		}
+		return f()
+	})
+}
+
+// getResponseErr retrieves the url, and unmarshals its response
+func (c *Client) getResponseErr(ctx context.Context, u string) (*JsonApiResponse, error) {
+	body, _, err := c.fetchErr(ctx, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	value := &JsonApiResponse{}
+	if err := decodeErr(u, body, value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// fetchErr answers the body and headers of the response from the url, sending the supplied request headers (e.g. the
+// validators of a conditional request) and the identification of the Client (see identify), and authenticated
+// according to basicAuth, or the bearer token if present.  If
+// the Client carries a Config, the request honors its client settings and rate limit, and is retried after a network
+// error or a 5xx response as many times as the Config allows.
+func (c *Client) fetchErr(ctx context.Context, u string, header http.Header) ([]byte, http.Header, error) {
+	username, password, header, err := c.authorizeErr(u, header)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	client, logger := c.client(), loggerOr(c.Logger)
+	retries, backoff := c.retries()
+
+	for attempt := 0; ; attempt++ {
+		if c.Config != nil {
+			c.Config.Throttle()
+		}
+		start := time.Now()
+		res, err := openResourceWithContextErr(ctx, client, u, username, password, header)
+		logRequest(logger, http.MethodGet, u, res, err, time.Since(start))
+		if err == nil {
+			defer func() { _ = res.Body.Close() }()
+			body, err := readLimitedErr(u, res.Body, c.maxResponseSize())
+			if err != nil {
+				return nil, nil, err
+			}
+			return body, res.Header, nil
+		}
+		if attempt >= retries || (res != nil && res.StatusCode < 500) || ctx.Err() != nil {
+			return nil, nil, err
+		}
+		logger.Log("jsonapi retry", "url", env.RedactUrl(u), "attempt", attempt+1, "of", retries, "error", err)
+		if err := sleepErr(ctx, time.Duration(attempt+1)*backoff); err != nil {
+			return nil, nil, err
+		}
+	}
+}
+
+// Response is the response to a request sent by Client.Do
+type Response struct {
+	// The status of the response, e.g. 200
+	Status int
+	Header http.Header
+	// The body of the response, bounded by the maximum response size of the Client
+	Body []byte
+}
+
+// Do sends a request using the method to the url, carrying the body (if not nil) and the supplied headers (e.g. Accept
+// or Content-Type), and answers the response.  It is the means by which the endpoints of Drupal, and of the services
+// beside it, that are not part of the JSON API (e.g. search, OAI-PMH, or the migrate endpoints) are requested, so that
+// their requests are authenticated, identified (see TestRunHeader), rate limited, logged, and bounded in size as the
+// JSON API requests of the Client are.  GET and HEAD requests are retried after a network error or a 5xx response as
+// many times as the Config allows; other methods are not retried, as they may not be idempotent.
+//
+// If the response carries a status other than 2xx, it is answered along with an HTTPError, so that the caller may
+// branch on the status (e.g. errors.Is(err, ErrNotFound)), or read an error payload from its body.
+func (c *Client) Do(ctx context.Context, method, u string, body []byte, header http.Header) (*Response, error) {
+	username, password, header, err := c.authorizeErr(u, header)
+	if err != nil {
+		return nil, err
+	}
+
+	client, logger := c.client(), loggerOr(c.Logger)
+	retries, backoff := c.retries()
+	if method != http.MethodGet && method != http.MethodHead {
+		retries = 0
+	}
+
+	for attempt := 0; ; attempt++ {
+		if c.Config != nil {
+			c.Config.Throttle()
+		}
+		start := time.Now()
+		res, err := sendRequestErr(ctx, client, method, u, body, username, password, header)
+		logRequest(logger, method, u, res, err, time.Since(start))
+		if err == nil {
+			resBody, readErr := readLimitedErr(u, res.Body, c.maxResponseSize())
+			_ = res.Body.Close()
+			if readErr != nil {
+				return nil, readErr
+			}
+			response := &Response{Status: res.StatusCode, Header: res.Header, Body: resBody}
+			if res.StatusCode >= 200 && res.StatusCode <= 299 {
+				return response, nil
+			}
+			if res.StatusCode < 500 || attempt >= retries || ctx.Err() != nil {
+				return response, newHTTPError(method, u, res.StatusCode, resBody)
+			}
+			err = newHTTPError(method, u, res.StatusCode, resBody)
+		} else if attempt >= retries || ctx.Err() != nil {
+			return nil, err
+		}
+		logger.Log("jsonapi retry", "url", env.RedactUrl(u), "attempt", attempt+1, "of", retries, "error", err)
+		if err := sleepErr(ctx, time.Duration(attempt+1)*backoff); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// sleepErr waits for the duration before a retry, answering the error of the context if it is done first
+func sleepErr(ctx context.Context, d time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d):
+		return nil
+	}
+}
+
+// sendRequestErr sends a request using the method to the url, carrying the body (if not nil) and the supplied headers,
+// authenticated by the username and password (if the username is not empty), and answers its response, whatever its
+// status.  The caller is responsible for closing the body of the response.
+func sendRequestErr(ctx context.Context, client *http.Client, method, u string, body []byte, username, password string,
+	header http.Header) (*http.Response, error) {
+	var content io.Reader
+	if body != nil {
+		content = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, content)
+	if err != nil {
+		return nil, fmt.Errorf("jsonapi: error creating request for %s: %w", env.RedactUrl(u), err)
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	identify(req, "", "")
+	if strings.TrimSpace(username) != "" {
+		req.SetBasicAuth(username, password)
+		log.Printf("Sending %s (with Authorization: basic) %s", method, env.RedactUrl(u))
+	} else {
+		log.Printf("Sending %s %s", method, env.RedactUrl(u))
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("jsonapi: encountered error sending %s %s: %w", method, env.RedactUrl(u), err)
+	}
+	return res, nil
+}
+
+// authorizeErr answers the username and password authenticating a request to the url (see basicAuth), and the supplied
+// headers carrying the identification of the Client (see identifiedHeader), and its bearer token, if present, in place
+// of the username and password
+func (c *Client) authorizeErr(u string, header http.Header) (string, string, http.Header, error) {
+	username, password := c.basicAuth()
+	header = c.identifiedHeader(header)
+	if c.BearerToken != nil && !c.Anonymous {
+		token, err := c.BearerToken()
+		if err != nil {
+			return "", "", nil, fmt.Errorf("jsonapi: unable to obtain a bearer token for %s: %w", env.RedactUrl(u), err)
+		}
+		return "", "", withBearer(header, token), nil
+	}
+	return username, password, header, nil
+}
+
+// retries answers the number of times a failed request is retried, and the delay before the first retry, according to
+// the Config (if any)
+func (c *Client) retries() (int, time.Duration) {
+	if c.Config == nil {
+		return 0, 0
+	}
+	return c.Config.Retries, c.Config.RetryBackoff
+}
+
+// maxResponseSize answers the maximum size of a response body: the MaxResponseSize of the Client if non-zero, otherwise
+// that of the Config, or without a Config that of 'IDC_MAX_RESPONSE_SIZE' (if it is an integer), or
+// env.DefaultMaxResponseSize.  A negative size is unlimited.
+func (c *Client) maxResponseSize() int64 {
+	switch {
+	case c.MaxResponseSize != 0:
+		return c.MaxResponseSize
+	case c.Config != nil && c.Config.MaxResponseSize != 0:
+		return c.Config.MaxResponseSize
+	case c.Config == nil:
+		if size, err := env.MaxResponseSizeOr(env.DefaultMaxResponseSize); err == nil {
+			return size
+		}
+	}
+	return env.DefaultMaxResponseSize
+}
+
+// readLimitedErr answers the body of the response from the url, or an error wrapping ErrResponseTooLarge if it exceeds
+// the limit (unless the limit is negative).  The body is read no further than the limit, so an oversized response does
+// not exhaust memory.
+func readLimitedErr(u string, r io.Reader, limit int64) ([]byte, error) {
+	if limit >= 0 {
+		r = io.LimitReader(r, limit+1)
+	}
+	body, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("jsonapi: error encountered reading response body from %s: %w", env.RedactUrl(u), err)
+	}
+	if limit >= 0 && int64(len(body)) > limit {
+		return nil, fmt.Errorf("%w: the response from %s exceeds %d bytes; narrow the query with filters, or page "+
+			"through its results (e.g. using GetAll with a page[limit]), or raise IDC_MAX_RESPONSE_SIZE", ErrResponseTooLarge,
+			env.RedactUrl(u), limit)
+	}
+	return body, nil
+}
+
+// readResponseErr behaves as readLimitedErr, limiting the body to the size from 'IDC_MAX_RESPONSE_SIZE' (or
+// env.DefaultMaxResponseSize), for the requests that are not sent by a Client, e.g. by GetResourceErr
+func readResponseErr(u string, r io.Reader) ([]byte, error) {
+	limit, err := env.MaxResponseSizeOr(env.DefaultMaxResponseSize)
+	if err != nil {
+		return nil, fmt.Errorf("jsonapi: unable to read the response body from %s: %w", env.RedactUrl(u), err)
+	}
+	return readLimitedErr(u, r, limit)
+}
+
+// logRequest records the status (or error) and duration of the request
+func logRequest(logger Logger, method, u string, res *http.Response, err error, elapsed time.Duration) {
+	if res == nil {
+		logger.Log("jsonapi request", "method", method, "url", env.RedactUrl(u), "error", err, "elapsed", elapsed)
+		return
+	}
+	logger.Log("jsonapi request", "method", method, "url", env.RedactUrl(u), "status", res.StatusCode, "elapsed", elapsed)
+}
+
+// client answers the HTTP client issuing requests: the client of the Config if present, bounded by the Timeout if set
+func (c *Client) client() *http.Client {
+	client := httpClient
+	if c.Config != nil {
+		client = c.Config.Client()
+	}
+	if c.Timeout > 0 {
+		withTimeout := *client
+		withTimeout.Timeout = c.Timeout
+		client = &withTimeout
+	}
+	return client
+}
+
+// basicAuth answers the username and password authenticating requests: none if Anonymous, otherwise from the
+// Credentials if present, or the Config if present
+func (c *Client) basicAuth() (string, string) {
+	switch {
+	case c.Anonymous:
+		return "", ""
+	case c.Credentials != nil:
+		return c.Credentials.Username, c.Credentials.Password
+	case c.Config != nil:
+		return c.Config.Credentials.Username, c.Config.Credentials.Password
+	}
+	return "", ""
+}
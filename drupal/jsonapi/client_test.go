@@ -0,0 +1,251 @@
+package jsonapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ClientGetSingle(t *testing.T) {
+	var user string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, _, _ = r.BasicAuth()
+		if r.URL.Query().Get("filter[id]") != "moo" {
+			_, _ = w.Write([]byte(`{"data": []}`))
+			return
+		}
+		_, _ = w.Write([]byte(stubResponse))
+	}))
+	defer server.Close()
+
+	c := NewClient(env.Config{BaseUrl: server.URL, Credentials: env.Credentials{Username: "admin", Password: "moo"}})
+	v := &struct{ Data []struct{ Id string } }{}
+	err := c.GetSingle(context.Background(), server.URL+"/jsonapi/media/document?filter[id]=moo", v)
+	require.Nil(t, err, "%s", err)
+	assert.Equal(t, "admin", user)
+	assert.Equal(t, "fd0b8969-ecc9-4a0d-81d3-537ba95bd5a8", v.Data[0].Id)
+
+	err = c.GetSingle(context.Background(), server.URL+"/jsonapi/media/document?filter[id]=oink", v)
+	assert.True(t, errors.Is(err, ErrNotFound), "expected ErrNotFound, got %v", err)
+
+	anonymous := &Client{}
+	require.Nil(t, anonymous.Get(context.Background(), server.URL+"/jsonapi/media/document?filter[id]=oink", v))
+	assert.Equal(t, "", user)
+}
+
+func Test_ClientGetSingleCanceled(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	c := NewClient(env.Config{BaseUrl: server.URL, Retries: 3, RetryBackoff: time.Millisecond})
+	err := c.GetSingle(ctx, server.URL+"/jsonapi/media/document?filter[id]=moo", &struct{}{})
+	assert.True(t, errors.Is(err, context.Canceled), "expected context.Canceled, got %v", err)
+	assert.Equal(t, 0, requests, "no request is expected to be made once the context is canceled")
+
+	u := &JsonApiUrl{T: t, BaseUrl: server.URL, DrupalEntity: "media", DrupalBundle: "document", Filter: "id",
+		Value: "moo", ExplicitBaseUrl: true, Context: ctx}
+	assert.Contains(t, fmt.Sprint(u.GetSingleErr(&struct{}{})), "context canceled")
+}
+
+func Test_ClientMaxResponseSize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(stubResponse))
+	}))
+	defer server.Close()
+
+	u := server.URL + "/jsonapi/media/document?filter[id]=moo"
+	c := NewClient(env.Config{BaseUrl: server.URL, MaxResponseSize: int64(len(stubResponse)) - 1})
+	err := c.GetSingle(context.Background(), u, &struct{}{})
+	assert.True(t, errors.Is(err, ErrResponseTooLarge), "expected ErrResponseTooLarge, got %v", err)
+	assert.Contains(t, fmt.Sprint(err), u)
+	assert.Contains(t, fmt.Sprint(err), "page")
+
+	c.MaxResponseSize = int64(len(stubResponse))
+	assert.Nil(t, c.GetSingle(context.Background(), u, &struct{}{}))
+
+	c.MaxResponseSize = -1
+	assert.Nil(t, c.GetSingle(context.Background(), u, &struct{}{}))
+	assert.Equal(t, int64(env.DefaultMaxResponseSize), (&Client{}).maxResponseSize())
+}
+
+// The size cap from the environment applies to the requests of a url without a Config
+func Test_UnconfiguredMaxResponseSize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(stubResponse))
+	}))
+	defer server.Close()
+	t.Setenv("IDC_MAX_RESPONSE_SIZE", fmt.Sprint(len(stubResponse)-1))
+
+	rec := &recordingT{}
+	u := &JsonApiUrl{T: rec, BaseUrl: server.URL, DrupalEntity: "media", DrupalBundle: "document", Filter: "id",
+		Value: "moo", ExplicitBaseUrl: true}
+	u.GetSingle(&struct{}{})
+	require.Equal(t, 1, len(rec.failures))
+	assert.Contains(t, rec.failures[0], ErrResponseTooLarge.Error())
+
+	_, _, err := GetResourceErr(u.String(), "", "")
+	assert.True(t, errors.Is(err, ErrResponseTooLarge), "expected ErrResponseTooLarge, got %v", err)
+
+	t.Setenv("IDC_MAX_RESPONSE_SIZE", fmt.Sprint(len(stubResponse)))
+	_, _, err = GetResourceErr(u.String(), "", "")
+	assert.Nil(t, err, "%s", err)
+}
+
+// recordingT records the failures of assertions, so that a method making assertions can be tested to fail
+type recordingT struct {
+	failures []string
+}
+
+func (r *recordingT) Errorf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func Test_ClientGetByUuid(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/jsonapi/media/document/moo" || r.URL.RawQuery != "" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data": {"type": "media--document", "id": "moo"}}`))
+	}))
+	defer server.Close()
+
+	v := &struct{ Data []struct{ Id string } }{}
+	c := NewClient(env.Config{BaseUrl: server.URL})
+	err := c.GetByUuid(context.Background(), server.URL+"/", NewDrupalType("media", "document"), "moo", v)
+	require.Nil(t, err, "%s", err)
+	assert.Equal(t, "moo", v.Data[0].Id)
+
+	err = c.GetByUuid(context.Background(), server.URL, NewDrupalType("media", "document"), "oink", v)
+	assert.True(t, errors.Is(err, ErrNotFound), "expected ErrNotFound, got %v", err)
+
+	v = &struct{ Data []struct{ Id string } }{}
+	u := &JsonApiUrl{T: t, BaseUrl: server.URL, DrupalEntity: "media", DrupalBundle: "document", Filter: "id",
+		Value: "oink", ExplicitBaseUrl: true}
+	u.GetByUuid("moo", v)
+	assert.Equal(t, "moo", v.Data[0].Id)
+	assert.Equal(t, server.URL+"/jsonapi/media/document/moo", (&JsonApiUrl{T: t, BaseUrl: server.URL,
+		DrupalEntity: "media", DrupalBundle: "document", Uuid: "moo", ExplicitBaseUrl: true}).String())
+}
+
+func Test_ClientEachPage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page[offset]") == "" {
+			_, _ = fmt.Fprintf(w, `{"data": [{"id": "1", "attributes": {"name": "moo"}}, {"id": "2"}],
+				"links": {"next": {"href": "http://%s%s?page[offset]=2"}}}`, r.Host, r.URL.Path)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data": [{"id": "3"}]}`))
+	}))
+	defer server.Close()
+
+	v := &struct {
+		Data []struct {
+			Id         string
+			Attributes struct{ Name string }
+		}
+	}{}
+	var pages [][]string
+	c := NewClient(env.Config{BaseUrl: server.URL})
+	err := c.EachPage(context.Background(), server.URL+"/jsonapi/taxonomy_term/subject", v, func() error {
+		var page []string
+		for _, d := range v.Data {
+			page = append(page, d.Id+d.Attributes.Name)
+		}
+		pages = append(pages, page)
+		return nil
+	})
+	require.Nil(t, err, "%s", err)
+	assert.Equal(t, [][]string{{"1moo", "2"}, {"3"}}, pages, "each page is expected to be decoded afresh")
+
+	stop := errors.New("stop")
+	err = c.EachPage(context.Background(), server.URL+"/jsonapi/taxonomy_term/subject", v, func() error { return stop })
+	assert.True(t, errors.Is(err, stop), "expected the error of f, got %v", err)
+}
+
+func Test_RetryHonorsContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	c := NewClient(env.Config{BaseUrl: server.URL, Retries: 1, RetryBackoff: time.Hour})
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := c.Do(ctx, http.MethodGet, server.URL, nil, nil)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "expected context.DeadlineExceeded, got %v", err)
+
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err = c.GetSingle(ctx, server.URL, &struct{}{})
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "expected context.DeadlineExceeded, got %v", err)
+	assert.Less(t, time.Since(start), time.Minute, "the backoff is expected to end when the context is done")
+}
+
+func Test_ClientDo(t *testing.T) {
+	var requests []*http.Request
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests, bodies = append(requests, r), append(bodies, string(body))
+		switch r.URL.Path {
+		case "/flaky":
+			if len(requests) == 1 {
+				w.WriteHeader(http.StatusBadGateway)
+			}
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error": "no such migration"}`))
+		}
+		_, _ = w.Write([]byte("moo"))
+	}))
+	defer server.Close()
+
+	c := NewClient(env.Config{BaseUrl: server.URL, Credentials: env.Credentials{Username: "admin", Password: "moo"},
+		Retries: 2, RetryBackoff: time.Millisecond})
+	res, err := c.Do(context.Background(), http.MethodGet, server.URL+"/flaky", nil,
+		http.Header{"Accept": {"text/plain"}})
+	require.Nil(t, err, "%s", err)
+	assert.Equal(t, http.StatusOK, res.Status)
+	assert.Equal(t, "moo", string(res.Body))
+	require.Equal(t, 2, len(requests), "a GET is expected to be retried after a 5xx status")
+	username, _, ok := requests[1].BasicAuth()
+	assert.True(t, ok && username == "admin", "the request is expected to carry the credentials of the Config")
+	assert.Equal(t, "text/plain", requests[1].Header.Get("Accept"))
+	assert.Equal(t, TestRunId(), requests[1].Header.Get(TestRunHeader))
+	assert.NotEmpty(t, requests[1].Header.Get("User-Agent"))
+
+	requests = nil
+	res, err = c.Do(context.Background(), http.MethodPost, server.URL+"/flaky", []byte("query=moo"), nil)
+	var httpErr *HTTPError
+	assert.True(t, errors.As(err, &httpErr), "expected an HTTPError, got %v", err)
+	assert.Equal(t, http.StatusBadGateway, res.Status)
+	assert.Equal(t, 1, len(requests), "a POST is not expected to be retried")
+	assert.Equal(t, []string{"query=moo"}, bodies[len(bodies)-1:])
+
+	res, err = (&Client{Anonymous: true}).Do(context.Background(), http.MethodPost, server.URL+"/missing", nil, nil)
+	assert.True(t, errors.Is(err, ErrNotFound), "expected ErrNotFound, got %v", err)
+	assert.Contains(t, string(res.Body), "no such migration")
+	_, _, ok = requests[len(requests)-1].BasicAuth()
+	assert.False(t, ok, "an Anonymous request is not expected to carry credentials")
+
+	c.MaxResponseSize = 2
+	_, err = c.Do(context.Background(), http.MethodGet, server.URL+"/", nil, nil)
+	assert.True(t, errors.Is(err, ErrResponseTooLarge), "expected ErrResponseTooLarge, got %v", err)
+}
@@ -0,0 +1,63 @@
+package jsonapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+)
+
+// Answered (wrapped) by a conditional request when the resource has not changed since the validators were issued
+var ErrNotModified = errors.New("jsonapi: resource not modified")
+
+// The validators of a response, used to issue a conditional request for the same url, e.g. when polling it.  Drupal
+// issues validators for cacheable responses; a zero Validators issues an unconditional request.
+type Validators struct {
+	// The value of the ETag response header
+	ETag string
+	// The value of the Last-Modified response header
+	LastModified string
+}
+
+// IsZero answers whether the response carried no validators
+func (vs Validators) IsZero() bool {
+	return vs.ETag == "" && vs.LastModified == ""
+}
+
+// GetSingleIfChangedErr behaves as GetSingleErr, but issues a conditional request carrying the validators of a
+// previous response from the url.  If Drupal answers that the response is unchanged, v is left untouched and the error
+// wraps ErrNotModified, so that a poll may cheaply skip unmarshaling a response it has already seen.  Answers the
+// validators of the response, to be supplied to the next request, even if the response carries no data element.
+func (jar *JsonApiUrl) GetSingleIfChangedErr(previous Validators, v interface{}) (Validators, error) {
+	u, err := jar.url()
+	if err != nil {
+		return previous, err
+	}
+
+	header := http.Header{}
+	if previous.ETag != "" {
+		header.Set("If-None-Match", previous.ETag)
+	}
+	if previous.LastModified != "" {
+		header.Set("If-Modified-Since", previous.LastModified)
+	}
+
+	body, resHeader, err := jar.fetchWithHeaderErr(u, header)
+	if err != nil {
+		return previous, err
+	}
+	validators := Validators{ETag: resHeader.Get("ETag"), LastModified: resHeader.Get("Last-Modified")}
+
+	value := &JsonApiResponse{}
+	if err := decodeErr(u, body, value); err != nil {
+		return validators, err
+	}
+	if len(value.Data) == 0 {
+		return validators, fmt.Errorf("%w: no JSONAPI data elements in the response from %s", ErrNotFound, env.RedactUrl(u))
+	}
+	if len(value.Data) != 1 {
+		return validators, fmt.Errorf("jsonapi: exactly one JSONAPI data element is expected in the response from %s, but found %d element(s)", env.RedactUrl(u), len(value.Data))
+	}
+	return validators, value.toErr(v)
+}
@@ -0,0 +1,39 @@
+package jsonapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GetSingleIfChangedErr(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"1"`)
+		w.Write([]byte(`{"data": [{"type": "node--islandora_object", "id": "1"}]}`))
+	}))
+	defer server.Close()
+
+	u := &JsonApiUrl{T: t, BaseUrl: server.URL, ExplicitBaseUrl: true, DrupalEntity: "node",
+		DrupalBundle: "islandora_object", Filter: "id", Value: "1"}
+	v := &struct{ Data []struct{ Id string } }{}
+	validators, err := u.GetSingleIfChangedErr(Validators{}, v)
+	assert.Nil(t, err, "%s", err)
+	assert.Equal(t, Validators{ETag: `"1"`}, validators)
+	assert.Equal(t, "1", v.Data[0].Id)
+
+	unchanged := &struct{ Data []struct{ Id string } }{}
+	next, err := u.GetSingleIfChangedErr(validators, unchanged)
+	assert.True(t, errors.Is(err, ErrNotModified), "expected ErrNotModified, got %v", err)
+	assert.Equal(t, validators, next)
+	assert.Empty(t, unchanged.Data)
+	assert.Equal(t, 2, requests)
+}
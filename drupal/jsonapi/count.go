@@ -0,0 +1,67 @@
+package jsonapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+)
+
+// CountErr answers the number of resources the url answers, as the user authenticated according to basicAuth (see
+// Client.Count)
+func (jar *JsonApiUrl) CountErr() (int, error) {
+	u, err := jar.url()
+	if err != nil {
+		return 0, err
+	}
+	c, err := jar.apiClient()
+	if err != nil {
+		return 0, err
+	}
+	return c.Count(jar.context(), u)
+}
+
+// Count answers the number of resources answered by the url.  If the response carries the total count of its results
+// in `meta.count` (as added by the JSON:API Extras module when its "include count" setting is enabled), only the first
+// page is retrieved; otherwise the `data` elements of every page are counted.
+func (c *Client) Count(ctx context.Context, u string) (int, error) {
+	first, _, err := c.fetchErr(ctx, u, nil)
+	if err != nil {
+		return 0, err
+	}
+	doc := struct {
+		Meta struct {
+			Count json.RawMessage `json:"count"`
+		} `json:"meta"`
+	}{}
+	if err := decodeErr(u, first, &doc); err != nil {
+		return 0, err
+	}
+	if len(doc.Meta.Count) > 0 && string(doc.Meta.Count) != "null" {
+		// the count is a string in some versions of JSON:API Extras
+		n, err := strconv.Atoi(strings.Trim(string(doc.Meta.Count), `"`))
+		if err != nil {
+			return 0, fmt.Errorf("jsonapi: unable to parse meta.count %s of the response from %s: %w", doc.Meta.Count,
+				env.RedactUrl(u), err)
+		}
+		return n, nil
+	}
+
+	count := 0
+	err = eachPage(u, func(next string) ([]byte, error) {
+		if next == u && first != nil {
+			body := first
+			first = nil
+			return body, nil
+		}
+		body, _, err := c.fetchErr(ctx, next, nil)
+		return body, err
+	}, func(page *JsonApiResponse) error {
+		count += len(page.Data)
+		return nil
+	})
+	return count, err
+}
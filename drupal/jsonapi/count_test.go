@@ -0,0 +1,48 @@
+package jsonapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CountErr(t *testing.T) {
+	pages := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pages++
+		switch {
+		case r.URL.Path == "/jsonapi/taxonomy_term/language":
+			w.Write([]byte(`{"data": [{"type": "taxonomy_term--language", "id": "1"}], "meta": {"count": "486"}}`))
+		case r.URL.Path == "/jsonapi/node/collection_object" && r.URL.Query().Get("page[offset]") == "":
+			w.Write([]byte(`{"data": [{"type": "node--collection_object", "id": "1"}, ` +
+				`{"type": "node--collection_object", "id": "2"}], ` +
+				`"links": {"next": {"href": "` + "http://" + r.Host + r.URL.Path + `?page[offset]=2"}}}`))
+		case r.URL.Path == "/jsonapi/node/collection_object":
+			w.Write([]byte(`{"data": [{"type": "node--collection_object", "id": "3"}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	u := &JsonApiUrl{T: t, BaseUrl: server.URL, ExplicitBaseUrl: true, DrupalEntity: "taxonomy_term",
+		DrupalBundle: "language", Anonymous: true}
+	count, err := u.CountErr()
+	assert.Nil(t, err, "%s", err)
+	assert.Equal(t, 486, count)
+	assert.Equal(t, 1, pages)
+
+	pages = 0
+	u.DrupalEntity, u.DrupalBundle = "node", "collection_object"
+	count, err = u.CountErr()
+	assert.Nil(t, err, "%s", err)
+	assert.Equal(t, 3, count)
+	assert.Equal(t, 2, pages)
+
+	u.DrupalBundle = "islandora_object"
+	_, err = u.CountErr()
+	assert.True(t, errors.Is(err, ErrNotFound), "expected ErrNotFound, got %v", err)
+}
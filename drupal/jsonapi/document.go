@@ -0,0 +1,112 @@
+package jsonapi
+
+import (
+	"encoding/json"
+)
+
+// The type of the Language taxonomy terms referenced by language-tagged values, e.g. `field_alternative_title`
+const languageTermType DrupalType = "taxonomy_term--language"
+
+// A value in the language identified by the UUID of its Language taxonomy term, e.g. the Spanish alternative title of a
+// repository object.  The UUID of the term of a language code may be resolved by model.LanguageTermValues.
+type LanguageValue struct {
+	Value        string
+	LangTermUuid string
+}
+
+// Identifier answers the reference to the Language taxonomy term of the value, carrying the value as `meta.value`
+func (lv LanguageValue) Identifier() ResourceIdentifier {
+	return ResourceIdentifier{Type: languageTermType, Id: lv.LangTermUuid, Meta: map[string]string{"value": lv.Value}}
+}
+
+// DocumentBuilder builds the request document of a resource to be created or updated, i.e.
+// `{"data": {"type": ..., "attributes": ..., "relationships": ...}}`, so that documents need not be composed by hand.
+// Each Set method answers the builder, so that calls may be chained:
+//
+//	doc := jsonapi.NewDocumentBuilder(jsonapi.NewDrupalType("node", "islandora_object")).
+//		SetAttribute("title", "Moonrise Over Hernandez").
+//		SetRelationship("field_member_of", collection).
+//		SetLanguageValue("field_alternative_title", "Salida de la luna sobre Hernández", spanishUuid)
+//	err := u.CreateErr(doc.Resource(), &obj)
+//
+// The builder is not safe for concurrent use.
+type DocumentBuilder struct {
+	resource Resource
+}
+
+// NewDocumentBuilder answers a builder of the document of a resource of the type, carrying no attributes or
+// relationships
+func NewDocumentBuilder(t DrupalType) *DocumentBuilder {
+	return &DocumentBuilder{resource: Resource{Type: t}}
+}
+
+// SetId sets the id of the resource, which is only carried when updating it
+func (b *DocumentBuilder) SetId(id string) *DocumentBuilder {
+	b.resource.Id = id
+	return b
+}
+
+// SetAttribute sets the value of the attribute, e.g. `title`, replacing any value previously set.  The value is
+// marshaled as-is, so that structured values may be supplied, e.g. `map[string]string{"value": ..., "format": ...}`
+// for formatted text.
+func (b *DocumentBuilder) SetAttribute(field string, value interface{}) *DocumentBuilder {
+	if b.resource.Attributes == nil {
+		b.resource.Attributes = map[string]interface{}{}
+	}
+	b.resource.Attributes[field] = value
+	return b
+}
+
+// SetRelationship sets the relationship to each of the identified resources, in order, replacing any relationship
+// previously set.  The relationship is written as an array, as Drupal expects of a multi-valued field; the meta of each
+// identifier (e.g. the `rel_type` of a typed relation) is written with it.  Supplying no identifiers empties the
+// relationship.
+func (b *DocumentBuilder) SetRelationship(field string, ids ...ResourceIdentifier) *DocumentBuilder {
+	b.relationships()[field] = ToMany(ids...)
+	return b
+}
+
+// SetToOneRelationship sets the relationship to the single identified resource, replacing any relationship previously
+// set, as Drupal expects of a single-valued field, e.g. `field_media_of`.  A nil identifier empties the relationship.
+func (b *DocumentBuilder) SetToOneRelationship(field string, id *ResourceIdentifier) *DocumentBuilder {
+	b.relationships()[field] = ToOne(id)
+	return b
+}
+
+// SetLanguageValue appends the value, in the language identified by the UUID of its Language taxonomy term, to the
+// language-tagged relationship, e.g. `field_alternative_title`.  Each value is written as a reference to its language,
+// carrying the value as `meta.value`, as Drupal answers them (see model.JsonApiLanguageValue).  Values are appended to
+// those previously set for the field, in order; a single-valued relationship previously set for the field is replaced.
+func (b *DocumentBuilder) SetLanguageValue(field, value, langTermUuid string) *DocumentBuilder {
+	id := LanguageValue{Value: value, LangTermUuid: langTermUuid}.Identifier()
+	rel, ok := b.relationships()[field].(map[string]interface{})
+	if values, isValues := rel["data"].([]ResourceIdentifier); ok && isValues {
+		return b.SetRelationship(field, append(append([]ResourceIdentifier{}, values...), id)...)
+	}
+	return b.SetRelationship(field, id)
+}
+
+// SetLanguageValues sets the language-tagged relationship to the values, in order, replacing any values previously set
+// for the field (see SetLanguageValue).  Supplying no values empties the relationship.
+func (b *DocumentBuilder) SetLanguageValues(field string, values ...LanguageValue) *DocumentBuilder {
+	b.relationships()[field] = ToLanguageValues(values...)
+	return b
+}
+
+// Resource answers the resource built, e.g. to be supplied to CreateErr or UpdateErr
+func (b *DocumentBuilder) Resource() Resource {
+	return b.resource
+}
+
+// MarshalJSON answers the request document of the resource built, i.e. `{"data": {...}}`
+func (b *DocumentBuilder) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{"data": b.resource})
+}
+
+// relationships answers the relationships of the resource, creating them if necessary
+func (b *DocumentBuilder) relationships() map[string]interface{} {
+	if b.resource.Relationships == nil {
+		b.resource.Relationships = map[string]interface{}{}
+	}
+	return b.resource.Relationships
+}
@@ -0,0 +1,79 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Compares the document built for a repository object to the request body of the same object, in the form accepted by
+// Drupal
+func Test_DocumentBuilder(t *testing.T) {
+	expected, err := os.ReadFile(filepath.Join("testdata", "create-islandora-object.json"))
+	require.Nil(t, err, "%s", err)
+
+	b := NewDocumentBuilder(NewDrupalType("node", "islandora_object")).
+		SetAttribute("title", "Moonrise Over Hernandez").
+		SetAttribute("status", true).
+		SetAttribute("field_description", []map[string]string{{
+			"value":  "A photograph of the moon rising over Hernandez, New Mexico",
+			"format": "basic_html",
+		}}).
+		SetToOneRelationship("field_member_of", &ResourceIdentifier{
+			Type: NewDrupalType("node", "collection_object"), Id: "0f8a3b0e-3bb4-4bd5-a2d4-05e1f2c8d6c1"}).
+		SetLanguageValue("field_alternative_title", "Moonrise Over Hernandez, New Mexico",
+			"7397e0c4-df0a-4800-95af-afccc6ff64a5").
+		SetLanguageValue("field_alternative_title", "Salida de la luna sobre Hernández",
+			"bacfc5b6-b4b9-4239-8744-46dca6a91f0e").
+		SetRelationship("field_linked_agent", ResourceIdentifier{Type: NewDrupalType("taxonomy_term", "person"),
+			Id: "2b3f4e6a-8c1d-4f7e-9a2b-3c4d5e6f7a8b", Meta: map[string]string{"rel_type": "relators:pht"}}).
+		SetRelationship("field_subject")
+
+	actual, err := json.Marshal(b)
+	require.Nil(t, err, "%s", err)
+	assert.JSONEq(t, string(expected), string(actual))
+
+	// the resource built is the data of the document
+	doc := struct{ Data Resource }{}
+	require.Nil(t, json.Unmarshal(expected, &doc))
+	resource, err := json.Marshal(b.Resource())
+	require.Nil(t, err, "%s", err)
+	data, err := json.Marshal(doc.Data)
+	require.Nil(t, err, "%s", err)
+	assert.JSONEq(t, string(data), string(resource))
+}
+
+func Test_DocumentBuilderUpdate(t *testing.T) {
+	b := NewDocumentBuilder(NewDrupalType("media", "image")).
+		SetId("moo").
+		SetToOneRelationship("field_media_of", nil).
+		SetLanguageValue("field_alternative_title", "Moo", "oink").
+		SetRelationship("field_alternative_title")
+
+	actual, err := json.Marshal(b)
+	require.Nil(t, err, "%s", err)
+	assert.JSONEq(t, `{"data": {"type": "media--image", "id": "moo", "relationships": {
+		"field_media_of": {"data": null}, "field_alternative_title": {"data": []}}}}`, string(actual),
+		"a relationship set again is expected to replace the values previously set")
+}
+
+func Test_DocumentBuilderLanguageValues(t *testing.T) {
+	b := NewDocumentBuilder(NewDrupalType("node", "islandora_object")).
+		SetLanguageValue("field_alternative_title", "Moo", "oink").
+		SetLanguageValues("field_alternative_title",
+			LanguageValue{Value: "Moonrise Over Hernandez", LangTermUuid: "7397e0c4-df0a-4800-95af-afccc6ff64a5"},
+			LanguageValue{Value: "Salida de la luna sobre Hernández", LangTermUuid: "bacfc5b6-b4b9-4239-8744-46dca6a91f0e"})
+
+	actual, err := json.Marshal(b)
+	require.Nil(t, err, "%s", err)
+	assert.JSONEq(t, `{"data": {"type": "node--islandora_object", "relationships": {"field_alternative_title": {"data": [
+		{"type": "taxonomy_term--language", "id": "7397e0c4-df0a-4800-95af-afccc6ff64a5",
+			"meta": {"value": "Moonrise Over Hernandez"}},
+		{"type": "taxonomy_term--language", "id": "bacfc5b6-b4b9-4239-8744-46dca6a91f0e",
+			"meta": {"value": "Salida de la luna sobre Hernández"}}]}}}}`, string(actual),
+		"the values are expected to replace the values previously set")
+}
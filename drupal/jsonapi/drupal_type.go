@@ -0,0 +1,102 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Answered (wrapped) when a Drupal type is malformed, e.g. `media--` or `media-image`
+var ErrMalformedType = errors.New("jsonapi: malformed Drupal type")
+
+// A Drupal type: a machine name, optionally followed by `--` and another machine name
+var drupalTypePattern = regexp.MustCompile(`^[a-z0-9_]+(--[a-z0-9_]+)?$`)
+
+// Encapsulates the Entity type and bundle of a Drupal resource.
+//
+// DrupalType is parsed from the JSONAPI response, where type is represented, e.g. as:
+//
+//	"type": "taxonomy_term--person"
+//
+// The type is validated as it is unmarshaled, so that a malformed type fails the decoding of the response that carries
+// it, rather than surfacing later from Entity or Bundle.  Entities without bundles are typed by Drupal with the entity
+// as the bundle, e.g. `user--user`; a type of a single segment, e.g. `file`, is also accepted, and answers the segment
+// as both its entity and bundle.
+type DrupalType string
+
+// NewDrupalType answers the DrupalType for the supplied entity and bundle, e.g. `node--islandora_object`
+func NewDrupalType(entity, bundle string) DrupalType {
+	return DrupalType(entity + "--" + bundle)
+}
+
+// ParseDrupalType answers the DrupalType of its JSON API form, e.g. `node--islandora_object` or `file`, or an error
+// wrapping ErrMalformedType that carries the offending string
+func ParseDrupalType(s string) (DrupalType, error) {
+	t := DrupalType(s)
+	if err := t.Validate(); err != nil {
+		return "", err
+	}
+	return t, nil
+}
+
+// Validate answers an error wrapping ErrMalformedType if the type is not a machine name, optionally followed by `--`
+// and another machine name
+func (t DrupalType) Validate() error {
+	if !drupalTypePattern.MatchString(string(t)) {
+		return fmt.Errorf("%w: '%s' is not of the form `entity--bundle` or `entity`", ErrMalformedType, string(t))
+	}
+	return nil
+}
+
+// Is answers true if this type encapsulates the supplied entity and bundle
+func (t DrupalType) Is(entity, bundle string) bool {
+	return t.Entity() == entity && t.Bundle() == bundle
+}
+
+// Marshals the type in its JSON API form, e.g. "node--islandora_object".  An empty type is marshaled as an empty
+// string; any other type must be well-formed (see Validate).
+func (t DrupalType) MarshalJSON() ([]byte, error) {
+	if t != "" {
+		if err := t.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(string(t))
+}
+
+// Unmarshals the type from its JSON API form, e.g. "node--islandora_object".  An empty type (or null) is unmarshaled
+// as the empty type; any other type must be well-formed (see Validate).
+func (t *DrupalType) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("jsonapi: unable to unmarshal Drupal type from %s: %w", string(b), err)
+	}
+	if s == "" {
+		*t = ""
+		return nil
+	}
+	parsed, err := ParseDrupalType(s)
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
+
+// The entity (e.g. taxonomy_term, node, etc) encapsulated by this type
+func (t DrupalType) Entity() string {
+	entity, _, _ := strings.Cut(string(t), "--")
+	return entity
+}
+
+// The bundle (e.g. 'person', 'islandora_object', etc) encapsulated by this type.  The bundle of a type of a single
+// segment, e.g. `file`, is the segment itself.
+func (t DrupalType) Bundle() string {
+	entity, bundle, ok := strings.Cut(string(t), "--")
+	if !ok {
+		return entity
+	}
+	return bundle
+}
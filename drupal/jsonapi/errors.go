@@ -0,0 +1,163 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+)
+
+// Answered (wrapped) when Drupal refuses a request with a 401 status, because it carries no credentials, or credentials
+// Drupal does not accept.  Such an error also wraps ErrForbidden, so that callers need not distinguish the statuses.
+var ErrUnauthorized = errors.New("jsonapi: unauthorized")
+
+// Answered (wrapped) when the body of a JSON API response exceeds the maximum response size (see
+// env.Config.MaxResponseSize), e.g. because a query lacks its filters
+var ErrResponseTooLarge = errors.New("jsonapi: response too large")
+
+const (
+	// The number of bytes of a response body retained by an HTTPError
+	maxErrorBody = 4096
+	// The number of bytes either side of the offset of a decoding failure retained by a DecodeError
+	snippetRadius = 40
+)
+
+// HTTPError is answered (wrapped) when a response carries a status other than the expected status.  Branch on the kind
+// of failure using errors.Is with ErrNotFound (a 404 status), ErrForbidden (a 401 or 403 status), ErrUnauthorized (a
+// 401 status), or ErrNotModified (a 304 status), or obtain the status and body using errors.As:
+//
+//	var httpErr *jsonapi.HTTPError
+//	if errors.As(err, &httpErr) && httpErr.Status >= 500 { ... }
+//
+// Failures to send a request, or to receive its response (e.g. a refused connection or a timeout), are not HTTPErrors:
+// they wrap the *url.Error answered by net/http.
+type HTTPError struct {
+	// The method of the request, e.g. `GET`
+	Method string
+	// The url of the request, without its userinfo (see env.RedactUrl)
+	Url string
+	// The status of the response, e.g. 404
+	Status int
+	// The body of the response, truncated to its first 4096 bytes
+	Body string
+	// The errors reported by Drupal in the body, formatted to be appended to the message, if any
+	detail string
+}
+
+// newHTTPError answers the HTTPError of the response to the request
+func newHTTPError(method, u string, status int, body []byte) *HTTPError {
+	if len(body) > maxErrorBody {
+		body = body[:maxErrorBody]
+	}
+	return &HTTPError{Method: method, Url: env.RedactUrl(u), Status: status, Body: string(body),
+		detail: responseErrors(body)}
+}
+
+// Error answers the status of the response and the url of the request, e.g.
+// `jsonapi: resource not found: 404 status encountered when requesting https://...`
+func (e *HTTPError) Error() string {
+	prefix := "jsonapi"
+	if sentinel := e.sentinel(); sentinel != nil {
+		prefix = sentinel.Error()
+	}
+	if e.Method == "" || e.Method == http.MethodGet {
+		return fmt.Sprintf("%s: %d status encountered when requesting %s%s", prefix, e.Status, e.Url, e.detail)
+	}
+	return fmt.Sprintf("%s: %d status encountered sending %s %s%s", prefix, e.Status, e.Method, e.Url, e.detail)
+}
+
+// Is answers whether the status of the response is described by the target, e.g. ErrNotFound for a 404 status
+func (e *HTTPError) Is(target error) bool {
+	if target == ErrUnauthorized {
+		return e.Status == http.StatusUnauthorized
+	}
+	return target != nil && target == e.sentinel()
+}
+
+// sentinel answers the error describing the status of the response, or nil if there is none
+func (e *HTTPError) sentinel() error {
+	switch e.Status {
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrForbidden
+	case http.StatusNotModified:
+		return ErrNotModified
+	}
+	return nil
+}
+
+// DecodeError is answered (wrapped) when a response body cannot be unmarshaled, e.g. because Drupal answered an HTML
+// error page, or a field has an unexpected type.  It carries the part of the body at which decoding failed, and wraps
+// the error answered by encoding/json.
+type DecodeError struct {
+	// The url of the response, if known, without its userinfo (see env.RedactUrl)
+	Url string
+	// The Go type the response was unmarshaled into, if known, e.g. `*model.JsonApiIslandoraObj`
+	Type string
+	// The offset in the body at which decoding failed, or -1 if it is not known
+	Offset int64
+	// The bytes of the body surrounding the offset, or its first bytes if the offset is not known
+	Snippet string
+	Err     error
+}
+
+// newDecodeError answers the DecodeError of the failure to unmarshal the body from the url
+func newDecodeError(u string, body []byte, err error) *DecodeError {
+	e := &DecodeError{Url: env.RedactUrl(u), Offset: -1, Err: err}
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		e.Offset = syntaxErr.Offset
+	case errors.As(err, &typeErr):
+		e.Offset = typeErr.Offset
+	}
+
+	start, end := int64(0), int64(2*snippetRadius)
+	if e.Offset >= 0 {
+		start, end = e.Offset-snippetRadius, e.Offset+snippetRadius
+	}
+	if start < 0 {
+		start = 0
+	}
+	if end > int64(len(body)) {
+		end = int64(len(body))
+	}
+	if start < end {
+		e.Snippet = string(body[start:end])
+	}
+	return e
+}
+
+// decodeErr unmarshals the body from the url into v, answering a DecodeError if it cannot be unmarshaled
+func decodeErr(u string, body []byte, v interface{}) error {
+	if err := json.Unmarshal(body, v); err != nil {
+		return newDecodeError(u, body, err)
+	}
+	return nil
+}
+
+// Error answers the url or type of the response, the error encountered, and the snippet of the body, e.g.
+// `jsonapi: error unmarshaling JSONAPI response body from https://...: invalid character '<' looking for beginning of
+// value (at offset 1: "<html>...")`
+func (e *DecodeError) Error() string {
+	s := fmt.Sprintf("jsonapi: error unmarshaling JSONAPI response body from %s: %s", e.Url, e.Err)
+	if e.Url == "" && e.Type != "" {
+		s = fmt.Sprintf("jsonapi: unable to unmarshal JSONAPI response to %s: %s", e.Type, e.Err)
+	}
+	if e.Snippet == "" {
+		return s
+	}
+	if e.Offset < 0 {
+		return fmt.Sprintf("%s (beginning %q)", s, e.Snippet)
+	}
+	return fmt.Sprintf("%s (at offset %d: %q)", s, e.Offset, e.Snippet)
+}
+
+// Unwrap answers the error answered by encoding/json
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
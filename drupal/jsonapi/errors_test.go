@@ -0,0 +1,133 @@
+package jsonapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_HTTPErrorBranches(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		case "/unauthorized":
+			w.WriteHeader(http.StatusUnauthorized)
+		case "/forbidden":
+			w.WriteHeader(http.StatusForbidden)
+		case "/broken":
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte("The website encountered an unexpected error."))
+		case "/html":
+			_, _ = w.Write([]byte("<html><body>Maintenance</body></html>"))
+		case "/mistyped":
+			_, _ = w.Write([]byte(`{"data": [{"type": "node--islandora_object", "id": 12}]}`))
+		}
+	}))
+	defer server.Close()
+	get := func(path string) error {
+		return (&Client{}).GetSingle(context.Background(), server.URL+path, &struct{ Data []struct{ Id string } }{})
+	}
+
+	err := get("/missing")
+	assert.True(t, errors.Is(err, ErrNotFound), "%s", err)
+	assert.False(t, errors.Is(err, ErrForbidden), "%s", err)
+	assert.Contains(t, fmt.Sprint(err), "jsonapi: resource not found: 404 status encountered when requesting")
+
+	err = get("/unauthorized")
+	assert.True(t, errors.Is(err, ErrUnauthorized), "%s", err)
+	assert.True(t, errors.Is(err, ErrForbidden), "a 401 status is expected to wrap ErrForbidden: %s", err)
+
+	err = get("/forbidden")
+	assert.True(t, errors.Is(err, ErrForbidden), "%s", err)
+	assert.False(t, errors.Is(err, ErrUnauthorized), "%s", err)
+
+	err = get("/broken")
+	var httpErr *HTTPError
+	require.True(t, errors.As(err, &httpErr), "%s", err)
+	assert.Equal(t, http.StatusInternalServerError, httpErr.Status)
+	assert.Equal(t, http.MethodGet, httpErr.Method)
+	assert.Equal(t, server.URL+"/broken", httpErr.Url)
+	assert.Equal(t, "The website encountered an unexpected error.", httpErr.Body)
+	assert.False(t, errors.Is(err, ErrNotFound) || errors.Is(err, ErrForbidden), "%s", err)
+
+	err = get("/html")
+	var decodeErr *DecodeError
+	require.True(t, errors.As(err, &decodeErr), "%s", err)
+	assert.Equal(t, server.URL+"/html", decodeErr.Url)
+	assert.Equal(t, int64(1), decodeErr.Offset)
+	assert.Equal(t, "<html><body>Maintenance</body></html>", decodeErr.Snippet)
+	assert.Contains(t, fmt.Sprint(err), `(at offset 1: "<html>`)
+	assert.False(t, errors.As(err, &httpErr), "%s", err)
+
+	err = get("/mistyped")
+	require.True(t, errors.As(err, &decodeErr), "%s", err)
+	assert.Equal(t, "*struct { Data []struct { Id string } }", decodeErr.Type)
+	assert.Contains(t, fmt.Sprint(err), "unable to unmarshal JSONAPI response to *struct")
+	assert.True(t, strings.Contains(decodeErr.Snippet, "12"), "%s", decodeErr.Snippet)
+
+	unreachable := httptest.NewServer(http.NotFoundHandler())
+	unreachable.Close()
+	err = (&Client{}).GetSingle(context.Background(), unreachable.URL, &struct{}{})
+	var urlErr *url.Error
+	assert.True(t, errors.As(err, &urlErr), "a transport failure is expected to wrap *url.Error: %s", err)
+	assert.False(t, errors.As(err, &httpErr) || errors.As(err, &decodeErr), "%s", err)
+}
+
+func Test_HTTPErrorOfWrite(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"errors": [{"title": "Forbidden", "detail": "The current user is not allowed to POST."}]}`))
+	}))
+	defer server.Close()
+
+	u := &JsonApiUrl{T: t, BaseUrl: server.URL, ExplicitBaseUrl: true, DrupalEntity: "node",
+		DrupalBundle: "collection_object", Username: "moo", Password: "moo"}
+	err := u.CreateErr(Resource{}, &struct{}{})
+	var httpErr *HTTPError
+	require.True(t, errors.As(err, &httpErr), "%s", err)
+	assert.True(t, errors.Is(err, ErrForbidden), "%s", err)
+	assert.Equal(t, http.MethodPost, httpErr.Method)
+	assert.Equal(t, "jsonapi: access forbidden: 403 status encountered sending POST "+server.URL+
+		"/jsonapi/node/collection_object: Forbidden: The current user is not allowed to POST.", err.Error())
+}
+
+func Test_UnauthorizedRedactsCredentials(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"errors": [{"title": "Unauthorized", "detail": "bad credentials"}]}`))
+	}))
+	defer server.Close()
+	withUserinfo, err := url.Parse(server.URL)
+	require.Nil(t, err)
+	withUserinfo.User = url.UserPassword("admin", "s3cret")
+	v := &struct{ Data []struct{ Id string } }{}
+
+	c := &Client{Credentials: &env.Credentials{Username: "admin", Password: "s3cret"}}
+	err = c.GetSingle(context.Background(), withUserinfo.String()+"/jsonapi/node/islandora_object", v)
+	require.True(t, errors.Is(err, ErrUnauthorized), "%s", err)
+	assert.NotContains(t, fmt.Sprintf("%+v", err), "s3cret")
+
+	jar := JsonApiUrl{T: t, BaseUrl: withUserinfo.String(), ExplicitBaseUrl: true, DrupalEntity: "node",
+		DrupalBundle: "islandora_object", Username: "admin", Password: "s3cret"}
+	err = jar.CreateErr(Resource{Attributes: map[string]interface{}{"title": "Moo"}}, v)
+	require.True(t, errors.Is(err, ErrUnauthorized), "%s", err)
+	assert.NotContains(t, fmt.Sprintf("%+v", err), "s3cret")
+	var httpErr *HTTPError
+	require.True(t, errors.As(err, &httpErr), "%s", err)
+	assert.Equal(t, server.URL+"/jsonapi/node/islandora_object", httpErr.Url)
+
+	server.Close()
+	err = c.GetSingle(context.Background(), withUserinfo.String()+"/jsonapi/node/islandora_object", v)
+	require.NotNil(t, err)
+	assert.NotContains(t, fmt.Sprintf("%+v", err), "s3cret", "a failure to connect is expected to be redacted")
+}
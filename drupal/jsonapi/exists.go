@@ -0,0 +1,52 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// ExistsErr answers whether the url answers at least one resource, as the user authenticated according to basicAuth.
+// A 404 status, or a response without data elements, answers false without error: Drupal omits the resources a user
+// may not view from the results of a filtered query, so a resource hidden from the user is indistinguishable from a
+// missing one.  A 401 or 403 status answers an error wrapping ErrForbidden; any other failure, e.g. a network error,
+// answers an error.
+func (jar *JsonApiUrl) ExistsErr() (bool, error) {
+	u, err := jar.url()
+	if err != nil {
+		return false, err
+	}
+	return jar.existsErr(u)
+}
+
+// ResourceExistsErr behaves as ExistsErr, but requests the canonical url of the resource with the supplied id, e.g.
+// `/jsonapi/node/islandora_object/{id}`, ignoring the filter of the url.  Drupal answers a resource the user may not
+// view with a 403 status at its canonical url, rather than omitting it, so the error wraps ErrForbidden.
+func (jar *JsonApiUrl) ResourceExistsErr(id string) (bool, error) {
+	u, err := jar.resourceUrl(id)
+	if err != nil {
+		return false, err
+	}
+	return jar.existsErr(u)
+}
+
+// existsErr answers whether the response from the url carries at least one data element
+func (jar *JsonApiUrl) existsErr(u string) (bool, error) {
+	body, err := jar.fetchErr(u)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	doc := struct {
+		Data json.RawMessage
+	}{}
+	if err := decodeErr(u, body, &doc); err != nil {
+		return false, err
+	}
+	switch string(doc.Data) {
+	case "", "null", "[]":
+		return false, nil
+	}
+	return true, nil
+}
@@ -0,0 +1,49 @@
+package jsonapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ExistsErr(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, _, _ := r.BasicAuth()
+		switch {
+		case r.URL.Path == "/jsonapi/node/islandora_object/1" && username == "":
+			w.WriteHeader(http.StatusForbidden)
+		case r.URL.Path == "/jsonapi/node/islandora_object/1":
+			w.Write([]byte(`{"data": {"type": "node--islandora_object", "id": "1"}}`))
+		case r.URL.Path == "/jsonapi/node/islandora_object" && username != "":
+			w.Write([]byte(`{"data": [{"type": "node--islandora_object", "id": "1"}]}`))
+		case r.URL.Path == "/jsonapi/node/islandora_object":
+			w.Write([]byte(`{"data": []}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	u := &JsonApiUrl{T: t, BaseUrl: server.URL, ExplicitBaseUrl: true, DrupalEntity: "node",
+		DrupalBundle: "islandora_object", Filter: "id", Value: "1", Anonymous: true}
+	exists, err := u.ExistsErr()
+	assert.Nil(t, err, "%s", err)
+	assert.False(t, exists)
+	exists, err = u.ResourceExistsErr("1")
+	assert.True(t, errors.Is(err, ErrForbidden), "expected ErrForbidden, got %v", err)
+	assert.False(t, exists)
+	exists, err = u.ResourceExistsErr("2")
+	assert.Nil(t, err, "%s", err)
+	assert.False(t, exists)
+
+	u.Anonymous, u.Username, u.Password = false, "admin", "moo"
+	exists, err = u.ExistsErr()
+	assert.Nil(t, err, "%s", err)
+	assert.True(t, exists)
+	exists, err = u.ResourceExistsErr("1")
+	assert.Nil(t, err, "%s", err)
+	assert.True(t, exists)
+}
@@ -0,0 +1,49 @@
+package jsonapi
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// FilterCreatedBetween restricts the url to the resources created at or after from, and before to, e.g. the nodes
+// created by a migration run.  See FilterChangedSince.
+func (jar *JsonApiUrl) FilterCreatedBetween(from, to time.Time) *JsonApiUrl {
+	jar.addCondition("created-from", "created", ">=", timestampValue(from))
+	jar.addCondition("created-to", "created", "<", timestampValue(to))
+	return jar
+}
+
+// FilterChangedSince restricts the url to the resources changed at or after since, e.g. the nodes updated by a
+// migration run.  Each condition is added to the RawFilter as a named condition, so conditions combine with each other
+// and with a Filter and Value already supplied (which are carried into the RawFilter):
+//
+//	u := model.Query(t, model.Node, model.RepositoryObject)
+//	u.FilterChangedSince(start).FilterCreatedBetween(start, end)
+//
+// The `created` and `changed` fields of Drupal entities are timestamps, which JSON:API filters compare as Unix times
+// (in seconds), although it renders them as ISO 8601 dates.
+func (jar *JsonApiUrl) FilterChangedSince(since time.Time) *JsonApiUrl {
+	jar.addCondition("changed-since", "changed", ">=", timestampValue(since))
+	return jar
+}
+
+// addCondition adds the named condition, comparing the field at the path to the value using the operator, to the
+// RawFilter.  A Filter and Value not yet carried into the RawFilter are added first.
+func (jar *JsonApiUrl) addCondition(name, path, operator, value string) {
+	if jar.RawFilter == "" && jar.Filter != "" {
+		jar.RawFilter = fmt.Sprintf("filter[%s]=%s", jar.Filter, url.QueryEscape(jar.Value))
+	}
+	condition := fmt.Sprintf("filter[%[1]s][condition][path]=%[2]s&filter[%[1]s][condition][operator]=%[3]s&"+
+		"filter[%[1]s][condition][value]=%[4]s", name, path, url.QueryEscape(operator), url.QueryEscape(value))
+	if jar.RawFilter != "" {
+		jar.RawFilter += "&"
+	}
+	jar.RawFilter += condition
+}
+
+// timestampValue answers the time as the value of a timestamp field, i.e. as Unix time
+func timestampValue(t time.Time) string {
+	return strconv.FormatInt(t.Unix(), 10)
+}
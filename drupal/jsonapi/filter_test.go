@@ -0,0 +1,31 @@
+package jsonapi
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_FilterCreatedBetweenAndChangedSince(t *testing.T) {
+	from := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+	u := &JsonApiUrl{T: t, BaseUrl: "http://localhost", ExplicitBaseUrl: true, DrupalEntity: "node",
+		DrupalBundle: "islandora_object", Filter: "field_member_of.id", Value: "1"}
+	u.FilterCreatedBetween(from, to).FilterChangedSince(from)
+
+	parsed, err := url.Parse(u.String())
+	require.Nil(t, err, "%s", err)
+	q := parsed.Query()
+	assert.Equal(t, "1", q.Get("filter[field_member_of.id]"), "the Filter and Value are expected to be retained")
+	assert.Equal(t, "created", q.Get("filter[created-from][condition][path]"))
+	assert.Equal(t, ">=", q.Get("filter[created-from][condition][operator]"))
+	assert.Equal(t, "1682942400", q.Get("filter[created-from][condition][value]"))
+	assert.Equal(t, "<", q.Get("filter[created-to][condition][operator]"))
+	assert.Equal(t, "1682946000", q.Get("filter[created-to][condition][value]"))
+	assert.Equal(t, "changed", q.Get("filter[changed-since][condition][path]"))
+	assert.Equal(t, ">=", q.Get("filter[changed-since][condition][operator]"))
+	assert.Equal(t, "1682942400", q.Get("filter[changed-since][condition][value]"))
+}
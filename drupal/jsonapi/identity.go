@@ -0,0 +1,116 @@
+package jsonapi
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+)
+
+// The header carrying the id of the test run issuing a request (see TestRunId), so that the requests of a run can be
+// found in the logs of the server, and integration-test traffic filtered out of its analytics
+const TestRunHeader = "X-IDC-Test-Run"
+
+// The path of this module, whose version is reported by DefaultUserAgent
+const modulePath = "github.com/jhu-idc/idc-golang"
+
+// The User-Agent sent with requests when 'IDC_USER_AGENT' is unset, e.g. `idc-golang/v1.2.0 go-test`
+var DefaultUserAgent = "idc-golang/" + moduleVersion() + " go-test"
+
+var (
+	runMu sync.Mutex
+	runId string
+)
+
+// StartTestRun begins a new test run, answering its id: the value of 'IDC_TEST_RUN_ID' if set (e.g. to correlate the
+// suites of a CI job), otherwise a new id.  The id is sent with every subsequent request (see TestRunHeader), and
+// logged to the default Logger, so that a failure can be correlated with the logs of the server.  Invoke StartTestRun from TestMain:
+//
+//	func TestMain(m *testing.M) {
+//		jsonapi.StartTestRun()
+//		os.Exit(m.Run())
+//	}
+func StartTestRun() string {
+	runMu.Lock()
+	defer runMu.Unlock()
+	return startTestRun()
+}
+
+// TestRunId answers the id of the current test run, starting a run if StartTestRun has not been invoked
+func TestRunId() string {
+	runMu.Lock()
+	defer runMu.Unlock()
+	if runId == "" {
+		return startTestRun()
+	}
+	return runId
+}
+
+// startTestRun begins a new test run; the caller must hold runMu
+func startTestRun() string {
+	runId = env.TestRunIdOr(newRunId())
+	DefaultLogger().Log("jsonapi test run", "id", runId)
+	return runId
+}
+
+// newRunId answers a new test run id: the time the run started, and a random suffix distinguishing runs started
+// concurrently, e.g. `20210615T142233Z-9f86d081`
+func newRunId() string {
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+	return time.Now().UTC().Format("20060102T150405Z") + "-" + hex.EncodeToString(suffix)
+}
+
+// identify sets the User-Agent and TestRunHeader of the request, unless already present: the supplied values if not
+// empty, otherwise the User-Agent from 'IDC_USER_AGENT' (or DefaultUserAgent), and the id of the current test run
+func identify(req *http.Request, userAgent, testRunId string) {
+	if req.Header.Get("User-Agent") == "" {
+		if userAgent == "" {
+			userAgent = env.UserAgentOr(DefaultUserAgent)
+		}
+		req.Header.Set("User-Agent", userAgent)
+	}
+	if req.Header.Get(TestRunHeader) == "" {
+		if testRunId == "" {
+			testRunId = TestRunId()
+		}
+		req.Header.Set(TestRunHeader, testRunId)
+	}
+}
+
+// identifiedHeader answers a copy of the headers carrying the User-Agent and test run id of the Client, if present, so
+// that they take precedence over the defaults set by identify
+func (c *Client) identifiedHeader(header http.Header) http.Header {
+	if c.UserAgent == "" && c.TestRunId == "" {
+		return header
+	}
+	identified := header.Clone()
+	if identified == nil {
+		identified = http.Header{}
+	}
+	if c.UserAgent != "" {
+		identified.Set("User-Agent", c.UserAgent)
+	}
+	if c.TestRunId != "" {
+		identified.Set(TestRunHeader, c.TestRunId)
+	}
+	return identified
+}
+
+// moduleVersion answers the version of this module recorded in the build, or `devel` if it is built from a checkout
+func moduleVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "devel"
+	}
+	for _, m := range append([]*debug.Module{&info.Main}, info.Deps...) {
+		if m.Path == modulePath && m.Version != "" && m.Version != "(devel)" {
+			return m.Version
+		}
+	}
+	return "devel"
+}
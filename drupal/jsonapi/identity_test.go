@@ -0,0 +1,88 @@
+package jsonapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ClientIdentification(t *testing.T) {
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+		}
+		_, _ = w.Write([]byte(stubResponse))
+	}))
+	defer server.Close()
+	v := &struct{ Data []struct{ Id string } }{}
+
+	require.Nil(t, (&Client{}).Get(context.Background(), server.URL+"/jsonapi/media/document", v))
+	assert.True(t, strings.HasPrefix(header.Get("User-Agent"), "idc-golang/"), "unexpected User-Agent %s",
+		header.Get("User-Agent"))
+	assert.True(t, strings.HasSuffix(header.Get("User-Agent"), " go-test"), "unexpected User-Agent %s",
+		header.Get("User-Agent"))
+	assert.Equal(t, TestRunId(), header.Get(TestRunHeader))
+	assert.NotEmpty(t, TestRunId())
+
+	t.Setenv("IDC_USER_AGENT", "moo/1.0")
+	require.Nil(t, (&Client{}).Get(context.Background(), server.URL+"/jsonapi/media/document", v))
+	assert.Equal(t, "moo/1.0", header.Get("User-Agent"))
+
+	c := &Client{UserAgent: "oink/2.0", TestRunId: "run-42"}
+	require.Nil(t, c.Get(context.Background(), server.URL+"/jsonapi/media/document", v))
+	assert.Equal(t, "oink/2.0", header.Get("User-Agent"))
+	assert.Equal(t, "run-42", header.Get(TestRunHeader))
+
+	jar := JsonApiUrl{T: t, BaseUrl: server.URL, ExplicitBaseUrl: true, DrupalEntity: "node",
+		DrupalBundle: "islandora_object", Username: "admin", Password: "moo", TestRunId: "run-43"}
+	require.Nil(t, jar.CreateErr(Resource{Attributes: map[string]interface{}{"title": "Moo"}}, v))
+	assert.Equal(t, "moo/1.0", header.Get("User-Agent"))
+	assert.Equal(t, "run-43", header.Get(TestRunHeader))
+}
+
+func Test_JsonApiUrlIdentification(t *testing.T) {
+	var headers []http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = append(headers, r.Header.Clone())
+		_, _ = w.Write([]byte(stubResponse))
+	}))
+	defer server.Close()
+
+	jar := JsonApiUrl{T: t, BaseUrl: server.URL, ExplicitBaseUrl: true, DrupalEntity: "media", DrupalBundle: "document",
+		Filter: "id", Value: "moo", UserAgent: "oink/2.0", TestRunId: "run-43"}
+	jar.GetSingle(&struct{ Data []struct{ Id string } }{})
+	jar.Get(&JsonApiResponse{})
+	require.Equal(t, 2, len(headers))
+	for _, header := range headers {
+		assert.Equal(t, "oink/2.0", header.Get("User-Agent"))
+		assert.Equal(t, "run-43", header.Get(TestRunHeader))
+	}
+}
+
+func Test_StartTestRun(t *testing.T) {
+	previous := TestRunId()
+	defer func() {
+		runMu.Lock()
+		runId = previous
+		runMu.Unlock()
+	}()
+
+	first := StartTestRun()
+	assert.Equal(t, first, TestRunId())
+	assert.NotEqual(t, first, StartTestRun(), "each run is expected to have a new id")
+
+	t.Setenv("IDC_TEST_RUN_ID", "ci-job-7")
+	logger := &recordingLogger{}
+	t.Cleanup(func() { SetLogger(nil) })
+	SetLogger(logger)
+	assert.Equal(t, "ci-job-7", StartTestRun())
+	assert.Equal(t, "ci-job-7", TestRunId())
+	assert.Equal(t, []string{"jsonapi test run id=ci-job-7"}, logger.events)
+}
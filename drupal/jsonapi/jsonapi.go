@@ -2,34 +2,30 @@
 package jsonapi
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/jhu-idc/idc-golang/drupal/env"
 	"github.com/stretchr/testify/assert"
+	"io"
 	"io/ioutil"
 	"log"
 	"net/http"
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 )
 
-// Encapsulates the Entity type and bundle of a Drupal resource.
-//
-// DrupalType is parsed from the JSONAPI response, where type is represented, e.g. as:
-//   "type": "taxonomy_term--person"
-type DrupalType string
-
-// The entity (e.g. taxonomy_term, node, etc) encapsulated by this type
-func (t DrupalType) Entity() string {
-	return strings.Split(string(t), "--")[0]
-}
+// Answered (wrapped) when a requested resource does not exist, or is not visible to the requesting user.  Drupal omits
+// resources the user is not authorized to view from JSONAPI collection responses, so an unauthorized resource is
+// indistinguishable from a missing one.
+var ErrNotFound = errors.New("jsonapi: resource not found")
 
-// The bundle (e.g. 'person', 'islandora_object', etc) encapsulated by this type
-func (t DrupalType) Bundle() string {
-	// TODO: some entities (like User) do not have a bundle type
-	return strings.Split(string(t), "--")[1]
-}
+// Answered (wrapped) when Drupal refuses the requesting user access to a resource with a 401 or 403 status, e.g. a
+// private file of a media with restricted access.  A 401 status also wraps ErrUnauthorized; see HTTPError.
+var ErrForbidden = errors.New("jsonapi: access forbidden")
 
 // Default HTTP client
 var httpClient = &http.Client{}
@@ -55,51 +51,190 @@ type JsonApiUrl struct {
 	Value string
 	// RawFilter is supplied by the caller and is used as-is.  In that case, Filter and Value are not used.
 	RawFilter string
+	// If present, the entity with this UUID is requested by its canonical path, `/jsonapi/{entity}/{bundle}/{uuid}`,
+	// rather than by filtering on its `id`; Filter, Value, and RawFilter are ignored.  Drupal answers the `data` element
+	// of the canonical path as an object, which is unmarshaled as the sole element of a `data` slice (see
+	// JsonApiResponse).
+	Uuid string
 	// The username to use when authenticating to Drupal's JSONAPI endpoint.  If this value is empty, no `Authorization` header will be sent, otherwise Basic authentication is used.
 	Username  string
 	// The password to use when authenticating to Drupal's JSONAPI endpoint.
 	Password  string
+	// If present, authenticates requests instead of Username and Password.  Incomplete credentials fail the request
+	// with an error naming the environment variable to set (see env.Credentials.Validate), without contacting Drupal.
+	Credentials *env.Credentials
+	// If present, supplies the base url and credentials of requests in place of BaseUrl, Username, Password, and the
+	// environment, and the timeout, TLS settings, retries, and rate limit of requests (see env.Load).  ExplicitBaseUrl and
+	// Credentials take precedence over the Config.
+	Config *env.Config
+	// If non-zero, bounds the time of each request, in place of the timeout of the Config
+	Timeout time.Duration
+	// If true, requests are sent without credentials, regardless of Username, Credentials, and Config
+	Anonymous bool
+	// If present, and the request is not Anonymous, answers a token sent as the bearer of each GET request in place of
+	// Basic authentication, e.g. the JWT signed by a syn.Signer for the endpoints of Islandora microservices
+	BearerToken func() (string, error)
+	// If present, carries the deadline and cancellation of requests; otherwise requests are bounded only by the timeout
+	Context context.Context
+	// If present, records the diagnostic events of requests in place of the Logger set by SetLogger
+	Logger Logger
+	// If present, sent as the User-Agent of requests in place of 'IDC_USER_AGENT' or DefaultUserAgent
+	UserAgent string
+	// If present, sent as the TestRunHeader of requests in place of the id of the current test run (see TestRunId)
+	TestRunId string
+	// If true, BaseUrl is used even if the `DRUPAL_BASE_URL` environment variable is set.  By default, the environment
+	// variable overrides BaseUrl.
+	ExplicitBaseUrl bool
 }
 
 // Get the JSON API content from the URL and unmarshal the response into the supplied interface (which must be a
 // pointer).  This method asserts that there is a single object in the `data` element of the JSON response.
 func (jar *JsonApiUrl) GetSingle(v interface{}) {
-	var res *http.Response
-	var body[]byte
-
-	// retrieve json of the migrated entity from the jsonapi and unmarshal the single response
-	if len(strings.TrimSpace(jar.Username)) == 0 {
-		res, body = GetResource(jar.T.(*testing.T), jar.String())
-	} else {
-		res, body = GetResourceWithBasicAuth(jar.T.(*testing.T), jar.String(), jar.Username, jar.Password)
-	}
-	defer func() { _ = res.Close }()
-	UnmarshalSingleResponse(jar.T.(*testing.T), body, res, &JsonApiResponse{}).To(v)
+	err := jar.GetSingleErr(v)
+	assert.Nil(jar.T, err, "%s", err)
 }
 
 // Get the JSON API content from the URL and unmarshal the response into the supplied interface (which must be a
 // pointer).
 func (jar *JsonApiUrl) Get(v interface{}) {
-	var res *http.Response
-	var body[]byte
+	err := jar.getErr(v)
+	assert.Nil(jar.T, err, "%s", err)
+}
 
-	// retrieve json of the migrated entity from the jsonapi and unmarshal the single response
-	if len(strings.TrimSpace(jar.Username)) == 0 {
-		res, body = GetResource(jar.T.(*testing.T), jar.String())
-	} else {
-		res, body = GetResourceWithBasicAuth(jar.T.(*testing.T), jar.String(), jar.Username, jar.Password)
+// GetByUuid retrieves the entity with the UUID by its canonical path (see Uuid), and unmarshals the response into the
+// supplied interface (which must be a pointer), asserting that it was retrieved.  It is cheaper than filtering on the
+// `id` of the entity, and answers a 404 status rather than an empty `data` element if the entity does not exist.
+func (jar *JsonApiUrl) GetByUuid(uuid string, v interface{}) {
+	err := jar.GetByUuidErr(uuid, v)
+	assert.Nil(jar.T, err, "%s", err)
+}
+
+// GetByUuidErr behaves as GetByUuid, but answers an error instead of making assertions.  If the entity does not exist,
+// the error wraps ErrNotFound.
+func (jar *JsonApiUrl) GetByUuidErr(uuid string, v interface{}) error {
+	u := *jar
+	u.Uuid = uuid
+	return u.GetSingleErr(v)
+}
+
+// GetSingleErr behaves as GetSingle, but answers an error instead of making assertions, which makes it safe to invoke
+// from goroutines and from non-test code.  An error is returned if the URL cannot be composed, the request fails, the
+// response cannot be unmarshaled, or the `data` element of the response does not contain exactly one object.  If the
+// response contains no objects, the error wraps ErrNotFound.
+func (jar *JsonApiUrl) GetSingleErr(v interface{}) error {
+	u, err := jar.url()
+	if err != nil {
+		return err
+	}
+	c, err := jar.apiClient()
+	if err != nil {
+		return err
+	}
+	return c.GetSingle(jar.context(), u, v)
+}
+
+// getErr behaves as Get, but answers an error instead of making assertions
+func (jar *JsonApiUrl) getErr(v interface{}) error {
+	u, err := jar.url()
+	if err != nil {
+		return err
+	}
+	c, err := jar.apiClient()
+	if err != nil {
+		return err
+	}
+	return c.Get(jar.context(), u, v)
+}
+
+// fetchErr answers the body of the response from the url, issued by the Client of the url (see apiClient)
+func (jar *JsonApiUrl) fetchErr(u string) ([]byte, error) {
+	body, _, err := jar.fetchWithHeaderErr(u, nil)
+	return body, err
+}
+
+// fetchWithHeaderErr behaves as fetchErr, sending the supplied request headers (e.g. the validators of a conditional
+// request), and answering the headers of the response as well as its body
+func (jar *JsonApiUrl) fetchWithHeaderErr(u string, header http.Header) ([]byte, http.Header, error) {
+	c, err := jar.apiClient()
+	if err != nil {
+		return nil, nil, err
+	}
+	return c.fetchErr(jar.context(), u, header)
+}
+
+// DoErr sends a request to a url other than a JSON API resource (e.g. an endpoint of a Drupal module), issued by the
+// Client of the url with its Context, so that it is authenticated and bounded as the JSON API requests of the url are
+// (see Client.Do)
+func (jar *JsonApiUrl) DoErr(method, u string, body []byte, header http.Header) (*Response, error) {
+	c, err := jar.apiClient()
+	if err != nil {
+		return nil, err
+	}
+	return c.Do(jar.context(), method, u, body, header)
+}
+
+// apiClient answers the Client issuing the requests of the url, carrying its Config, Timeout, and bearer token, and
+// authenticated according to basicAuth
+func (jar *JsonApiUrl) apiClient() (*Client, error) {
+	username, password, err := jar.basicAuth()
+	if err != nil {
+		return nil, err
+	}
+	return &Client{
+		Config:      jar.Config,
+		Credentials: &env.Credentials{Username: username, Password: password},
+		Anonymous:   jar.Anonymous,
+		BearerToken: jar.BearerToken,
+		Timeout:     jar.Timeout,
+		Logger:      jar.Logger,
+		UserAgent:   jar.UserAgent,
+		TestRunId:   jar.TestRunId,
+	}, nil
+}
+
+// client answers the HTTP client issuing requests: the client of the Config if present, bounded by the Timeout if set
+func (jar *JsonApiUrl) client() *http.Client {
+	return (&Client{Config: jar.Config, Timeout: jar.Timeout}).client()
+}
+
+// context answers the Context of requests, or the background context if none is present
+func (jar *JsonApiUrl) context() context.Context {
+	if jar.Context == nil {
+		return context.Background()
+	}
+	return jar.Context
+}
+
+// basicAuth answers the username and password authenticating requests: none if Anonymous, otherwise from the
+// Credentials if present, the Config if present, or the Username and Password.  An error is answered if the Credentials
+// are incomplete.
+func (jar *JsonApiUrl) basicAuth() (string, string, error) {
+	if jar.Anonymous {
+		return "", "", nil
+	}
+	if jar.Credentials == nil && jar.Config != nil {
+		return jar.Config.Credentials.Username, jar.Config.Credentials.Password, nil
 	}
-	defer func() { _ = res.Close }()
-	UnmarshalResponse(jar.T.(*testing.T), body, res, &JsonApiResponse{}, nil).To(v)
+	if jar.Credentials == nil {
+		return jar.Username, jar.Password, nil
+	}
+	if err := jar.Credentials.Validate(); err != nil {
+		return "", "", fmt.Errorf("jsonapi: unable to authenticate request for %s/%s: %w", jar.DrupalEntity,
+			jar.DrupalBundle, err)
+	}
+	return jar.Credentials.Username, jar.Credentials.Password, nil
 }
 
 // Encapsulates a generic JSON API response
 type JsonApiResponse struct {
 	// The 'data' element(s) of the response
 	Data []map[string]interface{}
+	// The href of the next page of results, present in the 'links' element of paginated responses
+	Next string `json:"-"`
 }
 
-// Handles the case where the 'data' key contains an array of objects, or a single object.
+// Handles the case where the 'data' key contains an array of objects (e.g. the response to a filtered query), or a
+// single object (e.g. the response to the canonical path of an entity), which is normalized to a one-element array.
 func (jar *JsonApiResponse) UnmarshalJSON(b []byte) error {
 	fullRes := make(map[string]interface{})
 
@@ -123,6 +258,12 @@ func (jar *JsonApiResponse) UnmarshalJSON(b []byte) error {
 			return fmt.Errorf("unable to determine type of JSONAPI key 'data': %v", e)
 		}
 	}
+
+	if links, ok := fullRes["links"].(map[string]interface{}); ok {
+		if next, ok := links["next"].(map[string]interface{}); ok {
+			jar.Next, _ = next["href"].(string)
+		}
+	}
 	return nil
 }
 
@@ -135,31 +276,79 @@ func (jar *JsonApiResponse) To(v interface{}) {
 	}
 }
 
+// Adapts the generic JsonApiResponse to a higher-fidelity type, answering any error encountered
+func (jar *JsonApiResponse) toErr(v interface{}) error {
+	// the generic response is copied as-is, as its marshaled form cannot be unmarshaled as a JSON API response
+	if r, ok := v.(*JsonApiResponse); ok {
+		*r = *jar
+		return nil
+	}
+
+	b, err := json.Marshal(jar)
+	if err != nil {
+		return fmt.Errorf("jsonapi: unable to marshal %v as json: %w", jar, err)
+	}
+
+	if err := json.Unmarshal(b, v); err != nil {
+		decodeErr := newDecodeError("", b, err)
+		decodeErr.Type = fmt.Sprintf("%T", v)
+		return decodeErr
+	}
+
+	return nil
+}
+
 // Compose and return a string representation of the JSONAPI URL
 func (moo *JsonApiUrl) String() string {
+	// url() answers an error naming the missing base url (unless a Config supplies it), entity, or bundle
+	u, err := moo.url()
+	assert.Nil(moo.T, err, "%s", err)
+	return u
+}
+
+// Compose the JSONAPI URL, answering an error if the URL cannot be composed
+func (moo *JsonApiUrl) url() (string, error) {
 	var u *url.URL
 	var err error
 
-	assert.NotEmpty(moo.T, moo.BaseUrl, "error generating a JsonAPI URL from %v: %s", moo, "base url must not be empty")
-	assert.NotEmpty(moo.T, moo.DrupalEntity, "error generating a JsonAPI URL from %v: %s", moo, "drupal entity must not be empty")
-	assert.NotEmpty(moo.T, moo.DrupalBundle, "error generating a JsonAPI URL from %v: %s", moo, "drupal bundle must not be empty")
+	if moo.BaseUrl == "" && (moo.Config == nil || moo.ExplicitBaseUrl) {
+		return "", fmt.Errorf("jsonapi: error generating a JsonAPI URL: %s", "base url must not be empty")
+	}
+	if moo.DrupalEntity == "" {
+		return "", fmt.Errorf("jsonapi: error generating a JsonAPI URL: %s", "drupal entity must not be empty")
+	}
+	if moo.DrupalBundle == "" {
+		return "", fmt.Errorf("jsonapi: error generating a JsonAPI URL: %s", "drupal bundle must not be empty")
+	}
 
-	baseUrl := env.BaseUrlOr(moo.BaseUrl)
+	baseUrl := moo.BaseUrl
+	if !moo.ExplicitBaseUrl && moo.Config != nil {
+		baseUrl = moo.Config.BaseUrl
+	} else if !moo.ExplicitBaseUrl {
+		baseUrl = env.BaseUrlOr(moo.BaseUrl)
+	}
 	if strings.HasSuffix(baseUrl, "/") {
 		baseUrl = baseUrl[:len(baseUrl) - 1]
 	}
 	u, err = url.Parse(fmt.Sprintf("%s", strings.Join([]string{baseUrl, "jsonapi", moo.DrupalEntity, moo.DrupalBundle}, "/")))
-	assert.Nil(moo.T, err, "error generating a JsonAPI URL from %v: %s", moo, err)
+	if err != nil {
+		return "", fmt.Errorf("jsonapi: error generating a JsonAPI URL for %s/%s: %w", moo.DrupalEntity, moo.DrupalBundle, err)
+	}
 
-	// If a raw filter is supplied, use it as-is, otherwise use the .Filter and .Value
-	if moo.RawFilter != "" {
+	// If a UUID is supplied, request the canonical path of the entity; otherwise if a raw filter is supplied, use it
+	// as-is, otherwise use the .Filter and .Value
+	if moo.Uuid != "" {
+		u = u.JoinPath(moo.Uuid)
+	} else if moo.RawFilter != "" {
 		u, err = url.Parse(fmt.Sprintf("%s?%s", u.String(), moo.RawFilter))
 	} else if moo.Filter != "" {
-		u, err = url.Parse(fmt.Sprintf("%s?filter[%s]=%s", u.String(), moo.Filter, moo.Value))
+		u, err = url.Parse(fmt.Sprintf("%s?filter[%s]=%s", u.String(), moo.Filter, url.QueryEscape(moo.Value)))
 	}
 
-	assert.Nil(moo.T, err, "error generating a JsonAPI URL from %v: %s", moo, err)
-	return u.String()
+	if err != nil {
+		return "", fmt.Errorf("jsonapi: error generating a JsonAPI URL for %s/%s: %w", moo.DrupalEntity, moo.DrupalBundle, err)
+	}
+	return u.String(), nil
 }
 
 // Unmarshal a JSONAPI response body and assert that exactly one data element is present
@@ -194,14 +383,110 @@ func GetResourceWithBasicAuth(t *testing.T, url, username, password string) (*ht
 	req, err := http.NewRequest(http.MethodGet, url, nil)
 	if len(strings.TrimSpace(username)) > 0 {
 		req.SetBasicAuth(username, password)
-		log.Printf("Retrieving (with Authorization: basic) %s", url)
+		log.Printf("Retrieving (with Authorization: basic) %s", env.RedactUrl(url))
 	} else {
-		log.Printf("Retrieving %s", url)
+		log.Printf("Retrieving %s", env.RedactUrl(url))
 	}
+	identify(req, "", "")
 	res, err := httpClient.Do(req)
-	assert.Nil(t, err, "encountered error requesting %s: %s", url, err)
-	assert.Equal(t, 200, res.StatusCode, "%d status encountered when requesting %s", res.StatusCode, url)
-	body, err := ioutil.ReadAll(res.Body)
-	assert.Nil(t, err, "error encountered reading response body from %s: %s", url, err)
+	assert.Nil(t, err, "encountered error requesting %s: %s", env.RedactUrl(url), err)
+	assert.Equal(t, 200, res.StatusCode, "%d status encountered when requesting %s", res.StatusCode, env.RedactUrl(url))
+	body, err := readResponseErr(url, res.Body)
+	assert.Nil(t, err, "%s", err)
 	return res, body
-}
\ No newline at end of file
+}
+// GetResourceErr returns the HTTP response and body from the supplied url, answering an error if the request cannot be
+// executed, the HTTP status code is not 200, or the response body cannot be read.  If the body exceeds the maximum
+// response size from 'IDC_MAX_RESPONSE_SIZE' (see env.DefaultMaxResponseSize), the error wraps ErrResponseTooLarge.  The supplied username and password
+// are used to send a Basic Authorization header.  If the supplied username is empty, then the request will be sent
+// without an Authorization header.
+func GetResourceErr(url, username, password string) (*http.Response, []byte, error) {
+	res, err := OpenResourceErr(url, username, password)
+	if err != nil {
+		return res, nil, err
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	body, err := readResponseErr(url, res.Body)
+	if err != nil {
+		return res, nil, err
+	}
+
+	return res, body, nil
+}
+
+// GetResourceWithBearerErr behaves as GetResourceErr, but authenticates the request by sending the token as its bearer
+// (e.g. the JWT signed by a syn.Signer) in place of Basic authentication
+func GetResourceWithBearerErr(url, token string) (*http.Response, []byte, error) {
+	res, err := openResourceWithHeaderErr(httpClient, url, "", "", withBearer(nil, token))
+	if err != nil {
+		return res, nil, err
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	body, err := readResponseErr(url, res.Body)
+	if err != nil {
+		return res, nil, err
+	}
+	return res, body, nil
+}
+
+// withBearer answers a copy of the headers carrying the token as the bearer of an Authorization header
+func withBearer(header http.Header, token string) http.Header {
+	withToken := header.Clone()
+	if withToken == nil {
+		withToken = http.Header{}
+	}
+	withToken.Set("Authorization", "Bearer "+token)
+	return withToken
+}
+
+// OpenResourceErr behaves as GetResourceErr, but does not read the response body, so that large resources (e.g. the
+// content of a file) may be streamed.  The caller is responsible for closing the body of the response.  If an error is
+// answered, the body has already been read and closed.  Unlike the JSON API requests of a Client, the body is not
+// bounded by the maximum response size.
+func OpenResourceErr(url, username, password string) (*http.Response, error) {
+	return openResourceErr(httpClient, url, username, password)
+}
+
+// openResourceErr behaves as OpenResourceErr, issuing the request using the supplied client
+func openResourceErr(client *http.Client, url, username, password string) (*http.Response, error) {
+	return openResourceWithHeaderErr(client, url, username, password, nil)
+}
+
+// openResourceWithHeaderErr behaves as openResourceErr, sending the supplied request headers.  If the response to a
+// conditional request carries a 304 status, the error wraps ErrNotModified.
+func openResourceWithHeaderErr(client *http.Client, url, username, password string, header http.Header) (*http.Response, error) {
+	return openResourceWithContextErr(context.Background(), client, url, username, password, header)
+}
+
+// openResourceWithContextErr behaves as openResourceWithHeaderErr, bounding the request by the context
+func openResourceWithContextErr(ctx context.Context, client *http.Client, url, username, password string, header http.Header) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("jsonapi: error creating request for %s: %w", env.RedactUrl(url), err)
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	identify(req, "", "")
+	if len(strings.TrimSpace(username)) > 0 {
+		req.SetBasicAuth(username, password)
+		log.Printf("Retrieving (with Authorization: basic) %s", env.RedactUrl(url))
+	} else {
+		log.Printf("Retrieving %s", env.RedactUrl(url))
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("jsonapi: encountered error requesting %s: %w", env.RedactUrl(url), err)
+	}
+	if res.StatusCode == 200 {
+		return res, nil
+	}
+
+	body, _ := ioutil.ReadAll(io.LimitReader(res.Body, maxErrorBody))
+	_, _ = io.Copy(ioutil.Discard, res.Body)
+	_ = res.Body.Close()
+	return res, newHTTPError(http.MethodGet, url, res.StatusCode, body)
+}
@@ -1,16 +1,22 @@
 package jsonapi
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"github.com/jhu-idc/idc-golang/drupal/env"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"html"
 	"log"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"sync"
 	"testing"
+	"time"
 )
 
 // host that the test http server listens on
@@ -56,8 +62,9 @@ func (chf *calledHandlerFunc) wasCalled() bool {
 	return result
 }
 
-// TestMain allocates an unused TCP port for the HTTP server or panics, then runs tests
+// TestMain starts a test run, and allocates an unused TCP port for the HTTP server or panics, then runs tests
 func TestMain(m *testing.M) {
+	StartTestRun()
 	addr, err := net.ResolveTCPAddr("tcp", "localhost:0")
 	if err != nil {
 		log.Panicf("Unable to resolve TCP address: %s", err.Error())
@@ -204,3 +211,211 @@ func Test_GetResourceWithBasicAuthn(t *testing.T) {
 	u.Get(result)
 	assert.True(t, handlers[noAuthHandlerPath].wasCalled())
 }
+
+func Test_DrupalType(t *testing.T) {
+	dt := NewDrupalType("node", "islandora_object")
+	assert.Equal(t, DrupalType("node--islandora_object"), dt)
+	assert.Equal(t, "node", dt.Entity())
+	assert.Equal(t, "islandora_object", dt.Bundle())
+	assert.True(t, dt.Is("node", "islandora_object"))
+	assert.False(t, dt.Is("node", "collection_object"))
+}
+
+func Test_DrupalTypeJson(t *testing.T) {
+	v := struct {
+		Type DrupalType
+	}{}
+
+	require.Nil(t, json.Unmarshal([]byte(`{"type": "taxonomy_term--person"}`), &v))
+	assert.True(t, v.Type.Is("taxonomy_term", "person"))
+
+	b, err := json.Marshal(v)
+	require.Nil(t, err)
+	assert.Equal(t, `{"Type":"taxonomy_term--person"}`, string(b))
+
+	assert.NotNil(t, json.Unmarshal([]byte(`{"type": 1}`), &v))
+}
+
+func Test_DrupalTypeSingleSegment(t *testing.T) {
+	dt, err := ParseDrupalType("file")
+	require.Nil(t, err, "%s", err)
+	assert.Equal(t, "file", dt.Entity())
+	assert.Equal(t, "file", dt.Bundle())
+	assert.True(t, dt.Is("file", "file"))
+
+	dt, err = ParseDrupalType("user--user")
+	require.Nil(t, err, "%s", err)
+	assert.Equal(t, "user", dt.Entity())
+	assert.Equal(t, "user", dt.Bundle())
+}
+
+func Test_DrupalTypeMalformed(t *testing.T) {
+	for _, malformed := range []string{"media--", "--image", "media-image", "media--image--moo", "Media--Image",
+		"media -- image"} {
+		_, err := ParseDrupalType(malformed)
+		assert.True(t, errors.Is(err, ErrMalformedType), "expected ErrMalformedType for '%s', got %v", malformed, err)
+
+		v := struct{ Type DrupalType }{}
+		err = json.Unmarshal([]byte(`{"type": "`+malformed+`"}`), &v)
+		assert.True(t, errors.Is(err, ErrMalformedType), "expected ErrMalformedType for '%s', got %v", malformed, err)
+		if err != nil {
+			assert.Contains(t, err.Error(), "'"+malformed+"'", "the error is expected to carry the malformed type")
+		}
+
+		_, err = json.Marshal(struct{ Type DrupalType }{DrupalType(malformed)})
+		assert.True(t, errors.Is(err, ErrMalformedType), "expected ErrMalformedType for '%s', got %v", malformed, err)
+	}
+
+	v := struct{ Type DrupalType }{Type: "node--islandora_object"}
+	require.Nil(t, json.Unmarshal([]byte(`{"type": null}`), &v))
+	assert.Equal(t, DrupalType(""), v.Type, "a null type is expected to be unmarshaled as the empty type")
+	b, err := json.Marshal(v)
+	require.Nil(t, err, "%s", err)
+	assert.Equal(t, `{"Type":""}`, string(b))
+}
+
+func Test_DecodeMalformedType(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data": [{"type": "node-islandora_object", "id": "moo"}]}`))
+	}))
+	defer server.Close()
+
+	v := &struct{ Data []struct{ Type DrupalType } }{}
+	err := (&Client{}).Get(context.Background(), server.URL, v)
+	assert.True(t, errors.Is(err, ErrMalformedType), "expected ErrMalformedType, got %v", err)
+	assert.Contains(t, fmt.Sprint(err), "node-islandora_object")
+}
+
+func Test_GetSingleErrNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/jsonapi/node/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"data": []}`))
+	}))
+	defer server.Close()
+	t.Setenv("DRUPAL_BASE_URL", server.URL)
+
+	u := &JsonApiUrl{T: t, BaseUrl: server.URL, DrupalEntity: "node", DrupalBundle: "islandora_object", Filter: "id", Value: "moo"}
+	assert.True(t, errors.Is(u.GetSingleErr(&JsonApiResponse{}), ErrNotFound))
+
+	u.DrupalBundle = "missing"
+	assert.True(t, errors.Is(u.GetSingleErr(&JsonApiResponse{}), ErrNotFound))
+}
+
+func Test_UrlEscapesFilterValue(t *testing.T) {
+	u := &JsonApiUrl{T: t, BaseUrl: "https://example.org/", DrupalEntity: "node", DrupalBundle: "collection_object",
+		Filter: "title", Value: "Photographs & Prints", ExplicitBaseUrl: true}
+	assert.Equal(t, "https://example.org/jsonapi/node/collection_object?filter[title]=Photographs+%26+Prints", u.String())
+}
+
+func Test_UrlWithBaseUrlOfConfig(t *testing.T) {
+	u := &JsonApiUrl{T: t, Config: &env.Config{BaseUrl: "https://example.org"}, DrupalEntity: "node",
+		DrupalBundle: "collection_object"}
+	assert.Equal(t, "https://example.org/jsonapi/node/collection_object", u.String())
+
+	rec := &recordingT{}
+	u = &JsonApiUrl{T: rec, DrupalEntity: "node", DrupalBundle: "collection_object", ExplicitBaseUrl: true}
+	assert.Equal(t, "", u.String())
+	require.Equal(t, 1, len(rec.failures))
+	assert.Contains(t, rec.failures[0], "base url must not be empty")
+}
+
+func Test_GetSingleErrWithCredentials(t *testing.T) {
+	var user, pass string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, _ = r.BasicAuth()
+		w.Write([]byte(stubResponse))
+	}))
+	defer server.Close()
+	t.Setenv("DRUPAL_BASE_URL", server.URL)
+	t.Setenv("IDC_ADMIN_USERNAME", "idc-admin")
+	t.Setenv("IDC_ADMIN_PASSWORD", "moo")
+
+	admin := env.AdminCredentials()
+	u := &JsonApiUrl{T: t, BaseUrl: server.URL, DrupalEntity: "media", DrupalBundle: "document", Filter: "id",
+		Value: "moo", Username: "ignored", Credentials: &admin}
+	require.Nil(t, u.GetSingleErr(&struct{ Data []struct{ Id string } }{}))
+	assert.Equal(t, "idc-admin", user)
+	assert.Equal(t, "moo", pass)
+
+	user = ""
+	creator := env.CredentialsFor("collection_creator")
+	u.Credentials = &creator
+	err := u.GetSingleErr(&struct{ Data []struct{ Id string } }{})
+	assert.Contains(t, fmt.Sprint(err), "set IDC_COLLECTION_CREATOR_PASSWORD")
+	assert.Equal(t, "", user, "no request is expected to be made with incomplete credentials")
+}
+
+func Test_GetSingleErrWithConfig(t *testing.T) {
+	var requests int
+	var user string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		user, _, _ = r.BasicAuth()
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(stubResponse))
+	}))
+	defer server.Close()
+	t.Setenv("DRUPAL_BASE_URL", "http://localhost:1")
+
+	c := env.Config{BaseUrl: server.URL, Credentials: env.Credentials{Username: "admin", Password: "moo"},
+		Timeout: time.Second, Retries: 1, RetryBackoff: time.Millisecond}
+	require.Nil(t, c.Validate())
+	u := &JsonApiUrl{T: t, DrupalEntity: "media", DrupalBundle: "document", Filter: "id", Value: "moo", Config: &c}
+	v := &struct{ Data []struct{ Id string } }{}
+	require.Nil(t, u.GetSingleErr(v))
+	assert.Equal(t, 2, requests)
+	assert.Equal(t, "admin", user)
+	assert.Equal(t, "fd0b8969-ecc9-4a0d-81d3-537ba95bd5a8", v.Data[0].Id)
+
+	requests = 0
+	c.Retries = 0
+	require.Nil(t, c.Validate())
+	assert.Contains(t, fmt.Sprint(u.GetSingleErr(v)), "503 status")
+	assert.Equal(t, 1, requests)
+}
+
+func Test_GetSingleErrWithBearerToken(t *testing.T) {
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		w.Write([]byte(stubResponse))
+	}))
+	defer server.Close()
+
+	u := &JsonApiUrl{T: t, BaseUrl: server.URL, DrupalEntity: "media", DrupalBundle: "document", Filter: "id",
+		Value: "moo", Username: "admin", Password: "moo", ExplicitBaseUrl: true,
+		BearerToken: func() (string, error) { return "signed.jwt.token", nil }}
+	require.Nil(t, u.GetSingleErr(&struct{ Data []struct{ Id string } }{}))
+	assert.Equal(t, "Bearer signed.jwt.token", authorization)
+
+	u.BearerToken = func() (string, error) { return "", fmt.Errorf("no key") }
+	authorization = ""
+	err := u.GetSingleErr(&struct{ Data []struct{ Id string } }{})
+	assert.Contains(t, fmt.Sprint(err), "unable to obtain a bearer token")
+	assert.Equal(t, "", authorization, "no request is expected to be made without a token")
+
+	_, _, err = GetResourceWithBearerErr(server.URL, "other.jwt.token")
+	require.Nil(t, err, "%s", err)
+	assert.Equal(t, "Bearer other.jwt.token", authorization)
+}
+
+func Test_GetSingleAndGetWithBearerToken(t *testing.T) {
+	var authorization []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = append(authorization, r.Header.Get("Authorization"))
+		w.Write([]byte(stubResponse))
+	}))
+	defer server.Close()
+
+	u := &JsonApiUrl{T: t, BaseUrl: server.URL, DrupalEntity: "media", DrupalBundle: "document", Filter: "id",
+		Value: "moo", ExplicitBaseUrl: true, BearerToken: func() (string, error) { return "signed.jwt.token", nil }}
+	u.GetSingle(&struct{ Data []struct{ Id string } }{})
+	u.Get(&JsonApiResponse{})
+	assert.Equal(t, []string{"Bearer signed.jwt.token", "Bearer signed.jwt.token"}, authorization)
+}
@@ -0,0 +1,109 @@
+package jsonapi
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Logger records the diagnostic events of the jsonapi and model packages, e.g. the status and duration of each
+// request, retries, cache hits, and the fan-out of concurrent resolution.  Events are described by a message and
+// attributes supplied as alternating keys and values, e.g. `"url", u, "status", 200`.  Loggers are provided for a test
+// (TestLogger) and for a *slog.Logger (SlogLogger); the default Logger discards every event, so that output is
+// unchanged unless a Logger is set, globally by SetLogger, or for the requests of a Client or JsonApiUrl.
+type Logger interface {
+	Log(msg string, keyvals ...interface{})
+}
+
+// A Logger discarding every event
+type nopLogger struct{}
+
+func (nopLogger) Log(string, ...interface{}) {}
+
+// A Logger recording events in the log of a test
+type testLogger struct {
+	t testing.TB
+}
+
+func (l testLogger) Log(msg string, keyvals ...interface{}) {
+	l.t.Helper()
+	l.t.Logf("%s", formatEvent(msg, keyvals))
+}
+
+// A Logger recording events using a *slog.Logger at a fixed level
+type slogLogger struct {
+	l     *slog.Logger
+	level slog.Level
+}
+
+func (l slogLogger) Log(msg string, keyvals ...interface{}) {
+	l.l.Log(context.Background(), l.level, msg, keyvals...)
+}
+
+var (
+	loggerMu sync.RWMutex
+	logger   Logger = nopLogger{}
+)
+
+// NopLogger answers a Logger discarding every event, the default Logger
+func NopLogger() Logger {
+	return nopLogger{}
+}
+
+// TestLogger answers a Logger recording each event in the log of the test (see testing.T.Logf), e.g.
+// `jsonapi request method=GET url=https://... status=200 elapsed=41ms`
+func TestLogger(t testing.TB) Logger {
+	return testLogger{t: t}
+}
+
+// SlogLogger answers a Logger recording each event using the *slog.Logger at the level, e.g. slog.LevelDebug, with its
+// key and value pairs as attributes
+func SlogLogger(l *slog.Logger, level slog.Level) Logger {
+	return slogLogger{l: l, level: level}
+}
+
+// SetLogger sets the Logger of every request that does not carry its own Logger.  A nil Logger restores the default,
+// which discards every event.  A test setting the Logger should restore the previous Logger when it completes:
+//
+//	t.Cleanup(func() { jsonapi.SetLogger(nil) })
+//	jsonapi.SetLogger(jsonapi.TestLogger(t))
+func SetLogger(l Logger) {
+	if l == nil {
+		l = nopLogger{}
+	}
+	loggerMu.Lock()
+	defer loggerMu.Unlock()
+	logger = l
+}
+
+// DefaultLogger answers the Logger set by SetLogger
+func DefaultLogger() Logger {
+	loggerMu.RLock()
+	defer loggerMu.RUnlock()
+	return logger
+}
+
+// loggerOr answers the Logger if not nil, or the default Logger
+func loggerOr(l Logger) Logger {
+	if l == nil {
+		return DefaultLogger()
+	}
+	return l
+}
+
+// formatEvent answers the message followed by each key and value, e.g. `jsonapi retry url=https://... attempt=1`
+func formatEvent(msg string, keyvals []interface{}) string {
+	b := strings.Builder{}
+	b.WriteString(msg)
+	for i := 0; i < len(keyvals); i += 2 {
+		if i+1 < len(keyvals) {
+			fmt.Fprintf(&b, " %v=%v", keyvals[i], keyvals[i+1])
+		} else {
+			fmt.Fprintf(&b, " %v", keyvals[i])
+		}
+	}
+	return b.String()
+}
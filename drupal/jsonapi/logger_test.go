@@ -0,0 +1,103 @@
+package jsonapi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// A Logger recording the formatted events it receives
+type recordingLogger struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (l *recordingLogger) Log(msg string, keyvals ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, formatEvent(msg, keyvals))
+}
+
+func Test_ClientLogsRequestsAndRetries(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(stubResponse))
+	}))
+	defer server.Close()
+	u := server.URL + "/jsonapi/media/document?filter[id]=moo"
+
+	global := &recordingLogger{}
+	t.Cleanup(func() { SetLogger(nil) })
+	SetLogger(global)
+
+	perClient := &recordingLogger{}
+	c := NewClient(env.Config{BaseUrl: server.URL, Retries: 1, RetryBackoff: time.Millisecond})
+	c.Logger = perClient
+	require.Nil(t, c.GetSingle(context.Background(), u, &struct{}{}))
+
+	require.Equal(t, 3, len(perClient.events), "%q", perClient.events)
+	assert.Contains(t, perClient.events[0], "jsonapi request method=GET url="+u+" status=503")
+	assert.Contains(t, perClient.events[1], "jsonapi retry url="+u+" attempt=1 of=1")
+	assert.Contains(t, perClient.events[2], "jsonapi request method=GET url="+u+" status=200")
+	assert.Empty(t, global.events, "the Logger of the Client is expected to replace the global Logger")
+
+	require.Nil(t, (&Client{}).GetSingle(context.Background(), u, &struct{}{}))
+	require.Equal(t, 1, len(global.events), "%q", global.events)
+	assert.Contains(t, global.events[0], "status=200 elapsed=")
+
+	SetLogger(nil)
+	assert.Equal(t, NopLogger(), DefaultLogger())
+}
+
+func Test_JsonApiUrlLogger(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(stubResponse))
+	}))
+	defer server.Close()
+
+	global := &recordingLogger{}
+	t.Cleanup(func() { SetLogger(nil) })
+	SetLogger(global)
+
+	logger := &recordingLogger{}
+	jar := JsonApiUrl{T: t, BaseUrl: server.URL, ExplicitBaseUrl: true, DrupalEntity: "media", DrupalBundle: "document",
+		Filter: "id", Value: "moo", Logger: logger}
+	jar.GetSingle(&struct{}{})
+	require.Equal(t, 1, len(logger.events), "%q", logger.events)
+	assert.Contains(t, logger.events[0], "jsonapi request method=GET url="+jar.String()+" status=200")
+	assert.Empty(t, global.events, "the Logger of the url is expected to replace the global Logger")
+}
+
+func Test_SlogLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := SlogLogger(slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug})), slog.LevelDebug)
+	l.Log("jsonapi request", "method", "GET", "status", 200)
+	assert.Contains(t, buf.String(), `level=DEBUG msg="jsonapi request" method=GET status=200`)
+
+	buf.Reset()
+	quiet := SlogLogger(slog.New(slog.NewTextHandler(buf, nil)), slog.LevelDebug)
+	quiet.Log("jsonapi request")
+	assert.Empty(t, buf.String(), "a debug event is expected to be discarded by an info handler")
+}
+
+func Test_FormatEvent(t *testing.T) {
+	assert.Equal(t, "model term cache hit type=taxonomy_term--subject id=1", formatEvent("model term cache hit",
+		[]interface{}{"type", "taxonomy_term--subject", "id", "1"}))
+	assert.Equal(t, "odd key=value dangling", formatEvent("odd", []interface{}{"key", "value", "dangling"}))
+	assert.Equal(t, "error err=boom", formatEvent("error", []interface{}{"err", fmt.Errorf("boom")}))
+}
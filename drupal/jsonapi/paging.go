@@ -0,0 +1,74 @@
+package jsonapi
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/stretchr/testify/assert"
+)
+
+// Get the JSON API content from the URL, following the `next` link of each paginated response until the last page is
+// retrieved, and unmarshal the `data` elements of every page into the supplied interface (which must be a pointer).
+func (jar *JsonApiUrl) GetAll(v interface{}) {
+	err := jar.GetAllErr(v)
+	assert.Nil(jar.T.(*testing.T), err, "%s", err)
+}
+
+// GetAllErr behaves as GetAll, but answers an error instead of making assertions.
+func (jar *JsonApiUrl) GetAllErr(v interface{}) error {
+	u, err := jar.url()
+	if err != nil {
+		return err
+	}
+	c, err := jar.apiClient()
+	if err != nil {
+		return err
+	}
+	return c.GetAll(jar.context(), u, v)
+}
+
+// EachPageErr behaves as GetAllErr, but rather than accumulating the `data` elements of every page, unmarshals those of
+// each page in turn into the supplied interface (which must be a pointer), and invokes f, so that large result sets
+// (e.g. a whole vocabulary) are processed a page at a time.  An error answered by f ends the paging, and is answered.
+func (jar *JsonApiUrl) EachPageErr(v interface{}, f func() error) error {
+	u, err := jar.url()
+	if err != nil {
+		return err
+	}
+	c, err := jar.apiClient()
+	if err != nil {
+		return err
+	}
+	return c.EachPage(jar.context(), u, v, f)
+}
+
+// eachPage retrieves the JSON API response from the url using the fetch function, and invokes the supplied function with
+// it.  If the response carries a link to the next page of results, the next page is retrieved in turn, until no pages
+// remain.
+func eachPage(url string, fetch func(url string) ([]byte, error), f func(page *JsonApiResponse) error) error {
+	visited := map[string]bool{}
+	for url != "" {
+		if visited[url] {
+			return fmt.Errorf("jsonapi: pagination loop detected at %s", env.RedactUrl(url))
+		}
+		visited[url] = true
+
+		body, err := fetch(url)
+		if err != nil {
+			return err
+		}
+
+		page := &JsonApiResponse{}
+		if err := decodeErr(url, body, page); err != nil {
+			return err
+		}
+
+		if err := f(page); err != nil {
+			return err
+		}
+		url = page.Next
+	}
+
+	return nil
+}
@@ -0,0 +1,239 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+)
+
+// The media type of JSON API request and response documents
+const contentType = "application/vnd.api+json"
+
+// The media type of the binary content of uploaded files
+const octetStream = "application/octet-stream"
+
+// Describes a resource to be created or updated: its type, and the values of its attributes and relationships, keyed by
+// field name.  Relationships are set using ToOne and ToMany.  The Id is only carried when updating.
+type Resource struct {
+	Type          DrupalType             `json:"type"`
+	Id            string                 `json:"id,omitempty"`
+	Attributes    map[string]interface{} `json:"attributes,omitempty"`
+	Relationships map[string]interface{} `json:"relationships,omitempty"`
+}
+
+// Identifies a resource in a relationship, optionally carrying meta values, e.g. the `rel_type` of a typed relation
+type ResourceIdentifier struct {
+	Type DrupalType        `json:"type"`
+	Id   string            `json:"id"`
+	Meta map[string]string `json:"meta,omitempty"`
+}
+
+// ToOne answers the value of a relationship to the single identified resource, or an empty relationship if the
+// identifier is nil
+func ToOne(id *ResourceIdentifier) interface{} {
+	if id == nil {
+		return map[string]interface{}{"data": nil}
+	}
+	return map[string]interface{}{"data": id}
+}
+
+// ToMany answers the value of a relationship to each of the identified resources, in order
+func ToMany(ids ...ResourceIdentifier) interface{} {
+	if ids == nil {
+		ids = []ResourceIdentifier{}
+	}
+	return map[string]interface{}{"data": ids}
+}
+
+// ToLanguageValues answers the value of a language-tagged relationship carrying each of the values, in order, e.g.
+// `field_alternative_title`
+func ToLanguageValues(values ...LanguageValue) interface{} {
+	ids := make([]ResourceIdentifier, len(values))
+	for i, v := range values {
+		ids[i] = v.Identifier()
+	}
+	return ToMany(ids...)
+}
+
+// CreateErr creates the resource by POSTing it to the entity and bundle of the url (Filter, Value, and RawFilter are
+// ignored), and unmarshals the created resource, as answered by Drupal, into the supplied interface (which must be a
+// pointer), e.g. a *model.JsonApiCollection.  Resources can only be created by an authenticated user, so an error is
+// answered without contacting Drupal if the request carries no credentials.
+func (jar *JsonApiUrl) CreateErr(r Resource, v interface{}) error {
+	u, err := jar.resourceUrl("")
+	if err != nil {
+		return err
+	}
+	if r.Type == "" {
+		r.Type = NewDrupalType(jar.DrupalEntity, jar.DrupalBundle)
+	}
+
+	body, err := json.Marshal(map[string]interface{}{"data": r})
+	if err != nil {
+		return fmt.Errorf("jsonapi: unable to marshal %s resource: %w", r.Type, err)
+	}
+
+	res, err := jar.sendErr(http.MethodPost, u, body, http.StatusCreated)
+	if err != nil {
+		return err
+	}
+
+	value := &JsonApiResponse{}
+	if err := decodeErr(u, res, value); err != nil {
+		return err
+	}
+	return value.toErr(v)
+}
+
+// UploadErr uploads the content as a new file named filename, to the file field of the entity and bundle of the url
+// (e.g. `field_media_image` of media/image), and unmarshals the created file entity, as answered by Drupal, into the
+// supplied interface (which must be a pointer), e.g. a *model.JsonApiFile.  The file is not referenced by any entity
+// until a resource referencing it is created.  As with CreateErr, the request must carry credentials.
+func (jar *JsonApiUrl) UploadErr(field, filename string, content io.Reader, v interface{}) error {
+	u, err := jar.resourceUrl("")
+	if err != nil {
+		return err
+	}
+	u += "/" + field
+
+	body, err := ioutil.ReadAll(content)
+	if err != nil {
+		return fmt.Errorf("jsonapi: unable to read the content of '%s': %w", filename, err)
+	}
+
+	header := http.Header{}
+	header.Set("Content-Type", octetStream)
+	// Drupal only accepts a quoted filename
+	header.Set("Content-Disposition", fmt.Sprintf(`file; filename="%s"`, filename))
+	res, err := jar.sendWithHeaderErr(http.MethodPost, u, body, header, http.StatusCreated)
+	if err != nil {
+		return err
+	}
+
+	value := &JsonApiResponse{}
+	if err := decodeErr(u, res, value); err != nil {
+		return err
+	}
+	return value.toErr(v)
+}
+
+// UpdateErr updates the resource with the supplied id, of the entity and bundle of the url, by PATCHing the attributes
+// and relationships of the resource; those absent from the resource are left unchanged.  The updated resource, as
+// answered by Drupal, is unmarshaled into the supplied interface (which must be a pointer).  As with CreateErr, the
+// request must carry credentials.  If the resource does not exist, the error wraps ErrNotFound.
+func (jar *JsonApiUrl) UpdateErr(id string, r Resource, v interface{}) error {
+	u, err := jar.resourceUrl(id)
+	if err != nil {
+		return err
+	}
+	if r.Type == "" {
+		r.Type = NewDrupalType(jar.DrupalEntity, jar.DrupalBundle)
+	}
+	r.Id = id
+
+	body, err := json.Marshal(map[string]interface{}{"data": r})
+	if err != nil {
+		return fmt.Errorf("jsonapi: unable to marshal %s resource %s: %w", r.Type, id, err)
+	}
+
+	res, err := jar.sendErr(http.MethodPatch, u, body, http.StatusOK)
+	if err != nil {
+		return err
+	}
+
+	value := &JsonApiResponse{}
+	if err := decodeErr(u, res, value); err != nil {
+		return err
+	}
+	return value.toErr(v)
+}
+
+// DeleteErr deletes the resource with the supplied id, of the entity and bundle of the url.  If the resource does not
+// exist, the error wraps ErrNotFound.
+func (jar *JsonApiUrl) DeleteErr(id string) error {
+	u, err := jar.resourceUrl(id)
+	if err != nil {
+		return err
+	}
+	_, err = jar.sendErr(http.MethodDelete, u, nil, http.StatusNoContent)
+	return err
+}
+
+// resourceUrl answers the url of the resource with the supplied id, or, if the id is empty, of the collection of the
+// entity and bundle of the url
+func (jar *JsonApiUrl) resourceUrl(id string) (string, error) {
+	collection := *jar
+	collection.Filter, collection.Value, collection.RawFilter = "", "", ""
+	u, err := collection.url()
+	if err != nil || id == "" {
+		return u, err
+	}
+	return u + "/" + id, nil
+}
+
+// sendErr sends the document to the url using the method, authenticated according to basicAuth, and answers the body of
+// the response.  An error is answered if the request carries no credentials, or if the response does not carry the
+// expected status.  The errors reported by Drupal in the response are included in the error.
+func (jar *JsonApiUrl) sendErr(method, u string, document []byte, expectedStatus int) ([]byte, error) {
+	header := http.Header{}
+	if document != nil {
+		header.Set("Content-Type", contentType)
+	}
+	return jar.sendWithHeaderErr(method, u, document, header, expectedStatus)
+}
+
+// sendWithHeaderErr behaves as sendErr, sending the body with the supplied headers, e.g. the Content-Type of the body.
+// The request is sent by the Client of the url (see Client.Do), bounded by its Context, and is not retried.
+func (jar *JsonApiUrl) sendWithHeaderErr(method, u string, body []byte, header http.Header,
+	expectedStatus int) ([]byte, error) {
+	username, _, err := jar.basicAuth()
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(username) == "" {
+		return nil, fmt.Errorf("jsonapi: %s %s requires credentials: set DRUPAL_USERNAME and DRUPAL_PASSWORD, or "+
+			"supply credentials", method, env.RedactUrl(u))
+	}
+
+	c, err := jar.apiClient()
+	if err != nil {
+		return nil, err
+	}
+	// writes are authenticated by Basic authentication, never by the bearer token of GET requests
+	c.BearerToken = nil
+	header.Set("Accept", contentType)
+
+	res, err := c.Do(jar.context(), method, u, body, header)
+	if err != nil {
+		return nil, err
+	}
+	if res.Status != expectedStatus {
+		return nil, newHTTPError(method, u, res.Status, res.Body)
+	}
+	return res.Body, nil
+}
+
+// responseErrors answers the title and detail of each error in the `errors` element of a JSON API response body,
+// formatted to be appended to an error message, or the empty string if there are none
+func responseErrors(body []byte) string {
+	doc := struct {
+		Errors []struct {
+			Title  string
+			Detail string
+		}
+	}{}
+	if json.Unmarshal(body, &doc) != nil || len(doc.Errors) == 0 {
+		return ""
+	}
+
+	var msgs []string
+	for _, e := range doc.Errors {
+		msgs = append(msgs, strings.TrimSpace(e.Title+": "+e.Detail))
+	}
+	return ": " + strings.Join(msgs, "; ")
+}
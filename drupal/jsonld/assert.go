@@ -0,0 +1,92 @@
+package jsonld
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+// The values of a repository object each predicate is expected to carry, keyed by compact IRI
+var objectValues = map[string]func(obj model.JsonApiIslandoraObj) ([]string, error){
+	"dcterms:title": func(obj model.JsonApiIslandoraObj) ([]string, error) {
+		return []string{obj.JsonApiData[0].JsonApiAttributes.Title}, nil
+	},
+	"dcterms:subject": func(obj model.JsonApiIslandoraObj) ([]string, error) {
+		return model.ResolveLabelsErr(obj.JsonApiData[0].JsonApiRelationships.Subject.Data)
+	},
+	"dcterms:created": func(obj model.JsonApiIslandoraObj) ([]string, error) {
+		return obj.JsonApiData[0].JsonApiAttributes.DateCreated, nil
+	},
+	"dcterms:extent": func(obj model.JsonApiIslandoraObj) ([]string, error) {
+		return obj.JsonApiData[0].JsonApiAttributes.Extent, nil
+	},
+	"dcterms:identifier": func(obj model.JsonApiIslandoraObj) ([]string, error) {
+		return obj.JsonApiData[0].JsonApiAttributes.DigitalIdentifier, nil
+	},
+}
+
+// AssertMatchesObject asserts that each of the predicates of the graph carries the values resolved from the repository
+// object, so that drift in the RDF mapping is caught.  The supported predicates are dcterms:title (the title),
+// dcterms:subject (the names of the subject terms), dcterms:created (the field_date_created values), dcterms:extent,
+// and dcterms:identifier (the field_digital_identifier values); every supported predicate is checked if none are
+// named.  Resources the graph labels (e.g. subject terms) are compared by label (see Graph.LabeledValues).  Every
+// predicate lacking a value of the object is reported.
+//
+//	jsonld.AssertMatchesObject(t, graph, obj, "dcterms:title", "dcterms:subject")
+func AssertMatchesObject(t *testing.T, g Graph, obj model.JsonApiIslandoraObj, predicates ...string) bool {
+	if !assert.NotEmpty(t, obj.JsonApiData, "unable to compare %s with an empty repository object response",
+		g.Subject) {
+		return false
+	}
+	if len(predicates) == 0 {
+		predicates = supportedPredicates()
+	}
+
+	var drift []string
+	for _, p := range predicates {
+		expectedValues, ok := objectValues[p]
+		if !ok {
+			return assert.Fail(t, fmt.Sprintf("unsupported predicate %s: expected one of %s", p,
+				strings.Join(supportedPredicates(), ", ")))
+		}
+		expected, err := expectedValues(obj)
+		if !assert.Nil(t, err, "%s", err) {
+			return false
+		}
+
+		actual := g.LabeledValues(p)
+		present := map[string]bool{}
+		for _, v := range actual {
+			present[v] = true
+		}
+		var missing []string
+		for _, v := range expected {
+			if !present[v] {
+				missing = append(missing, v)
+			}
+		}
+		if len(missing) > 0 {
+			drift = append(drift, fmt.Sprintf("%s lacks %q; its values are %q", p, missing, actual))
+		}
+	}
+
+	if len(drift) > 0 {
+		return assert.Fail(t, fmt.Sprintf("the JSON-LD of %s does not agree with %s %s:\n\t%s", g.Subject,
+			obj.JsonApiData[0].Type, obj.JsonApiData[0].Id, strings.Join(drift, "\n\t")))
+	}
+	return true
+}
+
+// supportedPredicates answers the sorted predicates supported by AssertMatchesObject
+func supportedPredicates() []string {
+	var predicates []string
+	for p := range objectValues {
+		predicates = append(predicates, p)
+	}
+	sort.Strings(predicates)
+	return predicates
+}
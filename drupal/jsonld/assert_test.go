@@ -0,0 +1,28 @@
+package jsonld
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_AssertMatchesObject(t *testing.T) {
+	newJsonLdServer(t, "")
+	model.ResetTermCache()
+	defer model.ResetTermCache()
+
+	obj := model.JsonApiIslandoraObj{}
+	require.Nil(t, json.Unmarshal([]byte(`{"data": [{"type": "node--islandora_object", "id": "1",
+		"attributes": {"title": "Moonrise", "field_date_created": ["1941"], "field_extent": ["1 photograph"]},
+		"relationships": {"field_subject": {"data": [{"type": "taxonomy_term--subject",
+			"id": "815a4c04-0be5-44f1-a876-e8ddc11dcf21"}]}}}]}`), &obj))
+
+	g := FetchJsonLd(t, "1")
+	assert.True(t, AssertMatchesObject(t, g, obj, "dcterms:title", "dcterms:subject", "dcterms:created",
+		"dcterms:extent"))
+	assert.Equal(t, []string{"dcterms:created", "dcterms:extent", "dcterms:identifier", "dcterms:subject",
+		"dcterms:title"}, supportedPredicates())
+}
@@ -0,0 +1,219 @@
+// Provides helpers that retrieve the RDF (JSON-LD) serialization of nodes, which the Fedora and triplestore indexers
+// consume, so that tests may verify the RDF mapping of a repository object, e.g. that its dcterms:title is its title.
+//
+// The serialization of a node is retrieved from its canonical url (see model.CanonicalUrlErr) with the query
+// `?_format=jsonld`, using the Config loaded by env.Load.
+package jsonld
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/jhu-idc/idc-golang/drupal/model"
+	"github.com/stretchr/testify/require"
+)
+
+// The namespaces of the compact IRIs (e.g. `dcterms:title`) accepted by Graph.Values
+var prefixes = map[string]string{
+	"dc":       "http://purl.org/dc/elements/1.1/",
+	"dcterms":  "http://purl.org/dc/terms/",
+	"pcdm":     "http://pcdm.org/models#",
+	"rdf":      "http://www.w3.org/1999/02/22-rdf-syntax-ns#",
+	"rdfs":     "http://www.w3.org/2000/01/rdf-schema#",
+	"relators": "http://id.loc.gov/vocabulary/relators/",
+	"schema":   "http://schema.org/",
+}
+
+// The predicates whose values label the other nodes of a graph, in order of preference
+var labelPredicates = []string{"http://schema.org/name", "http://www.w3.org/2000/01/rdf-schema#label",
+	"http://purl.org/dc/terms/title"}
+
+// Graph is the RDF serialization of a node: the values of each predicate of the node's subject
+type Graph struct {
+	// The absolute IRI of the node's subject, e.g. `https://islandora.example.edu/node/12?_format=jsonld`
+	Subject string
+	// The values of each predicate of the subject, keyed by the absolute IRI of the predicate.  A literal is answered
+	// as its lexical form, regardless of its datatype or language, and a resource as its absolute IRI.
+	Predicates map[string][]string
+	// The label of each other subject of the graph that carries one (e.g. the schema:name of a taxonomy term), keyed by
+	// its absolute IRI
+	Labels map[string]string
+}
+
+// Values answers the values of the predicate, which may be an absolute IRI or a compact IRI with a well-known prefix
+// (e.g. `dcterms:title`)
+func (g Graph) Values(predicate string) []string {
+	return g.Predicates[expand(predicate)]
+}
+
+// LabeledValues behaves as Values, but answers the label of each resource that the graph labels in place of its IRI,
+// e.g. the name of each subject term
+func (g Graph) LabeledValues(predicate string) []string {
+	var values []string
+	for _, v := range g.Values(predicate) {
+		if label, ok := g.Labels[v]; ok {
+			v = label
+		}
+		values = append(values, v)
+	}
+	return values
+}
+
+// FetchJsonLd retrieves the JSON-LD serialization of the repository object or collection with the uuid, and answers
+// the graph of its subject, failing the test immediately if it cannot:
+//
+//	graph := jsonld.FetchJsonLd(t, obj.JsonApiData[0].Id)
+//	assert.Equal(t, []string{"Moonrise"}, graph.Values("dcterms:title"))
+func FetchJsonLd(t *testing.T, nodeUuid string) Graph {
+	g, err := FetchJsonLdErr(nodeUuid)
+	require.Nil(t, err, "%s", err)
+	return g
+}
+
+// FetchJsonLdErr behaves as FetchJsonLd, but answers an error instead of failing the test
+func FetchJsonLdErr(nodeUuid string) (Graph, error) {
+	c, err := env.Load()
+	if err != nil {
+		return Graph{}, err
+	}
+	canonical, err := model.CanonicalUrlErr(nodeUuid, model.WithConfig(&c))
+	if err != nil {
+		return Graph{}, err
+	}
+	u := canonical + "?_format=jsonld"
+
+	body, err := getErr(c, u)
+	if err != nil {
+		return Graph{}, err
+	}
+	g, err := parseGraph(body, u)
+	if err != nil {
+		return Graph{}, fmt.Errorf("jsonld: unable to parse the JSON-LD of %s: %w", env.RedactUrl(u), err)
+	}
+	return g, nil
+}
+
+// parseGraph parses the JSON-LD document retrieved from the url, answering the graph of the subject identified by the
+// url.  Subjects are matched by path, ignoring their query, so that relative IRIs, and IRIs using another host name
+// (e.g. that of a proxy), identify the same subject.
+func parseGraph(body []byte, documentUrl string) (Graph, error) {
+	base, err := url.Parse(documentUrl)
+	if err != nil {
+		return Graph{}, err
+	}
+
+	var nodes []map[string]json.RawMessage
+	doc := struct {
+		Graph []map[string]json.RawMessage `json:"@graph"`
+	}{}
+	if err := json.Unmarshal(body, &doc); err == nil && doc.Graph != nil {
+		nodes = doc.Graph
+	} else if err := json.Unmarshal(body, &nodes); err != nil {
+		single := map[string]json.RawMessage{}
+		if err := json.Unmarshal(body, &single); err != nil {
+			return Graph{}, err
+		}
+		nodes = []map[string]json.RawMessage{single}
+	}
+
+	g := Graph{Labels: map[string]string{}}
+	var found bool
+	for _, node := range nodes {
+		var id string
+		if err := json.Unmarshal(node["@id"], &id); err != nil {
+			continue
+		}
+		subject := resolve(base, id)
+		predicates := map[string][]string{}
+		for name, raw := range node {
+			if strings.HasPrefix(name, "@") {
+				continue
+			}
+			predicates[expand(name)] = values(base, raw)
+		}
+
+		if samePath(base, subject) {
+			g.Subject, g.Predicates, found = subject, predicates, true
+			continue
+		}
+		for _, p := range labelPredicates {
+			if len(predicates[p]) > 0 {
+				g.Labels[subject] = predicates[p][0]
+				break
+			}
+		}
+	}
+	if !found {
+		return Graph{}, fmt.Errorf("no subject of the graph is %s", base.Path)
+	}
+	return g, nil
+}
+
+// values answers the values of a predicate: the lexical form of each literal, and the absolute IRI of each resource
+func values(base *url.URL, raw json.RawMessage) []string {
+	var elements []json.RawMessage
+	if err := json.Unmarshal(raw, &elements); err != nil {
+		elements = []json.RawMessage{raw}
+	}
+
+	var result []string
+	for _, e := range elements {
+		var object struct {
+			Id    *string     `json:"@id"`
+			Value interface{} `json:"@value"`
+		}
+		var plain interface{}
+		switch {
+		case json.Unmarshal(e, &object) == nil && object.Id != nil:
+			result = append(result, resolve(base, *object.Id))
+		case object.Value != nil:
+			result = append(result, fmt.Sprint(object.Value))
+		case json.Unmarshal(e, &plain) == nil && plain != nil:
+			result = append(result, fmt.Sprint(plain))
+		}
+	}
+	return result
+}
+
+// expand answers the absolute IRI of a compact IRI with a well-known prefix, or the IRI unchanged
+func expand(iri string) string {
+	if prefix, local, ok := strings.Cut(iri, ":"); ok {
+		if ns, ok := prefixes[prefix]; ok && !strings.HasPrefix(local, "//") {
+			return ns + local
+		}
+	}
+	return iri
+}
+
+// resolve answers the IRI resolved against the base, so that relative IRIs become absolute
+func resolve(base *url.URL, iri string) string {
+	u, err := url.Parse(iri)
+	if err != nil {
+		return iri
+	}
+	return base.ResolveReference(u).String()
+}
+
+// samePath answers whether the IRI has the path of the base
+func samePath(base *url.URL, iri string) bool {
+	u, err := url.Parse(iri)
+	return err == nil && strings.TrimSuffix(u.Path, "/") == strings.TrimSuffix(base.Path, "/")
+}
+
+// getErr retrieves the url, authenticated by the credentials of the Config (if any), and answers the body of the
+// response
+func getErr(c env.Config, u string) ([]byte, error) {
+	res, err := jsonapi.NewClient(c).Do(context.Background(), http.MethodGet, u, nil,
+		http.Header{"Accept": {"application/ld+json"}})
+	if err != nil {
+		return nil, err
+	}
+	return res.Body, nil
+}
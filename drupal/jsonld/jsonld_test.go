@@ -0,0 +1,90 @@
+package jsonld
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The JSON-LD of node 12, whose subject IRI is formatted by %[1]s (e.g. absolute or relative), as serialized by
+// Islandora: a graph of the node and the resources it references
+const nodeJsonLd = `{"@graph": [
+	{"@id": "%[1]s/node/12?_format=jsonld", "@type": ["http://pcdm.org/models#Object"],
+		"http://purl.org/dc/terms/title": [{"@value": "Moonrise", "@language": "en"}],
+		"http://purl.org/dc/terms/created": [{"@value": "1941", "@type": "http://id.loc.gov/datatypes/edtf/EDTF"}],
+		"http://purl.org/dc/terms/extent": ["1 photograph"],
+		"http://schema.org/position": [{"@value": 3}],
+		"http://purl.org/dc/terms/subject": [{"@id": "%[1]s/taxonomy/term/5?_format=jsonld"},
+			{"@id": "/taxonomy/term/6?_format=jsonld"}]},
+	{"@id": "%[1]s/taxonomy/term/5?_format=jsonld", "http://schema.org/name": [{"@value": "Photography"}]}
+]}`
+
+// newJsonLdServer answers a server serving the JSON-LD of node 12, whose uuid is `1`, with subject IRIs prefixed by
+// the prefix, and the JSON:API resources of the node and its subject terms, and configures the environment to use it
+func newJsonLdServer(t *testing.T, prefix string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.URL.Query().Get("filter[id]")
+		switch {
+		case r.URL.Path == "/node/12" && r.URL.Query().Get("_format") == "jsonld":
+			_, _ = fmt.Fprintf(w, nodeJsonLd, prefix)
+		case r.URL.Path == "/jsonapi/node/islandora_object" && id == "1":
+			_, _ = w.Write([]byte(`{"data": [{"type": "node--islandora_object", "id": "1",
+				"attributes": {"drupal_internal__nid": 12}}]}`))
+		case strings.HasPrefix(r.URL.Path, "/jsonapi/taxonomy_term/subject/"):
+			_, _ = fmt.Fprintf(w, `{"data": {"type": "taxonomy_term--subject", "id": "%s",
+				"attributes": {"name": "Photography"}}}`, strings.TrimPrefix(r.URL.Path, "/jsonapi/taxonomy_term/subject/"))
+		default:
+			_, _ = w.Write([]byte(`{"data": []}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	t.Cleanup(func() { _, _ = env.Reload() })
+	t.Setenv("DRUPAL_BASE_URL", server.URL)
+	_, err := env.Reload()
+	require.Nil(t, err, "%s", err)
+	return server
+}
+
+func Test_FetchJsonLd(t *testing.T) {
+	for name, prefix := range map[string]string{"absolute": "https://islandora.example.edu", "relative": ""} {
+		t.Run(name, func(t *testing.T) {
+			server := newJsonLdServer(t, prefix)
+
+			g := FetchJsonLd(t, "1")
+			if prefix == "" {
+				prefix = server.URL
+			}
+			assert.Equal(t, prefix+"/node/12?_format=jsonld", g.Subject)
+			assert.Equal(t, []string{"Moonrise"}, g.Values("dcterms:title"))
+			assert.Equal(t, []string{"1941"}, g.Values("http://purl.org/dc/terms/created"))
+			assert.Equal(t, []string{"1 photograph"}, g.Values("dcterms:extent"))
+			assert.Equal(t, []string{"3"}, g.Values("schema:position"))
+			assert.Equal(t, []string{prefix + "/taxonomy/term/5?_format=jsonld",
+				server.URL + "/taxonomy/term/6?_format=jsonld"}, g.Values("dcterms:subject"))
+			assert.Equal(t, []string{"Photography", server.URL + "/taxonomy/term/6?_format=jsonld"},
+				g.LabeledValues("dcterms:subject"))
+		})
+	}
+}
+
+func Test_parseGraph(t *testing.T) {
+	g, err := parseGraph([]byte(`{"@id": "/node/12", "dcterms:title": "Moonrise"}`), "http://localhost/node/12")
+	assert.Nil(t, err, "%s", err)
+	assert.Equal(t, []string{"Moonrise"}, g.Values("http://purl.org/dc/terms/title"))
+
+	_, err = parseGraph([]byte(`[{"@id": "/node/13"}]`), "http://localhost/node/12")
+	assert.Contains(t, fmt.Sprint(err), "no subject of the graph is /node/12")
+}
+
+func Test_expand(t *testing.T) {
+	assert.Equal(t, "http://purl.org/dc/terms/title", expand("dcterms:title"))
+	assert.Equal(t, "http://purl.org/dc/terms/title", expand("http://purl.org/dc/terms/title"))
+	assert.Equal(t, "moo:title", expand("moo:title"))
+}
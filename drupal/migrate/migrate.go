@@ -0,0 +1,160 @@
+// Provides helpers that run Drupal migrations from tests, and wait for them to complete, so that a suite verifying
+// migrated content need not assume that migrations started out-of-band (e.g. by CI) have finished.
+//
+// Migrations are run using the migration endpoints exposed by the IDC site, rooted at the path named by the environment
+// variable 'IDC_MIGRATE_PATH' (by default `/idc/migrate`):
+//
+//	POST {base url}/idc/migrate/{migration id}/import    starts importing the migration
+//	POST {base url}/idc/migrate/{migration id}/rollback  starts rolling back the migration
+//	GET  {base url}/idc/migrate/{migration id}           answers the Status of the migration
+//
+// Requests are issued using the Config loaded by env.Load, and must be authenticated.
+package migrate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/jhu-idc/idc-golang/drupal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	migratePath        = "IDC_MIGRATE_PATH"
+	defaultMigratePath = "/idc/migrate"
+)
+
+// The state of a migration that is not running
+const Idle = "idle"
+
+// Status describes the state of a migration, and the number of its source rows in each outcome
+type Status struct {
+	// The id of the migration, e.g. `idc_ingest_new_items`
+	Id string `json:"id"`
+	// The state of the migration, e.g. Idle, `importing`, or `rolling_back`
+	State string `json:"status"`
+	// The number of rows in the source of the migration
+	Total int `json:"total"`
+	// The number of rows imported
+	Imported int `json:"imported"`
+	// The number of rows updated by the last run
+	Updated int `json:"updated"`
+	// The number of rows that failed to import
+	Failed int `json:"failed"`
+	// The number of rows not yet processed
+	Unprocessed int `json:"unprocessed"`
+	// The messages recorded for the failed rows, if any
+	Messages []string `json:"messages"`
+}
+
+// IsIdle answers whether the migration is not running
+func (s Status) IsIdle() bool {
+	return strings.EqualFold(s.State, Idle)
+}
+
+// String answers the counts of the migration, e.g. `idc_ingest_new_items (idle): 412 of 412 rows imported, 0 updated,
+// 0 failed, 0 unprocessed`
+func (s Status) String() string {
+	return fmt.Sprintf("%s (%s): %d of %d rows imported, %d updated, %d failed, %d unprocessed", s.Id, s.State,
+		s.Imported, s.Total, s.Updated, s.Failed, s.Unprocessed)
+}
+
+// RunMigration starts importing the migration, failing the test immediately if it cannot be started.  The import runs
+// asynchronously; use WaitForMigration to wait for it to complete:
+//
+//	migrate.RunMigration(t, "idc_ingest_new_items")
+//	status := migrate.WaitForMigration(t, "idc_ingest_new_items", 10*time.Minute)
+//	assert.Equal(t, 412, status.Imported)
+func RunMigration(t *testing.T, migrationId string) {
+	err := RunMigrationErr(migrationId)
+	require.Nil(t, err, "%s", err)
+}
+
+// RunMigrationErr behaves as RunMigration, but answers an error instead of failing the test
+func RunMigrationErr(migrationId string) error {
+	_, err := sendErr(http.MethodPost, migrationId, "import")
+	return err
+}
+
+// StatusErr answers the current Status of the migration
+func StatusErr(migrationId string) (Status, error) {
+	body, err := sendErr(http.MethodGet, migrationId, "")
+	if err != nil {
+		return Status{}, err
+	}
+
+	s := Status{}
+	if err := json.Unmarshal(body, &s); err != nil {
+		return Status{}, fmt.Errorf("migrate: unable to unmarshal the status of migration %s: %w", migrationId, err)
+	}
+	if s.Id == "" {
+		s.Id = migrationId
+	}
+	return s, nil
+}
+
+// WaitForMigration polls the status of the migration until it is idle, and answers its final Status, so that its
+// counts may be asserted.  The test fails if the migration is not idle before the timeout, reporting its last status,
+// or if any row failed to import, reporting the number of failed rows and their messages.  The interval between polls
+// defaults to two seconds, and may be overridden by the environment variable 'IDC_WAIT_INTERVAL'.
+func WaitForMigration(t *testing.T, migrationId string, timeout time.Duration) Status {
+	last, idle := waitForIdle(t, migrationId, timeout)
+	if idle {
+		assert.Equal(t, 0, last.Failed, "migration %s: %d rows failed:\n\t%s", last, last.Failed,
+			strings.Join(last.Messages, "\n\t"))
+	}
+	return last
+}
+
+// waitForIdle polls the status of the migration until it is idle, answering its last Status, and whether it became
+// idle before the timeout.  The test fails if it did not.
+func waitForIdle(t *testing.T, migrationId string, timeout time.Duration) (Status, bool) {
+	var last Status
+	idle := model.WaitFor(t, timeout, model.WaitInterval(t), func() (bool, error) {
+		s, err := StatusErr(migrationId)
+		if err != nil {
+			return false, err
+		}
+		last = s
+		if !s.IsIdle() {
+			return false, fmt.Errorf("migration %s", s)
+		}
+		return true, nil
+	})
+	return last, idle
+}
+
+// sendErr sends a request for the operation (e.g. `import`) of the migration using the method, authenticated by the
+// credentials of the Config, and answers the body of the response.  An empty operation requests the migration itself.
+func sendErr(method, migrationId, operation string) ([]byte, error) {
+	c, err := env.Load()
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(c.Credentials.Username) == "" {
+		return nil, fmt.Errorf("migrate: migration %s requires credentials: set DRUPAL_USERNAME and DRUPAL_PASSWORD",
+			migrationId)
+	}
+
+	u := strings.TrimSuffix(c.BaseUrl, "/") + "/" + strings.Trim(env.StringOr(migratePath, defaultMigratePath), "/") +
+		"/" + url.PathEscape(migrationId)
+	if operation != "" {
+		u += "/" + operation
+	}
+
+	res, err := jsonapi.NewClient(c).Do(context.Background(), method, u, nil,
+		http.Header{"Accept": {"application/json"}})
+	if err != nil {
+		return nil, err
+	}
+	return res.Body, nil
+}
@@ -0,0 +1,109 @@
+package migrate
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// A fake site running a single migration, which imports its rows over the supplied number of status polls, and serves
+// a repository object while the migration's rows are imported
+type fakeSite struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []string
+	status   Status
+	// the number of status polls before an import completes
+	polls int
+	// the status of the migration once an import completes
+	done Status
+}
+
+// newFakeSite answers a fake site, and configures the environment to use it
+func newFakeSite(t *testing.T, polls int, done Status) *fakeSite {
+	s := &fakeSite{status: Status{Id: done.Id, State: Idle}, polls: polls, done: done}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+
+	t.Cleanup(func() { _, _ = env.Reload() })
+	t.Setenv("DRUPAL_BASE_URL", s.URL)
+	t.Setenv("DRUPAL_USERNAME", "admin")
+	t.Setenv("DRUPAL_PASSWORD", "moo")
+	t.Setenv("IDC_WAIT_INTERVAL", "1ms")
+	_, err := env.Reload()
+	require.Nil(t, err, "%s", err)
+	return s
+}
+
+func (s *fakeSite) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, fmt.Sprintf("%s %s", r.Method, r.URL.Path))
+	if username, _, ok := r.BasicAuth(); !ok || username != "admin" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	switch fmt.Sprintf("%s %s", r.Method, r.URL.Path) {
+	case "POST /idc/migrate/" + s.done.Id + "/import":
+		s.status.State = "importing"
+		w.WriteHeader(http.StatusAccepted)
+	case "POST /idc/migrate/" + s.done.Id + "/rollback":
+		s.status.State = "rolling_back"
+		w.WriteHeader(http.StatusAccepted)
+	case "GET /idc/migrate/" + s.done.Id:
+		switch s.status.State {
+		case "importing":
+			if s.polls--; s.polls < 0 {
+				s.status = s.done
+			}
+		case "rolling_back":
+			s.status = Status{Id: s.done.Id, State: Idle, Total: s.done.Total, Unprocessed: s.done.Total}
+		}
+		body, _ := json.Marshal(s.status)
+		_, _ = w.Write(body)
+	case "GET /jsonapi/node/islandora_object":
+		// the imported repository object exists until the migration is rolled back
+		if s.status.Imported > 0 {
+			_, _ = w.Write([]byte(`{"data": [{"type": "node--islandora_object", "id": "1"}]}`))
+		} else {
+			_, _ = w.Write([]byte(`{"data": []}`))
+		}
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func Test_RunAndWaitForMigration(t *testing.T) {
+	site := newFakeSite(t, 2, Status{Id: "idc_ingest_new_items", State: Idle, Total: 412, Imported: 412})
+
+	RunMigration(t, "idc_ingest_new_items")
+	status := WaitForMigration(t, "idc_ingest_new_items", time.Second)
+	assert.Equal(t, 412, status.Imported)
+	assert.Equal(t, 0, status.Failed)
+	assert.Equal(t, "idc_ingest_new_items (idle): 412 of 412 rows imported, 0 updated, 0 failed, 0 unprocessed",
+		status.String())
+	assert.Equal(t, "POST /idc/migrate/idc_ingest_new_items/import", site.requests[0])
+	assert.Equal(t, 4, len(site.requests), "expected the status to be polled until the migration is idle")
+}
+
+func Test_MigrationErrors(t *testing.T) {
+	newFakeSite(t, 0, Status{Id: "idc_ingest_new_items", State: Idle})
+
+	err := RunMigrationErr("unknown")
+	assert.Contains(t, fmt.Sprint(err), "404 status encountered sending POST")
+
+	t.Setenv("DRUPAL_USERNAME", "")
+	_, err = env.Reload()
+	require.Nil(t, err, "%s", err)
+	_, err = StatusErr("idc_ingest_new_items")
+	assert.Contains(t, fmt.Sprint(err), "requires credentials")
+}
@@ -0,0 +1,95 @@
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Answered (wrapped) when a destructive operation is requested, but the environment variable 'IDC_ALLOW_DESTRUCTIVE'
+// is not `true`
+var ErrDestructiveNotAllowed = errors.New("migrate: destructive operations are not allowed: set IDC_ALLOW_DESTRUCTIVE=true")
+
+// RollbackMigration rolls back the migration, waits for the rollback to complete, and confirms that the entity
+// answered by gone (an entity imported by the migration, e.g. a repository object filtered by its title) no longer
+// exists.  Answers the Status of the migration after the rollback.  Rolling back deletes content, so the test fails
+// immediately, without contacting the site, unless the environment variable 'IDC_ALLOW_DESTRUCTIVE' is `true`; it is
+// never to be set for a production site.
+func RollbackMigration(t *testing.T, migrationId string, gone jsonapi.JsonApiUrl, timeout time.Duration) Status {
+	status, _ := rollback(t, migrationId, gone, timeout)
+	return status
+}
+
+// rollback behaves as RollbackMigration, but also answers whether the rollback completed before the timeout, and the
+// entity answered by gone no longer exists
+func rollback(t *testing.T, migrationId string, gone jsonapi.JsonApiUrl, timeout time.Duration) (Status, bool) {
+	err := RollbackMigrationErr(migrationId)
+	require.Nil(t, err, "%s", err)
+
+	status, idle := waitForIdle(t, migrationId, timeout)
+	if !idle {
+		return status, false
+	}
+
+	gone.T = t
+	err = gone.GetSingleErr(&struct{ Data []interface{} }{})
+	if errors.Is(err, jsonapi.ErrNotFound) {
+		return status, true
+	}
+	return status, assert.Fail(t, fmt.Sprintf("migration %s was rolled back, but %s is expected to be gone: %s",
+		migrationId, gone.String(), describePresent(err)))
+}
+
+// RollbackMigrationErr starts rolling back the migration, answering ErrDestructiveNotAllowed unless destructive
+// operations are allowed (see RollbackMigration).  The rollback runs asynchronously.
+func RollbackMigrationErr(migrationId string) error {
+	if err := allowDestructiveErr(fmt.Sprintf("roll back migration %s", migrationId)); err != nil {
+		return err
+	}
+	_, err := sendErr(http.MethodPost, migrationId, "rollback")
+	return err
+}
+
+// ReRun rolls back the migration (see RollbackMigration), imports it again, and waits for the import to complete,
+// answering its final Status, e.g. to verify the behavior of a migration that updates existing content.  The test
+// stops without importing if the rollback did not complete, or left the entity answered by gone:
+//
+//	u := model.Query(t, model.Node, model.RepositoryObject)
+//	u.Filter, u.Value = "title", "Moonrise"
+//	status := migrate.ReRun(t, "idc_ingest_new_items", u, 10*time.Minute)
+//	assert.Equal(t, 412, status.Imported)
+func ReRun(t *testing.T, migrationId string, gone jsonapi.JsonApiUrl, timeout time.Duration) Status {
+	if _, ok := rollback(t, migrationId, gone, timeout); !ok {
+		require.FailNow(t, fmt.Sprintf("unable to re-run migration %s: its rollback failed", migrationId))
+	}
+	RunMigration(t, migrationId)
+	return WaitForMigration(t, migrationId, timeout)
+}
+
+// allowDestructiveErr answers ErrDestructiveNotAllowed, describing the operation, unless destructive operations are
+// allowed
+func allowDestructiveErr(operation string) error {
+	allowed, err := env.AllowDestructiveOr(false)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return fmt.Errorf("%w: refusing to %s", ErrDestructiveNotAllowed, operation)
+	}
+	return nil
+}
+
+// describePresent describes the outcome of retrieving an entity expected to be gone
+func describePresent(err error) string {
+	if err == nil {
+		return "it still exists"
+	}
+	return err.Error()
+}
@@ -0,0 +1,61 @@
+package migrate
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/stretchr/testify/assert"
+)
+
+// importedObject answers a url of the repository object imported by the migration of the site
+func importedObject(site *fakeSite) jsonapi.JsonApiUrl {
+	return jsonapi.JsonApiUrl{BaseUrl: site.URL, ExplicitBaseUrl: true, DrupalEntity: "node",
+		DrupalBundle: "islandora_object", Filter: "title", Value: "Moonrise", Username: "admin", Password: "moo"}
+}
+
+func Test_ReRun(t *testing.T) {
+	site := newFakeSite(t, 1, Status{Id: "idc_ingest_new_items", State: Idle, Total: 412, Imported: 412})
+	site.status = site.done
+	t.Setenv("IDC_ALLOW_DESTRUCTIVE", "true")
+
+	status := ReRun(t, "idc_ingest_new_items", importedObject(site), time.Second)
+	assert.Equal(t, 412, status.Imported)
+	assert.Equal(t, []string{
+		"POST /idc/migrate/idc_ingest_new_items/rollback",
+		"GET /idc/migrate/idc_ingest_new_items",
+		"GET /jsonapi/node/islandora_object",
+		"POST /idc/migrate/idc_ingest_new_items/import",
+		"GET /idc/migrate/idc_ingest_new_items",
+		"GET /idc/migrate/idc_ingest_new_items",
+	}, site.requests)
+}
+
+func Test_RollbackMigrationWithConfig(t *testing.T) {
+	site := newFakeSite(t, 0, Status{Id: "idc_ingest_new_items", State: Idle, Total: 412, Imported: 412})
+	site.status = site.done
+	t.Setenv("IDC_ALLOW_DESTRUCTIVE", "true")
+
+	gone := jsonapi.JsonApiUrl{Config: &env.Config{BaseUrl: site.URL, Credentials: env.Credentials{Username: "admin",
+		Password: "moo"}}, DrupalEntity: "node", DrupalBundle: "islandora_object", Filter: "title", Value: "Moonrise"}
+	status := RollbackMigration(t, "idc_ingest_new_items", gone, time.Second)
+	assert.Equal(t, 412, status.Unprocessed)
+	assert.Contains(t, site.requests, "GET /jsonapi/node/islandora_object")
+}
+
+func Test_RollbackRequiresAllowDestructive(t *testing.T) {
+	site := newFakeSite(t, 0, Status{Id: "idc_ingest_new_items", State: Idle})
+
+	err := RollbackMigrationErr("idc_ingest_new_items")
+	assert.True(t, errors.Is(err, ErrDestructiveNotAllowed), "expected ErrDestructiveNotAllowed, got %v", err)
+
+	t.Setenv("IDC_ALLOW_DESTRUCTIVE", "false")
+	err = RollbackMigrationErr("idc_ingest_new_items")
+	assert.True(t, errors.Is(err, ErrDestructiveNotAllowed), "expected ErrDestructiveNotAllowed, got %v", err)
+
+	t.Setenv("IDC_ALLOW_DESTRUCTIVE", "yes please")
+	assert.NotNil(t, RollbackMigrationErr("idc_ingest_new_items"))
+	assert.Empty(t, site.requests, "no request is expected unless destructive operations are allowed")
+}
@@ -0,0 +1,97 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// The identifier Drupal answers for the parent of a root taxonomy term
+const virtualParentId = "virtual"
+
+// AccessTermNames resolves the field_access_terms of the first repository object in the response, and answers the
+// sorted names of the terms and of their ancestors (see accessTermNamesErr)
+func (obj JsonApiIslandoraObj) AccessTermNames(t *testing.T) []string {
+	if !assert.NotEmpty(t, obj.JsonApiData, "unable to resolve access terms of an empty repository object response") {
+		return nil
+	}
+	return accessTermNames(t, obj.JsonApiData[0].JsonApiRelationships.AccessTerms.Data)
+}
+
+// AccessTermNames resolves the field_access_terms of the first collection in the response, and answers the sorted
+// names of the terms and of their ancestors (see accessTermNamesErr)
+func (c JsonApiCollection) AccessTermNames(t *testing.T) []string {
+	if !assert.NotEmpty(t, c.JsonApiData, "unable to resolve access terms of an empty collection response") {
+		return nil
+	}
+	return accessTermNames(t, c.JsonApiData[0].JsonApiRelationships.AccessTerms.Data)
+}
+
+// AccessTermNames resolves the field_access_terms of the media, and answers the sorted names of the terms and of their
+// ancestors (see accessTermNamesErr)
+func (mr JsonApiMediaRelationships) AccessTermNames(t *testing.T) []string {
+	return accessTermNames(t, mr.AccessTerms.Data)
+}
+
+// AssertAccessTerms asserts that the access terms of the supplied entity, including the ancestors of each term, are
+// exactly the expected names, in any order.  The entity may be a JsonApiIslandoraObj, a JsonApiCollection, any Media,
+// or a pointer to one of those.
+//
+//	model.AssertAccessTerms(t, obj, "Staff Only")
+func AssertAccessTerms(t *testing.T, entity interface{}, expected ...string) bool {
+	var terms []JsonApiData
+	switch e := entity.(type) {
+	case *JsonApiIslandoraObj:
+		return AssertAccessTerms(t, *e, expected...)
+	case *JsonApiCollection:
+		return AssertAccessTerms(t, *e, expected...)
+	case JsonApiIslandoraObj:
+		return assert.ElementsMatch(t, expected, e.AccessTermNames(t), "unexpected access terms")
+	case JsonApiCollection:
+		return assert.ElementsMatch(t, expected, e.AccessTermNames(t), "unexpected access terms")
+	case Media:
+		terms = e.AccessTerms()
+	default:
+		return assert.Fail(t, fmt.Sprintf("%T does not carry a field_access_terms relationship", entity))
+	}
+	return assert.ElementsMatch(t, expected, accessTermNames(t, terms), "unexpected access terms")
+}
+
+// accessTermNames behaves as accessTermNamesErr, but asserts that no error occurs
+func accessTermNames(t *testing.T, terms []JsonApiData) []string {
+	names, err := accessTermNamesErr(terms)
+	assert.Nil(t, err, "%s", err)
+	return names
+}
+
+// accessTermNamesErr resolves each access term, and each of its ancestors in turn, answering the sorted, distinct names
+// of the resolved terms.  Terms are resolved using the term cache.
+func accessTermNamesErr(terms []JsonApiData) ([]string, error) {
+	visited := map[string]bool{}
+	names := map[string]bool{}
+
+	for len(terms) > 0 {
+		term := terms[0]
+		terms = terms[1:]
+		if term.Id == virtualParentId || visited[term.Id] {
+			continue
+		}
+		visited[term.Id] = true
+
+		res, err := resolveCachedErr[JsonApiIslandoraAccessTerms](term)
+		if err != nil {
+			return nil, fmt.Errorf("model: unable to resolve access term: %w", err)
+		}
+		names[res.JsonApiData[0].JsonApiAttributes.Name] = true
+		terms = append(terms, res.JsonApiData[0].JsonApiRelationships.AccessTerms.Data...)
+	}
+
+	var result []string
+	for name := range names {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result, nil
+}
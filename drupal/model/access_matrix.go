@@ -0,0 +1,187 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/stretchr/testify/assert"
+)
+
+// The role identity of unauthenticated requests in an access matrix
+const AnonymousRole = "anonymous"
+
+// The outcome of retrieving an entity, and the file of a media, as a single role identity
+type accessCell struct {
+	role     string
+	expected bool
+	// the errors retrieving the entity, and the file of a media; nil if retrieved
+	entityErr, fileErr error
+	// whether the entity carries a file that was requested
+	hasFile bool
+}
+
+// visible answers whether the role could retrieve the entity, and its file if any
+func (c accessCell) visible() bool {
+	return c.entityErr == nil && (!c.hasFile || c.fileErr == nil)
+}
+
+// failed answers whether the outcome differs from the expectation, or could not be determined, e.g. because of a
+// network error
+func (c accessCell) failed() bool {
+	return c.visible() != c.expected || !isAccessOutcome(c.entityErr) || !isAccessOutcome(c.fileErr)
+}
+
+// String answers the expected and actual outcome of the cell, e.g.
+// `anonymous: expected denied, actual: entity visible, file forbidden (401/403)`
+func (c accessCell) String() string {
+	s := fmt.Sprintf("%s: expected %s, actual: entity %s", c.role, describeExpectedAccess(c.expected),
+		describeAccess(c.entityErr))
+	if c.hasFile {
+		s += ", file " + describeAccess(c.fileErr)
+	}
+	if c.failed() {
+		s += "  <-- MISMATCH"
+	}
+	return s
+}
+
+// AssertAccessMatrix asserts, for each role identity of the matrix, whether a user holding the role can retrieve the
+// entity, and, if the entity is a media, the content of its file.  Each key is AnonymousRole or a role whose
+// credentials are read from the environment by env.CredentialsFor (e.g. `collection_creator`), and each value is
+// whether the role is expected to see the entity.  Each cell is checked concurrently, and the outcome of every cell is
+// reported, distinguishing a resource that is absent (a 404 status, or no data) from one that is forbidden (a 401 or
+// 403 status).  A cell whose outcome cannot be determined (e.g. because of a network error) always fails:
+//
+//	model.AssertAccessMatrix(t, media.JsonApiData[0].JsonApiData, map[string]bool{
+//		model.AnonymousRole: false,
+//		"collection_creator": true,
+//	})
+//
+// The file of a media is found as the user from the environment (or as supplied by the options).
+func AssertAccessMatrix(t *testing.T, entity JsonApiData, matrix map[string]bool, opts ...Option) bool {
+	if err := entity.Validate(); err != nil {
+		return assert.Fail(t, fmt.Sprintf("unable to assert access to %s %s: %s", entity.Type, entity.Id, err))
+	}
+
+	fileUrl, err := accessFileUrlErr(entity, opts)
+	if err != nil {
+		return assert.Fail(t, fmt.Sprintf("unable to assert access to %s %s: %s", entity.Type, entity.Id, err))
+	}
+
+	var roles []string
+	for role := range matrix {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+
+	cells := make([]accessCell, len(roles))
+	wg := sync.WaitGroup{}
+	for i, role := range roles {
+		wg.Add(1)
+		go func(i int, role string) {
+			defer wg.Done()
+			cells[i] = checkAccess(entity, fileUrl, role, matrix[role], opts)
+		}(i, role)
+	}
+	wg.Wait()
+
+	lines := make([]string, len(cells))
+	failed := false
+	for i, c := range cells {
+		lines[i] = c.String()
+		failed = failed || c.failed()
+	}
+	if failed {
+		return assert.Fail(t, fmt.Sprintf("access to %s %s does not match the matrix:\n\t%s", entity.Type, entity.Id,
+			strings.Join(lines, "\n\t")))
+	}
+	t.Logf("access to %s %s matches the matrix:\n\t%s", entity.Type, entity.Id, strings.Join(lines, "\n\t"))
+	return true
+}
+
+// checkAccess retrieves the entity, and the file url if not empty, as the role
+func checkAccess(entity JsonApiData, fileUrl, role string, expected bool, opts []Option) accessCell {
+	c := accessCell{role: role, expected: expected, hasFile: fileUrl != ""}
+
+	username, password := "", ""
+	roleOpts := append([]Option{}, opts...)
+	if role == AnonymousRole {
+		roleOpts = append(roleOpts, WithAnonymous())
+	} else {
+		creds := env.CredentialsFor(role)
+		if err := creds.Validate(); err != nil {
+			c.entityErr, c.fileErr = err, err
+			return c
+		}
+		username, password = creds.Username, creds.Password
+		roleOpts = append(roleOpts, WithUser(creds))
+	}
+
+	u := entity.url("", "", roleOpts...)
+	c.entityErr = u.GetSingleErr(&struct{ Data []JsonApiData }{})
+	if c.hasFile {
+		_, _, c.fileErr = jsonapi.GetResourceErr(fileUrl, username, password)
+	}
+	return c
+}
+
+// accessFileUrlErr answers the absolute url of the file of the entity, if it is a media carrying a file, or the empty
+// string otherwise
+func accessFileUrlErr(entity JsonApiData, opts []Option) (string, error) {
+	bundle, ok := mediaFileFields[entity.Type.Bundle()]
+	if entity.Type.Entity() != MediaEntity || !ok {
+		return "", nil
+	}
+
+	media := struct {
+		Data []struct {
+			Relationships map[string]struct {
+				Data JsonApiData
+			}
+		}
+	}{}
+	u := entity.url(env.UsernameOr(""), env.PasswordOr(""), opts...)
+	if err := u.GetSingleErr(&media); err != nil {
+		return "", fmt.Errorf("model: unable to retrieve the file of the media: %w", err)
+	}
+
+	file, err := ResolveAsErr[JsonApiFile](media.Data[0].Relationships[bundle.field].Data, opts...)
+	if err != nil {
+		return "", fmt.Errorf("model: unable to resolve the file of the media: %w", err)
+	}
+	return fileUrl(file.JsonApiData[0].JsonApiAttributes.Uri.Url)
+}
+
+// isAccessOutcome answers whether the error of a request describes whether the resource is accessible: either it
+// was retrieved, or it was absent or forbidden
+func isAccessOutcome(err error) bool {
+	return err == nil || errors.Is(err, jsonapi.ErrNotFound) || errors.Is(err, jsonapi.ErrForbidden)
+}
+
+// describeExpectedAccess answers `visible` or `denied`
+func describeExpectedAccess(visible bool) string {
+	if visible {
+		return "visible"
+	}
+	return "denied"
+}
+
+// describeAccess answers a readable description of the outcome of a request, e.g. `not found (404 or no data)`
+func describeAccess(err error) string {
+	switch {
+	case err == nil:
+		return "visible"
+	case errors.Is(err, jsonapi.ErrNotFound):
+		return "not found (404 or no data)"
+	case errors.Is(err, jsonapi.ErrForbidden):
+		return "forbidden (401/403)"
+	default:
+		return fmt.Sprintf("error (%s)", err)
+	}
+}
@@ -0,0 +1,74 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/stretchr/testify/assert"
+)
+
+// newRestrictedMediaServer answers a server whose image media, and its private file, may only be seen by the users
+// named by visibleTo
+func newRestrictedMediaServer(t *testing.T, mediaId, fileId string, visibleTo ...string) *httptest.Server {
+	visible := func(r *http.Request) bool {
+		username, _, _ := r.BasicAuth()
+		return containsString(visibleTo, username)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch collectionPath(r) {
+		case "/jsonapi/media/image":
+			if !visible(r) {
+				_, _ = w.Write([]byte(`{"data": []}`))
+				return
+			}
+			_, _ = fmt.Fprintf(w, `{"data": [{"type": "media--image", "id": "%s", "relationships": {
+				"field_media_image": {"data": {"type": "file--file", "id": "%s"}}}}]}`, mediaId, fileId)
+		case "/jsonapi/file/file":
+			_, _ = fmt.Fprintf(w, `{"data": [{"type": "file--file", "id": "%s", "attributes": {
+				"uri": {"url": "/system/files/moo.tiff", "value": "private://moo.tiff"}}}]}`, fileId)
+		case "/system/files/moo.tiff":
+			if !visible(r) {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			_, _ = w.Write([]byte("moo"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	setBaseUrl(t, server)
+	return server
+}
+
+func Test_AssertAccessMatrix(t *testing.T) {
+	newRestrictedMediaServer(t, testUuid(1), testUuid(2), "admin", "staff")
+	t.Setenv("DRUPAL_USERNAME", "admin")
+	t.Setenv("DRUPAL_PASSWORD", "moo")
+	t.Setenv("IDC_STAFF_PASSWORD", "moo")
+
+	media := JsonApiData{Type: "media--image", Id: testUuid(1)}
+	assert.True(t, AssertAccessMatrix(t, media, map[string]bool{AnonymousRole: false, "staff": true}))
+
+	fileUrl, err := accessFileUrlErr(media, nil)
+	assert.Nil(t, err, "%s", err)
+	anonymous := checkAccess(media, fileUrl, AnonymousRole, true, nil)
+	assert.True(t, anonymous.failed())
+	assert.Equal(t, "anonymous: expected visible, actual: entity not found (404 or no data), "+
+		"file forbidden (401/403)  <-- MISMATCH", anonymous.String())
+
+	guest := checkAccess(media, fileUrl, "guest", false, nil)
+	assert.True(t, guest.failed(), "a cell without credentials is expected to fail")
+	assert.Contains(t, guest.String(), "set IDC_GUEST_PASSWORD")
+}
+
+func Test_describeAccess(t *testing.T) {
+	assert.Equal(t, "visible", describeAccess(nil))
+	assert.Equal(t, "not found (404 or no data)", describeAccess(fmt.Errorf("%w: 404", jsonapi.ErrNotFound)))
+	assert.Equal(t, "forbidden (401/403)", describeAccess(fmt.Errorf("%w: 403", jsonapi.ErrForbidden)))
+	assert.Equal(t, "error (connection refused)", describeAccess(errors.New("connection refused")))
+}
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_AccessTerms(t *testing.T) {
+	staffOnly, restricted, public := testUuid(1), testUuid(2), testUuid(3)
+	terms := map[string]string{
+		staffOnly:  fmt.Sprintf(`"attributes": {"name": "Staff Only"}, "relationships": {"parent": {"data": [{"type": "taxonomy_term--islandora_access", "id": "%s"}]}}`, restricted),
+		restricted: `"attributes": {"name": "Restricted"}, "relationships": {"parent": {"data": [{"type": "taxonomy_term--islandora_access", "id": "virtual"}]}}`,
+		public:     `"attributes": {"name": "Public"}, "relationships": {"parent": {"data": [{"type": "taxonomy_term--islandora_access", "id": "virtual"}]}}`,
+	}
+
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		id := requestedId(r)
+		_, _ = fmt.Fprintf(w, `{"data": [{"type": "taxonomy_term--islandora_access", "id": "%s", %s}]}`, id, terms[id])
+	}))
+	defer server.Close()
+	setBaseUrl(t, server)
+
+	obj := JsonApiIslandoraObj{}
+	require.Nil(t, json.Unmarshal([]byte(fmt.Sprintf(`{"data": [{"type": "node--islandora_object", "id": "%s",
+		"relationships": {"field_access_terms": {"data": [{"type": "taxonomy_term--islandora_access", "id": "%s"}]}}}]}`,
+		testUuid(0), staffOnly)), &obj))
+	media := JsonApiImageMedia{}
+	require.Nil(t, json.Unmarshal([]byte(fmt.Sprintf(`{"data": [{"type": "media--image", "id": "%s",
+		"relationships": {"field_access_terms": {"data": [{"type": "taxonomy_term--islandora_access", "id": "%s"},
+		{"type": "taxonomy_term--islandora_access", "id": "%s"}]}}}]}`, testUuid(4), staffOnly, public)), &media))
+
+	assert.Equal(t, []string{"Restricted", "Staff Only"}, obj.AccessTermNames(t))
+	assert.True(t, AssertAccessTerms(t, &obj, "Staff Only", "Restricted"))
+	assert.True(t, AssertAccessTerms(t, media, "Public", "Restricted", "Staff Only"))
+	assert.Equal(t, []string{"Public", "Restricted", "Staff Only"}, media.JsonApiData[0].JsonApiRelationships.AccessTermNames(t))
+
+	// each term is only requested once
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+
+	ResetTermCache()
+	obj.AccessTermNames(t)
+	assert.Equal(t, int32(5), atomic.LoadInt32(&requests))
+}
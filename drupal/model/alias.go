@@ -0,0 +1,125 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/stretchr/testify/require"
+)
+
+// The path of the endpoint of the Decoupled Router module translating a path (e.g. an alias) to the entity it routes to
+const translatePathEndpoint = "/router/translate-path"
+
+// Answered (wrapped) when a path does not route to an entity, e.g. because no such alias exists; it also wraps
+// jsonapi.ErrNotFound
+var ErrAliasNotFound = fmt.Errorf("%w: path alias not found", jsonapi.ErrNotFound)
+
+// Answered (wrapped) when a path is redirected (e.g. by the Redirect module) before it routes to an entity, so that a
+// test expecting the path to be an alias learns of the redirect rather than silently asserting its target
+var ErrAliasRedirected = errors.New("model: path alias redirected")
+
+// PathTranslation is the entity a path routes to, as answered by the `/router/translate-path` endpoint
+type PathTranslation struct {
+	// The absolute url the path resolves to, after any redirects
+	Resolved string `json:"resolved"`
+	// The label of the entity, e.g. the title of a node
+	Label  string `json:"label"`
+	Entity struct {
+		// The canonical url of the entity, e.g. `https://islandora.example.edu/node/12`
+		Canonical string `json:"canonical"`
+		// The entity type, e.g. `node`
+		Type string `json:"type"`
+		// The bundle, e.g. `collection_object`
+		Bundle string `json:"bundle"`
+		// The internal id of the entity, e.g. the node id `12`
+		Id   string `json:"id"`
+		Uuid string `json:"uuid"`
+	} `json:"entity"`
+	// The redirects followed before the path routed to the entity, if any
+	Redirect []struct {
+		From   string      `json:"from"`
+		To     string      `json:"to"`
+		Status json.Number `json:"status"`
+	} `json:"redirect"`
+}
+
+// Data answers the data object identifying the entity, ready to Resolve
+func (pt PathTranslation) Data() JsonApiData {
+	return JsonApiData{Type: jsonapi.NewDrupalType(pt.Entity.Type, pt.Entity.Bundle), Id: pt.Entity.Uuid}
+}
+
+// TranslatePathErr answers the entity the path (e.g. the alias `/collections/sheridan-photos`, or `/node/12`) routes
+// to, following any redirects.  The error wraps ErrAliasNotFound if the path routes to no entity.  The options
+// customize the request, e.g. to translate the path on another site, or as another user.
+func TranslatePathErr(path string, opts ...Option) (PathTranslation, error) {
+	u := strings.TrimSuffix(baseUrlOf(opts), "/") + translatePathEndpoint + "?_format=json&path=" +
+		url.QueryEscape("/"+strings.TrimPrefix(path, "/"))
+
+	jar := query(nil, "", "", opts...)
+	res, err := jar.DoErr(http.MethodGet, u, nil, nil)
+	if errors.Is(err, jsonapi.ErrNotFound) {
+		return PathTranslation{}, fmt.Errorf("%w: %s (%s)", ErrAliasNotFound, path, err)
+	}
+	if err != nil {
+		return PathTranslation{}, fmt.Errorf("model: unable to translate path %s: %w", path, err)
+	}
+
+	pt := PathTranslation{}
+	if err := json.Unmarshal(res.Body, &pt); err != nil {
+		return PathTranslation{}, fmt.Errorf("model: unable to unmarshal the translation of path %s: %w", path, err)
+	}
+	if pt.Entity.Uuid == "" {
+		return PathTranslation{}, fmt.Errorf("%w: %s routes to no entity", ErrAliasNotFound, path)
+	}
+	return pt, nil
+}
+
+// AliasData answers the data object of the entity with the alias, e.g. `/collections/sheridan-photos`, failing the
+// test immediately if the alias does not exist, or is redirected (see AliasDataErr)
+func AliasData(t *testing.T, alias string, opts ...Option) JsonApiData {
+	jad, err := AliasDataErr(alias, opts...)
+	require.Nil(t, err, "%s", err)
+	return jad
+}
+
+// AliasDataErr behaves as AliasData, but answers an error instead of failing the test.  The error wraps
+// ErrAliasNotFound if the alias does not exist, or ErrAliasRedirected, naming the redirect, if it is redirected.
+func AliasDataErr(alias string, opts ...Option) (JsonApiData, error) {
+	pt, err := TranslatePathErr(alias, opts...)
+	if err != nil {
+		return JsonApiData{}, err
+	}
+	if len(pt.Redirect) > 0 {
+		r := pt.Redirect[0]
+		return JsonApiData{}, fmt.Errorf("%w: %s is redirected (%s) from %s to %s", ErrAliasRedirected, alias, r.Status,
+			r.From, r.To)
+	}
+	return pt.Data(), nil
+}
+
+// FindByAlias resolves the entity with the alias into a new JSON API struct of type T (e.g. JsonApiCollection), failing
+// the test immediately if the alias does not exist, is redirected, or cannot be resolved:
+//
+//	col := model.FindByAlias[model.JsonApiCollection](t, "/collections/sheridan-photos")
+func FindByAlias[T any](t *testing.T, alias string, opts ...Option) T {
+	v, err := FindByAliasErr[T](alias, opts...)
+	require.Nil(t, err, "%s", err)
+	return v
+}
+
+// FindByAliasErr behaves as FindByAlias, but answers an error instead of failing the test.  The options apply to both
+// the translation of the alias and the resolution of the entity.
+func FindByAliasErr[T any](alias string, opts ...Option) (T, error) {
+	jad, err := AliasDataErr(alias, opts...)
+	if err != nil {
+		var v T
+		return v, err
+	}
+	return ResolveAsErr[T](jad, opts...)
+}
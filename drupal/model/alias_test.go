@@ -0,0 +1,83 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_FindByAlias(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/router/translate-path" && r.URL.Query().Get("path") == "/collections/sheridan-photos":
+			_, _ = fmt.Fprintf(w, `{"resolved": "http://%[1]s/collections/sheridan-photos", "label": "Sheridan Photos",
+				"entity": {"canonical": "http://%[1]s/node/12", "type": "node", "bundle": "collection_object", "id": "12",
+				"uuid": "%[2]s"}}`, r.Host, testUuid(1))
+		case r.URL.Path == "/router/translate-path" && r.URL.Query().Get("path") == "/collections/old-photos":
+			_, _ = fmt.Fprintf(w, `{"resolved": "http://%[1]s/collections/sheridan-photos",
+				"redirect": [{"from": "/collections/old-photos", "to": "/collections/sheridan-photos", "status": "301"}],
+				"entity": {"type": "node", "bundle": "collection_object", "id": "12", "uuid": "%[2]s"}}`, r.Host,
+				testUuid(1))
+		case r.URL.Path == "/router/translate-path":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message": "Unable to resolve path /collections/moo.", "details": "None of the available methods were able to find a match for this path."}`))
+		case r.URL.Path == "/jsonapi/node/collection_object/"+testUuid(1):
+			_, _ = fmt.Fprintf(w, `{"data": {"type": "node--collection_object", "id": "%s",
+				"attributes": {"title": "Sheridan Photos"}}}`, testUuid(1))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	setBaseUrl(t, server)
+
+	col := FindByAlias[JsonApiCollection](t, "collections/sheridan-photos")
+	require.Len(t, col.JsonApiData, 1)
+	assert.Equal(t, "Sheridan Photos", col.JsonApiData[0].JsonApiAttributes.Title)
+
+	pt, err := TranslatePathErr("/collections/old-photos")
+	require.Nil(t, err, "%s", err)
+	assert.Equal(t, JsonApiData{Type: "node--collection_object", Id: testUuid(1)}, pt.Data())
+	assert.Equal(t, "12", pt.Entity.Id)
+
+	_, err = AliasDataErr("/collections/old-photos")
+	assert.True(t, errors.Is(err, ErrAliasRedirected), "expected ErrAliasRedirected, got %v", err)
+	assert.Contains(t, fmt.Sprint(err), "/collections/sheridan-photos")
+
+	_, err = FindByAliasErr[JsonApiCollection]("/collections/moo")
+	assert.True(t, errors.Is(err, ErrAliasNotFound), "expected ErrAliasNotFound, got %v", err)
+	assert.True(t, errors.Is(err, jsonapi.ErrNotFound), "expected ErrNotFound, got %v", err)
+	assert.False(t, errors.Is(err, ErrAliasRedirected))
+}
+
+func Test_FindByAliasWithOptions(t *testing.T) {
+	var users []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, _, _ := r.BasicAuth()
+		users = append(users, username)
+		switch r.URL.Path {
+		case "/router/translate-path":
+			_, _ = fmt.Fprintf(w, `{"entity": {"type": "node", "bundle": "collection_object", "id": "12",
+				"uuid": "%s"}}`, testUuid(1))
+		case "/jsonapi/node/collection_object/" + testUuid(1):
+			_, _ = fmt.Fprintf(w, `{"data": {"type": "node--collection_object", "id": "%s",
+				"attributes": {"title": "Sheridan Photos"}}}`, testUuid(1))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	t.Setenv("DRUPAL_BASE_URL", "http://127.0.0.1:1")
+
+	col := FindByAlias[JsonApiCollection](t, "/collections/sheridan-photos", WithBaseUrl(server.URL),
+		WithCredentials("moo", "cow"))
+	require.Len(t, col.JsonApiData, 1)
+	assert.Equal(t, "Sheridan Photos", col.JsonApiData[0].JsonApiAttributes.Title)
+	assert.Equal(t, []string{"moo", "moo"}, users, "the alias is expected to be translated as the user of the options")
+}
@@ -0,0 +1,145 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// The mode of an Asserter
+type AssertMode int
+
+const (
+	// Fail the test, and stop it, at the first entity that does not match its expectation
+	Fatal AssertMode = iota
+	// Collect the failures of every entity, and report them together when the test completes
+	Accumulate
+)
+
+// An Asserter reports the failures of the Compare* functions (see WithAsserter), AssertMedia, and AssertMemberOfPath
+// for a test.  In Fatal mode the test is stopped at the first entity that does not match.  In Accumulate mode the
+// failures are collected, so that a single mismatch does not hide the others, and are reported when the test completes
+// with a summary of each entity and its mismatches:
+//
+//	a := model.NewAsserter(t, model.Accumulate)
+//	for _, expected := range expectedObjs {
+//		model.CompareRepoObj(t, expected, model.FindObjectByTitle(t, expected.Title), model.WithAsserter(a))
+//	}
+//
+// An Asserter is safe to use from multiple goroutines.
+type Asserter struct {
+	mode AssertMode
+	// reports the accumulated failures; t.Errorf, unless replaced by a test
+	errorf func(format string, args ...interface{})
+	// reports a failure and stops the test; t.Fatalf, unless replaced by a test
+	fatalf   func(format string, args ...interface{})
+	mu       sync.Mutex
+	failures []entityFailures
+}
+
+// The failures of a single entity, e.g. the mismatched fields of a repository object
+type entityFailures struct {
+	// describes the entity, e.g. `repository object 'Moonrise'`
+	description string
+	failures    []string
+}
+
+// NewAsserter answers an Asserter for the test in the supplied mode.  Accumulated failures are reported when the test
+// and its subtests complete (see Flush).
+func NewAsserter(t *testing.T, mode AssertMode) *Asserter {
+	a := &Asserter{mode: mode, errorf: t.Errorf, fatalf: t.Fatalf}
+	t.Cleanup(a.Flush)
+	return a
+}
+
+// WithAsserter reports the mismatched fields of the compared entity to the Asserter, rather than failing the test
+// directly
+func WithAsserter(a *Asserter) CompareOption {
+	return func(o *compareOptions) {
+		o.asserter = a
+	}
+}
+
+// Flush reports the accumulated failures, if any, as a single test error listing each entity and its failures, and
+// discards them.  Flush is invoked when the test completes, but may be invoked earlier, e.g. at the end of a subtest.
+func (a *Asserter) Flush() {
+	a.mu.Lock()
+	failures := a.failures
+	a.failures = nil
+	a.mu.Unlock()
+
+	if len(failures) == 0 {
+		return
+	}
+
+	summaries := make([]string, len(failures))
+	for i, f := range failures {
+		summaries[i] = f.summary()
+	}
+	a.errorf("%d entities do not match their expectations:\n%s", len(failures), strings.Join(summaries, "\n"))
+}
+
+// AssertMedia behaves as the AssertMedia function, reporting its failure to the Asserter
+func (a *Asserter) AssertMedia(t *testing.T, objUuid string, expected ExpectedMediaAttachment) []Media {
+	matched, failure := assertMedia(t, objUuid, expected)
+	if failure != "" {
+		a.report(fmt.Sprintf("repository object %s", objUuid), []string{failure})
+	}
+	return matched
+}
+
+// AssertMemberOfPath behaves as the AssertMemberOfPath function, reporting its failure to the Asserter
+func (a *Asserter) AssertMemberOfPath(entity interface{}, expected string) bool {
+	title, _ := titleOf(entity)
+	if failure := memberOfPathFailure(entity, expected); failure != "" {
+		return a.report(fmt.Sprintf("'%s'", title), []string{failure})
+	}
+	return true
+}
+
+// report records the failures of the described entity, or stops the test if the Asserter is in Fatal mode.  Answers
+// true if there are no failures.
+func (a *Asserter) report(description string, failures []string) bool {
+	if len(failures) == 0 {
+		return true
+	}
+
+	f := entityFailures{description: description, failures: failures}
+	if a.mode == Fatal {
+		a.fatalf("%s", f.summary())
+		return false
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.failures = append(a.failures, f)
+	return false
+}
+
+// summary answers the description of the entity, the number of failures, and each failure, e.g.
+//
+//	repository object 'Moonrise': 2 mismatches
+//		DateCreated: ...
+func (f entityFailures) summary() string {
+	sb := strings.Builder{}
+	noun := "mismatches"
+	if len(f.failures) == 1 {
+		noun = "mismatch"
+	}
+	sb.WriteString(fmt.Sprintf("%s: %d %s", f.description, len(f.failures), noun))
+	for _, failure := range f.failures {
+		sb.WriteString("\n\t")
+		sb.WriteString(strings.ReplaceAll(failure, "\n", "\n\t"))
+	}
+	return sb.String()
+}
+
+// diffStrings answers the readable form of each of the diffs
+func diffStrings(diffs []FieldDiff) []string {
+	result := make([]string, len(diffs))
+	for i, d := range diffs {
+		result[i] = d.String()
+	}
+	return result
+}
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// capturingAsserter answers an Asserter in the mode whose reports are captured rather than failing the test
+func capturingAsserter(mode AssertMode, reports *[]string) *Asserter {
+	capture := func(format string, args ...interface{}) { *reports = append(*reports, fmt.Sprintf(format, args...)) }
+	return &Asserter{mode: mode, errorf: capture, fatalf: capture}
+}
+
+func Test_AsserterAccumulate(t *testing.T) {
+	obj := compareTestObj(t)
+	var reports []string
+	a := capturingAsserter(Accumulate, &reports)
+
+	expected := ExpectedRepoObj{}
+	expected.Title = "Moonset"
+	assert.False(t, CompareRepoObj(t, expected, obj, WithAsserter(a), IgnoreFields("Subject", "Creator", "Abstract")))
+	expected.Title = "Moonrise"
+	assert.False(t, CompareRepoObj(t, expected, obj, WithAsserter(a), IgnoreFields("Subject", "Creator", "Abstract", "JhirUri")))
+	assert.Empty(t, reports)
+
+	a.Flush()
+	require.Equal(t, 1, len(reports))
+	assert.Contains(t, reports[0], "2 entities do not match")
+	assert.Contains(t, reports[0], "repository object 'Moonset': 3 mismatches\n\tTitle:")
+	assert.Contains(t, reports[0], "repository object 'Moonrise': 1 mismatch\n\tDateCreated:")
+
+	a.Flush()
+	assert.Equal(t, 1, len(reports))
+}
+
+func Test_AsserterFatal(t *testing.T) {
+	obj := compareTestObj(t)
+	var reports []string
+	a := capturingAsserter(Fatal, &reports)
+
+	expected := ExpectedRepoObj{}
+	expected.Title = "Moonset"
+	assert.False(t, CompareRepoObj(t, expected, obj, WithAsserter(a), IgnoreFields("Subject", "Creator", "Abstract", "DateCreated", "JhirUri")))
+	require.Equal(t, 1, len(reports))
+	assert.Contains(t, reports[0], "repository object 'Moonset': 1 mismatch")
+
+	assert.True(t, a.AssertMemberOfPath(obj, "Moonrise"))
+	assert.False(t, a.AssertMemberOfPath(obj, "Archives > Moonrise"))
+	require.Equal(t, 2, len(reports))
+	assert.Contains(t, reports[1], "actual path  : Moonrise")
+}
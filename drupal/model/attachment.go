@@ -0,0 +1,143 @@
+package model
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/stretchr/testify/assert"
+)
+
+// Describes a media expected to be attached to a repository object, e.g. "an Original File image of type image/tiff of
+// at least 40MB".  Zero values are not matched, so an expectation may be as broad or as narrow as needed.
+type ExpectedMediaAttachment struct {
+	// The name of a media use term the media must carry, e.g. OriginalFile
+	MediaUse string
+	// The bundle of the media, e.g. Image
+	Bundle string
+	// The mime type of the media, e.g. `image/tiff`
+	MimeType string
+	// The minimum size of the media's file, in bytes
+	MinSize int
+	// The maximum size of the media's file, in bytes
+	MaxSize int
+	// Whether access to the media must be restricted, or unrestricted; not matched if nil
+	RestrictedAccess *bool
+	// The exact number of media that must match; if zero, at least one media must match
+	Count int
+}
+
+// String answers a readable description of the expectation, e.g. `Original File image/tiff media`
+func (e ExpectedMediaAttachment) String() string {
+	parts := nonEmpty([]string{e.MediaUse, e.Bundle, e.MimeType})
+	if e.MinSize > 0 {
+		parts = append(parts, fmt.Sprintf(">= %d bytes", e.MinSize))
+	}
+	if e.MaxSize > 0 {
+		parts = append(parts, fmt.Sprintf("<= %d bytes", e.MaxSize))
+	}
+	if e.RestrictedAccess != nil && *e.RestrictedAccess {
+		parts = append(parts, "restricted")
+	} else if e.RestrictedAccess != nil {
+		parts = append(parts, "unrestricted")
+	}
+	return strings.Join(append(parts, "media"), " ")
+}
+
+// matches answers whether the media, of the bundle and carrying the named media uses, meets the expectation
+func (e ExpectedMediaAttachment) matches(m Media, bundle string, uses []string) bool {
+	if e.MediaUse != "" && !containsString(uses, e.MediaUse) {
+		return false
+	}
+	if e.Bundle != "" && e.Bundle != bundle {
+		return false
+	}
+	if e.MimeType != "" && e.MimeType != m.MimeType() {
+		return false
+	}
+	if e.MinSize > 0 && m.FileSize() < e.MinSize {
+		return false
+	}
+	if e.MaxSize > 0 && m.FileSize() > e.MaxSize {
+		return false
+	}
+	return e.RestrictedAccess == nil || *e.RestrictedAccess == m.RestrictedAccess()
+}
+
+// AssertMedia asserts that the repository object has media matching the expectation, and answers the matching media.
+// Media are found by their field_media_of (see MediaOfObject), and their media use terms are resolved and matched by
+// name.  If ExpectedMediaAttachment.Count is set, exactly that many media must match; otherwise at least one must.
+// The failure lists every media that the object actually has:
+//
+//	model.AssertMedia(t, objUuid, model.ExpectedMediaAttachment{MediaUse: model.OriginalFile, MimeType: "image/tiff",
+//		MinSize: 40_000_000})
+//	model.AssertMedia(t, objUuid, model.ExpectedMediaAttachment{MediaUse: model.ServiceFile, Count: 1})
+func AssertMedia(t *testing.T, objUuid string, expected ExpectedMediaAttachment) []Media {
+	matched, failure := assertMedia(t, objUuid, expected)
+	if failure != "" {
+		assert.Fail(t, failure)
+	}
+	return matched
+}
+
+// assertMedia answers the media of the repository object matching the expectation, and a description of the failure
+// to meet the expectation, or the empty string
+func assertMedia(t *testing.T, objUuid string, expected ExpectedMediaAttachment) ([]Media, string) {
+	var matched []Media
+	var actual []string
+	for _, m := range MediaOfObject(t, objUuid) {
+		bundle, uses := mediaBundle(m), resolveLabels(t, m.MediaUse())
+		if expected.matches(m, bundle, uses) {
+			matched = append(matched, m)
+		}
+		actual = append(actual, describeMedia(m, bundle, uses))
+	}
+
+	listing := "(none)"
+	if len(actual) > 0 {
+		listing = "\n\t" + strings.Join(actual, "\n\t")
+	}
+	if expected.Count > 0 && len(matched) != expected.Count {
+		return matched, fmt.Sprintf("repository object %s has %d %s, expected %d; its media are: %s", objUuid,
+			len(matched), expected, expected.Count, listing)
+	} else if expected.Count == 0 && len(matched) == 0 {
+		return matched, fmt.Sprintf("repository object %s has no %s; its media are: %s", objUuid, expected, listing)
+	}
+
+	return matched, ""
+}
+
+// mediaBundle answers the bundle of the media, e.g. Image, or the empty string if the media carries no data element
+func mediaBundle(m Media) string {
+	data := reflect.ValueOf(m).FieldByName("JsonApiData")
+	if !data.IsValid() || data.Len() == 0 {
+		return ""
+	}
+	if mediaType, ok := data.Index(0).FieldByName("Type").Interface().(jsonapi.DrupalType); ok {
+		return mediaType.Bundle()
+	}
+	return ""
+}
+
+// describeMedia answers a readable description of the media, e.g.
+// `'moo.tiff' (image, image/tiff, 41943040 bytes, media use: Original File)`
+func describeMedia(m Media, bundle string, uses []string) string {
+	s := fmt.Sprintf("'%s' (%s, %s, %d bytes, media use: %s", m.Name(), bundle, m.MimeType(), m.FileSize(),
+		strings.Join(uses, ", "))
+	if m.RestrictedAccess() {
+		s += ", restricted"
+	}
+	return s + ")"
+}
+
+// containsString answers whether the values contain the value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
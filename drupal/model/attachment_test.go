@@ -0,0 +1,38 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_AssertMedia(t *testing.T) {
+	objId, originalUse, serviceUse := testUuid(0), testUuid(1), testUuid(2)
+	original := strings.Replace(mediaElement(Image, testUuid(3), "image/tiff", originalUse, objId),
+		`"attributes": {`, `"attributes": {"field_file_size": 41943040, `, 1)
+	media := map[string]string{
+		"/jsonapi/media/image": original + "," + mediaElement(Image, testUuid(4), "image/jp2", serviceUse, objId),
+	}
+	server := newMediaServer(t, objId, media, map[string]string{originalUse: OriginalFile, serviceUse: ServiceFile})
+	defer server.Close()
+	setBaseUrl(t, server)
+
+	matched := AssertMedia(t, objId, ExpectedMediaAttachment{MediaUse: OriginalFile, Bundle: Image, MimeType: "image/tiff",
+		MinSize: 40_000_000})
+	require.Equal(t, 1, len(matched))
+	assert.Equal(t, testUuid(3), matched[0].Name())
+
+	unrestricted := false
+	assert.Equal(t, 1, len(AssertMedia(t, objId, ExpectedMediaAttachment{MediaUse: ServiceFile, MimeType: "image/jp2",
+		RestrictedAccess: &unrestricted, Count: 1})))
+	assert.Equal(t, 2, len(AssertMedia(t, objId, ExpectedMediaAttachment{Bundle: Image, Count: 2})))
+
+	assert.Equal(t, "Original File image/tiff >= 40000000 bytes media", ExpectedMediaAttachment{MediaUse: OriginalFile,
+		MimeType: "image/tiff", MinSize: 40_000_000}.String())
+	assert.Equal(t, fmt.Sprintf("'%s' (image, image/tiff, 41943040 bytes, media use: Original File)", testUuid(3)),
+		describeMedia(matched[0], mediaBundle(matched[0]), []string{OriginalFile}))
+	assert.False(t, ExpectedMediaAttachment{MediaUse: OriginalFile, MaxSize: 1000}.matches(matched[0], Image, []string{OriginalFile}))
+}
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/stretchr/testify/require"
+)
+
+// AssertDeletedCascade deletes the repository object, and asserts that its media, and their files, are deleted with it
+// by the hooks of the site.  The media of the object (see MediaOfObject) and their files are recorded before the object
+// is deleted, as the user from the environment; the existence of the object, each media, and each file is then polled
+// until none remain.  The test fails immediately if the object cannot be deleted, and fails reporting each entity that
+// survived if any remain when the wait times out.  See WaitForEntity for the time waited.
+//
+//	obj := model.EnsureObject(t, spec)
+//	model.AssertDeletedCascade(t, obj.JsonApiData[0].Id)
+func AssertDeletedCascade(t *testing.T, objUuid string) bool {
+	obj := JsonApiData{Type: jsonapi.NewDrupalType(Node, RepositoryObject), Id: objUuid}
+	entities := []JsonApiData{obj}
+	for _, b := range mediaBundles {
+		media, err := b.mediaOf(t, b.bundle, objUuid)
+		require.Nil(t, err, "%s", err)
+		for _, m := range media {
+			id, err := createdIdErr(m)
+			require.Nil(t, err, "%s", err)
+			entities = append(entities, JsonApiData{Type: jsonapi.NewDrupalType(MediaEntity, b.bundle), Id: id})
+			if file := m.File().JsonApiData; file.Validate() == nil {
+				entities = append(entities, file)
+			}
+		}
+	}
+
+	u := query(t, Node, RepositoryObject)
+	err := u.DeleteErr(objUuid)
+	require.Nil(t, err, "unable to delete repository object %s: %s", objUuid, err)
+
+	return WaitFor(t, WaitTimeout(t), WaitInterval(t), func() (bool, error) {
+		var survivors []string
+		for _, e := range entities {
+			u := query(t, e.Type.Entity(), e.Type.Bundle())
+			exists, err := u.ResourceExistsErr(e.Id)
+			if err != nil {
+				return false, err
+			}
+			if exists {
+				survivors = append(survivors, fmt.Sprintf("%s %s", e.Type, e.Id))
+			}
+		}
+		if len(survivors) > 0 {
+			return false, fmt.Errorf("%d of the %d entities of repository object %s survived its deletion: %s",
+				len(survivors), len(entities), objUuid, strings.Join(survivors, ", "))
+		}
+		return true, nil
+	})
+}
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_AssertDeletedCascade(t *testing.T) {
+	objId, mediaId, fileId := testUuid(0), testUuid(1), testUuid(2)
+	var mu sync.Mutex
+	existing := map[string]bool{
+		"/jsonapi/node/islandora_object/" + objId: true,
+		"/jsonapi/media/image/" + mediaId:         true,
+		"/jsonapi/file/file/" + fileId:            true,
+	}
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.Method == http.MethodDelete:
+			assert.Equal(t, "/jsonapi/node/islandora_object/"+objId, r.URL.Path)
+			delete(existing, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		case r.URL.Path == "/jsonapi/media/image":
+			_, _ = fmt.Fprintf(w, `{"data": [{"type": "media--image", "id": "%s",
+				"relationships": {"field_media_image": {"data": {"type": "file--file", "id": "%s"}}}}]}`, mediaId, fileId)
+		case strings.Count(r.URL.Path, "/") == 4:
+			// the media and file are deleted by the site once they have been polled
+			if polls++; polls > 2 {
+				delete(existing, "/jsonapi/media/image/"+mediaId)
+				delete(existing, "/jsonapi/file/file/"+fileId)
+			}
+			if !existing[r.URL.Path] {
+				http.NotFound(w, r)
+				return
+			}
+			_, _ = fmt.Fprintf(w, `{"data": {"type": "moo", "id": "%s"}}`, requestedId(r))
+		default:
+			_, _ = w.Write([]byte(`{"data": []}`))
+		}
+	}))
+	defer server.Close()
+	setBaseUrl(t, server)
+	t.Setenv("DRUPAL_USERNAME", "admin")
+	t.Setenv("DRUPAL_PASSWORD", "moo")
+	t.Setenv("IDC_WAIT_INTERVAL", "1ms")
+
+	assert.True(t, AssertDeletedCascade(t, objId))
+	assert.Empty(t, existing)
+	assert.True(t, polls > 3, "expected the surviving media and file to be polled again, polled %d times", polls)
+}
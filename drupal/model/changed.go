@@ -0,0 +1,48 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// AssertOnlyChangedSince asserts that no repository object other than those with the supplied uuids changed at or after
+// since, e.g. that a migration run starting at since touched only the objects it migrated:
+//
+//	start := time.Now()
+//	// ... run the migration ...
+//	model.AssertOnlyChangedSince(t, start, migratedUuids)
+//
+// Every object changed since then is retrieved (see jsonapi.JsonApiUrl.FilterChangedSince), page by page; each one not
+// supplied is reported with its title and the time it changed.  Objects the user may not view are not retrieved, so
+// audits should authenticate as an administrator.
+func AssertOnlyChangedSince(t *testing.T, since time.Time, objUuids []string, opts ...Option) bool {
+	allowed := map[string]bool{}
+	for _, id := range objUuids {
+		allowed[id] = true
+	}
+
+	u := query(t, Node, RepositoryObject, opts...)
+	u.FilterChangedSince(since)
+	changed := JsonApiIslandoraObj{}
+	if err := u.GetAllErr(&changed); err != nil {
+		return assert.Fail(t, fmt.Sprintf("unable to retrieve the repository objects changed since %s: %s",
+			since.Format(time.RFC3339), err))
+	}
+
+	var unexpected []string
+	for _, obj := range changed.JsonApiData {
+		if !allowed[obj.Id] {
+			unexpected = append(unexpected, fmt.Sprintf("%s ('%s', changed %s)", obj.Id, obj.JsonApiAttributes.Title,
+				obj.JsonApiAttributes.Changed))
+		}
+	}
+	if len(unexpected) > 0 {
+		return assert.Fail(t, fmt.Sprintf("%d unexpected repository object(s) changed since %s:\n\t%s",
+			len(unexpected), since.Format(time.RFC3339), strings.Join(unexpected, "\n\t")))
+	}
+	return true
+}
@@ -0,0 +1,26 @@
+package model
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_AssertOnlyChangedSince(t *testing.T) {
+	since := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/jsonapi/node/islandora_object", r.URL.Path)
+		assert.Equal(t, "1682942400", r.URL.Query().Get("filter[changed-since][condition][value]"))
+		_, _ = fmt.Fprintf(w, `{"data": [
+			{"type": "node--islandora_object", "id": "%s", "attributes": {"title": "Moonrise"}},
+			{"type": "node--islandora_object", "id": "%s", "attributes": {"title": "Moonset"}}]}`, testUuid(1), testUuid(2))
+	}))
+	defer server.Close()
+	setBaseUrl(t, server)
+
+	assert.True(t, AssertOnlyChangedSince(t, since, []string{testUuid(1), testUuid(2), testUuid(3)}))
+}
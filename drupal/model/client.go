@@ -0,0 +1,76 @@
+package model
+
+import (
+	"context"
+	"fmt"
+)
+
+// Client finds and resolves the entities of the model, answering errors rather than making assertions, so that the
+// model may be used outside of tests, e.g. by a command line tool auditing content.  The testing.T functions of the
+// model (e.g. FindObjectByTitle, Resolve, and LangCode) are adapters reporting the errors of a Client.  Requests are
+// customized by the options of the Client, e.g. WithConfig, and bounded by the context of each call:
+//
+//	config, err := env.Load()
+//	...
+//	c := model.NewClient(model.WithConfig(&config))
+//	obj, err := c.FindObjectByTitle(ctx, "Moonrise")
+type Client struct {
+	opts []Option
+}
+
+// NewClient answers a Client whose requests are customized by the options.  Without options, requests are
+// authenticated by the credentials from the environment, as the testing.T functions are.
+func NewClient(opts ...Option) *Client {
+	return &Client{opts: opts}
+}
+
+// FindCollectionByTitle answers the collection with the supplied title, or an error if exactly one collection does
+// not match.  If no collection matches, the error wraps jsonapi.ErrNotFound.
+func (c *Client) FindCollectionByTitle(ctx context.Context, title string) (JsonApiCollection, error) {
+	collection := JsonApiCollection{}
+	err := c.findSingleErr(ctx, Collection, "title", title, &collection)
+	return collection, err
+}
+
+// FindObjectByTitle answers the repository object with the supplied title.  See FindCollectionByTitle for the errors
+// answered.
+func (c *Client) FindObjectByTitle(ctx context.Context, title string) (JsonApiIslandoraObj, error) {
+	obj := JsonApiIslandoraObj{}
+	err := c.findSingleErr(ctx, RepositoryObject, "title", title, &obj)
+	return obj, err
+}
+
+// FindObjectByDigitalIdentifier answers the repository object carrying the supplied digital identifier.  See
+// FindCollectionByTitle for the errors answered.
+func (c *Client) FindObjectByDigitalIdentifier(ctx context.Context, id string) (JsonApiIslandoraObj, error) {
+	obj := JsonApiIslandoraObj{}
+	err := c.findSingleErr(ctx, RepositoryObject, "field_digital_identifier", id, &obj)
+	return obj, err
+}
+
+// Resolve resolves the reference of the data object into v (see JsonApiData.Resolve)
+func (c *Client) Resolve(ctx context.Context, jad JsonApiData, v interface{}) error {
+	return jad.ResolveErr(v, c.options(ctx)...)
+}
+
+// LangCode answers the language code of the value string (see JsonApiLanguageValue.LangCode)
+func (c *Client) LangCode(ctx context.Context, lv JsonApiLanguageValue) (string, error) {
+	code, err := lv.langCodeErr(c.options(ctx)...)
+	if err != nil {
+		return "", fmt.Errorf("model: unable to resolve language %s: %w", lv.Id, err)
+	}
+	return code, nil
+}
+
+// findSingleErr retrieves the single node of the bundle whose field matches the value into v
+func (c *Client) findSingleErr(ctx context.Context, bundle, field, value string, v interface{}) error {
+	u := query(nil, Node, bundle, c.options(ctx)...)
+	u.Filter = field
+	u.Value = value
+	return u.GetSingleErr(v)
+}
+
+// options answers the options of the Client, bounding requests by the context
+func (c *Client) options(ctx context.Context) []Option {
+	return append(append([]Option{}, c.opts...), WithContext(ctx))
+}
@@ -0,0 +1,90 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ClientFindAndResolve(t *testing.T) {
+	ResetLangCodeCache()
+	t.Cleanup(ResetLangCodeCache)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch {
+		case r.URL.Path == "/jsonapi/node/islandora_object" && q.Get("filter[title]") == "Moonrise":
+			_, _ = fmt.Fprintf(w, `{"data": [{"type": "node--islandora_object", "id": "%s",
+				"attributes": {"title": "Moonrise"}}]}`, testUuid(1))
+		case collectionPath(r) == "/jsonapi/taxonomy_term/language" && requestedId(r) == testUuid(2):
+			_, _ = fmt.Fprintf(w, `{"data": [{"type": "taxonomy_term--language", "id": "%s",
+				"attributes": {"field_language_code": "es"}}]}`, testUuid(2))
+		default:
+			_, _ = w.Write([]byte(`{"data": []}`))
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient(WithBaseUrl(server.URL), WithAnonymous())
+	obj, err := c.FindObjectByTitle(context.Background(), "Moonrise")
+	require.Nil(t, err, "%s", err)
+	assert.Equal(t, testUuid(1), obj.JsonApiData[0].Id)
+
+	_, err = c.FindCollectionByTitle(context.Background(), "Moonrise")
+	assert.True(t, errors.Is(err, jsonapi.ErrNotFound), "expected ErrNotFound, got %v", err)
+
+	lv := JsonApiLanguageValue{JsonApiData: JsonApiData{Type: "taxonomy_term--language", Id: testUuid(2)}}
+	code, err := c.LangCode(context.Background(), lv)
+	require.Nil(t, err, "%s", err)
+	assert.Equal(t, "es", code)
+
+	var events []string
+	logged := NewClient(WithBaseUrl(server.URL), WithAnonymous(), WithLogger(eventLogger(func(msg string) {
+		events = append(events, msg)
+	})))
+	code, err = logged.LangCode(context.Background(), lv)
+	require.Nil(t, err, "%s", err)
+	assert.Equal(t, "es", code)
+	assert.Equal(t, []string{"model language cache hit"}, events)
+
+	resolved := JsonApiIslandoraObj{}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = c.Resolve(ctx, JsonApiData{Type: "node--islandora_object", Id: testUuid(1)}, &resolved)
+	assert.True(t, errors.Is(err, context.Canceled), "expected context.Canceled, got %v", err)
+}
+
+// A Logger passing the message of each event to the function
+type eventLogger func(msg string)
+
+func (l eventLogger) Log(msg string, _ ...interface{}) {
+	l(msg)
+}
+
+func Test_LangCodePerSite(t *testing.T) {
+	ResetLangCodeCache()
+	t.Cleanup(ResetLangCodeCache)
+	site := func(code string) *httptest.Server {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = fmt.Fprintf(w, `{"data": [{"type": "taxonomy_term--language", "id": "%s",
+				"attributes": {"field_language_code": "%s"}}]}`, testUuid(2), code)
+		}))
+		t.Cleanup(server.Close)
+		return server
+	}
+	first, second := site("es"), site("fr")
+	lv := JsonApiLanguageValue{JsonApiData: JsonApiData{Type: "taxonomy_term--language", Id: testUuid(2)}}
+
+	code, err := NewClient(WithBaseUrl(first.URL)).LangCode(context.Background(), lv)
+	require.Nil(t, err, "%s", err)
+	assert.Equal(t, "es", code)
+	code, err = NewClient(WithBaseUrl(second.URL)).LangCode(context.Background(), lv)
+	require.Nil(t, err, "%s", err)
+	assert.Equal(t, "fr", code, "language codes are expected to be cached per site")
+}
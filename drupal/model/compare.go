@@ -0,0 +1,656 @@
+package model
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// A mismatch between the expected and actual value of a single field
+type FieldDiff struct {
+	// The name of the field of the expected struct, e.g. `DateAvailable`
+	Field    string
+	Expected interface{}
+	Actual   interface{}
+	// The error encountered when retrieving the actual value (e.g. resolving a relationship), if any
+	Err error
+	// The expected values of a multi-valued field that are missing from the actual values, if compared as a set
+	Missing []string
+	// The actual values of a multi-valued field that are not expected, if compared as a set
+	Unexpected []string
+}
+
+// String answers a readable description of the mismatch
+func (d FieldDiff) String() string {
+	if d.Err != nil {
+		return fmt.Sprintf("%s: unable to compare: %s", d.Field, d.Err)
+	}
+	if len(d.Missing) > 0 || len(d.Unexpected) > 0 {
+		s := d.Field + ":"
+		if len(d.Missing) > 0 {
+			s += fmt.Sprintf("\n\tmissing from actual : %q", d.Missing)
+		}
+		if len(d.Unexpected) > 0 {
+			s += fmt.Sprintf("\n\tunexpected in actual: %q", d.Unexpected)
+		}
+		return s
+	}
+	return fmt.Sprintf("%s:\n\texpected: %#v\n\tactual  : %#v", d.Field, d.Expected, d.Actual)
+}
+
+// A field to be compared: its expected value, and a function answering its actual value
+type comparedField struct {
+	name     string
+	expected interface{}
+	actual   func() (interface{}, error)
+	// if true, the field is only compared if its expected value is not zero (or a nil list)
+	optional bool
+	// if true, lists are compared in order; otherwise they are compared as sets
+	ordered bool
+	// if not nil, answers whether the expected and actual values are equivalent
+	equal func(expected, actual interface{}) bool
+}
+
+// field answers a field that is compared even if its expected value is empty
+func field(name string, expected interface{}, actual func() (interface{}, error)) comparedField {
+	return comparedField{name: name, expected: expected, actual: actual}
+}
+
+// ifExpected answers a copy of the field that is only compared if its expected value is not zero (or a nil list)
+func (f comparedField) ifExpected() comparedField {
+	f.optional = true
+	return f
+}
+
+// inOrder answers a copy of the field whose lists are compared in order, rather than as sets
+func (f comparedField) inOrder() comparedField {
+	f.ordered = true
+	return f
+}
+
+// comparedWith answers a copy of the field whose values are compared using the supplied function
+func (f comparedField) comparedWith(equal func(expected, actual interface{}) bool) comparedField {
+	f.equal = equal
+	return f
+}
+
+// A CompareOption customizes how the Compare* functions (e.g. CompareRepoObj or CompareImageMedia) compare fields.
+// Fields are identified by the name of the field of the expected struct, e.g. `DateAvailable`.
+type CompareOption func(o *compareOptions)
+
+type compareOptions struct {
+	ignored     map[string]bool
+	substring   map[string]bool
+	comparators map[string]func(expected, actual interface{}) bool
+	ordered     map[string]bool
+	// the expected file written instead of comparing, if UPDATE_EXPECTED is true (see GoldenFile)
+	golden string
+	// reports mismatches instead of the test, if not nil (see WithAsserter)
+	asserter *Asserter
+}
+
+// IgnoreFields skips the comparison of the named fields, e.g. values that legitimately vary between environments such
+// as `DateAvailable` or `JhirUri`.  Ignored fields are logged, so that gaps in verification remain visible when tests
+// are run verbosely.
+func IgnoreFields(fields ...string) CompareOption {
+	return func(o *compareOptions) {
+		for _, f := range fields {
+			o.ignored[f] = true
+		}
+	}
+}
+
+// MatchSubstring considers the named fields equal if the actual value contains the expected value.  A list matches if
+// it has the same number of values as the expected list, and each actual value contains the corresponding expected
+// value.  Fields that are not strings or lists of strings are compared for equality.
+func MatchSubstring(fields ...string) CompareOption {
+	return func(o *compareOptions) {
+		for _, f := range fields {
+			o.substring[f] = true
+		}
+	}
+}
+
+// RequireOrder compares the named multi-valued fields in order, rather than as sets, e.g. the children of paged content
+// where the order of the values is significant
+func RequireOrder(fields ...string) CompareOption {
+	return func(o *compareOptions) {
+		for _, f := range fields {
+			o.ordered[f] = true
+		}
+	}
+}
+
+// CompareFieldWith compares the named field using the supplied function, which answers whether the expected and actual
+// values are equivalent.  The values are those reported by FieldDiff, e.g. a string or a []string.
+func CompareFieldWith(field string, equal func(expected, actual interface{}) bool) CompareOption {
+	return func(o *compareOptions) {
+		o.comparators[field] = equal
+	}
+}
+
+// newCompareOptions answers the options resulting from applying the supplied options
+func newCompareOptions(opts []CompareOption) compareOptions {
+	o := compareOptions{
+		ignored:     map[string]bool{},
+		substring:   map[string]bool{},
+		comparators: map[string]func(expected, actual interface{}) bool{},
+		ordered:     map[string]bool{},
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// fieldNames answers the sorted, distinct names of the fields named by the options
+func (o compareOptions) fieldNames() []string {
+	set := map[string]bool{}
+	for name := range o.ignored {
+		set[name] = true
+	}
+	for name := range o.substring {
+		set[name] = true
+	}
+	for name := range o.comparators {
+		set[name] = true
+	}
+	for name := range o.ordered {
+		set[name] = true
+	}
+	return sortedKeys(set)
+}
+
+// equal answers whether the expected and actual values of the field are equivalent, according to the options
+func (o compareOptions) equal(f comparedField, actual interface{}) bool {
+	if equal, ok := o.comparators[f.name]; ok {
+		return equal(f.expected, actual)
+	}
+	if f.equal != nil {
+		return f.equal(f.expected, actual)
+	}
+	if o.substring[f.name] {
+		if contains, ok := containsValues(f.expected, actual); ok {
+			return contains
+		}
+	}
+	if o.asSet(f) {
+		if missing, unexpected, ok := setDifference(f.expected, actual); ok {
+			return len(missing) == 0 && len(unexpected) == 0
+		}
+	}
+	return equalValues(f.expected, actual)
+}
+
+// asSet answers whether lists of the field are compared as sets, i.e. they are not required to be in order, nor are
+// they compared by a comparator or by substring
+func (o compareOptions) asSet(f comparedField) bool {
+	_, compared := o.comparators[f.name]
+	return !compared && f.equal == nil && !o.substring[f.name] && !f.ordered && !o.ordered[f.name]
+}
+
+// CompareRepoObj asserts that every modeled field of the repository object matches the expected repository object,
+// reporting a failure for each mismatched field (see DiffRepoObj), rather than stopping at the first mismatch.  If
+// UPDATE_EXPECTED is true, the actual repository object is written to the golden file instead (see GoldenFile).
+func CompareRepoObj(t *testing.T, expected ExpectedRepoObj, actual JsonApiIslandoraObj, opts ...CompareOption) bool {
+	if o := newCompareOptions(opts); o.updating() {
+		return updateExpected(t, o.golden, func() (interface{}, error) { return ExpectedRepoObjOfErr(actual) })
+	}
+	return reportDiffs(t, fmt.Sprintf("repository object '%s'", expected.Title), DiffRepoObj(expected, actual, opts...), opts)
+}
+
+// DiffRepoObj compares every modeled field of the first repository object in the response to the expected repository
+// object, and answers the mismatched fields, without making assertions.  Relationships are resolved, so that the names
+// of terms, the titles of nodes, and the language codes of language values are compared to the expected values, e.g.
+// a language value is compared as `en: Moonrise over Hernandez`, and an agent as `relators:pht: Adams, Ansel`.
+//
+// Empty expected values are compared too: they are expected to be empty.  Multi-valued fields (e.g. Subject or
+// AltTitle) are compared as sets by default, as Drupal does not guarantee the order of their values; use RequireOrder
+// where order is significant.  Dates (e.g. DateCreated) are compared as EDTF values (see EqualEDTF), and a date that is
+// not valid EDTF is reported as an error wrapping ErrInvalidEDTF.  LinkedAgent is not modeled by JsonApiIslandoraObj, and is not compared.
+func DiffRepoObj(expected ExpectedRepoObj, actual JsonApiIslandoraObj, opts ...CompareOption) []FieldDiff {
+	if len(actual.JsonApiData) == 0 {
+		return []FieldDiff{{Field: "JsonApiData", Err: fmt.Errorf("model: the repository object response is empty")}}
+	}
+
+	return diffFields(repoObjFields(expected, actual), newCompareOptions(opts))
+}
+
+// CompareCollection asserts that every modeled field of the collection matches the expected collection, reporting a
+// failure for each mismatched field (see DiffCollection)
+func CompareCollection(t *testing.T, expected ExpectedCollection, actual JsonApiCollection, opts ...CompareOption) bool {
+	return reportDiffs(t, fmt.Sprintf("collection '%s'", expected.Title), DiffCollection(expected, actual, opts...), opts)
+}
+
+// DiffCollection compares every modeled field of the first collection in the response to the expected collection, and
+// answers the mismatched fields, without making assertions (see DiffRepoObj).  The language of the title is compared
+// by its language code.
+func DiffCollection(expected ExpectedCollection, actual JsonApiCollection, opts ...CompareOption) []FieldDiff {
+	if len(actual.JsonApiData) == 0 {
+		return []FieldDiff{{Field: "JsonApiData", Err: fmt.Errorf("model: the collection response is empty")}}
+	}
+
+	return diffFields(collectionFields(expected, actual), newCompareOptions(opts))
+}
+
+// diffFields answers the mismatches between the expected and actual values of the fields.  A field named by an option
+// that is not one of the compared fields is answered as a mismatch, so that misspelled options are not silently
+// ignored.
+func diffFields(fields []comparedField, o compareOptions) []FieldDiff {
+	var diffs []FieldDiff
+	known := map[string]bool{}
+	for _, f := range fields {
+		known[f.name] = true
+		if o.ignored[f.name] || (f.optional && isEmpty(f.expected)) {
+			continue
+		}
+
+		actual, err := f.actual()
+		if err != nil {
+			diffs = append(diffs, FieldDiff{Field: f.name, Expected: f.expected, Err: err})
+			continue
+		}
+		if !o.equal(f, actual) {
+			d := FieldDiff{Field: f.name, Expected: f.expected, Actual: actual}
+			if o.asSet(f) {
+				d.Missing, d.Unexpected, _ = setDifference(f.expected, actual)
+			}
+			diffs = append(diffs, d)
+		}
+	}
+
+	for _, name := range o.fieldNames() {
+		if !known[name] {
+			diffs = append(diffs, FieldDiff{Field: name, Err: fmt.Errorf("model: option names a field that is not compared")})
+		}
+	}
+	return diffs
+}
+
+// reportDiffs reports a failure for each of the mismatched fields of the described entity, and logs the fields that
+// were ignored.  If an Asserter is supplied, the mismatches are reported to it instead (see WithAsserter).  Answers true
+// if there are no mismatches.
+func reportDiffs(t *testing.T, description string, diffs []FieldDiff, opts []CompareOption) bool {
+	o := newCompareOptions(opts)
+	if len(o.ignored) > 0 {
+		t.Logf("Ignored fields of %s: %s", description, strings.Join(sortedKeys(o.ignored), ", "))
+	}
+	if o.asserter != nil {
+		return o.asserter.report(description, diffStrings(diffs))
+	}
+
+	for _, d := range diffs {
+		assert.Fail(t, fmt.Sprintf("%s does not match:\n%s", description, d))
+	}
+	return len(diffs) == 0
+}
+
+// equalValues answers whether the values are deeply equal, considering nil and empty slices to be equal
+func equalValues(expected, actual interface{}) bool {
+	e, a := reflect.ValueOf(expected), reflect.ValueOf(actual)
+	if e.Kind() == reflect.Slice && a.Kind() == reflect.Slice && e.Len() == 0 && a.Len() == 0 {
+		return true
+	}
+	return reflect.DeepEqual(expected, actual)
+}
+
+// isEmpty answers whether the value is the zero value of its type; a nil slice is empty, but an empty slice is not
+func isEmpty(v interface{}) bool {
+	val := reflect.ValueOf(v)
+	return !val.IsValid() || val.IsZero()
+}
+
+// sortedKeys answers the sorted keys of the set
+func sortedKeys(set map[string]bool) []string {
+	var keys []string
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// setDifference compares the values of two lists as multisets, answering the expected values missing from the actual
+// list and the actual values that are not expected, each in the order they appear.  Values are compared by their
+// string form.  Answers false if either value is not a list.
+func setDifference(expected, actual interface{}) ([]string, []string, bool) {
+	e, a := reflect.ValueOf(expected), reflect.ValueOf(actual)
+	if e.Kind() != reflect.Slice || a.Kind() != reflect.Slice {
+		return nil, nil, false
+	}
+
+	counts := map[string]int{}
+	for i := 0; i < a.Len(); i++ {
+		counts[setElement(a.Index(i))]++
+	}
+	var missing []string
+	for i := 0; i < e.Len(); i++ {
+		if v := setElement(e.Index(i)); counts[v] > 0 {
+			counts[v]--
+		} else {
+			missing = append(missing, v)
+		}
+	}
+	var unexpected []string
+	for i := 0; i < a.Len(); i++ {
+		if v := setElement(a.Index(i)); counts[v] > 0 {
+			counts[v]--
+			unexpected = append(unexpected, v)
+		}
+	}
+	return missing, unexpected, true
+}
+
+// setElement answers the string form of a list element
+func setElement(v reflect.Value) string {
+	if v.Kind() == reflect.String {
+		return v.String()
+	}
+	return fmt.Sprintf("%+v", v.Interface())
+}
+
+// containsValues answers whether the actual value contains the expected value, and whether the values are strings or
+// lists of strings that may be compared in this way
+func containsValues(expected, actual interface{}) (bool, bool) {
+	switch e := expected.(type) {
+	case string:
+		a, ok := actual.(string)
+		return ok && strings.Contains(a, e), ok
+	case []string:
+		a, ok := actual.([]string)
+		if !ok {
+			return false, false
+		}
+		if len(a) != len(e) {
+			return false, true
+		}
+		for i := range e {
+			if !strings.Contains(a[i], e[i]) {
+				return false, true
+			}
+		}
+		return true, true
+	}
+	return false, false
+}
+
+// repoObjFields answers the modeled fields of the repository object, paired with their expected values
+func repoObjFields(e ExpectedRepoObj, actual JsonApiIslandoraObj) []comparedField {
+	attrs := actual.JsonApiData[0].JsonApiAttributes
+	rels := actual.JsonApiData[0].JsonApiRelationships
+
+	var expectedContributor, expectedCreator []string
+	for _, c := range e.Contributor {
+		expectedContributor = append(expectedContributor, agentString(c.RelType, c.Name))
+	}
+	for _, c := range e.Creator {
+		expectedCreator = append(expectedCreator, agentString(c.RelType, c.Name))
+	}
+
+	return []comparedField{
+		field("Title", e.Title, valueOf(attrs.Title)),
+		field("Abstract", languageStrings(e.Abstract), langValuesOf(rels.Abstract.Data)),
+		field("AccessRights", e.AccessRights, labelsOf(rels.AccessRights.Data)),
+		field("AltTitle", languageStrings(e.AltTitle), langValuesOf(rels.AltTitle.Data)),
+		field("CollectionNumber", e.CollectionNumber, valueOf(attrs.CollectionNumber)),
+		field("CopyrightAndUse", e.CopyrightAndUse, labelOf(rels.CopyrightAndUse.Data)),
+		field("CopyrightHolder", e.CopyrightHolder, labelsOf(rels.CopyrightHolder.Data)),
+		field("Contributor", expectedContributor, agentsOf(rels.Contributor.Data)),
+		field("Creator", expectedCreator, agentsOf(rels.Creator.Data)),
+		field("CustodialHistory", languageStrings(e.CustodialHistory), langValuesOf(rels.CustodialHistory.Data)),
+		field("DateAvailable", e.DateAvailable, edtfOf(e.DateAvailable, attrs.DateAvailable)),
+		field("DateCopyrighted", e.DateCopyrighted, edtfOf(e.DateCopyrighted, attrs.DateCopyrighted)),
+		field("DateCreated", e.DateCreated, edtfOf(e.DateCreated, attrs.DateCreated)),
+		field("DatePublished", e.DatePublished, edtfOf(e.DatePublished, attrs.DatePublished)),
+		field("DigitalIdentifier", e.DigitalIdentifier, valueOf(attrs.DigitalIdentifier)),
+		field("DigitalPublisher", e.DigitalPublisher, labelsOf(rels.DigitalPublisher.Data)),
+		field("DisplayHint", e.DisplayHint, labelOf(rels.DisplayHint.Data)),
+		field("DspaceIdentifier", e.DspaceIdentifier, valueOf(attrs.DspaceIdentifier.Uri)),
+		field("DspaceItemId", e.DspaceItemId, valueOf(attrs.DspaceItemid)),
+		field("Extent", e.Extent, valueOf(attrs.Extent)),
+		field("FeaturedItem", e.FeaturedItem, valueOf(attrs.FeaturedItem)),
+		field("FindingAid", e.FindingAid, valueOf(attrs.FindingAid)),
+		field("Genre", e.Genre, labelsOf(rels.Genre.Data)),
+		field("GeoportalLink", e.GeoportalLink, valueOf(attrs.GeoportalLink.Uri)),
+		field("AccessTerms", e.AccessTerms, labelsOf(rels.AccessTerms.Data)),
+		field("Issn", e.Issn, valueOf(attrs.Issn)),
+		field("IsPartOf", e.IsPartOf, valueOf(attrs.IsPartOf.Uri)),
+		field("ItemBarcode", e.ItemBarcode, valueOf(attrs.ItemBarcode)),
+		field("JhirUri", e.JhirUri, valueOf(attrs.JhirUri.Uri)),
+		field("LibraryCatalogLink", e.LibraryCatalogLink, valueOf(linkUris(attrs.LibraryCatalogLink))),
+		field("Model", modelString(e.Model.Name, e.Model.ExternalUri), func() (interface{}, error) { return modelErr(rels.Model.Data) }),
+		field("OclcNumber", e.OclcNumber, valueOf(attrs.OclcNumber)),
+		field("Publisher", e.Publisher, labelsOf(rels.Publisher.Data)),
+		field("PublisherCountry", e.PublisherCountry, labelsOf(rels.PublisherCountry.Data)),
+		field("ResourceType", e.ResourceType, labelsOf(rels.ResourceType.Data)),
+		field("SpatialCoverage", e.SpatialCoverage, labelsOf(rels.SpatialCoverage.Data)),
+		field("Subject", e.Subject, labelsOf(rels.Subject.Data)),
+		field("TableOfContents", languageStrings(e.TableOfContents), langValuesOf(rels.TableOfContents.Data)),
+		field("MemberOf", e.MemberOf, labelOf(rels.MemberOf.Data)),
+		field("Description", languageStrings(e.Description), langValuesOf(rels.Description.Data)),
+	}
+}
+
+// collectionFields answers the modeled fields of the collection, paired with their expected values
+func collectionFields(e ExpectedCollection, actual JsonApiCollection) []comparedField {
+	attrs := actual.JsonApiData[0].JsonApiAttributes
+	rels := actual.JsonApiData[0].JsonApiRelationships
+
+	fields := []comparedField{
+		field("Title", e.Title, valueOf(attrs.Title)),
+		field("TitleLangCode", e.TitleLangCode, func() (interface{}, error) { return langCodeOf(rels.TitleLanguage.Data) }),
+		field("AltTitle", languageStrings(e.AltTitle), langValuesOf(rels.AltTitle.Data)),
+		field("Description", languageStrings(e.Description), langValuesOf(rels.Description.Data)),
+		field("ContactEmail", e.ContactEmail, valueOf(attrs.ContactEmail)),
+		field("ContactName", e.ContactName, valueOf(attrs.ContactName)),
+		field("CollectionNumber", e.CollectionNumber, valueOf(attrs.CollectionNumber)),
+		field("MemberOf", e.MemberOf, labelOf(rels.MemberOf.Data)),
+		field("AccessTerms", e.AccessTerms, labelsOf(rels.AccessTerms.Data)),
+		field("FindingAid", e.FindingAid, valueOf(attrs.FindingAid)),
+		field("RepresentativeImage", e.RepresentativeImage, labelOf(rels.RepresentativeImage.Data)).ifExpected(),
+	}
+	if e.FeaturedItem != nil {
+		fields = append(fields, field("FeaturedItem", *e.FeaturedItem, valueOf(attrs.FeaturedItem)))
+	}
+	if e.Weight != nil {
+		fields = append(fields, field("Weight", *e.Weight, valueOf(attrs.Weight)))
+	}
+	if e.Published != nil {
+		fields = append(fields, field("Published", *e.Published, valueOf(attrs.Status)))
+	}
+	return fields
+}
+
+// valueOf answers a function answering the supplied value
+func valueOf(v interface{}) func() (interface{}, error) {
+	return func() (interface{}, error) { return v, nil }
+}
+
+// edtfOf answers a function answering the actual dates (a string, or a list of strings), in which each date that is
+// equivalent to an expected date (see EqualEDTF) is answered as the expected date is written, so that dates written
+// differently (e.g. `192x` and `192X`) are compared as equal.  An error wrapping ErrInvalidEDTF is answered if an
+// expected or actual date is not valid EDTF, so that invalid dates are reported distinctly from mismatched dates.
+// Empty dates are not parsed.
+func edtfOf(expected, actual interface{}) func() (interface{}, error) {
+	return func() (interface{}, error) {
+		if a, ok := actual.(string); ok {
+			e, _ := expected.(string)
+			dates, err := equivalentDates([]string{e}, []string{a})
+			if err != nil {
+				return nil, err
+			}
+			return dates[0], nil
+		}
+		e, _ := expected.([]string)
+		a, _ := actual.([]string)
+		return equivalentDates(e, a)
+	}
+}
+
+// equivalentDates answers the actual dates, each replaced by the first expected date it is equivalent to that has not
+// already replaced another, or an error if an expected or actual date is not valid EDTF
+func equivalentDates(expected, actual []string) ([]string, error) {
+	parsed := make([]EDTF, len(expected))
+	for i, e := range expected {
+		if strings.TrimSpace(e) == "" {
+			continue
+		}
+		var err error
+		if parsed[i], err = ParseEDTF(e); err != nil {
+			return nil, fmt.Errorf("model: expected value: %w", err)
+		}
+	}
+
+	if actual == nil {
+		return nil, nil
+	}
+	result := make([]string, len(actual))
+	used := make([]bool, len(expected))
+	for i, a := range actual {
+		result[i] = a
+		if strings.TrimSpace(a) == "" {
+			continue
+		}
+		date, err := ParseEDTF(a)
+		if err != nil {
+			return nil, fmt.Errorf("model: actual value: %w", err)
+		}
+		for j := range expected {
+			if !used[j] && strings.TrimSpace(expected[j]) != "" && parsed[j].Equal(date) {
+				used[j], result[i] = true, expected[j]
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+// labelOf answers a function answering the label of the data object (see labelErr)
+func labelOf(jad JsonApiData) func() (interface{}, error) {
+	return func() (interface{}, error) { return labelErr(jad) }
+}
+
+// labelsOf answers a function answering the labels of the data objects (see labelsErr)
+func labelsOf(data []JsonApiData) func() (interface{}, error) {
+	return func() (interface{}, error) { return labelsErr(data) }
+}
+
+// langValuesOf answers a function answering the language values (see langValuesErr)
+func langValuesOf(values []JsonApiLanguageValue) func() (interface{}, error) {
+	return func() (interface{}, error) { return langValuesErr(values) }
+}
+
+// agentsOf answers a function answering the agents (see agentsErr)
+func agentsOf(data []RelData) func() (interface{}, error) {
+	return func() (interface{}, error) { return agentsErr(data) }
+}
+
+// labelErr answers the label of the data object (see resolveLabelErr), or the empty string if it is zero
+func labelErr(jad JsonApiData) (string, error) {
+	if jad.IsZero() {
+		return "", nil
+	}
+	return resolveLabelErr(jad)
+}
+
+// labelsErr answers the label of each of the data objects, in order
+func labelsErr(data []JsonApiData) ([]string, error) {
+	var result []string
+	for _, jad := range data {
+		l, err := resolveLabelErr(jad)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, l)
+	}
+	return result, nil
+}
+
+// langValuesErr answers each language value in the form `en: value`, in order
+func langValuesErr(values []JsonApiLanguageValue) ([]string, error) {
+	var result []string
+	for _, lv := range values {
+		code, err := lv.langCodeErr()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, langValueString(code, lv.Value()))
+	}
+	return result, nil
+}
+
+// agentsErr answers each agent in the form `relators:pht: Adams, Ansel`, in order
+func agentsErr(data []RelData) ([]string, error) {
+	var result []string
+	for _, rd := range data {
+		relType, err := rd.MetaString(relTypeKey)
+		if err != nil {
+			return nil, fmt.Errorf("model: unable to read the role of %s %s: %w", rd.Type, rd.Id, err)
+		}
+		name, err := resolveLabelErr(rd.JsonApiData)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, agentString(relType, name))
+	}
+	return result, nil
+}
+
+// modelErr answers the Islandora model in the form `Paged Content <https://schema.org/Book>`, or the empty string if
+// the data object is zero
+func modelErr(jad JsonApiData) (string, error) {
+	if jad.IsZero() {
+		return "", nil
+	}
+	model, err := resolveCachedErr[JsonApiIslandoraModel](jad)
+	if err != nil {
+		return "", err
+	}
+	attrs := model.JsonApiData[0].JsonApiAttributes
+	return modelString(attrs.Name, attrs.ExternalUri.Uri), nil
+}
+
+// langCodeOf answers the language code of the language value, or the empty string if it is zero
+func langCodeOf(lv JsonApiLanguageValue) (string, error) {
+	if lv.IsZero() {
+		return "", nil
+	}
+	return lv.langCodeErr()
+}
+
+// languageStrings answers each expected language string in the form `en: value`, in order
+func languageStrings(values []ExpectedLangString) []string {
+	var result []string
+	for _, ls := range values {
+		result = append(result, langValueString(ls.LangCode, ls.Value))
+	}
+	return result
+}
+
+// linkUris answers the uri of each link, in order
+func linkUris(links []Link) []string {
+	var result []string
+	for _, l := range links {
+		result = append(result, l.Uri)
+	}
+	return result
+}
+
+func langValueString(langCode, value string) string {
+	return fmt.Sprintf("%s: %s", langCode, value)
+}
+
+func agentString(relType, name string) string {
+	return fmt.Sprintf("%s: %s", relType, name)
+}
+
+func modelString(name, externalUri string) string {
+	if name == "" && externalUri == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s <%s>", name, externalUri)
+}
@@ -0,0 +1,236 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// compareTestObj answers a repository object with a title, dates, a subject, a creator, and an English abstract,
+// along with a server resolving its relationships
+func compareTestObj(t *testing.T) JsonApiIslandoraObj {
+	server := newDocumentServer(map[string]string{
+		testUuid(1): fmt.Sprintf(`{"type": "taxonomy_term--subject", "id": "%s", "attributes": {"name": "Landscapes"}}`, testUuid(1)),
+		testUuid(2): fmt.Sprintf(`{"type": "taxonomy_term--person", "id": "%s", "attributes": {"name": "Adams, Ansel"}}`, testUuid(2)),
+		testUuid(3): fmt.Sprintf(`{"type": "taxonomy_term--language", "id": "%s", "attributes": {"field_language_code": "en"}}`, testUuid(3)),
+	})
+	t.Cleanup(server.Close)
+	setBaseUrl(t, server)
+
+	obj := JsonApiIslandoraObj{}
+	require.Nil(t, json.Unmarshal([]byte(fmt.Sprintf(`{"data": [{"type": "node--islandora_object", "id": "%s",
+		"attributes": {"title": "Moonrise", "field_date_created": ["1941"], "field_jhir": {"uri": "https://jscholarship.library.jhu.edu/handle/1774.2/1"}},
+		"relationships": {
+			"field_subject": {"data": [{"type": "taxonomy_term--subject", "id": "%s"}]},
+			"field_creator": {"data": [{"type": "taxonomy_term--person", "id": "%s", "meta": {"rel_type": "relators:pht"}}]},
+			"field_abstract": {"data": [{"type": "taxonomy_term--language", "id": "%s", "meta": {"value": "Moonrise over Hernandez"}}]}}}]}`,
+		testUuid(0), testUuid(1), testUuid(2), testUuid(3))), &obj))
+	return obj
+}
+
+func Test_DiffRepoObj(t *testing.T) {
+	obj := compareTestObj(t)
+
+	expected := ExpectedRepoObj{
+		Abstract:    []ExpectedLangString{{Value: "Moonrise over Hernandez", LangCode: "en"}},
+		DateCreated: []string{"1942"},
+		Subject:     []string{"Landscapes"},
+		JhirUri:     "https://jscholarship.library.jhu.edu/handle/1774.2/1",
+		Creator: []struct {
+			RelType string `json:"rel_type"`
+			Name    string
+		}{{RelType: "relators:pht", Name: "Adams, Ansel"}},
+	}
+	expected.Title = "Moonrise"
+
+	diffs := DiffRepoObj(expected, obj)
+	require.Equal(t, 1, len(diffs), "%v", diffs)
+	assert.Equal(t, FieldDiff{Field: "DateCreated", Expected: []string{"1942"}, Actual: []string{"1941"},
+		Missing: []string{"1942"}, Unexpected: []string{"1941"}}, diffs[0])
+	assert.Contains(t, diffs[0].String(), "missing from actual : [\"1942\"]")
+	assert.Contains(t, diffs[0].String(), "unexpected in actual: [\"1941\"]")
+
+	expected.DateCreated = []string{"1941"}
+	expected.Genre = []string{"Photographs"}
+	expected.Title = "Moonset"
+	diffs = DiffRepoObj(expected, obj)
+	require.Equal(t, 2, len(diffs), "%v", diffs)
+	assert.Equal(t, "Title", diffs[0].Field)
+	assert.Equal(t, "Genre", diffs[1].Field)
+
+	expected.Genre = nil
+	expected.Title = "Moonrise"
+	assert.True(t, CompareRepoObj(t, expected, obj))
+}
+
+func Test_DiffRepoObjDates(t *testing.T) {
+	obj := compareTestObj(t)
+	attrs := &obj.JsonApiData[0].JsonApiAttributes
+	attrs.DateCreated = []string{"194x", "1941-06~"}
+	attrs.DateAvailable = "2021-03-04"
+
+	expected := ExpectedRepoObj{
+		Abstract:      []ExpectedLangString{{Value: "Moonrise over Hernandez", LangCode: "en"}},
+		DateCreated:   []string{"1941-06~", "194X"},
+		DateAvailable: " 2021-03-04",
+		Subject:       []string{"Landscapes"},
+		JhirUri:       "https://jscholarship.library.jhu.edu/handle/1774.2/1",
+		Creator: []struct {
+			RelType string `json:"rel_type"`
+			Name    string
+		}{{RelType: "relators:pht", Name: "Adams, Ansel"}},
+	}
+	expected.Title = "Moonrise"
+	assert.Empty(t, DiffRepoObj(expected, obj), "equivalent dates are expected to match however they are written")
+
+	expected.DateCreated = []string{"1941-06", "194X"}
+	diffs := DiffRepoObj(expected, obj)
+	require.Equal(t, 1, len(diffs), "%v", diffs)
+	assert.Equal(t, []string{"1941-06"}, diffs[0].Missing)
+	assert.Equal(t, []string{"1941-06~"}, diffs[0].Unexpected)
+
+	attrs.DateCreated = []string{"circa 1941"}
+	diffs = DiffRepoObj(expected, obj)
+	require.Equal(t, 1, len(diffs), "%v", diffs)
+	assert.Equal(t, "DateCreated", diffs[0].Field)
+	assert.True(t, errors.Is(diffs[0].Err, ErrInvalidEDTF), "expected ErrInvalidEDTF, got %v", diffs[0].Err)
+	assert.Contains(t, diffs[0].String(), "actual value")
+
+	attrs.DateCreated = []string{"194X"}
+	expected.DateCreated = []string{"194X"}
+	expected.DateAvailable = "2021-13"
+	diffs = DiffRepoObj(expected, obj)
+	require.Equal(t, 1, len(diffs), "%v", diffs)
+	assert.Equal(t, "DateAvailable", diffs[0].Field)
+	assert.True(t, errors.Is(diffs[0].Err, ErrInvalidEDTF), "expected ErrInvalidEDTF, got %v", diffs[0].Err)
+	assert.Contains(t, diffs[0].String(), "expected value")
+}
+
+func Test_DiffRepoObjOptions(t *testing.T) {
+	obj := compareTestObj(t)
+
+	expected := ExpectedRepoObj{JhirUri: "https://jscholarship.library.jhu.edu/handle/1774.2/2"}
+	expected.Title = "Moon"
+
+	diffs := DiffRepoObj(expected, obj, IgnoreFields("JhirUri", "Subject", "Creator", "Abstract", "DateCreated"))
+	require.Equal(t, 1, len(diffs), "%v", diffs)
+	assert.Equal(t, "Title", diffs[0].Field)
+
+	diffs = DiffRepoObj(expected, obj, IgnoreFields("JhirUri", "Subject", "Creator", "Abstract", "DateCreated"),
+		MatchSubstring("Title"))
+	assert.Empty(t, diffs)
+
+	diffs = DiffRepoObj(expected, obj, IgnoreFields("JhirUri", "Subject", "Creator", "Abstract", "DateCreated", "Moo"),
+		CompareFieldWith("Title", func(expected, actual interface{}) bool { return actual == "Moonrise" }))
+	require.Equal(t, 1, len(diffs), "%v", diffs)
+	assert.Equal(t, "Moo", diffs[0].Field)
+	assert.NotNil(t, diffs[0].Err)
+}
+
+func Test_DiffCollectionLanguages(t *testing.T) {
+	server := newDocumentServer(map[string]string{
+		testUuid(1): fmt.Sprintf(`{"type": "taxonomy_term--language", "id": "%s", "attributes": {"field_language_code": "en"}}`, testUuid(1)),
+		testUuid(2): fmt.Sprintf(`{"type": "taxonomy_term--language", "id": "%s", "attributes": {"field_language_code": "es"}}`, testUuid(2)),
+	})
+	defer server.Close()
+	setBaseUrl(t, server)
+	ResetLangCodeCache()
+	t.Cleanup(ResetLangCodeCache)
+
+	c := JsonApiCollection{}
+	require.Nil(t, json.Unmarshal([]byte(fmt.Sprintf(`{"data": [{"type": "node--collection_object", "id": "%s",
+		"attributes": {"title": "Photographs"},
+		"relationships": {
+			"field_title_language": {"data": {"type": "taxonomy_term--language", "id": "%s"}},
+			"field_alternative_title": {"data": [
+				{"type": "taxonomy_term--language", "id": "%s", "meta": {"value": "Fotografías"}},
+				{"type": "taxonomy_term--language", "id": "%s", "meta": {"value": "Pictures"}}]}}}]}`,
+		testUuid(0), testUuid(1), testUuid(2), testUuid(1))), &c))
+
+	expected := ExpectedCollection{
+		TitleLangCode: "en",
+		AltTitle:      []ExpectedLangString{{Value: "Pictures", LangCode: "en"}, {Value: "Fotografías", LangCode: "es"}},
+	}
+	expected.Title = "Photographs"
+	assert.True(t, CompareCollection(t, expected, c))
+
+	expected.AltTitle[1].LangCode = "en"
+	diffs := DiffCollection(expected, c)
+	require.Equal(t, 1, len(diffs), "%v", diffs)
+	assert.Equal(t, "AltTitle", diffs[0].Field)
+	assert.Equal(t, []string{"es: Fotografías", "en: Pictures"}, diffs[0].Actual)
+}
+
+func Test_DiffCollectionSiteFields(t *testing.T) {
+	server := newDocumentServer(map[string]string{
+		"5b6c7d8e-9f0a-4b1c-8d2e-3f4a5b6c7d8e": `{"type": "media--image", "id": "5b6c7d8e-9f0a-4b1c-8d2e-3f4a5b6c7d8e",
+			"attributes": {"name": "sheridan-thumbnail.jpg"}}`,
+		"c1d2e3f4-a5b6-4c7d-8e9f-0a1b2c3d4e5f": `{"type": "taxonomy_term--islandora_access", "id": "c1d2e3f4-a5b6-4c7d-8e9f-0a1b2c3d4e5f",
+			"attributes": {"name": "Public"}}`,
+	})
+	defer server.Close()
+	setBaseUrl(t, server)
+
+	c := JsonApiCollection{}
+	unmarshalTestdata(t, "collection.json", &c)
+	published, featured, weight := true, true, 3
+	expected := ExpectedCollection{
+		ContactEmail:        "specialcollections@example.edu",
+		ContactName:         "Special Collections",
+		CollectionNumber:    []string{"MS-0406"},
+		AccessTerms:         []string{"Public"},
+		FindingAid:          []Link{{Uri: "https://aspace.example.edu/repositories/3/resources/406", Title: "Finding aid"}},
+		FeaturedItem:        &featured,
+		Weight:              &weight,
+		Published:           &published,
+		RepresentativeImage: "sheridan-thumbnail.jpg",
+	}
+	expected.Title = "Sheridan Libraries Photographs"
+	assert.Empty(t, DiffCollection(expected, c, IgnoreFields("TitleLangCode")))
+
+	published = false
+	weight = 4
+	diffs := DiffCollection(expected, c, IgnoreFields("TitleLangCode"))
+	require.Equal(t, 2, len(diffs), "%v", diffs)
+	assert.Equal(t, "Weight", diffs[0].Field)
+	assert.Equal(t, "Published", diffs[1].Field)
+
+	// an expected false or zero is compared, rather than taken as absent
+	published, featured, weight = true, false, 0
+	diffs = DiffCollection(expected, c, IgnoreFields("TitleLangCode"))
+	require.Equal(t, 2, len(diffs), "%v", diffs)
+	assert.Equal(t, "FeaturedItem", diffs[0].Field)
+	assert.Equal(t, "Weight", diffs[1].Field)
+
+	expected.FeaturedItem, expected.Weight = nil, nil
+	assert.Empty(t, DiffCollection(expected, c, IgnoreFields("TitleLangCode")))
+}
+
+func Test_DiffFieldsAsSets(t *testing.T) {
+	fields := []comparedField{
+		field("Extent", []string{"1 box", "2 folders", "2 folders"}, valueOf([]string{"2 folders", "1 box", "3 maps"})),
+		field("Subject", []string{"Landscapes", "Moon"}, valueOf([]string{"Moon", "Landscapes"})),
+		field("FindingAid", []Link{{Uri: "https://example.org/a"}}, valueOf([]Link{{Uri: "https://example.org/a"}})),
+	}
+
+	diffs := diffFields(fields, newCompareOptions(nil))
+	require.Equal(t, 1, len(diffs), "%v", diffs)
+	assert.Equal(t, "Extent", diffs[0].Field)
+	assert.Equal(t, []string{"2 folders"}, diffs[0].Missing)
+	assert.Equal(t, []string{"3 maps"}, diffs[0].Unexpected)
+
+	diffs = diffFields(fields, newCompareOptions([]CompareOption{IgnoreFields("Extent"), RequireOrder("Subject")}))
+	require.Equal(t, 1, len(diffs), "%v", diffs)
+	assert.Equal(t, "Subject", diffs[0].Field)
+	assert.Nil(t, diffs[0].Missing)
+	assert.Contains(t, diffs[0].String(), "expected:")
+
+	diffs = diffFields(fields[1:2], newCompareOptions(nil))
+	assert.Empty(t, diffs)
+	diffs = diffFields([]comparedField{fields[1].inOrder()}, newCompareOptions(nil))
+	assert.Equal(t, 1, len(diffs))
+}
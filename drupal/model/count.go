@@ -0,0 +1,35 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// AssertCount asserts the number of entities of the bundle, e.g. after a migration:
+//
+//	model.AssertCount(t, model.Node, model.Collection, 14)
+//	model.AssertCount(t, model.TaxonomyTerm, "language", 486)
+//
+// Entities are counted by the `meta.count` of the response if Drupal supplies it (see jsonapi.Client.Count), and
+// otherwise by paging through them.  The count is of the entities visible to the user from the environment (or as
+// supplied by the options).  A failure reports the actual count and the url of the query.
+func AssertCount(t *testing.T, entity, bundle string, expected int, opts ...Option) bool {
+	return AssertCountWhere(t, entity, bundle, "", expected, opts...)
+}
+
+// AssertCountWhere behaves as AssertCount, but counts only the entities matching the filter, which is appended to the
+// url of the query as-is (see jsonapi.JsonApiUrl.RawFilter), e.g. the repository objects of a collection:
+//
+//	model.AssertCountWhere(t, model.Node, model.RepositoryObject, "filter[field_member_of.id]="+colUuid, 212)
+func AssertCountWhere(t *testing.T, entity, bundle, filter string, expected int, opts ...Option) bool {
+	u := query(t, entity, bundle, opts...)
+	u.RawFilter = filter
+	count, err := u.CountErr()
+	if err != nil {
+		return assert.Fail(t, fmt.Sprintf("unable to count %s--%s: %s", entity, bundle, err))
+	}
+	return assert.Equal(t, expected, count, "expected %d %s--%s, but counted %d: %s", expected, entity, bundle, count,
+		u.String())
+}
@@ -0,0 +1,28 @@
+package model
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_AssertCount(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/jsonapi/node/islandora_object" && r.URL.Query().Get("filter[field_member_of.id]") == testUuid(1):
+			_, _ = fmt.Fprintf(w, `{"data": [{"type": "node--islandora_object", "id": "%s"}]}`, testUuid(2))
+		case r.URL.Path == "/jsonapi/node/islandora_object":
+			_, _ = w.Write([]byte(`{"data": [], "meta": {"count": 212}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	setBaseUrl(t, server)
+
+	assert.True(t, AssertCount(t, Node, RepositoryObject, 212))
+	assert.True(t, AssertCountWhere(t, Node, RepositoryObject, "filter[field_member_of.id]="+testUuid(1), 1))
+}
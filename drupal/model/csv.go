@@ -0,0 +1,201 @@
+package model
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// The default delimiter between the values of a multi-valued cell, matching the Islandora Workbench subdelimiter
+	DefaultCsvDelimiter = "|"
+	// Separates the parts of a typed value, e.g. the uri and title of a link (`uri%%title`), or the value and language of
+	// a language-tagged value (`value%%lang`)
+	CsvTypedSeparator = "%%"
+)
+
+// CsvMapping describes how the rows of a migration CSV (e.g. an Islandora Workbench input CSV) populate an expected
+// struct such as ExpectedRepoObj or ExpectedCollection.
+//
+// Cells are decoded according to the type of the field they populate:
+//   - strings are copied as-is, and booleans and numbers are parsed
+//   - pointers (e.g. the optional FeaturedItem of ExpectedCollection) are set to the decoded value
+//   - slices are split on the Delimiter, each value populating one element
+//   - structs (e.g. Link, ExpectedLangString, or the creators of a repository object) are split on CsvTypedSeparator,
+//     the parts populating the string fields of the struct in the order they are declared.  Links are written as
+//     `uri%%title`, language-tagged values as `value%%lang`, and creators as `rel_type%%name`.
+//
+// Empty cells leave the field at its zero value.
+type CsvMapping struct {
+	// Maps the header of a CSV column to the name of the expected struct field it populates, e.g. `field_subject` to
+	// `Subject`.  Columns that are not mapped are ignored.
+	Columns map[string]string
+	// Separates the values of a multi-valued cell; DefaultCsvDelimiter is used if empty
+	Delimiter string
+}
+
+// ReadExpectedCsv reads the CSV file at the supplied path, and answers one expected struct of type T per row, in order.
+// The first row of the file must be a header naming each column.  The test fails if the file cannot be read, or if
+// any row cannot be mapped (see CsvMapping):
+//
+//	mapping := model.CsvMapping{Columns: map[string]string{"title": "Title", "field_subject": "Subject"}}
+//	for _, expected := range model.ReadExpectedCsv[model.ExpectedRepoObj](t, "testdata/objects.csv", mapping) {
+//		actual := model.FindObjectByTitle(t, expected.Title)
+//		model.CompareRepoObj(t, expected, actual)
+//	}
+func ReadExpectedCsv[T any](t *testing.T, path string, mapping CsvMapping) []T {
+	v, err := ReadExpectedCsvErr[T](path, mapping)
+	require.Nil(t, err, "%s", err)
+	return v
+}
+
+// ReadExpectedCsvErr behaves as ReadExpectedCsv, but answers an error instead of failing the test
+func ReadExpectedCsvErr[T any](path string, mapping CsvMapping) ([]T, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("model: unable to read expected CSV: %w", err)
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	header, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("model: unable to read the header of %s: %w", path, err)
+	}
+
+	var result []T
+	for line := 2; ; line++ {
+		row, err := r.Read()
+		if err == io.EOF {
+			return result, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("model: unable to read %s: %w", path, err)
+		}
+
+		v, err := ExpectedFromCsvErr[T](mapping, header, row)
+		if err != nil {
+			return nil, fmt.Errorf("model: %s line %d: %w", path, line, err)
+		}
+		result = append(result, v)
+	}
+}
+
+// ExpectedFromCsvErr maps a single CSV row into a new expected struct of type T.  The header names the column of each
+// cell in the row.  An error is answered if a mapped column is missing from the header, if a mapped field does not
+// exist on T, or if a cell cannot be converted to the type of its field.
+func ExpectedFromCsvErr[T any](mapping CsvMapping, header, row []string) (T, error) {
+	var v T
+	val := reflect.ValueOf(&v).Elem()
+	if val.Kind() != reflect.Struct {
+		return v, fmt.Errorf("model: %T is not a struct", v)
+	}
+
+	delimiter := mapping.Delimiter
+	if delimiter == "" {
+		delimiter = DefaultCsvDelimiter
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+
+	for column, fieldName := range mapping.Columns {
+		i, ok := columns[column]
+		if !ok {
+			return v, fmt.Errorf("model: mapped column '%s' is not present in the CSV header", column)
+		}
+		f := val.FieldByName(fieldName)
+		if !f.IsValid() || !f.CanSet() {
+			return v, fmt.Errorf("model: column '%s' is mapped to '%s', which is not a field of %T", column, fieldName, v)
+		}
+		if i >= len(row) {
+			continue
+		}
+		if err := setCsvValue(f, strings.TrimSpace(row[i]), delimiter); err != nil {
+			return v, fmt.Errorf("model: column '%s': %w", column, err)
+		}
+	}
+
+	return v, nil
+}
+
+// setCsvValue decodes the cell into the supplied field according to its type (see CsvMapping)
+func setCsvValue(f reflect.Value, cell string, delimiter string) error {
+	if cell == "" {
+		return nil
+	}
+
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(cell)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(cell)
+		if err != nil {
+			return fmt.Errorf("%w: '%s' to bool", ErrConversion, cell)
+		}
+		f.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(cell, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%w: '%s' to int", ErrConversion, cell)
+		}
+		f.SetInt(n)
+	case reflect.Float64:
+		n, err := strconv.ParseFloat(cell, 64)
+		if err != nil {
+			return fmt.Errorf("%w: '%s' to float64", ErrConversion, cell)
+		}
+		f.SetFloat(n)
+	case reflect.Struct:
+		return setCsvTypedValue(f, cell)
+	case reflect.Ptr:
+		elem := reflect.New(f.Type().Elem())
+		if err := setCsvValue(elem.Elem(), cell, delimiter); err != nil {
+			return err
+		}
+		f.Set(elem)
+	case reflect.Slice:
+		values := reflect.MakeSlice(f.Type(), 0, 0)
+		for _, part := range strings.Split(cell, delimiter) {
+			if part = strings.TrimSpace(part); part == "" {
+				continue
+			}
+			elem := reflect.New(f.Type().Elem()).Elem()
+			if err := setCsvValue(elem, part, delimiter); err != nil {
+				return err
+			}
+			values = reflect.Append(values, elem)
+		}
+		f.Set(values)
+	default:
+		return fmt.Errorf("%w: '%s' to %s", ErrConversion, cell, f.Type())
+	}
+
+	return nil
+}
+
+// setCsvTypedValue splits the value on CsvTypedSeparator, and assigns each part to the string fields of the struct in
+// the order they are declared.  A value may have fewer parts than the struct has string fields, but not more.
+func setCsvTypedValue(f reflect.Value, value string) error {
+	parts := strings.Split(value, CsvTypedSeparator)
+	next := 0
+	for i := 0; i < f.NumField() && next < len(parts); i++ {
+		if field := f.Field(i); field.Kind() == reflect.String && field.CanSet() {
+			field.SetString(strings.TrimSpace(parts[next]))
+			next++
+		}
+	}
+
+	if next < len(parts) {
+		return fmt.Errorf("%w: '%s' to %s: too many '%s' separated parts", ErrConversion, value, f.Type(), CsvTypedSeparator)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testCsvMapping = CsvMapping{Columns: map[string]string{
+	"title":               "Title",
+	"field_model":         "Model",
+	"field_subject":       "Subject",
+	"field_abstract":      "Abstract",
+	"field_finding_aid":   "FindingAid",
+	"field_creator":       "Creator",
+	"field_featured_item": "FeaturedItem",
+}}
+
+func Test_ReadExpectedCsv(t *testing.T) {
+	objs := ReadExpectedCsv[ExpectedRepoObj](t, filepath.Join("testdata", "objects.csv"), testCsvMapping)
+	require.Equal(t, 2, len(objs))
+
+	obj := objs[0]
+	assert.Equal(t, "Moonrise", obj.Title)
+	assert.Equal(t, "Photograph", obj.Model.Name)
+	assert.Equal(t, "http://purl.org/coar/resource_type/c_c513", obj.Model.ExternalUri)
+	assert.Equal(t, []string{"Landscapes", "Moon"}, obj.Subject)
+	assert.Equal(t, []ExpectedLangString{{Value: "Moonrise over Hernandez", LangCode: "en"}}, obj.Abstract)
+	assert.Equal(t, []Link{{Uri: "https://example.org/aid", Title: "Finding Aid"}}, obj.FindingAid)
+	require.Equal(t, 1, len(obj.Creator))
+	assert.Equal(t, "relators:pht", obj.Creator[0].RelType)
+	assert.Equal(t, "Adams  Ansel", obj.Creator[0].Name)
+	assert.True(t, obj.FeaturedItem)
+
+	obj = objs[1]
+	assert.Equal(t, "Clearing Winter Storm", obj.Title)
+	assert.Equal(t, []string{"Landscapes"}, obj.Subject)
+	assert.Nil(t, obj.Abstract)
+	assert.False(t, obj.FeaturedItem)
+}
+
+func Test_ExpectedFromCsvErr(t *testing.T) {
+	header := []string{"title", "field_description", "field_access_terms", "field_finding_aid"}
+	mapping := CsvMapping{Delimiter: ";", Columns: map[string]string{
+		"title":              "Title",
+		"field_description":  "Description",
+		"field_access_terms": "AccessTerms",
+		"field_finding_aid":  "FindingAid",
+	}}
+
+	c, err := ExpectedFromCsvErr[ExpectedCollection](mapping, header,
+		[]string{"Photographs", "Prints%%en; Estampes%%fr", "Public; Staff", "https://example.org/aid"})
+	require.Nil(t, err, "%s", err)
+	assert.Equal(t, "Photographs", c.Title)
+	require.Equal(t, 2, len(c.Description))
+	assert.Equal(t, "Estampes", c.Description[1].Value)
+	assert.Equal(t, "fr", c.Description[1].LangCode)
+	assert.Equal(t, []string{"Public", "Staff"}, c.AccessTerms)
+	assert.Equal(t, []Link{{Uri: "https://example.org/aid"}}, c.FindingAid)
+
+	_, err = ExpectedFromCsvErr[ExpectedCollection](mapping, header, []string{"Photographs", "", "", "a%%b%%c"})
+	assert.True(t, errors.Is(err, ErrConversion))
+
+	_, err = ExpectedFromCsvErr[ExpectedCollection](CsvMapping{Columns: map[string]string{"title": "Moo"}}, header, []string{""})
+	assert.Contains(t, fmt.Sprint(err), "'Moo'")
+
+	_, err = ExpectedFromCsvErr[ExpectedCollection](CsvMapping{Columns: map[string]string{"moo": "Title"}}, header, []string{""})
+	assert.Contains(t, fmt.Sprint(err), "'moo'")
+
+	_, err = ExpectedFromCsvErr[ExpectedRepoObj](CsvMapping{Columns: map[string]string{"title": "FeaturedItem"}}, header, []string{"moo"})
+	assert.True(t, errors.Is(err, ErrConversion))
+
+	optional := CsvMapping{Columns: map[string]string{"field_featured_item": "FeaturedItem", "field_weight": "Weight"}}
+	c, err = ExpectedFromCsvErr[ExpectedCollection](optional, []string{"field_featured_item", "field_weight"},
+		[]string{"false", "0"})
+	require.Nil(t, err, "%s", err)
+	require.NotNil(t, c.FeaturedItem)
+	assert.False(t, *c.FeaturedItem)
+	require.NotNil(t, c.Weight)
+	assert.Equal(t, 0, *c.Weight)
+
+	c, err = ExpectedFromCsvErr[ExpectedCollection](optional, []string{"field_featured_item", "field_weight"},
+		[]string{"", ""})
+	require.Nil(t, err, "%s", err)
+	assert.Nil(t, c.FeaturedItem, "an empty cell is expected to leave the field absent")
+	assert.Nil(t, c.Weight)
+}
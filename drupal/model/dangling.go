@@ -0,0 +1,166 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/stretchr/testify/require"
+)
+
+// The number of referenced entities whose existence is checked by a single request
+const existenceBatchSize = 50
+
+// The data object Drupal renders for a reference to an entity that no longer exists
+const (
+	missingType = "unknown"
+	missingId   = "missing"
+)
+
+// DanglingRef is a reference of a relationship to an entity that does not exist, or is not visible to the user
+type DanglingRef struct {
+	// The referencing entity
+	Source JsonApiData
+	// The name, or title, of the referencing entity
+	Label string
+	// The name of the relationship field, e.g. `field_media_of`
+	Field string
+	// The referenced entity.  Drupal renders a reference to a deleted entity as the type `unknown` and the id
+	// `missing`, in which case the internal id of the deleted entity is carried by the TargetMeta, e.g. as
+	// `drupal_internal__target_id`.
+	Target     JsonApiData
+	TargetMeta map[string]interface{}
+}
+
+// String answers a readable description of the reference, e.g.
+// `media--image 0f6a… ('Moonrise.jpg') field_media_of -> node--islandora_object 5c2e…`
+func (d DanglingRef) String() string {
+	target := fmt.Sprintf("%s %s", d.Target.Type, d.Target.Id)
+	if id, ok := d.TargetMeta["drupal_internal__target_id"]; ok {
+		target = fmt.Sprintf("%s (internal id %v)", target, id)
+	}
+	return fmt.Sprintf("%s %s ('%s') %s -> %s", d.Source.Type, d.Source.Id, d.Label, d.Field, target)
+}
+
+// FindOrphanedMedia answers the media, of every bundle, whose field_media_of references a node that does not exist,
+// e.g. after a partial rollback deleted the node but not its media, failing the test immediately if they cannot be
+// retrieved.  An audit asserts that none are answered:
+//
+//	assert.Empty(t, model.FindOrphanedMedia(t))
+func FindOrphanedMedia(t *testing.T, opts ...Option) []DanglingRef {
+	orphans, err := FindOrphanedMediaErr(opts...)
+	require.Nil(t, err, "%s", err)
+	return orphans
+}
+
+// FindOrphanedMediaErr behaves as FindOrphanedMedia, but answers an error instead of failing the test
+func FindOrphanedMediaErr(opts ...Option) ([]DanglingRef, error) {
+	var orphans []DanglingRef
+	for _, b := range mediaBundles {
+		dangling, err := FindDanglingRefsErr(MediaEntity, b.bundle, "field_media_of", opts...)
+		if err != nil {
+			return nil, err
+		}
+		orphans = append(orphans, dangling...)
+	}
+	return orphans, nil
+}
+
+// FindDanglingRefs answers the references of the relationship field of each entity of the bundle to entities that do
+// not exist, failing the test immediately if they cannot be retrieved:
+//
+//	assert.Empty(t, model.FindDanglingRefs(t, model.Node, model.RepositoryObject, "field_member_of"))
+//
+// Every entity of the bundle is retrieved, page by page, carrying only its label and the relationship field.  The
+// existence of the referenced entities is then checked in batches, by filtering each referenced bundle on the ids of
+// the batch.  References to config entities (e.g. a node type), which are not identified by a UUID, are not checked.
+// An entity the user may not view is indistinguishable from one that does not exist, so audits should authenticate as
+// an administrator.  The result is ordered by the type and id of the referencing entity.
+func FindDanglingRefs(t *testing.T, entity, bundle, relationshipField string, opts ...Option) []DanglingRef {
+	dangling, err := FindDanglingRefsErr(entity, bundle, relationshipField, opts...)
+	require.Nil(t, err, "%s", err)
+	return dangling
+}
+
+// FindDanglingRefsErr behaves as FindDanglingRefs, but answers an error instead of failing the test
+func FindDanglingRefsErr(entity, bundle, relationshipField string, opts ...Option) ([]DanglingRef, error) {
+	u := query(nil, entity, bundle, opts...)
+	u.RawFilter = fmt.Sprintf("fields[%s]=name,title,%s", jsonapi.NewDrupalType(entity, bundle), relationshipField)
+	sources := rawEntity{}
+	if err := u.GetAllErr(&sources); err != nil {
+		return nil, fmt.Errorf("model: unable to retrieve %s %s: %w", entity, bundle, err)
+	}
+
+	var dangling, unchecked []DanglingRef
+	for _, d := range sources.JsonApiData {
+		refs, err := relationshipRefsErr(d.Relationships[relationshipField].Data)
+		if err != nil {
+			return nil, fmt.Errorf("model: unable to read %s of %s %s: %w", relationshipField, d.Type, d.Id, err)
+		}
+		for _, ref := range refs {
+			r := DanglingRef{Source: JsonApiData{Type: d.Type, Id: d.Id}, Label: rawLabel(d.Attributes),
+				Field: relationshipField, Target: JsonApiData{Type: ref.Type, Id: ref.Id}, TargetMeta: ref.Meta}
+			switch {
+			case string(ref.Type) == missingType || ref.Id == missingId:
+				dangling = append(dangling, r)
+			case r.Target.Validate() == nil:
+				unchecked = append(unchecked, r)
+			}
+		}
+	}
+
+	existing, err := existingErr(unchecked, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range unchecked {
+		if !existing[string(r.Target.Type)+" "+r.Target.Id] {
+			dangling = append(dangling, r)
+		}
+	}
+
+	sort.SliceStable(dangling, func(i, j int) bool {
+		a, b := dangling[i].Source, dangling[j].Source
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		return a.Id < b.Id
+	})
+	return dangling, nil
+}
+
+// existingErr answers the `type id` of each target of the references that exists, checking the targets of each bundle
+// in batches of existenceBatchSize
+func existingErr(refs []DanglingRef, opts []Option) (map[string]bool, error) {
+	ids := map[jsonapi.DrupalType][]string{}
+	seen := map[string]bool{}
+	for _, r := range refs {
+		key := string(r.Target.Type) + " " + r.Target.Id
+		if !seen[key] {
+			seen[key] = true
+			ids[r.Target.Type] = append(ids[r.Target.Type], r.Target.Id)
+		}
+	}
+
+	existing := map[string]bool{}
+	for t, all := range ids {
+		for start := 0; start < len(all); start += existenceBatchSize {
+			batch := all[start:min(start+existenceBatchSize, len(all))]
+			u := query(nil, t.Entity(), t.Bundle(), opts...)
+			u.RawFilter = "filter[ids][condition][path]=id&filter[ids][condition][operator]=IN&" +
+				"filter[ids][condition][value][]=" + strings.Join(batch, "&filter[ids][condition][value][]=")
+			found := struct {
+				Data []JsonApiData
+			}{}
+			if err := u.GetAllErr(&found); err != nil {
+				return nil, fmt.Errorf("model: unable to check the existence of %d %s: %w", len(batch), t, err)
+			}
+			for _, d := range found.Data {
+				existing[string(d.Type)+" "+d.Id] = true
+			}
+		}
+	}
+	return existing, nil
+}
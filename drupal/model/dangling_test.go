@@ -0,0 +1,79 @@
+package model
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_FindOrphanedMedia(t *testing.T) {
+	var checked []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/jsonapi/media/image":
+			assert.Equal(t, "name,title,field_media_of", r.URL.Query().Get("fields[media--image]"))
+			_, _ = fmt.Fprintf(w, `{"data": [
+				{"type": "media--image", "id": "%[1]s", "attributes": {"name": "moo.jpg"},
+					"relationships": {"field_media_of": {"data": {"type": "node--islandora_object", "id": "%[4]s"}}}},
+				{"type": "media--image", "id": "%[2]s", "attributes": {"name": "cow.jpg"},
+					"relationships": {"field_media_of": {"data": {"type": "node--islandora_object", "id": "%[5]s"}}}},
+				{"type": "media--image", "id": "%[3]s", "attributes": {"name": "calf.jpg"},
+					"relationships": {"field_media_of": {"data": {"type": "unknown", "id": "missing",
+						"meta": {"drupal_internal__target_id": 12}}}}}]}`,
+				testUuid(1), testUuid(2), testUuid(3), testUuid(4), testUuid(5))
+		case "/jsonapi/node/islandora_object":
+			q := r.URL.Query()
+			assert.Equal(t, "IN", q.Get("filter[ids][condition][operator]"))
+			checked = append(checked, q["filter[ids][condition][value][]"]...)
+			_, _ = fmt.Fprintf(w, `{"data": [{"type": "node--islandora_object", "id": "%s"}]}`, testUuid(4))
+		default:
+			_, _ = w.Write([]byte(`{"data": []}`))
+		}
+	}))
+	defer server.Close()
+	setBaseUrl(t, server)
+
+	orphans := FindOrphanedMedia(t)
+	assert.Equal(t, []string{testUuid(4), testUuid(5)}, checked, "the targets are expected to be checked in one batch")
+	if assert.Len(t, orphans, 2) {
+		assert.Equal(t, testUuid(2), orphans[0].Source.Id)
+		assert.Equal(t, "cow.jpg", orphans[0].Label)
+		assert.Equal(t, testUuid(5), orphans[0].Target.Id)
+		assert.Equal(t, testUuid(3), orphans[1].Source.Id)
+		assert.True(t, strings.HasSuffix(orphans[1].String(), "field_media_of -> unknown missing (internal id 12)"),
+			orphans[1].String())
+	}
+}
+
+func Test_FindDanglingRefsBatches(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/jsonapi/node/islandora_object":
+			var refs []string
+			for i := 0; i < existenceBatchSize+1; i++ {
+				refs = append(refs, fmt.Sprintf(`{"type": "taxonomy_term--subject", "id": "%s"}`, testUuid(100+i)))
+			}
+			_, _ = fmt.Fprintf(w, `{"data": [{"type": "node--islandora_object", "id": "%s",
+				"relationships": {"field_subject": {"data": [%s]}}}]}`, testUuid(1), strings.Join(refs, ","))
+		case "/jsonapi/taxonomy_term/subject":
+			requests++
+			var found []string
+			for _, id := range r.URL.Query()["filter[ids][condition][value][]"] {
+				found = append(found, fmt.Sprintf(`{"type": "taxonomy_term--subject", "id": "%s"}`, id))
+			}
+			_, _ = fmt.Fprintf(w, `{"data": [%s]}`, strings.Join(found, ","))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	setBaseUrl(t, server)
+
+	assert.Empty(t, FindDanglingRefs(t, Node, RepositoryObject, "field_subject"))
+	assert.Equal(t, 2, requests)
+}
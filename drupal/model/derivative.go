@@ -0,0 +1,89 @@
+package model
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Constant for the Drupal media entity type
+const MediaEntity = "media"
+
+// Islandora Media Use term names of the media produced by the derivative pipeline
+const (
+	OriginalFile     = "Original File"
+	ServiceFile      = "Service File"
+	ThumbnailImage   = "Thumbnail Image"
+	ExtractedTextUse = "Extracted Text"
+)
+
+// mediaOf retrieves the media of the bundle that belong to the repository object
+type mediaOf func(t *testing.T, bundle, objUuid string) ([]Media, error)
+
+// The media bundles searched by MediaOfObject, with the function retrieving the media of each bundle
+var mediaBundles = []struct {
+	bundle  string
+	mediaOf mediaOf
+}{
+	{Image, mediaOfBundle[JsonApiImageMedia]},
+	{Document, mediaOfBundle[JsonApiDocumentMedia]},
+	{Audio, mediaOfBundle[JsonApiAudioMedia]},
+	{Video, mediaOfBundle[JsonApiVideoMedia]},
+	{ExtractedText, mediaOfBundle[JsonApiExtractedTextMedia]},
+	{File, mediaOfBundle[JsonApiGenericFileMedia]},
+	{Fits, mediaOfBundle[JsonApiFitsMedia]},
+	{RemoteVideo, mediaOfBundle[JsonApiRemoteVideoMedia]},
+}
+
+// MediaOfObject retrieves every media, of every bundle, whose field_media_of references the repository object.  Each
+// element of the result is a media struct of the appropriate bundle (e.g. JsonApiImageMedia) carrying exactly one data
+// element.
+func MediaOfObject(t *testing.T, objUuid string) []Media {
+	var result []Media
+	for _, b := range mediaBundles {
+		media, err := b.mediaOf(t, b.bundle, objUuid)
+		assert.Nil(t, err, "%s", err)
+		result = append(result, media...)
+	}
+	return result
+}
+
+// DerivativePresent asserts that the repository object has a media with the named media use (e.g. ThumbnailImage), and
+// answers it so that its attributes (e.g. its MimeType) may be asserted.  The media use terms of each media are resolved
+// and matched by name.  If no such media is present, nil is answered.
+func DerivativePresent(t *testing.T, objUuid, mediaUseName string) Media {
+	for _, m := range MediaOfObject(t, objUuid) {
+		for _, use := range resolveLabels(t, m.MediaUse()) {
+			if use == mediaUseName {
+				return m
+			}
+		}
+	}
+
+	assert.Fail(t, fmt.Sprintf("repository object %s has no media with media use '%s'", objUuid, mediaUseName))
+	return nil
+}
+
+// mediaOfBundle retrieves the media of type T that belong to the repository object, and splits them into one T per
+// data element
+func mediaOfBundle[T Media](t *testing.T, bundle, objUuid string) ([]Media, error) {
+	u := query(t, MediaEntity, bundle)
+	u.Filter = "field_media_of.id"
+	u.Value = objUuid
+
+	var all T
+	if err := u.GetAllErr(&all); err != nil {
+		return nil, fmt.Errorf("model: unable to retrieve %s media of %s: %w", bundle, objUuid, err)
+	}
+
+	data := reflect.ValueOf(all).FieldByName("JsonApiData")
+	media := make([]Media, data.Len())
+	for i := range media {
+		single := reflect.New(reflect.TypeOf(all)).Elem()
+		single.FieldByName("JsonApiData").Set(data.Slice(i, i+1))
+		media[i] = single.Interface().(Media)
+	}
+	return media, nil
+}
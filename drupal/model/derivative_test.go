@@ -0,0 +1,58 @@
+package model
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mediaElement answers a JSON API data element for a media of the bundle, with the mime type and media use
+func mediaElement(bundle, id, mimeType, mediaUseId, objId string) string {
+	return fmt.Sprintf(`{"type": "media--%s", "id": "%s", "attributes": {"name": "%s", "field_mime_type": "%s"},
+		"relationships": {"field_media_use": {"data": [{"type": "taxonomy_term--islandora_media_use", "id": "%s"}]},
+		"field_media_of": {"data": {"type": "node--islandora_object", "id": "%s"}}}}`, bundle, id, id, mimeType, mediaUseId, objId)
+}
+
+// newMediaServer answers a server responding with the media elements of each path, which must be media of the object,
+// and with the media use terms named by id
+func newMediaServer(t *testing.T, objId string, media, terms map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if name, ok := terms[requestedId(r)]; ok {
+			_, _ = fmt.Fprintf(w, `{"data": [{"type": "taxonomy_term--islandora_media_use", "id": "%s", "attributes": {"name": "%s"}}]}`,
+				requestedId(r), name)
+			return
+		}
+		assert.Equal(t, objId, r.URL.Query().Get("filter[field_media_of.id]"))
+		_, _ = fmt.Fprintf(w, `{"data": [%s]}`, media[r.URL.Path])
+	}))
+}
+
+func Test_DerivativePresent(t *testing.T) {
+	objId, originalUse, thumbnailUse := testUuid(0), testUuid(1), testUuid(2)
+	media := map[string]string{
+		"/jsonapi/media/document": mediaElement(Document, testUuid(3), "application/pdf", originalUse, objId),
+		"/jsonapi/media/image": mediaElement(Image, testUuid(4), "image/tiff", originalUse, objId) + "," +
+			mediaElement(Image, testUuid(5), "image/jpeg", thumbnailUse, objId),
+	}
+	terms := map[string]string{originalUse: OriginalFile, thumbnailUse: ThumbnailImage}
+
+	server := newMediaServer(t, objId, media, terms)
+	defer server.Close()
+	setBaseUrl(t, server)
+
+	all := MediaOfObject(t, objId)
+	require.Equal(t, 3, len(all))
+	assert.IsType(t, JsonApiImageMedia{}, all[0])
+	assert.Equal(t, "image/tiff", all[0].MimeType())
+	assert.IsType(t, JsonApiDocumentMedia{}, all[2])
+	assert.Equal(t, objId, all[2].MediaOf().Id)
+
+	thumbnail := DerivativePresent(t, objId, ThumbnailImage)
+	require.NotNil(t, thumbnail)
+	assert.Equal(t, "image/jpeg", thumbnail.MimeType())
+	assert.Equal(t, testUuid(5), thumbnail.Name())
+}
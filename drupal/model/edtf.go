@@ -0,0 +1,195 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var ErrInvalidEDTF = errors.New("invalid EDTF")
+
+// matches a single EDTF date: an optionally negative year, optional month (or season) and day, where any digit may be
+// unspecified ('X'), followed by an optional uncertain ('?'), approximate ('~'), or uncertain and approximate ('%')
+// qualifier
+var edtfDatePattern = regexp.MustCompile(`^(-?[0-9X]{4})(?:-([0-9X]{2})(?:-([0-9X]{2}))?)?([?~%])?$`)
+
+// A single date of an EDTF value, e.g. `1922~` or `1985-04-XX`
+type EDTFDate struct {
+	// The year, which may carry unspecified digits ('X'), e.g. `192X`
+	Year string
+	// The month (or season, 21 through 24), empty if the date has year precision
+	Month string
+	// The day, empty if the date has year or month precision
+	Day string
+	// Whether the date is approximate ('~' or '%')
+	Approximate bool
+	// Whether the date is uncertain ('?' or '%')
+	Uncertain bool
+	// Whether the date is the open end of an interval ('..')
+	Open bool
+	// Whether the date is the unknown end of an interval (empty)
+	Unknown bool
+}
+
+// An Extended Date/Time Format value, e.g. `1922~`, `192X`, or `1900/1910`.  Level 0 and level 1 features are
+// supported: reduced precision, unspecified digits, qualification of an entire date, seasons, and intervals with open
+// or unknown ends.
+type EDTF struct {
+	Start EDTFDate
+	// The end of the interval; only meaningful if Interval is true
+	End      EDTFDate
+	Interval bool
+}
+
+// ParseEDTF parses the supplied string as an EDTF value, answering an error wrapping ErrInvalidEDTF if it cannot be
+// parsed.  Unspecified digits are accepted in either case.
+func ParseEDTF(s string) (EDTF, error) {
+	value := strings.ToUpper(strings.TrimSpace(s))
+	if value == "" {
+		return EDTF{}, fmt.Errorf("%w: empty value", ErrInvalidEDTF)
+	}
+
+	if !strings.Contains(value, "/") {
+		date, err := parseEDTFDate(value, false)
+		if err != nil {
+			return EDTF{}, fmt.Errorf("%w: '%s': %s", ErrInvalidEDTF, s, err)
+		}
+		return EDTF{Start: date}, nil
+	}
+
+	parts := strings.Split(value, "/")
+	if len(parts) != 2 {
+		return EDTF{}, fmt.Errorf("%w: '%s': an interval must have exactly one '/'", ErrInvalidEDTF, s)
+	}
+
+	start, err := parseEDTFDate(parts[0], true)
+	if err != nil {
+		return EDTF{}, fmt.Errorf("%w: '%s': start of interval: %s", ErrInvalidEDTF, s, err)
+	}
+	end, err := parseEDTFDate(parts[1], true)
+	if err != nil {
+		return EDTF{}, fmt.Errorf("%w: '%s': end of interval: %s", ErrInvalidEDTF, s, err)
+	}
+	if (start.Open || start.Unknown) && (end.Open || end.Unknown) {
+		return EDTF{}, fmt.Errorf("%w: '%s': an interval must have at least one date", ErrInvalidEDTF, s)
+	}
+
+	return EDTF{Start: start, End: end, Interval: true}, nil
+}
+
+// parseEDTFDate parses a single EDTF date.  If the date is part of an interval, it may be open or unknown.
+func parseEDTFDate(s string, interval bool) (EDTFDate, error) {
+	if interval && s == ".." {
+		return EDTFDate{Open: true}, nil
+	}
+	if interval && s == "" {
+		return EDTFDate{Unknown: true}, nil
+	}
+
+	m := edtfDatePattern.FindStringSubmatch(s)
+	if m == nil {
+		return EDTFDate{}, fmt.Errorf("'%s' is not a date of the form YYYY[-MM[-DD]][?~%%]", s)
+	}
+
+	date := EDTFDate{Year: m[1], Month: m[2], Day: m[3]}
+	switch m[4] {
+	case "~":
+		date.Approximate = true
+	case "?":
+		date.Uncertain = true
+	case "%":
+		date.Approximate, date.Uncertain = true, true
+	}
+
+	if !inRange(date.Month, 1, 12) && !inRange(date.Month, 21, 24) {
+		return EDTFDate{}, fmt.Errorf("'%s' has an invalid month '%s'", s, date.Month)
+	}
+	if date.Day != "" && (!inRange(date.Day, 1, 31) || inRange(date.Month, 21, 24)) {
+		return EDTFDate{}, fmt.Errorf("'%s' has an invalid day '%s'", s, date.Day)
+	}
+
+	return date, nil
+}
+
+// inRange answers true if the component is empty, contains unspecified digits, or is a number between min and max
+func inRange(component string, min, max int) bool {
+	if component == "" || strings.Contains(component, "X") {
+		return true
+	}
+	n, err := strconv.Atoi(component)
+	return err == nil && n >= min && n <= max
+}
+
+// Equal answers true if the supplied EDTF value is equivalent to this value
+func (e EDTF) Equal(other EDTF) bool {
+	return e == other
+}
+
+// String answers the normalized form of the EDTF value, e.g. `192x` is normalized to `192X`
+func (e EDTF) String() string {
+	if !e.Interval {
+		return e.Start.String()
+	}
+	return e.Start.String() + "/" + e.End.String()
+}
+
+// String answers the normalized form of the EDTF date
+func (d EDTFDate) String() string {
+	if d.Open {
+		return ".."
+	}
+	if d.Unknown {
+		return ""
+	}
+
+	s := d.Year
+	if d.Month != "" {
+		s += "-" + d.Month
+	}
+	if d.Day != "" {
+		s += "-" + d.Day
+	}
+
+	switch {
+	case d.Approximate && d.Uncertain:
+		s += "%"
+	case d.Approximate:
+		s += "~"
+	case d.Uncertain:
+		s += "?"
+	}
+	return s
+}
+
+// EqualEDTF parses the expected and actual values as EDTF, and answers whether they are equivalent.  An error wrapping
+// ErrInvalidEDTF is answered if either value cannot be parsed, so that invalid values may be distinguished from
+// mismatched values.
+func EqualEDTF(expected, actual string) (bool, error) {
+	e, err := ParseEDTF(expected)
+	if err != nil {
+		return false, fmt.Errorf("expected value: %w", err)
+	}
+	a, err := ParseEDTF(actual)
+	if err != nil {
+		return false, fmt.Errorf("actual value: %w", err)
+	}
+	return e.Equal(a), nil
+}
+
+// AssertEDTF asserts that the expected and actual values are equivalent EDTF values.  Invalid values are reported
+// distinctly from mismatched values.
+func AssertEDTF(t *testing.T, expected, actual string, msgAndArgs ...interface{}) bool {
+	equal, err := EqualEDTF(expected, actual)
+	if err != nil {
+		return assert.Fail(t, fmt.Sprintf("Invalid EDTF: %s", err), msgAndArgs...)
+	}
+	if !equal {
+		return assert.Fail(t, fmt.Sprintf("EDTF values are not equivalent:\nexpected: %s\nactual  : %s", expected, actual), msgAndArgs...)
+	}
+	return true
+}
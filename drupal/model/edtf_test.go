@@ -0,0 +1,63 @@
+package model
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ParseEDTF(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected EDTF
+	}{
+		{"1922", EDTF{Start: EDTFDate{Year: "1922"}}},
+		{"1922~", EDTF{Start: EDTFDate{Year: "1922", Approximate: true}}},
+		{"1922?", EDTF{Start: EDTFDate{Year: "1922", Uncertain: true}}},
+		{"1922%", EDTF{Start: EDTFDate{Year: "1922", Approximate: true, Uncertain: true}}},
+		{"192X", EDTF{Start: EDTFDate{Year: "192X"}}},
+		{"192x", EDTF{Start: EDTFDate{Year: "192X"}}},
+		{"1985-04-XX", EDTF{Start: EDTFDate{Year: "1985", Month: "04", Day: "XX"}}},
+		{"2001-21", EDTF{Start: EDTFDate{Year: "2001", Month: "21"}}},
+		{"-0044-03-15", EDTF{Start: EDTFDate{Year: "-0044", Month: "03", Day: "15"}}},
+		{"1900/1910", EDTF{Start: EDTFDate{Year: "1900"}, End: EDTFDate{Year: "1910"}, Interval: true}},
+		{"1900~/1910-06", EDTF{Start: EDTFDate{Year: "1900", Approximate: true}, End: EDTFDate{Year: "1910", Month: "06"}, Interval: true}},
+		{"1900/..", EDTF{Start: EDTFDate{Year: "1900"}, End: EDTFDate{Open: true}, Interval: true}},
+		{"/1910", EDTF{Start: EDTFDate{Unknown: true}, End: EDTFDate{Year: "1910"}, Interval: true}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			actual, err := ParseEDTF(test.value)
+			assert.Nil(t, err)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func Test_ParseEDTFInvalid(t *testing.T) {
+	for _, value := range []string{"", "192", "1922-13", "1922-04-32", "2001-21-01", "1922~~", "June 1922", "../..", "1900/1910/1920"} {
+		t.Run(value, func(t *testing.T) {
+			_, err := ParseEDTF(value)
+			assert.True(t, errors.Is(err, ErrInvalidEDTF), "expected ErrInvalidEDTF for '%s', got %v", value, err)
+		})
+	}
+}
+
+func Test_EqualEDTF(t *testing.T) {
+	equal, err := EqualEDTF("192x", " 192X")
+	assert.Nil(t, err)
+	assert.True(t, equal)
+
+	equal, err = EqualEDTF("1922~", "1922")
+	assert.Nil(t, err)
+	assert.False(t, equal)
+
+	_, err = EqualEDTF("1922", "circa 1922")
+	assert.True(t, errors.Is(err, ErrInvalidEDTF))
+	assert.Contains(t, err.Error(), "actual value")
+
+	assert.True(t, AssertEDTF(t, "1900/1910", "1900/1910"))
+	assert.Equal(t, "1900~/..", EDTF{Start: EDTFDate{Year: "1900", Approximate: true}, End: EDTFDate{Open: true}, Interval: true}.String())
+}
@@ -0,0 +1,164 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	// Constant for the embargo entity type of the embargoes module (which is also its only bundle)
+	Embargo = "embargo"
+	// The layout of the expiration date of an embargo
+	embargoDateLayout = "2006-01-02"
+)
+
+// The values of the embargo_type of an embargo
+const (
+	// The embargo restricts access to the files of the media of the node
+	EmbargoFiles = 0
+	// The embargo restricts access to the node itself
+	EmbargoNode = 1
+)
+
+// The values of the expiration_type of an embargo
+const (
+	// The embargo never expires
+	EmbargoIndefinite = 0
+	// The embargo expires on its expiration date
+	EmbargoScheduled = 1
+)
+
+// Represents the results of a JSONAPI query for the embargoes of a node
+type JsonApiEmbargo struct {
+	JsonApiData []struct {
+		Type              jsonapi.DrupalType
+		Id                string
+		JsonApiAttributes struct {
+			// EmbargoFiles or EmbargoNode
+			EmbargoType int `json:"embargo_type"`
+			// EmbargoIndefinite or EmbargoScheduled
+			ExpirationType int `json:"expiration_type"`
+			// The date the embargo expires, e.g. `2030-01-01`; empty if the embargo is indefinite
+			ExpirationDate string `json:"expiration_date"`
+		} `json:"attributes"`
+		JsonApiRelationships struct {
+			EmbargoedNode struct {
+				Data JsonApiData
+			} `json:"embargoed_node"`
+			// The users who may access the embargoed content
+			ExemptUsers struct {
+				Data []JsonApiData
+			} `json:"exempt_users"`
+			// The IP range whose requests may access the embargoed content
+			ExemptIps struct {
+				Data JsonApiData
+			} `json:"exempt_ips"`
+		} `json:"relationships"`
+	} `json:"data"`
+}
+
+// EmbargoesFor retrieves every embargo whose embargoed_node references the node, one data element per embargo.  Listing
+// embargoes requires authentication, so the request is issued using the credentials from the environment (see
+// Resolve).
+func EmbargoesFor(t *testing.T, nodeUuid string, opts ...Option) JsonApiEmbargo {
+	embargoes, err := embargoesForErr(t, nodeUuid, opts)
+	assert.Nil(t, err, "%s", err)
+	return embargoes
+}
+
+// AssertEmbargoedUntil asserts that the node has an embargo scheduled to expire on the date (compared by day).  The
+// failure reports the embargoes the node has.
+func AssertEmbargoedUntil(t *testing.T, nodeUuid string, date time.Time, opts ...Option) bool {
+	embargoes, err := embargoesForErr(t, nodeUuid, opts)
+	if !assert.Nil(t, err, "%s", err) {
+		return false
+	}
+
+	expected := date.Format(embargoDateLayout)
+	for i := range embargoes.JsonApiData {
+		if embargoes.describe(i) == "until "+expected {
+			return true
+		}
+	}
+	return assert.Fail(t, fmt.Sprintf("node %s is not embargoed until %s; its embargoes are: %s", nodeUuid, expected,
+		embargoes))
+}
+
+// AssertNotEmbargoed asserts that the node has no embargo in effect: every embargo it has, if any, is scheduled and
+// has expired.  The failure reports the embargoes in effect.
+func AssertNotEmbargoed(t *testing.T, nodeUuid string, opts ...Option) bool {
+	embargoes, err := embargoesForErr(t, nodeUuid, opts)
+	if !assert.Nil(t, err, "%s", err) {
+		return false
+	}
+
+	var effective []string
+	for i := range embargoes.JsonApiData {
+		inEffect, err := embargoes.inEffectErr(i, time.Now())
+		if !assert.Nil(t, err, "%s", err) {
+			return false
+		}
+		if inEffect {
+			effective = append(effective, embargoes.describe(i))
+		}
+	}
+	if len(effective) > 0 {
+		return assert.Fail(t, fmt.Sprintf("node %s is embargoed: %s", nodeUuid, strings.Join(effective, ", ")))
+	}
+	return true
+}
+
+// String answers a readable description of each embargo, e.g. `[files until 2030-01-01, node indefinitely]`
+func (e JsonApiEmbargo) String() string {
+	described := make([]string, len(e.JsonApiData))
+	for i, d := range e.JsonApiData {
+		kind := "files"
+		if d.JsonApiAttributes.EmbargoType == EmbargoNode {
+			kind = "node"
+		}
+		described[i] = kind + " " + e.describe(i)
+	}
+	return "[" + strings.Join(described, ", ") + "]"
+}
+
+// describe answers the expiry of the i-th embargo, e.g. `until 2030-01-01`, or `indefinitely`
+func (e JsonApiEmbargo) describe(i int) string {
+	attrs := e.JsonApiData[i].JsonApiAttributes
+	if attrs.ExpirationType == EmbargoIndefinite {
+		return "indefinitely"
+	}
+	return "until " + attrs.ExpirationDate
+}
+
+// inEffectErr answers whether the i-th embargo is in effect at the supplied time: it is indefinite, or expires after
+// the day of the time
+func (e JsonApiEmbargo) inEffectErr(i int, at time.Time) (bool, error) {
+	attrs := e.JsonApiData[i].JsonApiAttributes
+	if attrs.ExpirationType == EmbargoIndefinite {
+		return true, nil
+	}
+	expires, err := time.Parse(embargoDateLayout, attrs.ExpirationDate)
+	if err != nil {
+		return false, fmt.Errorf("model: unable to parse the expiration date of embargo %s: %w", e.JsonApiData[i].Id,
+			err)
+	}
+	return expires.Format(embargoDateLayout) > at.Format(embargoDateLayout), nil
+}
+
+// embargoesForErr retrieves every embargo of the node
+func embargoesForErr(t *testing.T, nodeUuid string, opts []Option) (JsonApiEmbargo, error) {
+	u := query(t, Embargo, Embargo, opts...)
+	u.Filter = "embargoed_node.id"
+	u.Value = nodeUuid
+
+	embargoes := JsonApiEmbargo{}
+	if err := u.GetAllErr(&embargoes); err != nil {
+		return embargoes, fmt.Errorf("model: unable to retrieve the embargoes of %s: %w", nodeUuid, err)
+	}
+	return embargoes, nil
+}
@@ -0,0 +1,89 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newEmbargoServer answers a server whose node carries the embargoes, and whose image media, and its private file, may
+// only be seen by authenticated users while the node is embargoed
+func newEmbargoServer(t *testing.T, nodeId, mediaId, fileId string, embargoes ...string) *httptest.Server {
+	restricted := newRestrictedMediaServer(t, mediaId, fileId, "admin")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/jsonapi/embargo/embargo" {
+			restricted.Config.Handler.ServeHTTP(w, r)
+			return
+		}
+		assert.Equal(t, nodeId, r.URL.Query().Get("filter[embargoed_node.id]"))
+		body := `{"data": [`
+		for i, e := range embargoes {
+			if i > 0 {
+				body += ","
+			}
+			body += fmt.Sprintf(`{"type": "embargo--embargo", "id": "%s", "attributes": %s, "relationships": {
+				"embargoed_node": {"data": {"type": "node--islandora_object", "id": "%s"}},
+				"exempt_users": {"data": [{"type": "user--user", "id": "%s"}]}}}`, testUuid(10+i), e, nodeId,
+				testUuid(20))
+		}
+		_, _ = w.Write([]byte(body + `]}`))
+	}))
+	t.Cleanup(server.Close)
+	setBaseUrl(t, server)
+	return server
+}
+
+func Test_AssertEmbargoedUntil(t *testing.T) {
+	nodeId, mediaId, fileId := testUuid(1), testUuid(2), testUuid(3)
+	newEmbargoServer(t, nodeId, mediaId, fileId,
+		`{"embargo_type": 0, "expiration_type": 1, "expiration_date": "2999-01-01"}`)
+	t.Setenv("DRUPAL_USERNAME", "admin")
+	t.Setenv("DRUPAL_PASSWORD", "moo")
+
+	embargoes := EmbargoesFor(t, nodeId)
+	assert.Equal(t, 1, len(embargoes.JsonApiData))
+	assert.Equal(t, nodeId, embargoes.JsonApiData[0].JsonApiRelationships.EmbargoedNode.Data.Id)
+	assert.Equal(t, testUuid(20), embargoes.JsonApiData[0].JsonApiRelationships.ExemptUsers.Data[0].Id)
+	assert.Equal(t, "[files until 2999-01-01]", embargoes.String())
+
+	assert.True(t, AssertEmbargoedUntil(t, nodeId, time.Date(2999, 1, 1, 12, 0, 0, 0, time.UTC)))
+
+	// the file of the media is inaccessible anonymously while the node is embargoed
+	media := JsonApiData{Type: "media--image", Id: mediaId}
+	assert.True(t, AssertAccessMatrix(t, media, map[string]bool{AnonymousRole: false}))
+}
+
+func Test_AssertNotEmbargoed(t *testing.T) {
+	newEmbargoServer(t, testUuid(1), testUuid(2), testUuid(3),
+		`{"embargo_type": 1, "expiration_type": 1, "expiration_date": "2001-01-01"}`)
+
+	assert.True(t, AssertNotEmbargoed(t, testUuid(1)))
+}
+
+func Test_JsonApiEmbargo_inEffectErr(t *testing.T) {
+	embargoes := JsonApiEmbargo{}
+	require.Nil(t, json.Unmarshal([]byte(`{"data": [
+		{"type": "embargo--embargo", "id": "1", "attributes": {"expiration_type": 0}},
+		{"type": "embargo--embargo", "id": "2", "attributes": {"expiration_type": 1, "expiration_date": "2020-06-01"}},
+		{"type": "embargo--embargo", "id": "3", "attributes": {"expiration_type": 1, "expiration_date": "moo"}}]}`),
+		&embargoes))
+	at := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	for i, expected := range []bool{true, false} {
+		inEffect, err := embargoes.inEffectErr(i, at)
+		assert.Nil(t, err, "%s", err)
+		assert.Equal(t, expected, inEffect, "embargo %d", i)
+	}
+	inEffect, err := embargoes.inEffectErr(1, at.AddDate(0, 0, -1))
+	assert.Nil(t, err, "%s", err)
+	assert.True(t, inEffect)
+
+	_, err = embargoes.inEffectErr(2, at)
+	assert.Contains(t, fmt.Sprint(err), "unable to parse the expiration date of embargo 3")
+}
@@ -0,0 +1,127 @@
+package model
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/stretchr/testify/assert"
+)
+
+// Remote video providers supported by the site's oEmbed configuration
+const (
+	YouTube = "youtube"
+	Vimeo   = "vimeo"
+)
+
+// The providers of remote video, keyed by the hosts that may appear in an embed url
+var embedProviders = map[string]string{
+	"youtube.com":      YouTube,
+	"www.youtube.com":  YouTube,
+	"m.youtube.com":    YouTube,
+	"youtu.be":         YouTube,
+	"vimeo.com":        Vimeo,
+	"www.vimeo.com":    Vimeo,
+	"player.vimeo.com": Vimeo,
+}
+
+var (
+	// matches the path of a YouTube embed url that carries the video id, e.g. `/embed/dQw4w9WgXcQ`
+	youTubePathPattern = regexp.MustCompile(`^/(?:embed|shorts|v|live)/([A-Za-z0-9_-]{11})/?$`)
+	// matches a YouTube video id, e.g. `dQw4w9WgXcQ`
+	youTubeIdPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`)
+	// matches the path of a Vimeo embed url, capturing the video id, e.g. `/76979871` or `/video/76979871`
+	vimeoPathPattern = regexp.MustCompile(`^/(?:video/)?([0-9]+)/?$`)
+)
+
+// The timeout of the request confirming that an embed url resolves
+const embedCheckTimeout = 10 * time.Second
+
+// Describes the remote video referenced by an embed url
+type EmbedInfo struct {
+	// The provider of the video: YouTube or Vimeo
+	Provider string
+	// The provider's identifier of the video, e.g. `dQw4w9WgXcQ`
+	VideoId string
+	// The embed url
+	Url string
+}
+
+// ValidateEmbed asserts that the embed url of the first remote video media in the response is a well-formed YouTube or
+// Vimeo url carrying a video id, and answers the provider and video id.  If the environment variable
+// 'CHECK_EMBED_URLS' is true, the url is also requested to confirm that it resolves; offline runs leave it unset.
+func (m JsonApiRemoteVideoMedia) ValidateEmbed(t *testing.T) EmbedInfo {
+	if !assert.NotEmpty(t, m.JsonApiData, "unable to validate the embed url of an empty remote video response") {
+		return EmbedInfo{}
+	}
+
+	info, err := ParseEmbedUrl(m.JsonApiData[0].JsonApiAttributes.EmbedUrl)
+	if !assert.Nil(t, err, "%s", err) {
+		return info
+	}
+
+	if env.CheckEmbedUrlsOr(false) {
+		err = checkEmbedResolves(info.Url)
+		assert.Nil(t, err, "%s", err)
+	}
+	return info
+}
+
+// ParseEmbedUrl parses the supplied YouTube or Vimeo url, answering its provider and video id.  An error wrapping
+// ErrInvalidData is answered if the url is malformed, its host is not a supported provider, or it carries no video id.
+func ParseEmbedUrl(embedUrl string) (EmbedInfo, error) {
+	u, err := url.Parse(strings.TrimSpace(embedUrl))
+	if err != nil || !u.IsAbs() || u.Host == "" {
+		return EmbedInfo{}, fmt.Errorf("%w: '%s' is not an absolute url", ErrInvalidData, embedUrl)
+	}
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return EmbedInfo{}, fmt.Errorf("%w: embed url '%s' must use http or https", ErrInvalidData, embedUrl)
+	}
+
+	info := EmbedInfo{Provider: embedProviders[strings.ToLower(u.Hostname())], Url: u.String()}
+	switch info.Provider {
+	case YouTube:
+		if strings.EqualFold(u.Hostname(), "youtu.be") {
+			info.VideoId = strings.Trim(u.Path, "/")
+		} else if m := youTubePathPattern.FindStringSubmatch(u.Path); m != nil {
+			info.VideoId = m[1]
+		} else if u.Path == "/watch" {
+			info.VideoId = u.Query().Get("v")
+		}
+		if !youTubeIdPattern.MatchString(info.VideoId) {
+			return EmbedInfo{}, fmt.Errorf("%w: YouTube embed url '%s' carries no video id", ErrInvalidData, embedUrl)
+		}
+	case Vimeo:
+		m := vimeoPathPattern.FindStringSubmatch(u.Path)
+		if m == nil {
+			return EmbedInfo{}, fmt.Errorf("%w: Vimeo embed url '%s' carries no video id", ErrInvalidData, embedUrl)
+		}
+		info.VideoId = m[1]
+	default:
+		return EmbedInfo{}, fmt.Errorf("%w: '%s' is not a supported remote video provider in embed url '%s'",
+			ErrInvalidData, u.Hostname(), embedUrl)
+	}
+
+	return info, nil
+}
+
+// checkEmbedResolves requests the embed url, answering an error unless the response is successful (or a redirect that
+// is followed to a successful response)
+func checkEmbedResolves(embedUrl string) error {
+	client := http.Client{Timeout: embedCheckTimeout}
+	res, err := client.Head(embedUrl)
+	if err != nil {
+		return fmt.Errorf("model: unable to request embed url '%s': %w", embedUrl, err)
+	}
+	_ = res.Body.Close()
+
+	if res.StatusCode >= 400 {
+		return fmt.Errorf("model: %d status encountered when requesting embed url '%s'", res.StatusCode, embedUrl)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseEmbedUrl(t *testing.T) {
+	tests := map[string]EmbedInfo{
+		"https://www.youtube.com/watch?v=dQw4w9WgXcQ":  {Provider: YouTube, VideoId: "dQw4w9WgXcQ"},
+		"https://youtu.be/dQw4w9WgXcQ":                 {Provider: YouTube, VideoId: "dQw4w9WgXcQ"},
+		"https://www.youtube.com/embed/dQw4w9WgXcQ":    {Provider: YouTube, VideoId: "dQw4w9WgXcQ"},
+		"https://vimeo.com/76979871":                   {Provider: Vimeo, VideoId: "76979871"},
+		"https://player.vimeo.com/video/76979871?h=ab": {Provider: Vimeo, VideoId: "76979871"},
+	}
+	for embedUrl, expected := range tests {
+		t.Run(embedUrl, func(t *testing.T) {
+			info, err := ParseEmbedUrl(embedUrl)
+			assert.Nil(t, err)
+			assert.Equal(t, expected.Provider, info.Provider)
+			assert.Equal(t, expected.VideoId, info.VideoId)
+		})
+	}
+
+	for _, embedUrl := range []string{"", "youtube.com/watch?v=dQw4w9WgXcQ", "https://www.youtube.com/watch?v=moo",
+		"https://www.youtube.com/oembed?url=x", "https://vimeo.com/channels/staffpicks", "https://example.org/video/1",
+		"ftp://youtu.be/dQw4w9WgXcQ"} {
+		_, err := ParseEmbedUrl(embedUrl)
+		assert.True(t, errors.Is(err, ErrInvalidData), "expected ErrInvalidData for '%s', got %v", embedUrl, err)
+	}
+}
+
+func Test_ValidateEmbed(t *testing.T) {
+	media := JsonApiRemoteVideoMedia{}
+	require.Nil(t, json.Unmarshal([]byte(`{"data": [{"type": "media--remote_video", "id": "00000001-0000-4000-8000-000000000000",
+		"attributes": {"name": "Commencement", "field_media_oembed_video": "https://vimeo.com/76979871"}}]}`), &media))
+
+	t.Setenv("CHECK_EMBED_URLS", "false")
+	info := media.ValidateEmbed(t)
+	assert.Equal(t, Vimeo, info.Provider)
+	assert.Equal(t, "76979871", info.VideoId)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodHead, r.Method)
+		if r.URL.Path != "/ok" {
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	assert.Nil(t, checkEmbedResolves(server.URL+"/ok"))
+	assert.NotNil(t, checkEmbedResolves(server.URL+"/missing"))
+}
@@ -0,0 +1,89 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/stretchr/testify/require"
+)
+
+// The prefix of the values marking entities created by EnsureCollection and EnsureObject, so that they may be found,
+// and purged, later.  Repository objects carry the marker as a field_digital_identifier, and collections as a
+// field_collection_number, e.g. `idc-test-fixture:Moonrise`.
+const FixtureMarker = "idc-test-fixture:"
+
+// Serializes the EnsureX invocations for each natural key, so that parallel tests do not create duplicates; a
+// *sync.Mutex keyed by the base url, entity, bundle, and natural key
+var ensureLocks = sync.Map{}
+
+// EnsureCollection answers the collection titled spec.Title, creating it as described by the spec if it does not
+// exist.  Unlike CreateCollection, the collection is not deleted when the test completes, so suites run repeatedly
+// against a long-lived site (e.g. staging) reuse the collection rather than creating a duplicate on every run.  A
+// collection created by EnsureCollection is marked with FixtureMarker.  The test fails immediately if more than one
+// collection carries the title, or if the collection cannot be created.
+func EnsureCollection(t *testing.T, spec CollectionSpec, opts ...Option) JsonApiCollection {
+	return ensure[JsonApiCollection](t, Node, Collection, "title", spec.Title, func() jsonapi.Resource {
+		r := collectionResource(t, spec, opts)
+		r.Attributes["field_collection_number"] = []string{FixtureMarker + spec.Title}
+		return r
+	}, opts)
+}
+
+// EnsureObject answers the repository object titled spec.Title, creating it as described by the spec if it does not
+// exist.  A repository object created by EnsureObject is marked with FixtureMarker.  It otherwise behaves as
+// EnsureCollection.
+func EnsureObject(t *testing.T, spec ObjectSpec, opts ...Option) JsonApiIslandoraObj {
+	return ensure[JsonApiIslandoraObj](t, Node, RepositoryObject, "title", spec.Title, func() jsonapi.Resource {
+		r := objectResource(t, spec, opts)
+		r.Attributes["field_digital_identifier"] = []string{FixtureMarker + spec.Title}
+		return r
+	}, opts)
+}
+
+// EnsureTerm answers the identifier of the term of the vocabulary named spec.Name, creating it as described by the spec
+// if it does not exist.  Terms carry no field suitable for a marker, so a term created by EnsureTerm is not marked.  It
+// otherwise behaves as EnsureCollection:
+//
+//	subject := model.EnsureTerm(t, "subject", model.TermSpec{Name: "Analog Photography"})
+func EnsureTerm(t *testing.T, vocabulary string, spec TermSpec, opts ...Option) JsonApiData {
+	term := ensure[struct {
+		JsonApiData []JsonApiData `json:"data"`
+	}](t, TaxonomyTerm, vocabulary, "name", spec.Name, func() jsonapi.Resource {
+		return termResource(spec)
+	}, opts)
+	return term.JsonApiData[0]
+}
+
+// ensure answers the entity of the bundle whose field matches the value, creating the resource answered by build if no
+// entity matches.  Invocations for the same entity, bundle, and value are serialized.  The test fails immediately if
+// more than one entity matches, or the entity cannot be created.
+func ensure[T any](t *testing.T, entity, bundle, field, value string, build func() jsonapi.Resource, opts []Option) T {
+	require.NotEmpty(t, value, "unable to ensure a %s/%s exists: its %s is required", entity, bundle, field)
+
+	key := fmt.Sprintf("%s %s %s %s", baseUrlOf(opts), entity, bundle, value)
+	lock, _ := ensureLocks.LoadOrStore(key, &sync.Mutex{})
+	lock.(*sync.Mutex).Lock()
+	defer lock.(*sync.Mutex).Unlock()
+
+	var v T
+	u := query(t, entity, bundle, opts...)
+	u.Filter, u.Value = field, value
+	err := u.GetSingleErr(&v)
+	if err == nil {
+		return v
+	}
+	require.True(t, errors.Is(err, jsonapi.ErrNotFound), "unable to find the %s/%s with %s '%s': %s", entity, bundle,
+		field, value, err)
+
+	u = query(t, entity, bundle, opts...)
+	err = u.CreateErr(build(), &v)
+	require.Nil(t, err, "%s", err)
+
+	id, err := createdIdErr(v)
+	require.Nil(t, err, "%s", err)
+	recordFixture(t, entity, bundle, id, false, opts)
+	return v
+}
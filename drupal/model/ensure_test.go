@@ -0,0 +1,62 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_EnsureObject(t *testing.T) {
+	d := newFakeDrupal(t, map[string]string{
+		"islandora_models name Image": testUuid(1),
+	})
+
+	first := EnsureObject(t, ObjectSpec{Title: "Moonrise", ModelName: "Image"})
+	require.Equal(t, 1, len(first.JsonApiData))
+	assert.Equal(t, []string{FixtureMarker + "Moonrise"}, first.JsonApiData[0].JsonApiAttributes.DigitalIdentifier)
+
+	second := EnsureObject(t, ObjectSpec{Title: "Moonrise", ModelName: "Image"})
+	require.Equal(t, 1, len(second.JsonApiData))
+	assert.Equal(t, first.JsonApiData[0].Id, second.JsonApiData[0].Id)
+	assert.Equal(t, 1, len(d.created))
+}
+
+func Test_EnsureCollectionConcurrently(t *testing.T) {
+	d := newFakeDrupal(t, map[string]string{})
+
+	ids := make([]string, 5)
+	t.Run("group", func(t *testing.T) {
+		for i := range ids {
+			i := i
+			t.Run(fmt.Sprint(i), func(t *testing.T) {
+				t.Parallel()
+				c := EnsureCollection(t, CollectionSpec{Title: "Shared Collection"})
+				require.Equal(t, 1, len(c.JsonApiData))
+				ids[i] = c.JsonApiData[0].Id
+			})
+		}
+	})
+
+	assert.Equal(t, 1, len(d.created), "expected exactly one collection to be created")
+	for _, id := range ids {
+		assert.Equal(t, ids[0], id)
+	}
+	assert.Empty(t, d.deleted, "ensured fixtures are expected to outlive the test")
+}
+
+func Test_EnsureTerm(t *testing.T) {
+	d := newFakeDrupal(t, map[string]string{
+		"subject name Analog Photography": testUuid(1),
+	})
+
+	existing := EnsureTerm(t, subjectVocabulary, TermSpec{Name: "Analog Photography"})
+	assert.Equal(t, testUuid(1), existing.Id)
+	assert.Empty(t, d.created)
+
+	created := EnsureTerm(t, genreVocabulary, TermSpec{Name: "Landscapes"})
+	assert.Equal(t, "taxonomy_term--genre", string(created.Type))
+	assert.Equal(t, created, EnsureTerm(t, genreVocabulary, TermSpec{Name: "Landscapes"}))
+	assert.Equal(t, 1, len(d.created))
+}
@@ -74,22 +74,18 @@ type ExpectedPerson struct {
 		Name string
 		Type string
 	}
-	Description struct {
-		Value     string
-		Format    string
-		Processed string
-	}
+	Description FormattedText
 }
 
 // Represents the expected results of a migrated repository object
 type ExpectedRepoObj struct {
 	ExpectedWithTitle
-	Abstract         []LanguageString
-	AccessRights     []string         `json:"access_rights"`
-	AltTitle         []LanguageString `json:"alt_title"`
-	CollectionNumber []string         `json:"collection_number"`
-	CopyrightAndUse  string           `json:"copyright_and_use"`
-	CopyrightHolder  []string         `json:"copyright_holder"`
+	Abstract         []ExpectedLangString
+	AccessRights     []string             `json:"access_rights"`
+	AltTitle         []ExpectedLangString `json:"alt_title"`
+	CollectionNumber []string             `json:"collection_number"`
+	CopyrightAndUse  string               `json:"copyright_and_use"`
+	CopyrightHolder  []string             `json:"copyright_holder"`
 	Contributor      []struct {
 		RelType string `json:"rel_type"`
 		Name    string
@@ -98,22 +94,19 @@ type ExpectedRepoObj struct {
 		RelType string `json:"rel_type"`
 		Name    string
 	}
-	CustodialHistory  []LanguageString `json:"custodial_history"`
-	DateAvailable     string           `json:"date_available"`
-	DateCopyrighted   []string         `json:"date_copyrighted"`
-	DateCreated       []string         `json:"date_created"`
-	DatePublished     []string         `json:"date_published"`
-	DigitalIdentifier []string         `json:"digital_identifier"`
-	DigitalPublisher  []string         `json:"digital_publisher"`
-	DisplayHint       string           `json:"display_hints"`
-	DspaceIdentifier  string           `json:"dspace_identifier"`
-	DspaceItemId      string           `json:"dspace_itemid"`
-	Extent            []string
-	FeaturedItem      bool `json:"featured_item"`
-	FindingAid        []struct {
-		Uri   string
-		Title string
-	} `json:"finding_aid"`
+	CustodialHistory   []ExpectedLangString `json:"custodial_history"`
+	DateAvailable      string               `json:"date_available"`
+	DateCopyrighted    []string             `json:"date_copyrighted"`
+	DateCreated        []string             `json:"date_created"`
+	DatePublished      []string             `json:"date_published"`
+	DigitalIdentifier  []string             `json:"digital_identifier"`
+	DigitalPublisher   []string             `json:"digital_publisher"`
+	DisplayHint        string               `json:"display_hints"`
+	DspaceIdentifier   string               `json:"dspace_identifier"`
+	DspaceItemId       string               `json:"dspace_itemid"`
+	Extent             []string
+	FeaturedItem       bool   `json:"featured_item"`
+	FindingAid         []Link `json:"finding_aid"`
 	Genre              []string
 	GeoportalLink      string   `json:"geoportal_link"`
 	AccessTerms        []string `json:"access_terms"`
@@ -132,213 +125,136 @@ type ExpectedRepoObj struct {
 	ResourceType     []string `json:"resource_type"`
 	SpatialCoverage  []string `json:"spatial_coverage"`
 	Subject          []string
-	TableOfContents  []LanguageString `json:"toc"`
-	MemberOf         string           `json:"member_of"`
+	TableOfContents  []ExpectedLangString `json:"toc"`
+	MemberOf         string               `json:"member_of"`
 	LinkedAgent      []struct {
 		Rel  string
 		Name string
 	}
-	Description []struct {
-		Value    string
-		LangCode string `json:"language"`
-	}
+	Description []ExpectedLangString
 }
 
 // Represents the expected results of a migrated Access Rights taxonomy term
 type ExpectedAccessRights struct {
 	ExpectedWithName
-	Authority []struct {
-		Uri    string
-		Title  string
-		Source string
-	}
-	Description struct {
-		Value     string
-		Format    string
-		Processed string
-	}
+	Authority   []Authority
+	Description FormattedText
 }
 
 // Represents the expected results of a migrated Islandora Access Terms taxonomy term
 type ExpectedIslandoraAccessTerms struct {
 	ExpectedWithName
 	Parent      []string `json:"parent"`
-	Description struct {
-		Value     string
-		Format    string
-		Processed string
-	}
+	Description FormattedText
 }
 
 // Represents the expected results of a migrated Copyright and Use taxonomy term
 type ExpectedCopyrightAndUse struct {
 	ExpectedWithName
-	Authority []struct {
-		Uri    string
-		Title  string
-		Source string
-	}
-	Description struct {
-		Value     string
-		Format    string
-		Processed string
-	}
+	Authority   []Authority
+	Description FormattedText
 }
 
 // Represents the expected results of a migrated Family taxonomy term
 type ExpectedFamily struct {
 	ExpectedWithName
-	Date       []string
-	FamilyName string `json:"family_name"`
-	Title      string
-	Authority  []struct {
-		Uri    string
-		Title  string
-		Source string
-	}
-	Description struct {
-		Value     string
-		Format    string
-		Processed string
-	}
-	KnowsAbout []string `json:"knowsAbout"`
+	Date        []string
+	FamilyName  string `json:"family_name"`
+	Title       string
+	Authority   []Authority
+	Description FormattedText
+	KnowsAbout  []string `json:"knowsAbout"`
 }
 
 // Represents the expected results of a migrated Genre taxonomy term
 type ExpectedGenre struct {
 	ExpectedWithName
-	Authority []struct {
-		Uri    string
-		Title  string
-		Source string
-	}
-	Description struct {
-		Value     string
-		Format    string
-		Processed string
-	}
+	Authority   []Authority
+	Description FormattedText
 }
 
 // Represents the expected results of a migrated Geolocation taxonomy term
 type ExpectedGeolocation struct {
 	ExpectedWithName
-	GeoAltName []string `json:"geo_alt_name"`
-	Broader    []struct {
-		Uri   string
-		Title string
-	}
-	Authority []struct {
-		Uri    string
-		Title  string
-		Source string
-	}
-	Description struct {
-		Value     string
-		Format    string
-		Processed string
-	}
+	GeoAltName  []string `json:"geo_alt_name"`
+	Broader     []Link
+	Authority   []Authority
+	Description FormattedText
+	Coordinates *Coordinates
 }
 
 // Represents the expected results of a migrated Resource Types taxonomy term
 type ExpectedResourceType struct {
 	ExpectedWithName
-	Authority []struct {
-		Uri    string
-		Title  string
-		Source string
-	}
-	Description struct {
-		Value     string
-		Format    string
-		Processed string
-	}
+	Authority   []Authority
+	Description FormattedText
 }
 
 // Represents the expected results of a migrated Subject taxonomy term
 type ExpectedSubject struct {
 	ExpectedWithName
-	Authority []struct {
-		Uri    string
-		Title  string
-		Source string
-	}
-	Description struct {
-		Value     string
-		Format    string
-		Processed string
-	}
+	Authority   []Authority
+	Description FormattedText
 }
 
 // Represents the expected results of a migrated Language taxonomy term
 type ExpectedLanguage struct {
 	ExpectedWithName
 	LanguageCode string `json:"language_code"`
-	Authority    []struct {
-		Uri    string
-		Title  string
-		Source string
-	}
-	Description struct {
-		Value     string
-		Format    string
-		Processed string
-	}
+	Authority    []Authority
+	Description  FormattedText
 }
 
 // Represents the expected results of a migrated Collection entity
 type ExpectedCollection struct {
 	ExpectedWithTitle
-	TitleLangCode string `json:"title_language"`
-	AltTitle      []struct {
-		Value    string
-		LangCode string `json:"language"`
-	} `json:"alternative_title"`
-	Description []struct {
-		Value    string
-		LangCode string `json:"language"`
-	}
+	TitleLangCode    string               `json:"title_language"`
+	AltTitle         []ExpectedLangString `json:"alternative_title"`
+	Description      []ExpectedLangString
 	ContactEmail     string   `json:"contact_email"`
 	ContactName      string   `json:"contact_name"`
 	CollectionNumber []string `json:"collection_number"`
 	MemberOf         string   `json:"member_of"`
 	AccessTerms      []string `json:"access_terms"`
-	FindingAid       []struct {
-		Uri   string
-		Title string
-	} `json:"finding_aid"`
+	FindingAid       []Link   `json:"finding_aid"`
+	// Whether the collection is featured; not compared if absent
+	FeaturedItem *bool `json:"featured_item"`
+	// The weight ordering the collection among its siblings; not compared if absent
+	Weight *int `json:"weight"`
+	// Whether the collection is published; not compared if absent
+	Published *bool `json:"published"`
+	// The name of the media representing the collection
+	RepresentativeImage string `json:"representative_image"`
 }
 
 // Represents the expected results of a migrated Corporate Body taxonomy term
 type ExpectedCorporateBody struct {
 	ExpectedWithName
-	Description struct {
-		Value     string
-		Format    string
-		Processed string
-	}
+	Description     FormattedText
 	PrimaryName     string   `json:"primary_name"`
 	SubordinateName []string `json:"subordinate_name"`
 	DateOfMeeting   []string `json:"date_of_meeting_or_treaty"`
 	Location        []string `json:"location_of_meeting"`
 	NumberOrSection []string `json:"num_of_section_or_meet"`
 	AltName         []string `json:"corporate_body_alternate_name"`
-	Authority       []struct {
-		Uri    string
-		Title  string
-		Source string
-	}
-	Date         []string
-	Relationship []struct {
+	Authority       []Authority
+	Date            []string
+	Relationship    []struct {
 		Name string
 		Rel  string `json:"rel_type"`
 	} `json:"relationships"`
 }
 
-type LanguageString struct {
+// A language-tagged expected value, e.g. the English alternative title of a repository object.  Multilingual fields are
+// compared without regard to order, as Drupal does not guarantee the order of their values.
+type ExpectedLangString struct {
 	Value    string
 	LangCode string `json:"language"`
 }
 
+// Deprecated: use ExpectedLangString
+type LanguageString = ExpectedLangString
+
 type ExpectedMediaGeneric struct {
 	ExpectedWithName
 	OriginalName string `json:"original_name"`
@@ -361,18 +277,38 @@ type ExpectedMediaImage struct {
 	Width   int
 }
 
+type ExpectedMediaDocument struct {
+	ExpectedMediaGeneric
+}
+
+type ExpectedMediaAudio struct {
+	ExpectedMediaGeneric
+	// The duration of the audio in seconds
+	Duration float64
+}
+
+type ExpectedMediaVideo struct {
+	ExpectedMediaGeneric
+	// The duration of the video in seconds
+	Duration float64
+	Height   int
+	Width    int
+}
+
+type ExpectedMediaFile struct {
+	ExpectedMediaGeneric
+}
+
 type ExpectedMediaExtractedText struct {
 	ExpectedMediaGeneric
-	ExtractedText struct {
-		Value     string
-		Format    string
-		Processed string
-	} `json:"extracted_text"`
+	ExtractedText FormattedText `json:"extracted_text"`
 }
 
 type ExpectedMediaRemoteVideo struct {
 	ExpectedWithName
-	EmbedUrl         string `json:"embed_url"`
+	EmbedUrl string `json:"embed_url"`
+	// The provider's identifier of the video (see EmbedInfo), which may be asserted instead of the embed url
+	VideoId          string `json:"video_id"`
 	MediaOf          string `json:"media_of"`
 	RestrictedAccess bool   `json:"restricted_access"`
 }
@@ -0,0 +1,64 @@
+package model
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// The maximum size, in bytes, of an extracted text file read by ExtractedTextOf and ContainsText
+const MaxExtractedTextSize = 16 * 1024 * 1024
+
+// ExtractedTextOf answers the content of the file attached to the extracted text media, e.g. the OCR output of a page,
+// asserting that it can be retrieved and is no larger than MaxExtractedTextSize
+func ExtractedTextOf(t *testing.T, media JsonApiExtractedTextMedia) string {
+	text, err := ExtractedTextOfErr(media, MaxExtractedTextSize)
+	assert.Nil(t, err, "%s", err)
+	return text
+}
+
+// ExtractedTextOfErr behaves as ExtractedTextOf, but answers an error instead of making assertions.  The file is
+// streamed, and an error is answered as soon as more than maxSize bytes have been read.
+func ExtractedTextOfErr(media JsonApiExtractedTextMedia, maxSize int64) (string, error) {
+	file := media.File()
+	if file.IsZero() {
+		return "", fmt.Errorf("model: extracted text media '%s' has no file", media.Name())
+	}
+
+	body, err := openFileErr(file.JsonApiData)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = body.Close() }()
+
+	text, err := ioutil.ReadAll(io.LimitReader(body, maxSize+1))
+	if err != nil {
+		return "", fmt.Errorf("model: unable to read extracted text of media '%s': %w", media.Name(), err)
+	}
+	if int64(len(text)) > maxSize {
+		return "", fmt.Errorf("model: extracted text of media '%s' exceeds %d bytes", media.Name(), maxSize)
+	}
+	return string(text), nil
+}
+
+// ContainsText asserts that the extracted text media contains the supplied text, either in its edited text
+// (field_edited_text) or in the content of its file.  The file is only retrieved if the edited text does not contain
+// the supplied text.
+func ContainsText(t *testing.T, media JsonApiExtractedTextMedia, needle string) bool {
+	if len(media.JsonApiData) > 0 && strings.Contains(media.JsonApiData[0].JsonApiAttributes.EditedText.Processed, needle) {
+		return true
+	}
+	if media.File().IsZero() {
+		return assert.Fail(t, fmt.Sprintf("extracted text media '%s' does not contain '%s'", media.Name(), needle))
+	}
+
+	text, err := ExtractedTextOfErr(media, MaxExtractedTextSize)
+	if !assert.Nil(t, err, "%s", err) {
+		return false
+	}
+	return assert.True(t, strings.Contains(text, needle), "extracted text media '%s' does not contain '%s'", media.Name(), needle)
+}
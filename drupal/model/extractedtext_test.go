@@ -0,0 +1,41 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ContainsText(t *testing.T) {
+	fileId := testUuid(2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch collectionPath(r) {
+		case "/jsonapi/file/file":
+			_, _ = fmt.Fprintf(w, `{"data": [{"type": "file--file", "id": "%s",
+				"attributes": {"filename": "ocr.txt", "uri": {"value": "private://ocr.txt", "url": "/system/files/ocr.txt"}}}]}`, fileId)
+		case "/system/files/ocr.txt":
+			_, _ = w.Write([]byte("The Johns Hopkins University Circular, February 1882"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	setBaseUrl(t, server)
+
+	media := JsonApiExtractedTextMedia{}
+	require.Nil(t, json.Unmarshal([]byte(fmt.Sprintf(`{"data": [{"type": "media--extracted_text", "id": "%s",
+		"attributes": {"name": "ocr.txt", "field_edited_text": {"value": "Corrected circular", "processed": "<p>Corrected circular</p>"}},
+		"relationships": {"field_media_file": {"data": {"type": "file--file", "id": "%s"}}}}]}`, testUuid(1), fileId)), &media))
+
+	assert.True(t, ContainsText(t, media, "Corrected circular"))
+	assert.True(t, ContainsText(t, media, "University Circular"))
+	assert.Equal(t, "The Johns Hopkins University Circular, February 1882", ExtractedTextOf(t, media))
+
+	_, err := ExtractedTextOfErr(media, 10)
+	assert.Contains(t, fmt.Sprint(err), "exceeds 10 bytes")
+}
@@ -0,0 +1,123 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	// Constant for the Drupal content type entity type (which is also its only bundle)
+	NodeType = "node_type"
+	// Constant for the Drupal field configuration entity type (which is also its only bundle)
+	FieldConfig = "field_config"
+)
+
+// Represents the results of a JSONAPI query for content types (node types)
+type JsonApiNodeType struct {
+	JsonApiData []struct {
+		Type              jsonapi.DrupalType
+		Id                string
+		JsonApiAttributes struct {
+			// The machine name of the content type, e.g. `islandora_object`
+			MachineName string `json:"drupal_internal__type"`
+			Name        string `json:"name"`
+			Description string `json:"description"`
+		} `json:"attributes"`
+	} `json:"data"`
+}
+
+// Represents the results of a JSONAPI query for field configurations, i.e. the configurable fields of a bundle
+type JsonApiFieldConfig struct {
+	JsonApiData []struct {
+		Type              jsonapi.DrupalType
+		Id                string
+		JsonApiAttributes struct {
+			// The id of the field configuration, e.g. `node.islandora_object.field_member_of`
+			ConfigId   string `json:"drupal_internal__id"`
+			FieldName  string `json:"field_name"`
+			EntityType string `json:"entity_type"`
+			Bundle     string `json:"bundle"`
+			Label      string `json:"label"`
+			Required   bool   `json:"required"`
+			// The type of the field, e.g. `entity_reference` or `typed_relation`
+			FieldType string `json:"field_type"`
+		} `json:"attributes"`
+	} `json:"data"`
+}
+
+// AssertNodeTypes asserts that the site has each of the content types, e.g. `islandora_object` and
+// `collection_object`, reporting every missing content type, and answers whether it has them all.  Content types are
+// config entities, which Drupal only exposes to users permitted to administer them.
+func AssertNodeTypes(t *testing.T, bundles []string, opts ...Option) bool {
+	res := JsonApiNodeType{}
+	u := query(t, NodeType, NodeType, opts...)
+	if err := u.GetAllErr(&res); err != nil {
+		return assert.Fail(t, fmt.Sprintf("unable to retrieve the content types: %s", err))
+	}
+
+	present := map[string]bool{}
+	for _, d := range res.JsonApiData {
+		present[d.JsonApiAttributes.MachineName] = true
+	}
+	return assertAllPresent(t, "content type(s)", bundles, present)
+}
+
+// AssertBundleHasFields asserts that the bundle of the entity type has each of the configurable fields, reporting every
+// missing field, and answers whether it has them all.  Run before a verification suite, it catches a deployment that
+// omitted a field (e.g. a forgotten feature revert) before hundreds of content assertions fail confusingly:
+//
+//	model.AssertBundleHasFields(t, model.Node, model.RepositoryObject,
+//		[]string{"field_member_of", "field_access_terms", "field_model"})
+//
+// Only configurable fields are checked; base fields, such as `title` or `status`, have no field configuration.  Field
+// configurations are config entities, which Drupal only exposes to users permitted to administer them.
+func AssertBundleHasFields(t *testing.T, entity, bundle string, fields []string, opts ...Option) bool {
+	configs, err := FieldConfigsErr(entity, bundle, opts...)
+	if err != nil {
+		return assert.Fail(t, fmt.Sprintf("unable to retrieve the fields of %s %s: %s", entity, bundle, err))
+	}
+
+	present := map[string]bool{}
+	for _, d := range configs.JsonApiData {
+		present[d.JsonApiAttributes.FieldName] = true
+	}
+	return assertAllPresent(t, fmt.Sprintf("field(s) of %s %s", entity, bundle), fields, present)
+}
+
+// FieldConfigsErr answers the field configurations of the bundle of the entity type
+func FieldConfigsErr(entity, bundle string, opts ...Option) (JsonApiFieldConfig, error) {
+	res := JsonApiFieldConfig{}
+	u := query(nil, FieldConfig, FieldConfig, opts...)
+	u.RawFilter = "filter[entity_type]=" + entity + "&filter[bundle]=" + bundle
+	if err := u.GetAllErr(&res); err != nil {
+		return JsonApiFieldConfig{}, err
+	}
+	return res, nil
+}
+
+// assertAllPresent asserts that each of the expected names is present, reporting the missing names, in order, and the
+// names that are present
+func assertAllPresent(t *testing.T, what string, expected []string, present map[string]bool) bool {
+	var missing []string
+	for _, name := range expected {
+		if !present[name] {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) == 0 {
+		return true
+	}
+
+	var found []string
+	for name := range present {
+		found = append(found, name)
+	}
+	sort.Strings(found)
+	return assert.Fail(t, fmt.Sprintf("missing %d %s: %s (present: %s)", len(missing), what,
+		strings.Join(missing, ", "), strings.Join(found, ", ")))
+}
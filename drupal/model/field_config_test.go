@@ -0,0 +1,47 @@
+package model
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_AssertBundleHasFieldsAndNodeTypes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/jsonapi/node_type/node_type":
+			_, _ = w.Write([]byte(`{"data": [
+				{"type": "node_type--node_type", "id": "1", "attributes": {"drupal_internal__type": "islandora_object"}},
+				{"type": "node_type--node_type", "id": "2", "attributes": {"drupal_internal__type": "collection_object"}}]}`))
+		case "/jsonapi/field_config/field_config":
+			assert.Equal(t, "node", r.URL.Query().Get("filter[entity_type]"))
+			assert.Equal(t, "islandora_object", r.URL.Query().Get("filter[bundle]"))
+			_, _ = w.Write([]byte(`{"data": [
+				{"type": "field_config--field_config", "id": "3", "attributes": {
+					"drupal_internal__id": "node.islandora_object.field_member_of", "field_name": "field_member_of",
+					"entity_type": "node", "bundle": "islandora_object", "label": "Member of", "required": false,
+					"field_type": "entity_reference"}},
+				{"type": "field_config--field_config", "id": "4", "attributes": {
+					"drupal_internal__id": "node.islandora_object.field_access_terms", "field_name": "field_access_terms",
+					"entity_type": "node", "bundle": "islandora_object", "required": true,
+					"field_type": "entity_reference"}}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	setBaseUrl(t, server)
+
+	assert.True(t, AssertNodeTypes(t, []string{RepositoryObject, Collection}))
+	assert.True(t, AssertBundleHasFields(t, Node, RepositoryObject, []string{"field_member_of", "field_access_terms"}))
+
+	configs, err := FieldConfigsErr(Node, RepositoryObject)
+	require.Nil(t, err, "%s", err)
+	attrs := configs.JsonApiData[1].JsonApiAttributes
+	assert.Equal(t, "node.islandora_object.field_access_terms", attrs.ConfigId)
+	assert.True(t, attrs.Required)
+	assert.Equal(t, "entity_reference", attrs.FieldType)
+}
@@ -0,0 +1,235 @@
+package model
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/stretchr/testify/assert"
+)
+
+// Checksum algorithms supported by JsonApiFileAttributes.Checksum
+const (
+	MD5    = "md5"
+	SHA1   = "sha1"
+	SHA256 = "sha256"
+)
+
+var checksumAlgorithms = map[string]func() hash.Hash{
+	MD5:    md5.New,
+	SHA1:   sha1.New,
+	SHA256: sha256.New,
+}
+
+// The attributes of a file entity
+type JsonApiFileAttributes struct {
+	Filename string
+	Uri      struct {
+		Url   string
+		Value string
+	}
+	MimeType    string `json:"filemime"`
+	FileSize    int
+	CreatedDate string `json:"created"`
+	ChangedDate string `json:"changed"`
+}
+
+// fileUrl answers the absolute form of the supplied file url.  Drupal answers the url of private files (e.g.
+// `/system/files/2021-06/report.xml`) relative to its base url.
+func fileUrl(fileUrl string) (string, error) {
+	u, err := url.Parse(fileUrl)
+	if err != nil {
+		return "", fmt.Errorf("model: unable to parse file url '%s': %w", fileUrl, err)
+	}
+	if u.IsAbs() {
+		return u.String(), nil
+	}
+
+	base, err := url.Parse(strings.TrimSuffix(env.BaseUrlOr(defaultBaseUrl), "/") + "/")
+	if err != nil {
+		return "", fmt.Errorf("model: unable to parse base url: %w", err)
+	}
+	return base.ResolveReference(u).String(), nil
+}
+
+// fetchFileErr resolves the supplied file relationship, and answers the content of the file, retrieved using the
+// credentials from the environment (see Resolve)
+func fetchFileErr(file JsonApiData) ([]byte, error) {
+	body, err := openFileErr(file)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = body.Close() }()
+
+	content, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("model: unable to read file %s: %w", file.Id, err)
+	}
+	return content, nil
+}
+
+// openFileErr resolves the supplied file relationship, and answers the open content of the file, retrieved using the
+// credentials from the environment (see Resolve).  The caller is responsible for closing the content.
+func openFileErr(file JsonApiData) (io.ReadCloser, error) {
+	f, err := ResolveAsErr[JsonApiFile](file)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := f.JsonApiData[0].JsonApiAttributes.open()
+	if err != nil {
+		return nil, fmt.Errorf("model: unable to retrieve file %s: %w", file.Id, err)
+	}
+	return res.Body, nil
+}
+
+// DownloadTo writes the content of the file to the supplied writer, asserting that the file can be retrieved and that
+// its length matches the file's size
+func (fa JsonApiFileAttributes) DownloadTo(t *testing.T, w io.Writer) {
+	_, err := fa.DownloadToErr(w)
+	assert.Nil(t, err, "%s", err)
+}
+
+// DownloadToErr behaves as DownloadTo, but answers an error instead of making assertions.  The number of bytes written
+// is answered.  The file is retrieved using the credentials from the environment (see Resolve), and a relative url
+// (e.g. of a private file) is resolved against the base url.
+//
+// An error wrapping ErrInvalidData is answered if the Content-Length of the response, or the number of bytes received,
+// does not match the size of the file.
+func (fa JsonApiFileAttributes) DownloadToErr(w io.Writer) (int64, error) {
+	res, err := fa.open()
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	if res.ContentLength >= 0 && res.ContentLength != int64(fa.FileSize) {
+		return 0, fmt.Errorf("%w: Content-Length %d of file '%s' does not match its size %d",
+			ErrInvalidData, res.ContentLength, fa.Filename, fa.FileSize)
+	}
+
+	n, err := io.Copy(w, res.Body)
+	if err != nil {
+		return n, fmt.Errorf("model: unable to download file '%s': %w", fa.Filename, err)
+	}
+	if n != int64(fa.FileSize) {
+		return n, fmt.Errorf("%w: received %d bytes of file '%s', but its size is %d", ErrInvalidData, n, fa.Filename, fa.FileSize)
+	}
+	return n, nil
+}
+
+// Checksum downloads the file and answers the hex-encoded digest of its content using the supplied algorithm (one of
+// MD5, SHA1, or SHA256), asserting that the file can be retrieved and that its length matches the file's size
+func (fa JsonApiFileAttributes) Checksum(t *testing.T, algorithm string) string {
+	sum, err := fa.ChecksumErr(algorithm)
+	assert.Nil(t, err, "%s", err)
+	return sum
+}
+
+// ChecksumErr behaves as Checksum, but answers an error instead of making assertions
+func (fa JsonApiFileAttributes) ChecksumErr(algorithm string) (string, error) {
+	newHash, ok := checksumAlgorithms[strings.ToLower(algorithm)]
+	if !ok {
+		return "", fmt.Errorf("model: unsupported checksum algorithm '%s'", algorithm)
+	}
+
+	h := newHash()
+	if _, err := fa.DownloadToErr(h); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// open answers the response carrying the content of the file, retrieved using the credentials from the environment.
+// The caller is responsible for closing the body of the response.
+func (fa JsonApiFileAttributes) open() (*http.Response, error) {
+	u, err := fileUrl(fa.Uri.Url)
+	if err != nil {
+		return nil, err
+	}
+	return jsonapi.OpenResourceErr(u, env.UsernameOr(""), env.PasswordOr(""))
+}
+
+// Describes the attributes expected of a file entity.  Zero values are not checked, so an expectation may be as broad
+// or as narrow as needed.
+type ExpectedFile struct {
+	// The mime type of the file, e.g. `image/tiff`
+	MimeType string
+	// The minimum size of the file, in bytes
+	MinSize int
+	// The maximum size of the file, in bytes
+	MaxSize int
+	// A regular expression the name of the file must match, e.g. `^moonrise.*\.tiff?$`
+	FilenameRegex string
+	// The prefix of the file's url.  A prefix carrying a scheme (e.g. `public://` or `private://`) is matched against the
+	// stream wrapper uri of the file; otherwise it is matched against the url the file is served from, e.g.
+	// `/system/files/` for private files, or `/sites/default/files/` for public files.
+	UrlPrefix string
+}
+
+// AssertFileAttributes resolves the file relationship (e.g. the field_media_image of an image media), and asserts that
+// the attributes of the file meet the expectation.  Every unmet expectation is reported in a single failure.  The
+// resolved attributes are answered:
+//
+//	model.AssertFileAttributes(t, media.File().JsonApiData, model.ExpectedFile{MimeType: "image/tiff",
+//		MinSize: 40_000_000, FilenameRegex: `\.tiff?$`, UrlPrefix: "private://"})
+func AssertFileAttributes(t *testing.T, fileData JsonApiData, expected ExpectedFile) JsonApiFileAttributes {
+	f, err := ResolveAsErr[JsonApiFile](fileData)
+	if !assert.Nil(t, err, "%s", err) {
+		return JsonApiFileAttributes{}
+	}
+
+	fa := f.JsonApiData[0].JsonApiAttributes
+	if failures := fileAttributeFailures(fa, expected); len(failures) > 0 {
+		assert.Fail(t, fmt.Sprintf("file '%s' (%s) does not meet its expectation:\n\t%s", fa.Filename, fileData.Id,
+			strings.Join(failures, "\n\t")))
+	}
+	return fa
+}
+
+// fileAttributeFailures answers a description of each expectation that the file attributes do not meet
+func fileAttributeFailures(fa JsonApiFileAttributes, expected ExpectedFile) []string {
+	var failures []string
+	if expected.MimeType != "" && expected.MimeType != fa.MimeType {
+		failures = append(failures, fmt.Sprintf("filemime: expected '%s', actual '%s'", expected.MimeType, fa.MimeType))
+	}
+	if expected.MinSize > 0 && fa.FileSize < expected.MinSize {
+		failures = append(failures, fmt.Sprintf("filesize: expected at least %d bytes, actual %d", expected.MinSize,
+			fa.FileSize))
+	}
+	if expected.MaxSize > 0 && fa.FileSize > expected.MaxSize {
+		failures = append(failures, fmt.Sprintf("filesize: expected at most %d bytes, actual %d", expected.MaxSize,
+			fa.FileSize))
+	}
+	if expected.FilenameRegex != "" {
+		if re, err := regexp.Compile(expected.FilenameRegex); err != nil {
+			failures = append(failures, fmt.Sprintf("filename: invalid pattern '%s': %s", expected.FilenameRegex, err))
+		} else if !re.MatchString(fa.Filename) {
+			failures = append(failures, fmt.Sprintf("filename: expected to match '%s', actual '%s'",
+				expected.FilenameRegex, fa.Filename))
+		}
+	}
+	if expected.UrlPrefix != "" {
+		actual := fa.Uri.Url
+		if strings.Contains(expected.UrlPrefix, "://") {
+			actual = fa.Uri.Value
+		}
+		if !strings.HasPrefix(actual, expected.UrlPrefix) {
+			failures = append(failures, fmt.Sprintf("uri: expected to start with '%s', actual '%s'", expected.UrlPrefix,
+				actual))
+		}
+	}
+	return failures
+}
@@ -0,0 +1,74 @@
+package model
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Checksum(t *testing.T) {
+	content := []byte("moo")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, _, _ := r.BasicAuth()
+		if r.URL.Path != "/system/files/moo.txt" || user != "admin" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(content)
+	}))
+	defer server.Close()
+	setBaseUrl(t, server)
+	t.Setenv("DRUPAL_USERNAME", "admin")
+	t.Setenv("DRUPAL_PASSWORD", "moo")
+
+	file := JsonApiFileAttributes{Filename: "moo.txt", FileSize: len(content)}
+	file.Uri.Url = "/system/files/moo.txt"
+
+	assert.Equal(t, "b7d192a44e0da16cd180ebe85efb7c8f", file.Checksum(t, MD5))
+	assert.Equal(t, "24a56b37819e0452df9c07432e5dd2e2b5cebf48", file.Checksum(t, "SHA1"))
+	assert.Equal(t, "47dfae9288abf3d5d2252abfb0bd6ac9662637d646e6df9d5d274bc336e27abc", file.Checksum(t, SHA256))
+
+	buf := bytes.Buffer{}
+	file.DownloadTo(t, &buf)
+	assert.Equal(t, content, buf.Bytes())
+
+	_, err := file.ChecksumErr("crc32")
+	assert.NotNil(t, err)
+
+	file.FileSize = 4
+	_, err = file.ChecksumErr(MD5)
+	assert.True(t, errors.Is(err, ErrInvalidData), "expected ErrInvalidData, got %v", err)
+}
+
+func Test_AssertFileAttributes(t *testing.T) {
+	server := newDocumentServer(map[string]string{testUuid(0): fmt.Sprintf(`{"type": "file--file", "id": "%s",
+		"attributes": {"filename": "moonrise.tiff", "filemime": "image/tiff", "filesize": 41943040,
+		"uri": {"value": "private://2021-06/moonrise.tiff", "url": "/system/files/2021-06/moonrise.tiff"}}}`,
+		testUuid(0))})
+	defer server.Close()
+	setBaseUrl(t, server)
+
+	file := JsonApiData{Type: "file--file", Id: testUuid(0)}
+	fa := AssertFileAttributes(t, file, ExpectedFile{MimeType: "image/tiff", MinSize: 40_000_000,
+		FilenameRegex: `^moonrise\.tiff?$`, UrlPrefix: "private://"})
+	assert.Equal(t, "moonrise.tiff", fa.Filename)
+	AssertFileAttributes(t, file, ExpectedFile{UrlPrefix: "/system/files/"})
+
+	failures := fileAttributeFailures(fa, ExpectedFile{MimeType: "image/jp2", MaxSize: 1024, FilenameRegex: `\.jp2$`,
+		UrlPrefix: "public://"})
+	assert.Equal(t, []string{
+		"filemime: expected 'image/jp2', actual 'image/tiff'",
+		"filesize: expected at most 1024 bytes, actual 41943040",
+		`filename: expected to match '\.jp2$', actual 'moonrise.tiff'`,
+		"uri: expected to start with 'public://', actual 'private://2021-06/moonrise.tiff'",
+	}, failures)
+
+	failures = fileAttributeFailures(fa, ExpectedFile{MinSize: 50_000_000, FilenameRegex: `(`})
+	assert.Equal(t, 2, len(failures), "%v", failures)
+	assert.Contains(t, failures[1], "invalid pattern")
+}
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// FindCollectionByTitle retrieves the collection with the supplied title, asserting that exactly one collection
+// matches.  By default the request is authenticated using the credentials from the environment; options may be used
+// to retrieve the collection anonymously, or from a different base url:
+//
+//	collection := model.FindCollectionByTitle(t, "Ansel Adams Images", model.WithAnonymous())
+func FindCollectionByTitle(t *testing.T, title string, opts ...Option) JsonApiCollection {
+	collection := JsonApiCollection{}
+	findSingle(t, Collection, "title", title, &collection, opts)
+	return collection
+}
+
+// FindObjectByTitle retrieves the repository object with the supplied title, asserting that exactly one repository
+// object matches.  See FindCollectionByTitle for the supported options.
+func FindObjectByTitle(t *testing.T, title string, opts ...Option) JsonApiIslandoraObj {
+	obj := JsonApiIslandoraObj{}
+	findSingle(t, RepositoryObject, "title", title, &obj, opts)
+	return obj
+}
+
+// FindObjectByDigitalIdentifier retrieves the repository object carrying the supplied digital identifier (one of the
+// values of field_digital_identifier), asserting that exactly one repository object matches.  See
+// FindCollectionByTitle for the supported options.
+func FindObjectByDigitalIdentifier(t *testing.T, id string, opts ...Option) JsonApiIslandoraObj {
+	obj := JsonApiIslandoraObj{}
+	findSingle(t, RepositoryObject, "field_digital_identifier", id, &obj, opts)
+	return obj
+}
+
+// findSingle retrieves the single node of the bundle whose field matches the value into v, as a Client customized by
+// the options
+func findSingle(t *testing.T, bundle, field, value string, v interface{}, opts []Option) {
+	err := NewClient(opts...).findSingleErr(context.Background(), bundle, field, value, v)
+	assert.Nil(t, err, "%s", err)
+}
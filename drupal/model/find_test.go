@@ -0,0 +1,63 @@
+package model
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_FindWithOptions(t *testing.T) {
+	var authenticated bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _, authenticated = r.BasicAuth()
+		switch {
+		case r.URL.Path == "/jsonapi/node/collection_object" && r.URL.Query().Get("filter[title]") == "Photographs & Prints":
+			_, _ = fmt.Fprintf(w, `{"data": [%s]}`, collectionElement(testUuid(1), "Photographs & Prints", ""))
+		case r.URL.Path == "/jsonapi/node/islandora_object" && r.URL.Query().Get("filter[field_digital_identifier]") == "ms-0001":
+			_, _ = fmt.Fprintf(w, `{"data": [{"type": "node--islandora_object", "id": "%s",
+				"attributes": {"title": "Letter", "field_digital_identifier": ["ms-0001"]}}]}`, testUuid(2))
+		default:
+			_, _ = w.Write([]byte(`{"data": []}`))
+		}
+	}))
+	defer server.Close()
+	t.Setenv("DRUPAL_BASE_URL", "http://localhost:1")
+	t.Setenv("DRUPAL_USERNAME", "admin")
+	t.Setenv("DRUPAL_PASSWORD", "moo")
+
+	collection := FindCollectionByTitle(t, "Photographs & Prints", WithBaseUrl(server.URL))
+	require.Equal(t, 1, len(collection.JsonApiData))
+	assert.Equal(t, testUuid(1), collection.JsonApiData[0].Id)
+	assert.True(t, authenticated)
+
+	obj := FindObjectByDigitalIdentifier(t, "ms-0001", WithBaseUrl(server.URL), WithAnonymous())
+	require.Equal(t, 1, len(obj.JsonApiData))
+	assert.Equal(t, "Letter", obj.JsonApiData[0].JsonApiAttributes.Title)
+	assert.False(t, authenticated)
+}
+
+func Test_FindWithUser(t *testing.T) {
+	var user string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, _, _ = r.BasicAuth()
+		_, _ = fmt.Fprintf(w, `{"data": [%s]}`, collectionElement(testUuid(1), "Photographs & Prints", ""))
+	}))
+	defer server.Close()
+	setBaseUrl(t, server)
+	t.Setenv("IDC_ADMIN_PASSWORD", "moo")
+
+	FindCollectionByTitle(t, "Photographs & Prints", WithUser(env.AdminCredentials()))
+	assert.Equal(t, "admin", user)
+
+	FindCollectionByTitle(t, "Photographs & Prints", WithUser(env.AdminCredentials()), WithAnonymous())
+	assert.Equal(t, "", user)
+
+	t.Setenv("IDC_ADMIN_USERNAME", "")
+	err := env.AdminCredentials().Validate()
+	assert.Contains(t, fmt.Sprint(err), "set IDC_ADMIN_USERNAME")
+}
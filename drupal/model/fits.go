@@ -0,0 +1,100 @@
+package model
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Represents the technical metadata reported by FITS (https://projects.iq.harvard.edu/fits) for a file, as stored in
+// the file of a fits_technical_metadata media
+type FitsReport struct {
+	XMLName        xml.Name `xml:"fits"`
+	Version        string   `xml:"version,attr"`
+	Identification struct {
+		Status     string         `xml:"status,attr"`
+		Identities []FitsIdentity `xml:"identity"`
+	} `xml:"identification"`
+	FileInfo struct {
+		Size        int64  `xml:"size"`
+		Filename    string `xml:"filename"`
+		Md5Checksum string `xml:"md5checksum"`
+	} `xml:"fileinfo"`
+	FileStatus struct {
+		WellFormed bool `xml:"well-formed"`
+		Valid      bool `xml:"valid"`
+	} `xml:"filestatus"`
+	Metadata struct {
+		Image struct {
+			Width  int `xml:"imageWidth"`
+			Height int `xml:"imageHeight"`
+		} `xml:"image"`
+	} `xml:"metadata"`
+}
+
+// A format identified by FITS, along with identifiers (e.g. PRONOM PUIDs) supplied by the identifying tools
+type FitsIdentity struct {
+	Format              string `xml:"format,attr"`
+	MimeType            string `xml:"mimetype,attr"`
+	ExternalIdentifiers []struct {
+		Type  string `xml:"type,attr"`
+		Value string `xml:",chardata"`
+	} `xml:"externalIdentifier"`
+}
+
+// MimeType answers the mime type of the first identity reported by FITS, or the empty string if none were reported
+func (r FitsReport) MimeType() string {
+	if len(r.Identification.Identities) == 0 {
+		return ""
+	}
+	return r.Identification.Identities[0].MimeType
+}
+
+// PUIDs answers the PRONOM unique identifiers (e.g. `fmt/353`) of every identity reported by FITS
+func (r FitsReport) PUIDs() []string {
+	var puids []string
+	for _, identity := range r.Identification.Identities {
+		for _, id := range identity.ExternalIdentifiers {
+			if id.Type == "puid" {
+				puids = append(puids, id.Value)
+			}
+		}
+	}
+	return puids
+}
+
+// ParseFitsReport parses FITS XML output
+func ParseFitsReport(r io.Reader) (FitsReport, error) {
+	report := FitsReport{}
+	if err := xml.NewDecoder(r).Decode(&report); err != nil {
+		return report, fmt.Errorf("model: unable to parse FITS report: %w", err)
+	}
+	return report, nil
+}
+
+// FitsReportOf resolves the file of the supplied FITS technical metadata media, retrieves it, and answers the parsed
+// report.
+func FitsReportOf(t *testing.T, media JsonApiFitsMedia) FitsReport {
+	report, err := FitsReportOfErr(media)
+	assert.Nil(t, err, "%s", err)
+	return report
+}
+
+// FitsReportOfErr behaves as FitsReportOf, but answers an error instead of making assertions.
+func FitsReportOfErr(media JsonApiFitsMedia) (FitsReport, error) {
+	file := media.File()
+	if file.IsZero() {
+		return FitsReport{}, fmt.Errorf("model: FITS media '%s' has no file", media.Name())
+	}
+
+	body, err := fetchFileErr(file.JsonApiData)
+	if err != nil {
+		return FitsReport{}, err
+	}
+
+	return ParseFitsReport(bytes.NewReader(body))
+}
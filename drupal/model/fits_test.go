@@ -0,0 +1,52 @@
+package model
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_FitsReportOf(t *testing.T) {
+	fileId := testUuid(2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch collectionPath(r) {
+		case "/jsonapi/file/file":
+			_, _ = fmt.Fprintf(w, `{"data": [{"type": "file--file", "id": "%s",
+				"attributes": {"filename": "fits.xml", "uri": {"value": "private://fits.xml", "url": "/system/files/fits.xml"}}}]}`, fileId)
+		case "/system/files/fits.xml":
+			http.ServeFile(w, r, filepath.Join("testdata", "fits.xml"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	setBaseUrl(t, server)
+
+	media := JsonApiFitsMedia{}
+	unmarshalTestdata(t, "media-fits.json", &media)
+	require.Equal(t, fileId, media.File().Id)
+
+	report := FitsReportOf(t, media)
+	assert.Equal(t, "image/tiff", report.MimeType())
+	assert.Equal(t, []string{"fmt/353"}, report.PUIDs())
+	assert.True(t, report.FileStatus.WellFormed)
+	assert.True(t, report.FileStatus.Valid)
+	assert.Equal(t, int64(41943040), report.FileInfo.Size)
+	assert.Equal(t, 4000, report.Metadata.Image.Width)
+	assert.Equal(t, 3000, report.Metadata.Image.Height)
+}
+
+func Test_ParseFitsReportInvalid(t *testing.T) {
+	f, err := os.Open(filepath.Join("testdata", "media-audio.json"))
+	require.Nil(t, err)
+	defer f.Close()
+
+	_, err = ParseFitsReport(f)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,388 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Vocabularies holding the terms referenced by fixtures
+const (
+	accessVocabulary       = "islandora_access"
+	languageVocabulary     = "language"
+	modelsVocabulary       = "islandora_models"
+	resourceTypeVocabulary = "resource_types"
+	subjectVocabulary      = "subject"
+	mediaUseVocabulary     = "islandora_media_use"
+)
+
+// The entity type and bundle of the files uploaded by CreateMedia
+const fileEntity = "file"
+
+// The file field of each media bundle accepting an upload, and the function creating media of the bundle as its
+// JSON API struct
+var mediaFileFields = map[string]struct {
+	field  string
+	create func(t *testing.T, bundle string, r jsonapi.Resource, opts []Option) Media
+}{
+	Image:         {"field_media_image", createMedia[JsonApiImageMedia]},
+	Document:      {"field_media_document", createMedia[JsonApiDocumentMedia]},
+	Audio:         {"field_media_audio_file", createMedia[JsonApiAudioMedia]},
+	Video:         {"field_media_video_file", createMedia[JsonApiVideoMedia]},
+	ExtractedText: {"field_media_file", createMedia[JsonApiExtractedTextMedia]},
+	File:          {"field_media_file", createMedia[JsonApiGenericFileMedia]},
+	Fits:          {"field_media_file", createMedia[JsonApiFitsMedia]},
+}
+
+// The language of the language-tagged values of fixtures, e.g. the description of a collection
+const fixtureLangCode = "en"
+
+// Describes a collection to be created by CreateCollection.  Only the Title is required.
+type CollectionSpec struct {
+	Title string
+	// The alternative titles of the collection, each in the language with its code, e.g. `es`
+	AltTitles []ExpectedLangString
+	// The description of the collection, in English
+	Description  string
+	ContactEmail string
+	// The names of the Islandora Access terms of the collection, e.g. `Staff Only`
+	AccessTermNames []string
+	// The uuid of the collection the new collection is a member of, if any
+	MemberOfUuid string
+}
+
+// CreateCollection creates a collection_object node described by the spec, and answers the created collection.  The
+// access terms are resolved by name before the collection is created, and the test fails immediately if any cannot be
+// found, or if the collection cannot be created.  The collection is created as the user from the environment (or as
+// supplied by the options), and is deleted when the test completes:
+//
+//	collection := model.CreateCollection(t, model.CollectionSpec{Title: "Staff Collection",
+//		AccessTermNames: []string{"Staff Only"}})
+func CreateCollection(t *testing.T, spec CollectionSpec, opts ...Option) JsonApiCollection {
+	return create[JsonApiCollection](t, Node, Collection, collectionResource(t, spec, opts), opts)
+}
+
+// collectionResource answers the resource described by the spec, resolving its terms by name.  The test fails
+// immediately if any term cannot be found.
+func collectionResource(t *testing.T, spec CollectionSpec, opts []Option) jsonapi.Resource {
+	r := jsonapi.Resource{
+		Attributes: map[string]interface{}{
+			"title": spec.Title,
+		},
+		Relationships: map[string]interface{}{},
+	}
+	if spec.ContactEmail != "" {
+		r.Attributes["field_collection_contact_email"] = spec.ContactEmail
+	}
+	if len(spec.AltTitles) > 0 {
+		r.Relationships["field_alternative_title"] = jsonapi.ToLanguageValues(
+			LanguageTermValues(t, spec.AltTitles, opts...)...)
+	}
+
+	if spec.Description != "" {
+		lang, err := findTermErr(t, languageVocabulary, "field_language_code", fixtureLangCode, opts)
+		require.Nil(t, err, "%s", err)
+		lang.Meta = map[string]string{"value": spec.Description}
+		r.Relationships["field_description"] = jsonapi.ToMany(lang)
+	}
+
+	access, err := findTermsErr(t, accessVocabulary, spec.AccessTermNames, opts)
+	require.Nil(t, err, "%s", err)
+	if len(access) > 0 {
+		r.Relationships["field_access_terms"] = jsonapi.ToMany(access...)
+	}
+
+	if spec.MemberOfUuid != "" {
+		r.Relationships["field_member_of"] = jsonapi.ToOne(&jsonapi.ResourceIdentifier{
+			Type: jsonapi.NewDrupalType(Node, Collection), Id: spec.MemberOfUuid})
+	}
+	return r
+}
+
+// Describes a repository object to be created by CreateObject.  Only the Title and ModelName are required.
+type ObjectSpec struct {
+	Title string
+	// The alternative titles of the object, each in the language with its code, e.g. `es`
+	AltTitles []ExpectedLangString
+	// The name of the Islandora Models term of the object, e.g. `Image` or `Paged Content`
+	ModelName string
+	// The uuid of the collection, or repository object, the new object is a member of, if any
+	MemberOfUuid string
+	// The names of the Resource Types terms of the object, e.g. `Still Image`
+	ResourceTypeNames []string
+	// The names of the Subject terms of the object
+	SubjectNames []string
+	// The creators of the object
+	Creators []CreatorSpec
+}
+
+// Describes the creator of a repository object to be created by CreateObject
+type CreatorSpec struct {
+	// The relator of the creator, e.g. `relators:pht`
+	RelType string
+	// The name of the term
+	Name string
+	// The vocabulary of the term: Person, Family, or CorporateBody.  Defaults to Person.
+	Kind string
+}
+
+// CreateObject creates an islandora_object node described by the spec, and answers the created repository object.
+// Every referenced term is resolved by name before the object is created, and the test fails immediately, naming each
+// term that cannot be found, or if the object cannot be created.  The object is created as the user from the
+// environment (or as supplied by the options), and is deleted when the test completes:
+//
+//	obj := model.CreateObject(t, model.ObjectSpec{Title: "Moonrise", ModelName: "Image",
+//		MemberOfUuid: collection.JsonApiData[0].Id, SubjectNames: []string{"Analog Photography"},
+//		Creators: []model.CreatorSpec{{RelType: "relators:pht", Name: "Adams, Ansel"}}})
+func CreateObject(t *testing.T, spec ObjectSpec, opts ...Option) JsonApiIslandoraObj {
+	return create[JsonApiIslandoraObj](t, Node, RepositoryObject, objectResource(t, spec, opts), opts)
+}
+
+// objectResource answers the resource described by the spec, resolving its terms by name.  The test fails
+// immediately, naming each term that cannot be found.
+func objectResource(t *testing.T, spec ObjectSpec, opts []Option) jsonapi.Resource {
+	r := jsonapi.Resource{
+		Attributes: map[string]interface{}{
+			"title": spec.Title,
+		},
+		Relationships: map[string]interface{}{},
+	}
+
+	var problems []string
+	resolve := func(vocabulary string, names []string) []jsonapi.ResourceIdentifier {
+		terms, err := findTermsErr(t, vocabulary, names, opts)
+		if err != nil {
+			problems = append(problems, err.Error())
+		}
+		return terms
+	}
+
+	if len(spec.AltTitles) > 0 {
+		if titles, err := LanguageTermValuesErr(spec.AltTitles, opts...); err != nil {
+			problems = append(problems, err.Error())
+		} else {
+			r.Relationships["field_alternative_title"] = jsonapi.ToLanguageValues(titles...)
+		}
+	}
+	if model := resolve(modelsVocabulary, []string{spec.ModelName}); len(model) > 0 {
+		r.Relationships["field_model"] = jsonapi.ToOne(&model[0])
+	}
+	if types := resolve(resourceTypeVocabulary, spec.ResourceTypeNames); len(types) > 0 {
+		r.Relationships["field_resource_type"] = jsonapi.ToMany(types...)
+	}
+	if subjects := resolve(subjectVocabulary, spec.SubjectNames); len(subjects) > 0 {
+		r.Relationships["field_subject"] = jsonapi.ToMany(subjects...)
+	}
+
+	var creators []jsonapi.ResourceIdentifier
+	for _, c := range spec.Creators {
+		kind := c.Kind
+		if kind == "" {
+			kind = Person
+		}
+		if term := resolve(kind, []string{c.Name}); len(term) > 0 {
+			term[0].Meta = map[string]string{relTypeKey: c.RelType}
+			creators = append(creators, term[0])
+		}
+	}
+	if len(creators) > 0 {
+		r.Relationships["field_creator"] = jsonapi.ToMany(creators...)
+	}
+
+	if spec.MemberOfUuid != "" {
+		r.Relationships["field_member_of"] = jsonapi.ToOne(&jsonapi.ResourceIdentifier{
+			Type: jsonapi.NewDrupalType(Node, Collection), Id: spec.MemberOfUuid})
+	}
+
+	require.Empty(t, problems, "unable to create repository object '%s':\n\t%s", spec.Title,
+		strings.Join(problems, "\n\t"))
+	return r
+}
+
+// Describes a media, and the file it carries, to be created by CreateMedia.  The Bundle, MediaOfUuid, and either Path
+// or Reader and Filename, are required.
+type MediaSpec struct {
+	// The bundle of the media, e.g. Image or Document
+	Bundle string
+	// The path of the local file to upload
+	Path string
+	// The content of the file to upload, if Path is empty
+	Reader io.Reader
+	// The name of the uploaded file; defaults to the base name of the Path
+	Filename string
+	// The name of the media; defaults to the name of the uploaded file
+	Name string
+	// The uuid of the repository object the media belongs to
+	MediaOfUuid string
+	// The name of the Islandora Media Use term of the media, e.g. OriginalFile
+	MediaUseName string
+	// The names of the Islandora Access terms of the media
+	AccessTermNames  []string
+	RestrictedAccess bool
+}
+
+// CreateMedia uploads the file described by the spec, creates a media of the bundle carrying it, and answers the created
+// media as the JSON API struct of the bundle, e.g. a JsonApiImageMedia.  The media use and access terms are resolved by
+// name before the file is uploaded, and the test fails immediately if any cannot be found, or if the file or media
+// cannot be created.  The media, and its file, are created as the user from the environment (or as supplied by the
+// options), and are deleted when the test completes:
+//
+//	media := model.CreateMedia(t, model.MediaSpec{Bundle: model.Image, Path: "testdata/moo.tiff",
+//		MediaOfUuid: obj.JsonApiData[0].Id, MediaUseName: model.OriginalFile})
+//	image := media.(model.JsonApiImageMedia)
+func CreateMedia(t *testing.T, spec MediaSpec, opts ...Option) Media {
+	bundle, ok := mediaFileFields[spec.Bundle]
+	require.True(t, ok, "unable to create media: %s media do not carry an uploaded file", spec.Bundle)
+	require.NotEmpty(t, spec.MediaOfUuid, "unable to create %s media: the repository object is required", spec.Bundle)
+
+	use, err := findTermsErr(t, mediaUseVocabulary, nonEmpty([]string{spec.MediaUseName}), opts)
+	require.Nil(t, err, "%s", err)
+	access, err := findTermsErr(t, accessVocabulary, spec.AccessTermNames, opts)
+	require.Nil(t, err, "%s", err)
+
+	filename, content := spec.Filename, spec.Reader
+	if spec.Path != "" {
+		f, err := os.Open(spec.Path)
+		require.Nil(t, err, "unable to create %s media: %s", spec.Bundle, err)
+		defer func() { _ = f.Close() }()
+		if filename == "" {
+			filename = filepath.Base(spec.Path)
+		}
+		content = f
+	}
+	require.NotNil(t, content, "unable to create %s media: a Path or Reader is required", spec.Bundle)
+	require.NotEmpty(t, filename, "unable to create %s media: a Filename is required with a Reader", spec.Bundle)
+
+	file := upload(t, spec.Bundle, bundle.field, filename, content, opts)
+
+	name := spec.Name
+	if name == "" {
+		name = filename
+	}
+	if spec.Bundle == Image {
+		file.Meta = map[string]string{"alt": name}
+	}
+	r := jsonapi.Resource{
+		Attributes: map[string]interface{}{
+			"name":                    name,
+			"field_restricted_access": spec.RestrictedAccess,
+		},
+		Relationships: map[string]interface{}{
+			bundle.field: jsonapi.ToOne(&file),
+			"field_media_of": jsonapi.ToOne(&jsonapi.ResourceIdentifier{
+				Type: jsonapi.NewDrupalType(Node, RepositoryObject), Id: spec.MediaOfUuid}),
+			"field_media_use": jsonapi.ToMany(use...),
+		},
+	}
+	if len(access) > 0 {
+		r.Relationships["field_access_terms"] = jsonapi.ToMany(access...)
+	}
+
+	return bundle.create(t, spec.Bundle, r, opts)
+}
+
+// createMedia creates the resource as a media of the bundle, answering it as the JSON API struct of the bundle
+func createMedia[T Media](t *testing.T, bundle string, r jsonapi.Resource, opts []Option) Media {
+	return create[T](t, MediaEntity, bundle, r, opts)
+}
+
+// upload uploads the content to the file field of the media bundle, answering the identifier of the created file, and
+// registers its deletion when the test completes.  The test fails immediately if the file cannot be uploaded.
+func upload(t *testing.T, bundle, field, filename string, content io.Reader, opts []Option) jsonapi.ResourceIdentifier {
+	file := JsonApiFile{}
+	u := query(t, MediaEntity, bundle, opts...)
+	err := u.UploadErr(field, filename, content, &file)
+	require.Nil(t, err, "%s", err)
+
+	id, err := createdIdErr(file)
+	require.Nil(t, err, "%s", err)
+	recordFixture(t, fileEntity, fileEntity, id, false, opts)
+	t.Cleanup(func() {
+		deleteFixture(t, fileEntity, fileEntity, id, opts)
+	})
+	return jsonapi.ResourceIdentifier{Type: jsonapi.NewDrupalType(fileEntity, fileEntity), Id: id}
+}
+
+// create creates the resource as an entity of the bundle, answering the created entity as a T, and registers its
+// deletion when the test completes.  The test fails immediately if the entity cannot be created.
+func create[T any](t *testing.T, entity, bundle string, r jsonapi.Resource, opts []Option) T {
+	var v T
+	u := query(t, entity, bundle, opts...)
+	err := u.CreateErr(r, &v)
+	require.Nil(t, err, "%s", err)
+
+	id, err := createdIdErr(v)
+	require.Nil(t, err, "%s", err)
+	recordFixture(t, entity, bundle, id, false, opts)
+	t.Cleanup(func() {
+		deleteFixture(t, entity, bundle, id, opts)
+	})
+	return v
+}
+
+// deleteFixture deletes the entity created by a fixture, asserting that no error occurs, and records its deletion (see
+// RecordedFixture).  Entities that no longer exist, e.g. because the test deleted them itself, are ignored.
+func deleteFixture(t *testing.T, entity, bundle, id string, opts []Option) {
+	u := query(t, entity, bundle, opts...)
+	if err := u.DeleteErr(id); err != nil && !errors.Is(err, jsonapi.ErrNotFound) {
+		assert.Nil(t, err, "unable to delete %s/%s %s created by the test: %s", entity, bundle, id, err)
+		return
+	}
+	recordFixture(t, entity, bundle, id, true, opts)
+}
+
+// createdIdErr answers the id of the first data element of the created entity
+func createdIdErr(v interface{}) (string, error) {
+	if n, ok := dataLen(v); !ok || n == 0 {
+		return "", fmt.Errorf("model: the response creating %T carries no data element", v)
+	}
+	return reflect.ValueOf(v).FieldByName("JsonApiData").Index(0).FieldByName("Id").String(), nil
+}
+
+// findTermsErr answers the identifiers of the named terms of the vocabulary, in order.  If any term cannot be found, the
+// error names every missing term, and wraps jsonapi.ErrNotFound.
+func findTermsErr(t *testing.T, vocabulary string, names []string, opts []Option) ([]jsonapi.ResourceIdentifier, error) {
+	var result []jsonapi.ResourceIdentifier
+	var missing []string
+	for _, name := range names {
+		term, err := findTermErr(t, vocabulary, "name", name, opts)
+		if errors.Is(err, jsonapi.ErrNotFound) {
+			missing = append(missing, fmt.Sprintf("'%s'", name))
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		result = append(result, term)
+	}
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("model: unable to find the %s terms named %s: %w", vocabulary,
+			strings.Join(missing, ", "), jsonapi.ErrNotFound)
+	}
+	return result, nil
+}
+
+// findTermErr answers the identifier of the term of the vocabulary whose field matches the value.  If no term matches,
+// the error wraps jsonapi.ErrNotFound.
+func findTermErr(t *testing.T, vocabulary, field, value string, opts []Option) (jsonapi.ResourceIdentifier, error) {
+	u := query(t, TaxonomyTerm, vocabulary, opts...)
+	u.Filter, u.Value = field, value
+
+	term := struct {
+		JsonApiData []JsonApiData `json:"data"`
+	}{}
+	if err := u.GetSingleErr(&term); err != nil {
+		return jsonapi.ResourceIdentifier{}, fmt.Errorf("model: unable to find the %s term with %s '%s': %w",
+			vocabulary, field, value, err)
+	}
+	return jsonapi.ResourceIdentifier{Type: term.JsonApiData[0].Type, Id: term.JsonApiData[0].Id}, nil
+}
@@ -0,0 +1,403 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// A fake Drupal that serves taxonomy terms, and records (and serves, by id or canonical path) the resources created,
+// updated, and deleted by fixtures, and the content of the files uploaded
+type fakeDrupal struct {
+	*httptest.Server
+	mu sync.Mutex
+	// term ids keyed by `vocabulary field value`, e.g. `islandora_access name Staff Only`
+	terms map[string]string
+	// the documents POSTed, keyed by the id assigned to the created resource
+	created map[string]map[string]interface{}
+	// the content of the files uploaded, keyed by the path they are served from
+	files map[string][]byte
+	// the paths of the resources deleted, in order
+	deleted []string
+	// the number of resources created, including those since deleted
+	n int
+}
+
+// newFakeDrupal answers a fake Drupal serving the supplied terms, and sets the base url of the test to it
+func newFakeDrupal(t *testing.T, terms map[string]string) *fakeDrupal {
+	d := &fakeDrupal{terms: terms, created: map[string]map[string]interface{}{},
+		files: map[string][]byte{}}
+	d.Server = httptest.NewServer(http.HandlerFunc(d.serve))
+	t.Cleanup(d.Close)
+	setBaseUrl(t, d.Server)
+	t.Setenv("DRUPAL_USERNAME", "admin")
+	t.Setenv("DRUPAL_PASSWORD", "moo")
+	return d
+}
+
+func (d *fakeDrupal) serve(w http.ResponseWriter, r *http.Request) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if content, ok := d.files[r.URL.Path]; ok && r.Method == http.MethodGet {
+		_, _ = w.Write(content)
+		return
+	}
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/jsonapi/"), "/")
+
+	switch created := d.findCreated(parts, r.URL.Query()); {
+	case r.Method == http.MethodGet && len(parts) == 3:
+		data, ok := d.created[parts[2]]
+		if !ok && parts[0] == TaxonomyTerm {
+			data, ok = d.term(parts[1], parts[2])
+		}
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		res, _ := json.Marshal(map[string]interface{}{"data": data})
+		_, _ = w.Write(res)
+	case r.Method == http.MethodGet && len(created) > 0:
+		res, _ := json.Marshal(map[string]interface{}{"data": created})
+		_, _ = w.Write(res)
+	case r.Method == http.MethodGet && parts[0] == TaxonomyTerm:
+		for key, value := range r.URL.Query() {
+			field := strings.TrimSuffix(strings.TrimPrefix(key, "filter["), "]")
+			if id, ok := d.terms[fmt.Sprintf("%s %s %s", parts[1], field, value[0])]; ok {
+				_, _ = fmt.Fprintf(w, `{"data": [{"type": "taxonomy_term--%s", "id": "%s", "attributes": {"name": "%s"}}]}`,
+					parts[1], id, value[0])
+				return
+			}
+		}
+		// the vocabulary is retrieved whole (e.g. to be indexed) unless filtered
+		data := []interface{}{}
+		if !strings.Contains(r.URL.RawQuery, "filter") {
+			for _, id := range d.termIds(parts[1]) {
+				term, _ := d.term(parts[1], id)
+				data = append(data, term)
+			}
+		}
+		res, _ := json.Marshal(map[string]interface{}{"data": data})
+		_, _ = w.Write(res)
+	case r.Method == http.MethodPost && r.Header.Get("Content-Type") == "application/octet-stream":
+		body, _ := ioutil.ReadAll(r.Body)
+		id := d.nextId()
+		filename := strings.TrimSuffix(strings.TrimPrefix(r.Header.Get("Content-Disposition"), `file; filename="`), `"`)
+		path := fmt.Sprintf("/system/files/%s/%s", id, filename)
+		d.files[path] = body
+		d.created[id] = map[string]interface{}{"type": "file--file", "id": id,
+			"attributes": map[string]interface{}{"filename": filename, "filesize": len(body),
+				"uri": map[string]interface{}{"url": path, "value": "private://" + id + "/" + filename}}}
+		res, _ := json.Marshal(map[string]interface{}{"data": d.created[id]})
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write(res)
+	case r.Method == http.MethodPost:
+		doc := map[string]map[string]interface{}{}
+		body, _ := ioutil.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &doc); err != nil || r.Header.Get("Content-Type") != "application/vnd.api+json" {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = w.Write([]byte(`{"errors": [{"title": "Unprocessable Entity", "detail": "malformed document"}]}`))
+			return
+		}
+		id := d.nextId()
+		data := doc["data"]
+		data["id"] = id
+		d.created[id] = data
+		res, _ := json.Marshal(map[string]interface{}{"data": data})
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write(res)
+	case r.Method == http.MethodPatch:
+		doc := map[string]map[string]interface{}{}
+		body, _ := ioutil.ReadAll(r.Body)
+		data, ok := d.created[parts[len(parts)-1]]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if err := json.Unmarshal(body, &doc); err != nil || doc["data"]["id"] != data["id"] {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = w.Write([]byte(`{"errors": [{"title": "Unprocessable Entity", "detail": "malformed document"}]}`))
+			return
+		}
+		for _, member := range []string{"attributes", "relationships"} {
+			updates, _ := doc["data"][member].(map[string]interface{})
+			if len(updates) > 0 && data[member] == nil {
+				data[member] = map[string]interface{}{}
+			}
+			for name, value := range updates {
+				data[member].(map[string]interface{})[name] = value
+			}
+		}
+		res, _ := json.Marshal(map[string]interface{}{"data": data})
+		_, _ = w.Write(res)
+	case r.Method == http.MethodDelete:
+		id := parts[len(parts)-1]
+		if _, ok := d.created[id]; !ok {
+			http.NotFound(w, r)
+			return
+		}
+		delete(d.created, id)
+		d.deleted = append(d.deleted, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		_, _ = w.Write([]byte(`{"data": []}`))
+	}
+}
+
+// termIds answers the ids of the terms of the vocabulary, in order
+func (d *fakeDrupal) termIds(vocabulary string) []string {
+	var ids []string
+	seen := map[string]bool{}
+	for key, id := range d.terms {
+		if strings.HasPrefix(key, vocabulary+" ") && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// term answers the term of the vocabulary with the id, carrying each of its fields as an attribute
+func (d *fakeDrupal) term(vocabulary, id string) (map[string]interface{}, bool) {
+	attrs := map[string]interface{}{}
+	for key, termId := range d.terms {
+		if parts := strings.SplitN(key, " ", 3); termId == id && parts[0] == vocabulary {
+			attrs[parts[1]] = parts[2]
+		}
+	}
+	if len(attrs) == 0 {
+		return nil, false
+	}
+	return map[string]interface{}{"type": "taxonomy_term--" + vocabulary, "id": id, "attributes": attrs}, true
+}
+
+// nextId answers the id of the next resource created; ids are never reused
+func (d *fakeDrupal) nextId() string {
+	d.n++
+	return testUuid(99 + d.n)
+}
+
+// findCreated answers the created resources of the entity and bundle of the path whose id, or attribute, matches a
+// filter of the query
+func (d *fakeDrupal) findCreated(parts []string, query url.Values) []interface{} {
+	var result []interface{}
+	for id, data := range d.created {
+		if len(parts) < 2 || data["type"] != parts[0]+"--"+parts[1] {
+			continue
+		}
+		attrs, _ := data["attributes"].(map[string]interface{})
+		for key, value := range query {
+			field := strings.TrimSuffix(strings.TrimPrefix(key, "filter["), "]")
+			if (field == "id" && id == value[0]) || fmt.Sprint(attrs[field]) == value[0] {
+				result = append(result, data)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// relationshipIds answers the ids of the resources in the named relationship of the created resource
+func (d *fakeDrupal) relationshipIds(id, name string) []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	rel, _ := d.created[id]["relationships"].(map[string]interface{})[name].(map[string]interface{})
+	var ids []string
+	switch data := rel["data"].(type) {
+	case []interface{}:
+		for _, item := range data {
+			ids = append(ids, item.(map[string]interface{})["id"].(string))
+		}
+	case map[string]interface{}:
+		ids = append(ids, data["id"].(string))
+	}
+	return ids
+}
+
+func Test_CreateCollection(t *testing.T) {
+	d := newFakeDrupal(t, map[string]string{
+		"islandora_access name Staff Only": testUuid(1),
+		"islandora_access name Public":     testUuid(2),
+		"language field_language_code en":  testUuid(3),
+	})
+
+	var id string
+	t.Run("create", func(t *testing.T) {
+		c := CreateCollection(t, CollectionSpec{Title: "Staff Collection", Description: "A collection for staff",
+			ContactEmail: "staff@example.org", AccessTermNames: []string{"Staff Only", "Public"},
+			MemberOfUuid: testUuid(4)})
+		require.Equal(t, 1, len(c.JsonApiData))
+		id = c.JsonApiData[0].Id
+		attrs := c.JsonApiData[0].JsonApiAttributes
+		assert.Equal(t, "Staff Collection", attrs.Title)
+		assert.Equal(t, "staff@example.org", attrs.ContactEmail)
+		assert.Equal(t, []string{testUuid(1), testUuid(2)}, d.relationshipIds(id, "field_access_terms"))
+		assert.Equal(t, []string{testUuid(4)}, d.relationshipIds(id, "field_member_of"))
+		assert.Equal(t, []string{testUuid(3)}, d.relationshipIds(id, "field_description"))
+		assert.Equal(t, "A collection for staff",
+			c.JsonApiData[0].JsonApiRelationships.Description.Data[0].Value())
+	})
+
+	assert.Equal(t, []string{"/jsonapi/node/collection_object/" + id}, d.deleted,
+		"the collection is expected to be deleted when the test completes")
+}
+
+func Test_CreateCollectionErrors(t *testing.T) {
+	newFakeDrupal(t, map[string]string{})
+
+	_, err := findTermsErr(t, accessVocabulary, []string{"Staff Only"}, nil)
+	assert.True(t, errors.Is(err, jsonapi.ErrNotFound), "expected ErrNotFound, got %v", err)
+	assert.Contains(t, fmt.Sprint(err), "'Staff Only'")
+
+	u := query(t, Node, Collection, WithAnonymous())
+	err = u.CreateErr(jsonapi.Resource{}, &JsonApiCollection{})
+	assert.Contains(t, fmt.Sprint(err), "requires credentials")
+}
+
+func Test_CreateObject(t *testing.T) {
+	d := newFakeDrupal(t, map[string]string{
+		"islandora_models name Image":            testUuid(1),
+		"resource_types name Still Image":        testUuid(2),
+		"subject name Analog Photography":        testUuid(3),
+		"person name Adams, Ansel":               testUuid(4),
+		"corporate_body name Sierra Club":        testUuid(5),
+		"subject name Landscape Photography":     testUuid(6),
+		"resource_types name Cartographic Image": testUuid(7),
+	})
+
+	var id string
+	t.Run("create", func(t *testing.T) {
+		obj := CreateObject(t, ObjectSpec{Title: "Moonrise", ModelName: "Image", MemberOfUuid: testUuid(8),
+			ResourceTypeNames: []string{"Still Image"},
+			SubjectNames:      []string{"Analog Photography", "Landscape Photography"},
+			Creators: []CreatorSpec{
+				{RelType: "relators:pht", Name: "Adams, Ansel"},
+				{RelType: "relators:spn", Name: "Sierra Club", Kind: CorporateBody},
+			}})
+		require.Equal(t, 1, len(obj.JsonApiData))
+		id = obj.JsonApiData[0].Id
+		assert.Equal(t, "Moonrise", obj.JsonApiData[0].JsonApiAttributes.Title)
+		assert.Equal(t, []string{testUuid(1)}, d.relationshipIds(id, "field_model"))
+		assert.Equal(t, []string{testUuid(2)}, d.relationshipIds(id, "field_resource_type"))
+		assert.Equal(t, []string{testUuid(3), testUuid(6)}, d.relationshipIds(id, "field_subject"))
+		assert.Equal(t, []string{testUuid(8)}, d.relationshipIds(id, "field_member_of"))
+		assert.Equal(t, []string{testUuid(4), testUuid(5)}, d.relationshipIds(id, "field_creator"))
+
+		creators := obj.JsonApiData[0].JsonApiRelationships.Creator.Data
+		require.Equal(t, 2, len(creators))
+		relType, err := creators[1].MetaString(relTypeKey)
+		assert.Nil(t, err, "%s", err)
+		assert.Equal(t, "relators:spn", relType)
+	})
+
+	assert.Equal(t, []string{"/jsonapi/node/islandora_object/" + id}, d.deleted,
+		"the repository object is expected to be deleted when the test completes")
+}
+
+func Test_CreateObjectUnresolvedTerms(t *testing.T) {
+	d := newFakeDrupal(t, map[string]string{
+		"islandora_models name Image": testUuid(1),
+	})
+
+	_, err := findTermsErr(t, modelsVocabulary, []string{"Image", "Collage"}, nil)
+	assert.True(t, errors.Is(err, jsonapi.ErrNotFound), "expected ErrNotFound, got %v", err)
+	assert.Contains(t, fmt.Sprint(err), "islandora_models terms named 'Collage'")
+	assert.NotContains(t, fmt.Sprint(err), "'Image'")
+	assert.Empty(t, d.created)
+}
+
+// Creates an object with alternative titles in two languages, and verifies the titles of the object as retrieved
+func Test_CreateObjectAltTitles(t *testing.T) {
+	d := newFakeDrupal(t, map[string]string{
+		"islandora_models name Image":     testUuid(1),
+		"language field_language_code en": testUuid(2),
+		"language name English":           testUuid(2),
+		"language field_language_code es": testUuid(3),
+		"language name Spanish":           testUuid(3),
+	})
+	ResetTermCache()
+	ResetLangCodeCache()
+	t.Cleanup(ResetTermCache)
+	t.Cleanup(ResetLangCodeCache)
+
+	t.Run("create", func(t *testing.T) {
+		obj := CreateObject(t, ObjectSpec{Title: "Moonrise", ModelName: "Image", AltTitles: []ExpectedLangString{
+			{Value: "Moonrise Over Hernandez", LangCode: "en"},
+			{Value: "Salida de la luna sobre Hernández", LangCode: "es"},
+		}})
+		require.Equal(t, 1, len(obj.JsonApiData))
+		id := obj.JsonApiData[0].Id
+		assert.Equal(t, []string{testUuid(2), testUuid(3)}, d.relationshipIds(id, "field_alternative_title"))
+
+		// the language codes are resolved anew from the terms referenced by the retrieved object
+		ResetLangCodeCache()
+		fetched := JsonApiIslandoraObj{}
+		u := query(t, Node, RepositoryObject)
+		u.Filter, u.Value = "id", id
+		require.Nil(t, u.GetSingleErr(&fetched))
+		assert.Equal(t, map[string]string{"en": "Moonrise Over Hernandez", "es": "Salida de la luna sobre Hernández"},
+			LanguageValueMap(t, fetched.JsonApiData[0].JsonApiRelationships.AltTitle.Data))
+	})
+
+	_, err := LanguageTermValuesErr([]ExpectedLangString{{Value: "Moonrise", LangCode: "en"},
+		{Value: "Mondaufgang", LangCode: "de"}, {Value: "Lever de lune", LangCode: "fr"}})
+	assert.True(t, errors.Is(err, jsonapi.ErrNotFound), "expected ErrNotFound, got %v", err)
+	assert.Contains(t, fmt.Sprint(err), "language terms with the codes 'de', 'fr'")
+}
+
+func Test_CreateMedia(t *testing.T) {
+	d := newFakeDrupal(t, map[string]string{
+		"islandora_media_use name Original File": testUuid(1),
+		"islandora_access name Staff Only":       testUuid(2),
+	})
+
+	var fileId, mediaId string
+	t.Run("create", func(t *testing.T) {
+		media := CreateMedia(t, MediaSpec{Bundle: Image, Reader: strings.NewReader("moo"), Filename: "moo.tiff",
+			MediaOfUuid: testUuid(3), MediaUseName: OriginalFile, AccessTermNames: []string{"Staff Only"},
+			RestrictedAccess: true})
+		image, ok := media.(JsonApiImageMedia)
+		require.True(t, ok, "expected a JsonApiImageMedia, got %T", media)
+		require.Equal(t, 1, len(image.JsonApiData))
+		mediaId = image.JsonApiData[0].Id
+		fileId = media.File().Id
+
+		assert.Equal(t, "moo.tiff", media.Name())
+		assert.True(t, media.RestrictedAccess())
+		assert.Equal(t, testUuid(3), media.MediaOf().Id)
+		assert.Equal(t, []string{fileId}, d.relationshipIds(mediaId, "field_media_image"))
+		assert.Equal(t, []string{testUuid(1)}, d.relationshipIds(mediaId, "field_media_use"))
+		assert.Equal(t, []string{testUuid(2)}, d.relationshipIds(mediaId, "field_access_terms"))
+		assert.Equal(t, "file--file", d.created[fileId]["type"])
+	})
+
+	assert.Equal(t, []string{"/jsonapi/media/image/" + mediaId, "/jsonapi/file/file/" + fileId}, d.deleted,
+		"the media, and then its file, are expected to be deleted when the test completes")
+}
+
+func Test_CreateMediaFromPath(t *testing.T) {
+	d := newFakeDrupal(t, map[string]string{})
+	path := filepath.Join(t.TempDir(), "moo.pdf")
+	require.Nil(t, ioutil.WriteFile(path, []byte("moo"), 0600))
+
+	media := CreateMedia(t, MediaSpec{Bundle: Document, Path: path, Name: "Moo", MediaOfUuid: testUuid(3)})
+	_, ok := media.(JsonApiDocumentMedia)
+	require.True(t, ok, "expected a JsonApiDocumentMedia, got %T", media)
+	assert.Equal(t, "Moo", media.Name())
+	attrs := d.created[media.File().Id]["attributes"].(map[string]interface{})
+	assert.Equal(t, "moo.pdf", attrs["filename"])
+	assert.EqualValues(t, 3, attrs["filesize"])
+}
@@ -0,0 +1,89 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// The tolerance, in decimal degrees, used when comparing coordinates; roughly 11 centimeters at the equator
+const CoordinateTolerance = 0.000001
+
+// Represents a geofield value, e.g. the value of `field_coordinates` on a Geolocation taxonomy term
+type Geofield struct {
+	// The well-known text (WKT) of the geometry, e.g. `POINT (-76.6205 39.3289)`
+	Value   string
+	GeoType string `json:"geo_type"`
+	Lat     *float64
+	Lon     *float64
+	// A comma-separated latitude and longitude, e.g. `39.3289,-76.6205`
+	LatLon string `json:"latlon"`
+}
+
+// A latitude and longitude, in decimal degrees
+type Coordinates struct {
+	Lat float64
+	Lon float64
+}
+
+// matches a WKT point, capturing the longitude and latitude, e.g. `POINT (-76.6205 39.3289)`
+var wktPointPattern = regexp.MustCompile(`(?i)^\s*POINT\s*\(\s*(\S+)\s+(\S+)\s*\)\s*$`)
+
+// Coordinates answers the latitude and longitude of the geofield.  The `lat` and `lon` properties are preferred; if
+// they are absent, the well-known text of the geometry is parsed, which must be a point.  An error wrapping
+// ErrConversion is answered if the geofield carries no point.
+func (g Geofield) Coordinates() (Coordinates, error) {
+	if g.Lat != nil && g.Lon != nil {
+		return Coordinates{Lat: *g.Lat, Lon: *g.Lon}, nil
+	}
+
+	m := wktPointPattern.FindStringSubmatch(g.Value)
+	if m == nil {
+		return Coordinates{}, fmt.Errorf("%w: geofield value '%s' to a point", ErrConversion, g.Value)
+	}
+	lon, lonErr := strconv.ParseFloat(m[1], 64)
+	lat, latErr := strconv.ParseFloat(m[2], 64)
+	if lonErr != nil || latErr != nil {
+		return Coordinates{}, fmt.Errorf("%w: geofield value '%s' to a point", ErrConversion, g.Value)
+	}
+
+	return Coordinates{Lat: lat, Lon: lon}, nil
+}
+
+// Near answers true if the supplied coordinates are within `tolerance` decimal degrees of these coordinates, in both
+// latitude and longitude
+func (c Coordinates) Near(other Coordinates, tolerance float64) bool {
+	return math.Abs(c.Lat-other.Lat) <= tolerance && math.Abs(c.Lon-other.Lon) <= tolerance
+}
+
+// AssertGeolocation asserts that the Geolocation term carries the name, alternate names, broader links, and coordinates
+// of the expected term.  Coordinates are compared within CoordinateTolerance; if the expected term has no coordinates,
+// none are asserted.
+func AssertGeolocation(t *testing.T, expected ExpectedGeolocation, actual JsonApiGeolocation) bool {
+	if !assert.Equal(t, 1, len(actual.JsonApiData), "expected exactly one geolocation data element") {
+		return false
+	}
+	attrs := actual.JsonApiData[0].JsonApiAttributes
+
+	ok := assert.Equal(t, expected.Name, attrs.Name)
+	ok = assert.ElementsMatch(t, expected.GeoAltName, attrs.GeoAltName) && ok
+	ok = assert.ElementsMatch(t, expected.Broader, attrs.Broader) && ok
+
+	if expected.Coordinates == nil {
+		return ok
+	}
+	if !assert.NotNil(t, attrs.Coordinates, "geolocation '%s' has no coordinates", attrs.Name) {
+		return false
+	}
+	coords, err := attrs.Coordinates.Coordinates()
+	if !assert.Nil(t, err, "%s", err) {
+		return false
+	}
+
+	return assert.True(t, expected.Coordinates.Near(coords, CoordinateTolerance),
+		"coordinates of geolocation '%s' differ: expected %v, actual %v", attrs.Name, *expected.Coordinates, coords) && ok
+}
@@ -0,0 +1,38 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_GeofieldCoordinates(t *testing.T) {
+	g := Geofield{}
+	require.Nil(t, json.Unmarshal([]byte(`{"value": "POINT (-76.6205 39.3289)", "geo_type": "Point",
+		"lat": 39.3289, "lon": -76.6205, "latlon": "39.3289,-76.6205"}`), &g))
+	c, err := g.Coordinates()
+	assert.Nil(t, err)
+	assert.Equal(t, Coordinates{Lat: 39.3289, Lon: -76.6205}, c)
+
+	c, err = Geofield{Value: "POINT(-76.6205 39.3289)"}.Coordinates()
+	assert.Nil(t, err)
+	assert.True(t, c.Near(Coordinates{Lat: 39.32890001, Lon: -76.62050001}, CoordinateTolerance))
+	assert.False(t, c.Near(Coordinates{Lat: 39.33, Lon: -76.6205}, CoordinateTolerance))
+
+	_, err = Geofield{Value: "LINESTRING (30 10, 10 30)"}.Coordinates()
+	assert.True(t, errors.Is(err, ErrConversion))
+}
+
+func Test_AssertGeolocation(t *testing.T) {
+	actual := JsonApiGeolocation{}
+	require.Nil(t, json.Unmarshal([]byte(`{"data": [{"type": "taxonomy_term--geo_location", "id": "00000001-0000-4000-8000-000000000000",
+		"attributes": {"name": "Baltimore", "field_geo_alt_name": ["Charm City"],
+		"field_coordinates": {"value": "POINT (-76.6121893 39.2903848)", "geo_type": "Point"}}}]}`), &actual))
+
+	expected := ExpectedGeolocation{GeoAltName: []string{"Charm City"}, Coordinates: &Coordinates{Lat: 39.2903848, Lon: -76.6121893}}
+	expected.Name = "Baltimore"
+	assert.True(t, AssertGeolocation(t, expected, actual))
+}
@@ -0,0 +1,282 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/stretchr/testify/assert"
+)
+
+// GoldenFile names the expected file (e.g. `testdata/expected/moonrise.json`) that the compared entity was loaded from.
+// If the environment variable UPDATE_EXPECTED is true, CompareRepoObj and the media comparers (e.g. CompareImageMedia)
+// do not make assertions; instead they resolve the actual entity into its expected struct, and write it to the file,
+// creating or overwriting it.  Otherwise this option has no effect, so goldens are only ever written on request:
+//
+//	UPDATE_EXPECTED=true go test ./...
+func GoldenFile(path string) CompareOption {
+	return func(o *compareOptions) {
+		o.golden = path
+	}
+}
+
+// updating answers whether the compared entity should be written to its golden file instead of being compared
+func (o compareOptions) updating() bool {
+	return o.golden != "" && env.UpdateExpectedOr(false)
+}
+
+// WriteExpectedErr writes the expected struct as indented JSON to the file at the supplied path, creating its directory
+// if necessary.  The file may be read by LoadExpected.  Writing is refused unless the environment variable
+// UPDATE_EXPECTED is true, so that expected files are not accidentally overwritten with actual values.
+func WriteExpectedErr(path string, v interface{}) error {
+	if !env.UpdateExpectedOr(false) {
+		return fmt.Errorf("model: refusing to write expected file %s: UPDATE_EXPECTED is not set", path)
+	}
+
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("model: unable to encode expected file %s: %w", path, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("model: unable to write expected file %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, append(b, '\n'), 0644); err != nil {
+		return fmt.Errorf("model: unable to write expected file %s: %w", path, err)
+	}
+	return nil
+}
+
+// updateExpected writes the expected struct answered by the supplied function to the golden file, asserting that no
+// error occurs
+func updateExpected(t *testing.T, path string, expected func() (interface{}, error)) bool {
+	v, err := expected()
+	if err == nil {
+		err = WriteExpectedErr(path, v)
+	}
+	if !assert.Nil(t, err, "%s", err) {
+		return false
+	}
+	t.Logf("Updated expected file %s", path)
+	return true
+}
+
+// ExpectedRepoObjOfErr answers the expected struct describing the first repository object in the response, suitable
+// for writing to a golden file.  Relationships are resolved to stable values, i.e. the names of terms, the titles of
+// nodes, and the language codes of language values, rather than identifiers that change when content is re-migrated.
+func ExpectedRepoObjOfErr(actual JsonApiIslandoraObj) (ExpectedRepoObj, error) {
+	e := ExpectedRepoObj{}
+	if len(actual.JsonApiData) == 0 {
+		return e, fmt.Errorf("model: the repository object response is empty")
+	}
+	attrs := actual.JsonApiData[0].JsonApiAttributes
+	rels := actual.JsonApiData[0].JsonApiRelationships
+	r := goldenResolver{}
+
+	e.Type, e.Bundle = actual.JsonApiData[0].Type.Entity(), actual.JsonApiData[0].Type.Bundle()
+	e.Title = attrs.Title
+	e.Abstract = r.languageStrings(rels.Abstract.Data)
+	e.AccessRights = r.labels(rels.AccessRights.Data)
+	e.AltTitle = r.languageStrings(rels.AltTitle.Data)
+	e.CollectionNumber = attrs.CollectionNumber
+	e.CopyrightAndUse = r.label(rels.CopyrightAndUse.Data)
+	e.CopyrightHolder = r.labels(rels.CopyrightHolder.Data)
+	for _, a := range r.agents(rels.Contributor.Data) {
+		e.Contributor = append(e.Contributor, struct {
+			RelType string `json:"rel_type"`
+			Name    string
+		}{a.Role, a.Name})
+	}
+	for _, a := range r.agents(rels.Creator.Data) {
+		e.Creator = append(e.Creator, struct {
+			RelType string `json:"rel_type"`
+			Name    string
+		}{a.Role, a.Name})
+	}
+	e.CustodialHistory = r.languageStrings(rels.CustodialHistory.Data)
+	e.DateAvailable = attrs.DateAvailable
+	e.DateCopyrighted = attrs.DateCopyrighted
+	e.DateCreated = attrs.DateCreated
+	e.DatePublished = attrs.DatePublished
+	e.DigitalIdentifier = attrs.DigitalIdentifier
+	e.DigitalPublisher = r.labels(rels.DigitalPublisher.Data)
+	e.DisplayHint = r.label(rels.DisplayHint.Data)
+	e.DspaceIdentifier = attrs.DspaceIdentifier.Uri
+	e.DspaceItemId = attrs.DspaceItemid
+	e.Extent = attrs.Extent
+	e.FeaturedItem = attrs.FeaturedItem
+	e.FindingAid = attrs.FindingAid
+	e.Genre = r.labels(rels.Genre.Data)
+	e.GeoportalLink = attrs.GeoportalLink.Uri
+	e.AccessTerms = r.labels(rels.AccessTerms.Data)
+	e.Issn = attrs.Issn
+	e.IsPartOf = attrs.IsPartOf.Uri
+	e.ItemBarcode = attrs.ItemBarcode
+	e.JhirUri = attrs.JhirUri.Uri
+	e.LibraryCatalogLink = linkUris(attrs.LibraryCatalogLink)
+	e.Model.Name, e.Model.ExternalUri = r.model(rels.Model.Data)
+	e.OclcNumber = attrs.OclcNumber
+	e.Publisher = r.labels(rels.Publisher.Data)
+	e.PublisherCountry = r.labels(rels.PublisherCountry.Data)
+	e.ResourceType = r.labels(rels.ResourceType.Data)
+	e.SpatialCoverage = r.labels(rels.SpatialCoverage.Data)
+	e.Subject = r.labels(rels.Subject.Data)
+	e.TableOfContents = r.languageStrings(rels.TableOfContents.Data)
+	e.MemberOf = r.label(rels.MemberOf.Data)
+	e.Description = r.languageStrings(rels.Description.Data)
+
+	return e, r.err
+}
+
+// expectedMediaGenericErr answers the expected struct describing the fields common to every media bundle, suitable for
+// writing to a golden file (see ExpectedRepoObjOfErr)
+func expectedMediaGenericErr(m Media, mediaType jsonapi.DrupalType) (ExpectedMediaGeneric, error) {
+	r := goldenResolver{}
+	e := ExpectedMediaGeneric{
+		OriginalName:     m.OriginalName(),
+		Size:             m.FileSize(),
+		MimeType:         m.MimeType(),
+		AccessTerms:      r.labels(m.AccessTerms()),
+		MediaUse:         r.labels(m.MediaUse()),
+		MediaOf:          r.label(m.MediaOf()),
+		RestrictedAccess: m.RestrictedAccess(),
+	}
+	e.Type, e.Bundle, e.Name = mediaType.Entity(), mediaType.Bundle(), m.Name()
+	return e, r.err
+}
+
+// optionalMeta answers the named meta value of the relationship, or the empty string if it is not present
+func optionalMeta(rd RelData, name string) (string, error) {
+	value, err := rd.MetaString(name)
+	if errors.Is(err, ErrMissing) {
+		return "", nil
+	}
+	return value, err
+}
+
+// Resolves relationships into the values of expected structs, retaining the first error encountered so that each
+// field need not be checked individually
+type goldenResolver struct {
+	err error
+}
+
+func (r *goldenResolver) label(jad JsonApiData) string {
+	if r.err != nil {
+		return ""
+	}
+	var label string
+	label, r.err = labelErr(jad)
+	return label
+}
+
+func (r *goldenResolver) labels(data []JsonApiData) []string {
+	if r.err != nil {
+		return nil
+	}
+	var labels []string
+	labels, r.err = labelsErr(data)
+	return labels
+}
+
+func (r *goldenResolver) languageStrings(values []JsonApiLanguageValue) []ExpectedLangString {
+	var result []ExpectedLangString
+	for _, lv := range values {
+		if r.err != nil {
+			return nil
+		}
+		var code string
+		code, r.err = lv.langCodeErr()
+		result = append(result, ExpectedLangString{Value: lv.Value(), LangCode: code})
+	}
+	return result
+}
+
+func (r *goldenResolver) agents(data []RelData) []Agent {
+	var result []Agent
+	for _, rd := range data {
+		if r.err != nil {
+			return nil
+		}
+		agent := Agent{Kind: rd.Type.Bundle()}
+		if agent.Role, r.err = rd.MetaString(relTypeKey); r.err != nil {
+			r.err = fmt.Errorf("model: unable to read the role of %s %s: %w", rd.Type, rd.Id, r.err)
+			return nil
+		}
+		agent.Name, r.err = resolveLabelErr(rd.JsonApiData)
+		result = append(result, agent)
+	}
+	return result
+}
+
+func (r *goldenResolver) model(jad JsonApiData) (string, string) {
+	if r.err != nil || jad.IsZero() {
+		return "", ""
+	}
+	model, err := resolveCachedErr[JsonApiIslandoraModel](jad)
+	if err != nil {
+		r.err = err
+		return "", ""
+	}
+	attrs := model.JsonApiData[0].JsonApiAttributes
+	return attrs.Name, attrs.ExternalUri.Uri
+}
+
+func expectedImageMediaErr(actual JsonApiImageMedia) (ExpectedMediaImage, error) {
+	g, err := expectedMediaGenericErr(actual, actual.JsonApiData[0].Type)
+	if err != nil {
+		return ExpectedMediaImage{}, err
+	}
+	attrs := actual.JsonApiData[0].JsonApiAttributes
+	alt, err := optionalMeta(actual.File(), "alt")
+	return ExpectedMediaImage{ExpectedMediaGeneric: g, AltText: alt, Height: attrs.Height, Width: attrs.Width}, err
+}
+
+func expectedDocumentMediaErr(actual JsonApiDocumentMedia) (ExpectedMediaDocument, error) {
+	g, err := expectedMediaGenericErr(actual, actual.JsonApiData[0].Type)
+	return ExpectedMediaDocument{ExpectedMediaGeneric: g}, err
+}
+
+func expectedAudioMediaErr(actual JsonApiAudioMedia) (ExpectedMediaAudio, error) {
+	g, err := expectedMediaGenericErr(actual, actual.JsonApiData[0].Type)
+	if err != nil {
+		return ExpectedMediaAudio{}, err
+	}
+	duration, err := actual.JsonApiData[0].JsonApiAttributes.DurationSeconds()
+	return ExpectedMediaAudio{ExpectedMediaGeneric: g, Duration: duration}, err
+}
+
+func expectedVideoMediaErr(actual JsonApiVideoMedia) (ExpectedMediaVideo, error) {
+	g, err := expectedMediaGenericErr(actual, actual.JsonApiData[0].Type)
+	if err != nil {
+		return ExpectedMediaVideo{}, err
+	}
+	attrs := actual.JsonApiData[0].JsonApiAttributes
+	duration, err := attrs.DurationSeconds()
+	return ExpectedMediaVideo{ExpectedMediaGeneric: g, Duration: duration, Height: attrs.Height, Width: attrs.Width}, err
+}
+
+func expectedExtractedTextMediaErr(actual JsonApiExtractedTextMedia) (ExpectedMediaExtractedText, error) {
+	g, err := expectedMediaGenericErr(actual, actual.JsonApiData[0].Type)
+	return ExpectedMediaExtractedText{ExpectedMediaGeneric: g, ExtractedText: actual.JsonApiData[0].JsonApiAttributes.EditedText}, err
+}
+
+func expectedFileMediaErr(actual JsonApiGenericFileMedia) (ExpectedMediaFile, error) {
+	g, err := expectedMediaGenericErr(actual, actual.JsonApiData[0].Type)
+	return ExpectedMediaFile{ExpectedMediaGeneric: g}, err
+}
+
+func expectedRemoteVideoMediaErr(actual JsonApiRemoteVideoMedia) (ExpectedMediaRemoteVideo, error) {
+	r := goldenResolver{}
+	data := actual.JsonApiData[0]
+	e := ExpectedMediaRemoteVideo{
+		EmbedUrl:         data.JsonApiAttributes.EmbedUrl,
+		MediaOf:          r.label(actual.MediaOf()),
+		RestrictedAccess: actual.RestrictedAccess(),
+	}
+	e.Type, e.Bundle, e.Name = data.Type.Entity(), data.Type.Bundle(), actual.Name()
+	return e, r.err
+}
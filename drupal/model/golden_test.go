@@ -0,0 +1,64 @@
+package model
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_GoldenFileRoundTrip(t *testing.T) {
+	obj := compareTestObj(t)
+	path := filepath.Join(t.TempDir(), "expected", "moonrise.json")
+
+	t.Setenv("UPDATE_EXPECTED", "true")
+	require.True(t, CompareRepoObj(t, ExpectedRepoObj{}, obj, GoldenFile(path)))
+
+	expected := LoadExpected[ExpectedRepoObj](t, path)
+	assert.Equal(t, "node", expected.Type)
+	assert.Equal(t, "islandora_object", expected.Bundle)
+	assert.Equal(t, "Moonrise", expected.Title)
+	assert.Equal(t, []string{"Landscapes"}, expected.Subject)
+	assert.Equal(t, []ExpectedLangString{{Value: "Moonrise over Hernandez", LangCode: "en"}}, expected.Abstract)
+	require.Equal(t, 1, len(expected.Creator))
+	assert.Equal(t, "relators:pht", expected.Creator[0].RelType)
+	assert.Equal(t, "Adams, Ansel", expected.Creator[0].Name)
+
+	t.Setenv("UPDATE_EXPECTED", "false")
+	assert.True(t, CompareRepoObj(t, expected, obj, GoldenFile(path)))
+}
+
+func Test_WriteExpectedErrRefusesWithoutFlag(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "moo.json")
+
+	t.Setenv("UPDATE_EXPECTED", "")
+	os.Unsetenv("UPDATE_EXPECTED")
+	err := WriteExpectedErr(path, ExpectedSubject{})
+	assert.Contains(t, fmt.Sprint(err), "UPDATE_EXPECTED")
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+
+	t.Setenv("UPDATE_EXPECTED", "false")
+	assert.NotNil(t, WriteExpectedErr(path, ExpectedSubject{}))
+}
+
+func Test_GoldenFileMedia(t *testing.T) {
+	video := JsonApiVideoMedia{}
+	unmarshalTestdata(t, "media-video.json", &video)
+	video.JsonApiData[0].JsonApiRelationships.MediaOf.Data = JsonApiData{}
+	video.JsonApiData[0].JsonApiRelationships.MediaUse.Data = nil
+	video.JsonApiData[0].JsonApiRelationships.AccessTerms.Data = nil
+	path := filepath.Join(t.TempDir(), "video.json")
+
+	t.Setenv("UPDATE_EXPECTED", "true")
+	require.True(t, CompareVideoMedia(t, ExpectedMediaVideo{}, video, GoldenFile(path)))
+
+	expected := LoadExpected[ExpectedMediaVideo](t, path)
+	assert.Equal(t, "media", expected.Type)
+	assert.Equal(t, "video", expected.Bundle)
+	assert.Equal(t, "video/mp4", expected.MimeType)
+	assert.NotZero(t, expected.Duration)
+}
@@ -0,0 +1,244 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/stretchr/testify/require"
+)
+
+// ExportedGraph is a self-contained snapshot of a repository object or collection: its attributes, its relationships
+// resolved to a depth, and its media and their files.  Each entity of the graph is exported once; later references to
+// it, or references beyond the depth, carry only its identity and label.  Written by WriteTo, the graph is a
+// deterministic JSON document, so that snapshots may be stored and compared (see DiffGraphs).
+type ExportedGraph struct {
+	// The depth to which relationships were resolved
+	Depth int `json:"depth"`
+	// The exported repository object or collection
+	Root GraphEntity `json:"root"`
+}
+
+// GraphEntity is an entity of an ExportedGraph
+type GraphEntity struct {
+	Type jsonapi.DrupalType `json:"type"`
+	Id   string             `json:"id"`
+	// The name of a taxonomy term, or the title of a node
+	Label      string                 `json:"label,omitempty"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+	// The references of each relationship, keyed by the name of the relationship field, e.g. `field_subject`
+	Relationships map[string][]GraphRef `json:"relationships,omitempty"`
+	// The media of a node, i.e. the media whose `field_media_of` references it, ordered by bundle and name
+	Media []GraphEntity `json:"media,omitempty"`
+}
+
+// GraphRef is a reference of a relationship of a GraphEntity
+type GraphRef struct {
+	Type jsonapi.DrupalType `json:"type"`
+	Id   string             `json:"id"`
+	// The label of the referenced entity, if it was resolved, or is a taxonomy term
+	Label string `json:"label,omitempty"`
+	// The meta of the reference, e.g. the relator of a contributor
+	Meta map[string]interface{} `json:"meta,omitempty"`
+	// The referenced entity, if it is first exported by this reference
+	Entity *GraphEntity `json:"entity,omitempty"`
+}
+
+// The JSON API representation of an entity of any type, as exported
+type rawEntity struct {
+	JsonApiData []struct {
+		Type          jsonapi.DrupalType
+		Id            string
+		Attributes    map[string]interface{} `json:"attributes"`
+		Relationships map[string]struct {
+			Data json.RawMessage
+		} `json:"relationships"`
+	} `json:"data"`
+}
+
+// ExportGraph answers the graph of the repository object or collection with the uuid, resolving its relationships, and
+// theirs, to the depth, failing the test immediately if any entity cannot be retrieved.  A depth of zero exports the
+// entity and its media, labelling the taxonomy terms they reference:
+//
+//	graph := model.ExportGraph(t, objUuid, 2)
+//	_, err := graph.WriteTo(f)
+func ExportGraph(t *testing.T, objUuid string, depth int, opts ...Option) ExportedGraph {
+	g, err := ExportGraphErr(objUuid, depth, opts...)
+	require.Nil(t, err, "%s", err)
+	return g
+}
+
+// ExportGraphErr behaves as ExportGraph, but answers an error instead of failing the test
+func ExportGraphErr(objUuid string, depth int, opts ...Option) (ExportedGraph, error) {
+	e := graphExporter{opts: opts, visited: map[string]bool{}}
+	for _, bundle := range []string{RepositoryObject, Collection} {
+		root, err := e.entityErr(JsonApiData{Type: jsonapi.NewDrupalType(Node, bundle), Id: objUuid}, depth)
+		if err == nil {
+			return ExportedGraph{Depth: depth, Root: root}, nil
+		}
+		if !errors.Is(err, jsonapi.ErrNotFound) {
+			return ExportedGraph{}, fmt.Errorf("model: unable to export the graph of %s: %w", objUuid, err)
+		}
+	}
+	return ExportedGraph{}, fmt.Errorf("%w: no repository object or collection %s", jsonapi.ErrNotFound, objUuid)
+}
+
+// WriteTo writes the graph to the writer as indented JSON, whose members are ordered by name, so that the same graph is
+// always written identically
+func (g ExportedGraph) WriteTo(w io.Writer) (int64, error) {
+	b, err := json.MarshalIndent(g, "", "  ")
+	if err != nil {
+		return 0, fmt.Errorf("model: unable to encode the graph of %s: %w", g.Root.Id, err)
+	}
+	n, err := w.Write(append(b, '\n'))
+	return int64(n), err
+}
+
+// graphExporter exports the entities of a graph, each only once
+type graphExporter struct {
+	opts []Option
+	// the `type id` of each entity exported
+	visited map[string]bool
+}
+
+// entityErr exports the entity, resolving its relationships to the depth, and its media if it is a node
+func (e *graphExporter) entityErr(jad JsonApiData, depth int) (GraphEntity, error) {
+	e.visited[string(jad.Type)+" "+jad.Id] = true
+	raw, err := e.resolveErr(jad)
+	if err != nil {
+		return GraphEntity{}, err
+	}
+	entity, err := e.exportErr(raw, depth)
+	if err != nil {
+		return GraphEntity{}, err
+	}
+
+	if jad.Type.Entity() == Node {
+		for _, b := range mediaBundles {
+			u := query(nil, MediaEntity, b.bundle, e.opts...)
+			u.Filter, u.Value = "field_media_of.id", jad.Id
+			media := rawEntity{}
+			if err := u.GetAllErr(&media); err != nil {
+				return GraphEntity{}, fmt.Errorf("model: unable to retrieve %s media of %s: %w", b.bundle, jad.Id, err)
+			}
+			for i := range media.JsonApiData {
+				single := rawEntity{JsonApiData: media.JsonApiData[i : i+1]}
+				e.visited[string(single.JsonApiData[0].Type)+" "+single.JsonApiData[0].Id] = true
+				m, err := e.exportErr(single, depth)
+				if err != nil {
+					return GraphEntity{}, err
+				}
+				entity.Media = append(entity.Media, m)
+			}
+		}
+		sort.SliceStable(entity.Media, func(i, j int) bool {
+			a, b := entity.Media[i], entity.Media[j]
+			if a.Type != b.Type {
+				return a.Type < b.Type
+			}
+			return a.Label < b.Label
+		})
+	}
+	return entity, nil
+}
+
+// exportErr exports the resolved entity, resolving the references of its relationships not yet exported if the depth
+// is positive
+func (e *graphExporter) exportErr(raw rawEntity, depth int) (GraphEntity, error) {
+	d := raw.JsonApiData[0]
+	entity := GraphEntity{Type: d.Type, Id: d.Id, Label: rawLabel(d.Attributes), Attributes: d.Attributes}
+
+	var names []string
+	for name := range d.Relationships {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		refs, err := relationshipRefsErr(d.Relationships[name].Data)
+		if err != nil {
+			return GraphEntity{}, fmt.Errorf("model: unable to export relationship %s of %s %s: %w", name, d.Type, d.Id,
+				err)
+		}
+		if refs == nil {
+			continue
+		}
+		for i, ref := range refs {
+			if refs[i], err = e.refErr(ref, depth); err != nil {
+				return GraphEntity{}, err
+			}
+		}
+		if entity.Relationships == nil {
+			entity.Relationships = map[string][]GraphRef{}
+		}
+		entity.Relationships[name] = refs
+	}
+	return entity, nil
+}
+
+// refErr answers the reference, carrying the entity it references if the depth is positive and the entity is not yet
+// exported, or otherwise the label of a taxonomy term
+func (e *graphExporter) refErr(ref GraphRef, depth int) (GraphRef, error) {
+	jad := JsonApiData{Type: ref.Type, Id: ref.Id}
+	if jad.Validate() != nil {
+		// e.g. a reference to a missing entity, or to a config entity such as a node type
+		return ref, nil
+	}
+	if depth > 0 && !e.visited[string(ref.Type)+" "+ref.Id] {
+		entity, err := e.entityErr(jad, depth-1)
+		if err != nil {
+			return ref, fmt.Errorf("model: unable to export %s %s: %w", ref.Type, ref.Id, err)
+		}
+		ref.Label, ref.Entity = entity.Label, &entity
+		return ref, nil
+	}
+	if ref.Type.Entity() == TaxonomyTerm {
+		raw, err := e.resolveErr(jad)
+		if err != nil {
+			return ref, fmt.Errorf("model: unable to label %s %s: %w", ref.Type, ref.Id, err)
+		}
+		ref.Label = rawLabel(raw.JsonApiData[0].Attributes)
+	}
+	return ref, nil
+}
+
+// resolveErr resolves the data object; taxonomy terms are cached unless options are supplied (see resolveCachedErr)
+func (e *graphExporter) resolveErr(jad JsonApiData) (rawEntity, error) {
+	if len(e.opts) == 0 {
+		return resolveCachedErr[rawEntity](jad)
+	}
+	return ResolveAsErr[rawEntity](jad, e.opts...)
+}
+
+// relationshipRefsErr answers the references of the data of a relationship: none if it is null or absent, or one per
+// element if it is an array
+func relationshipRefsErr(data json.RawMessage) ([]GraphRef, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, nil
+	}
+	var refs []GraphRef
+	if data[0] == '[' {
+		if err := json.Unmarshal(data, &refs); err != nil {
+			return nil, err
+		}
+		return refs, nil
+	}
+	ref := GraphRef{}
+	if err := json.Unmarshal(data, &ref); err != nil {
+		return nil, err
+	}
+	return []GraphRef{ref}, nil
+}
+
+// rawLabel answers the name, or title, attribute of an entity
+func rawLabel(attributes map[string]interface{}) string {
+	if name, ok := attributes["name"].(string); ok && name != "" {
+		return name
+	}
+	title, _ := attributes["title"].(string)
+	return title
+}
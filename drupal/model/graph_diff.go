@@ -0,0 +1,218 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/stretchr/testify/assert"
+)
+
+// The kinds of Diff
+const (
+	// The value is present in the second graph, but not the first
+	Added = "added"
+	// The value is present in the first graph, but not the second
+	Removed = "removed"
+	// The value differs between the graphs
+	Changed = "changed"
+)
+
+// The depth to which CompareAcrossEnvironments exports the graph of each object
+const compareGraphDepth = 1
+
+// The members ignored by DiffGraphs unless an ignore list is supplied: identifiers, internal ids, and timestamps, which
+// differ between environments, and between migrations of the same content
+var DefaultGraphIgnores = []string{"id", "drupal_internal__*", "changed", "created", "revision_timestamp",
+	"revision_translation_affected", "content_translation_changed", "content_translation_created", "metadata"}
+
+// matches a UUID within a string, e.g. within the url of an entity
+var embeddedUuidPattern = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[1-5][0-9a-fA-F]{3}-[89abAB][0-9a-fA-F]{3}-[0-9a-fA-F]{12}`)
+
+// Diff is a difference between two exported graphs
+type Diff struct {
+	// The JSON pointer (RFC 6901) of the value in the written graphs, e.g. `/root/attributes/title`
+	Path string
+	// Added, Removed, or Changed
+	Kind string
+	// The value in the first graph, or nil if Added
+	A interface{}
+	// The value in the second graph, or nil if Removed
+	B interface{}
+}
+
+// String answers a readable description of the difference, e.g. `changed /root/attributes/title: "Moonrise" -> "Moon"`
+func (d Diff) String() string {
+	switch d.Kind {
+	case Added:
+		return fmt.Sprintf("%s %s: %s", d.Kind, d.Path, diffValue(d.B))
+	case Removed:
+		return fmt.Sprintf("%s %s: %s", d.Kind, d.Path, diffValue(d.A))
+	default:
+		return fmt.Sprintf("%s %s: %s -> %s", d.Kind, d.Path, diffValue(d.A), diffValue(d.B))
+	}
+}
+
+// DiffGraphs compares the graphs as written (see ExportedGraph.WriteTo), and answers their differences, ordered by
+// path: the members present in only one graph, and the values that differ.  Elements of arrays are compared by
+// position.  Environment-specific noise is disregarded: UUIDs within strings are compared as `{uuid}`, and absolute urls
+// are compared by their path, ignoring their scheme and host.  Members whose name matches an entry of the ignore list,
+// (a pattern as used by path.Match, e.g. `drupal_internal__*`), or whose JSON pointer is an entry starting with `/`
+// (e.g. `/root/attributes/field_weight`), are not compared.  If no ignore list is supplied, DefaultGraphIgnores is
+// used; to extend it, supply `append(model.DefaultGraphIgnores, ...)`.
+func DiffGraphs(a, b ExportedGraph, ignore ...string) []Diff {
+	if len(ignore) == 0 {
+		ignore = DefaultGraphIgnores
+	}
+	var diffs []Diff
+	diffValues("", genericGraph(a), genericGraph(b), ignore, &diffs)
+	sort.SliceStable(diffs, func(i, j int) bool { return diffs[i].Path < diffs[j].Path })
+	return diffs
+}
+
+// CompareAcrossEnvironments finds the repository object with the digital identifier in each environment, exports its
+// graph from each, and asserts that the graphs do not differ (see DiffGraphs), e.g. before and after a site upgrade.
+// Every difference is reported, and answered:
+//
+//	before := env.Config{BaseUrl: "https://stage.example.edu", Credentials: ...}
+//	after := env.Config{BaseUrl: "https://upgrade.example.edu", Credentials: ...}
+//	model.CompareAcrossEnvironments(t, "ms-0001", before, after)
+func CompareAcrossEnvironments(t *testing.T, digitalIdentifier string, envA, envB env.Config, ignore ...string) []Diff {
+	var graphs [2]ExportedGraph
+	for i, c := range []env.Config{envA, envB} {
+		c := c
+		obj := FindObjectByDigitalIdentifier(t, digitalIdentifier, WithConfig(&c))
+		if len(obj.JsonApiData) == 0 {
+			return nil
+		}
+		g, err := ExportGraphErr(obj.JsonApiData[0].Id, compareGraphDepth, WithConfig(&c))
+		if !assert.Nil(t, err, "unable to export %s from %s: %s", digitalIdentifier, c.BaseUrl, err) {
+			return nil
+		}
+		graphs[i] = g
+	}
+
+	diffs := DiffGraphs(graphs[0], graphs[1], ignore...)
+	if len(diffs) > 0 {
+		lines := make([]string, len(diffs))
+		for i, d := range diffs {
+			lines[i] = d.String()
+		}
+		assert.Fail(t, fmt.Sprintf("%s differs between %s and %s in %d place(s):\n\t%s", digitalIdentifier,
+			envA.BaseUrl, envB.BaseUrl, len(diffs), strings.Join(lines, "\n\t")))
+	}
+	return diffs
+}
+
+// genericGraph answers the graph as written, decoded into maps, slices, and scalars
+func genericGraph(g ExportedGraph) interface{} {
+	b, _ := json.Marshal(g)
+	var v interface{}
+	_ = json.Unmarshal(b, &v)
+	return v
+}
+
+// diffValues appends the differences between the values at the JSON pointer to the diffs
+func diffValues(pointer string, a, b interface{}, ignore []string, diffs *[]Diff) {
+	switch av := a.(type) {
+	case map[string]interface{}:
+		if bv, ok := b.(map[string]interface{}); ok {
+			names := map[string]bool{}
+			for name := range av {
+				names[name] = true
+			}
+			for name := range bv {
+				names[name] = true
+			}
+			for name := range names {
+				member := pointer + "/" + escapePointer(name)
+				if ignored(name, member, ignore) {
+					continue
+				}
+				x, inA := av[name]
+				y, inB := bv[name]
+				switch {
+				case !inA:
+					*diffs = append(*diffs, Diff{Path: member, Kind: Added, B: y})
+				case !inB:
+					*diffs = append(*diffs, Diff{Path: member, Kind: Removed, A: x})
+				default:
+					diffValues(member, x, y, ignore, diffs)
+				}
+			}
+			return
+		}
+	case []interface{}:
+		if bv, ok := b.([]interface{}); ok {
+			for i := 0; i < len(av) || i < len(bv); i++ {
+				element := pointer + "/" + strconv.Itoa(i)
+				switch {
+				case i >= len(av):
+					*diffs = append(*diffs, Diff{Path: element, Kind: Added, B: bv[i]})
+				case i >= len(bv):
+					*diffs = append(*diffs, Diff{Path: element, Kind: Removed, A: av[i]})
+				default:
+					diffValues(element, av[i], bv[i], ignore, diffs)
+				}
+			}
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(normalizeGraphValue(a), normalizeGraphValue(b)) {
+		*diffs = append(*diffs, Diff{Path: pointer, Kind: Changed, A: a, B: b})
+	}
+}
+
+// ignored answers whether the member is ignored: its name matches a pattern of the ignore list, or its pointer is an
+// entry of the list
+func ignored(name, pointer string, ignore []string) bool {
+	for _, pattern := range ignore {
+		if strings.HasPrefix(pattern, "/") {
+			if pattern == pointer {
+				return true
+			}
+			continue
+		}
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeGraphValue answers a string value without the scheme and host of an absolute url, and with each UUID
+// replaced by `{uuid}`; other values are answered as-is
+func normalizeGraphValue(v interface{}) interface{} {
+	s, ok := v.(string)
+	if !ok {
+		return v
+	}
+	if u, err := url.Parse(s); err == nil && u.IsAbs() && u.Host != "" {
+		u.Scheme, u.Host, u.User = "", "", nil
+		s = u.String()
+	}
+	return embeddedUuidPattern.ReplaceAllString(s, "{uuid}")
+}
+
+// escapePointer escapes a member name as a reference token of a JSON pointer
+func escapePointer(name string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(name, "~", "~0"), "/", "~1")
+}
+
+// diffValue answers the value as compact JSON
+func diffValue(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
@@ -0,0 +1,49 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DiffGraphs(t *testing.T) {
+	a := ExportedGraph{Depth: 1, Root: GraphEntity{Type: "node--islandora_object", Id: testUuid(1), Label: "Moonrise",
+		Attributes: map[string]interface{}{"title": "Moonrise", "changed": "2021-01-01T00:00:00+00:00",
+			"drupal_internal__nid": 12, "field_extent": []interface{}{"1 photograph"},
+			"field_link": "https://stage.example.edu/node/" + testUuid(1) + "/manifest", "a/b": "moo"},
+		Relationships: map[string][]GraphRef{"field_subject": {{Type: "taxonomy_term--subject", Id: testUuid(2),
+			Label: "Photography"}}}}}
+	b := ExportedGraph{Depth: 1, Root: GraphEntity{Type: "node--islandora_object", Id: testUuid(9), Label: "Moonrise",
+		Attributes: map[string]interface{}{"title": "Moonrise", "changed": "2022-02-02T00:00:00+00:00",
+			"drupal_internal__nid": 40, "field_extent": []interface{}{"1 photograph", "8 x 10 in."},
+			"field_link": "https://upgrade.example.edu/node/" + testUuid(8) + "/manifest", "a/b": "oink"},
+		Relationships: map[string][]GraphRef{"field_subject": {{Type: "taxonomy_term--subject", Id: testUuid(3),
+			Label: "Photographs"}}}}}
+
+	assert.Empty(t, DiffGraphs(a, a))
+	diffs := DiffGraphs(a, b)
+	require.Len(t, diffs, 3, "%v", diffs)
+	assert.Equal(t, Diff{Path: "/root/attributes/a~1b", Kind: Changed, A: "moo", B: "oink"}, diffs[0])
+	assert.Equal(t, Diff{Path: "/root/attributes/field_extent/1", Kind: Added, B: "8 x 10 in."}, diffs[1])
+	assert.Equal(t, "/root/relationships/field_subject/0/label", diffs[2].Path)
+	assert.Equal(t, `changed /root/relationships/field_subject/0/label: "Photography" -> "Photographs"`,
+		diffs[2].String())
+
+	diffs = DiffGraphs(b, a, append(DefaultGraphIgnores, "field_extent", "/root/attributes/a~1b", "label")...)
+	assert.Empty(t, diffs)
+
+	diffs = DiffGraphs(a, b, "title")
+	assert.Contains(t, diffs, Diff{Path: "/root/attributes/drupal_internal__nid", Kind: Changed, A: float64(12),
+		B: float64(40)})
+	assert.NotContains(t, diffs, Diff{Path: "/root/id", Kind: Changed, A: testUuid(1), B: testUuid(9)},
+		"UUIDs are expected to be disregarded")
+}
+
+func Test_CompareAcrossEnvironments(t *testing.T) {
+	stage, upgrade := newGraphServer(t), newGraphServer(t)
+
+	diffs := CompareAcrossEnvironments(t, "ms-0001", env.Config{BaseUrl: stage.URL}, env.Config{BaseUrl: upgrade.URL})
+	assert.Empty(t, diffs)
+}
@@ -0,0 +1,104 @@
+package model
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newGraphServer answers a test server serving a repository object (testUuid(1), with the digital identifier `ms-0001`)
+// with a subject, a model, and a parent collection (testUuid(4)), and an image media (testUuid(5)) of the object whose
+// file is testUuid(6).  The url and change time of the file carry the host of the server.
+func newGraphServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/jsonapi/node/islandora_object/"+testUuid(1):
+			_, _ = fmt.Fprintf(w, `{"data": {"type": "node--islandora_object", "id": "%s",
+				"attributes": {"title": "Moonrise", "field_extent": ["1 photograph"], "drupal_internal__nid": 12},
+				"relationships": {
+					"field_subject": {"data": [{"type": "taxonomy_term--subject", "id": "%s"}]},
+					"field_model": {"data": {"type": "taxonomy_term--islandora_models", "id": "%s"}},
+					"field_member_of": {"data": {"type": "node--collection_object", "id": "%s"}},
+					"field_copyright_and_use": {"data": null},
+					"node_type": {"data": {"type": "node_type--node_type", "id": "islandora_object"}}}}}`,
+				testUuid(1), testUuid(2), testUuid(3), testUuid(4))
+		case r.URL.Path == "/jsonapi/node/collection_object/"+testUuid(4):
+			_, _ = fmt.Fprintf(w, `{"data": {"type": "node--collection_object", "id": "%s",
+				"attributes": {"title": "Photographs"},
+				"relationships": {"field_member_of": {"data": null},
+					"field_access_terms": {"data": [{"type": "taxonomy_term--islandora_access", "id": "%s"}]}}}}`,
+				testUuid(4), testUuid(7))
+		case strings.HasPrefix(r.URL.Path, "/jsonapi/taxonomy_term/"):
+			vocabulary := strings.TrimPrefix(collectionPath(r), "/jsonapi/taxonomy_term/")
+			_, _ = fmt.Fprintf(w, `{"data": {"type": "taxonomy_term--%s", "id": "%s", "attributes": {"name": "term-%[2]s"}}}`,
+				vocabulary, requestedId(r))
+		case r.URL.Path == "/jsonapi/media/image" && r.URL.Query().Get("filter[field_media_of.id]") == testUuid(1):
+			_, _ = fmt.Fprintf(w, `{"data": [{"type": "media--image", "id": "%s", "attributes": {"name": "moonrise.jpg"},
+				"relationships": {
+					"field_media_of": {"data": [{"type": "node--islandora_object", "id": "%s"}]},
+					"field_media_image": {"data": {"type": "file--file", "id": "%s", "meta": {"width": 800}}}}}]}`,
+				testUuid(5), testUuid(1), testUuid(6))
+		case r.URL.Path == "/jsonapi/file/file/"+testUuid(6):
+			_, _ = fmt.Fprintf(w, `{"data": {"type": "file--file", "id": "%s", "attributes": {"filename": "moonrise.jpg",
+				"uri": {"url": "http://%s/system/files/moonrise.jpg"}, "changed": "%s"}}}`, testUuid(6), r.Host, r.Host)
+		case r.URL.Path == "/jsonapi/node/islandora_object" && r.URL.Query().Get("filter[field_digital_identifier]") == "ms-0001":
+			_, _ = fmt.Fprintf(w, `{"data": [{"type": "node--islandora_object", "id": "%s"}]}`, testUuid(1))
+		case r.URL.Query().Get("filter[field_media_of.id]") != "":
+			_, _ = w.Write([]byte(`{"data": []}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	setBaseUrl(t, server)
+	t.Cleanup(ResetTermCache)
+	return server
+}
+
+func Test_ExportGraph(t *testing.T) {
+	newGraphServer(t)
+
+	g := ExportGraph(t, testUuid(1), 1)
+	assert.Equal(t, "Moonrise", g.Root.Label)
+	assert.Equal(t, []interface{}{"1 photograph"}, g.Root.Attributes["field_extent"])
+
+	rels := g.Root.Relationships
+	require.Len(t, rels["field_subject"], 1)
+	assert.Equal(t, "term-"+testUuid(2), rels["field_subject"][0].Label)
+	require.NotNil(t, rels["field_member_of"][0].Entity)
+	parent := rels["field_member_of"][0].Entity
+	assert.Equal(t, "Photographs", parent.Label)
+	assert.Nil(t, parent.Relationships["field_access_terms"][0].Entity, "the depth is expected to bound the graph")
+	assert.Equal(t, "term-"+testUuid(7), parent.Relationships["field_access_terms"][0].Label)
+	assert.NotContains(t, rels, "field_copyright_and_use")
+	assert.Equal(t, "islandora_object", rels["node_type"][0].Id)
+
+	require.Len(t, g.Root.Media, 1)
+	media := g.Root.Media[0]
+	assert.Equal(t, "moonrise.jpg", media.Label)
+	assert.Nil(t, media.Relationships["field_media_of"][0].Entity, "the object is expected to be exported once")
+	file := media.Relationships["field_media_image"][0]
+	require.NotNil(t, file.Entity)
+	assert.Equal(t, "moonrise.jpg", file.Entity.Attributes["filename"])
+	assert.Equal(t, float64(800), file.Meta["width"])
+
+	a, b := bytes.Buffer{}, bytes.Buffer{}
+	_, err := g.WriteTo(&a)
+	require.Nil(t, err, "%s", err)
+	_, err = ExportGraph(t, testUuid(1), 1).WriteTo(&b)
+	require.Nil(t, err, "%s", err)
+	assert.Equal(t, a.String(), b.String())
+
+	shallow := ExportGraph(t, testUuid(1), 0)
+	assert.Nil(t, shallow.Root.Relationships["field_member_of"][0].Entity)
+	assert.Equal(t, "term-"+testUuid(3), shallow.Root.Relationships["field_model"][0].Label)
+
+	_, err = ExportGraphErr(testUuid(9), 1)
+	assert.NotNil(t, err)
+}
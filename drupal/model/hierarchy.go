@@ -0,0 +1,232 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// The maximum number of field_member_of relationships followed by AncestorsOf before giving up
+const maxAncestorDepth = 32
+
+// AncestorsOf resolves the field_member_of relationship of the supplied JsonApiIslandoraObj or JsonApiCollection,
+// and of each resolved parent in turn, until a collection without a parent is reached.  The ancestors are answered in
+// order from the root collection down to the immediate parent, e.g. for an item in "University Archives > Photographs"
+// the result is [University Archives, Photographs].
+//
+// Cycles, or hierarchies deeper than 32 levels, result in a failed assertion.
+func AncestorsOf(t *testing.T, obj interface{}) []JsonApiCollection {
+	ancestors, err := AncestorsOfErr(obj)
+	assert.Nil(t, err, "%s", err)
+	return ancestors
+}
+
+// AncestorsOfErr behaves as AncestorsOf, but answers an error instead of making assertions.
+func AncestorsOfErr(obj interface{}) ([]JsonApiCollection, error) {
+	parent, err := memberOf(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	var ancestors []JsonApiCollection
+	visited := map[string]bool{}
+
+	for !parent.IsZero() {
+		if visited[parent.Id] {
+			return nil, fmt.Errorf("model: cycle detected in field_member_of hierarchy at %s %s", parent.Type, parent.Id)
+		}
+		if len(ancestors) == maxAncestorDepth {
+			return nil, fmt.Errorf("model: field_member_of hierarchy exceeds the maximum depth of %d", maxAncestorDepth)
+		}
+		visited[parent.Id] = true
+
+		col, err := ResolveAsErr[JsonApiCollection](parent)
+		if err != nil {
+			return nil, err
+		}
+		ancestors = append([]JsonApiCollection{col}, ancestors...)
+		parent = col.JsonApiData[0].JsonApiRelationships.MemberOf.Data
+	}
+
+	return ancestors, nil
+}
+
+// The separator between the titles of a hierarchy path, e.g. `University Archives > Photographs > Item`
+const pathSeparator = " > "
+
+// AssertMemberOfPath asserts that the supplied JsonApiIslandoraObj or JsonApiCollection is located at the expected
+// path, i.e. the titles of its ancestors from the root collection down (see AncestorsOf), followed by its own title,
+// separated by `>`:
+//
+//	model.AssertMemberOfPath(t, obj, "University Archives > Photographs > Moonrise")
+//
+// Whitespace surrounding each title of the expected path is ignored.  The failure message includes the actual path.
+func AssertMemberOfPath(t *testing.T, entity interface{}, expected string) bool {
+	if failure := memberOfPathFailure(entity, expected); failure != "" {
+		return assert.Fail(t, failure)
+	}
+	return true
+}
+
+// memberOfPathFailure answers a description of the failure of the entity to be located at the expected path, or the
+// empty string
+func memberOfPathFailure(entity interface{}, expected string) string {
+	actual, err := MemberOfPathErr(entity)
+	if err != nil {
+		return err.Error()
+	}
+
+	var titles []string
+	for _, title := range strings.Split(expected, ">") {
+		titles = append(titles, strings.TrimSpace(title))
+	}
+	if path := strings.Join(titles, pathSeparator); path != actual {
+		return fmt.Sprintf("unexpected field_member_of path:\nexpected path: %s\nactual path  : %s", path, actual)
+	}
+	return ""
+}
+
+// MemberOfPathErr answers the path of the supplied JsonApiIslandoraObj or JsonApiCollection: the titles of its
+// ancestors from the root collection down, followed by its own title, separated by ` > `.  An error is answered if the
+// hierarchy cannot be resolved, or contains a cycle (see AncestorsOfErr).
+func MemberOfPathErr(entity interface{}) (string, error) {
+	title, err := titleOf(entity)
+	if err != nil {
+		return "", err
+	}
+	ancestors, err := AncestorsOfErr(entity)
+	if err != nil {
+		return "", err
+	}
+
+	var titles []string
+	for _, a := range ancestors {
+		titles = append(titles, a.JsonApiData[0].JsonApiAttributes.Title)
+	}
+	return strings.Join(append(titles, title), pathSeparator), nil
+}
+
+// titleOf answers the title of the first data element of the supplied JsonApiIslandoraObj or JsonApiCollection
+func titleOf(obj interface{}) (string, error) {
+	switch o := obj.(type) {
+	case *JsonApiIslandoraObj:
+		return titleOf(*o)
+	case *JsonApiCollection:
+		return titleOf(*o)
+	case JsonApiIslandoraObj:
+		if len(o.JsonApiData) == 0 {
+			return "", fmt.Errorf("model: %T has no data elements", o)
+		}
+		return o.JsonApiData[0].JsonApiAttributes.Title, nil
+	case JsonApiCollection:
+		if len(o.JsonApiData) == 0 {
+			return "", fmt.Errorf("model: %T has no data elements", o)
+		}
+		return o.JsonApiData[0].JsonApiAttributes.Title, nil
+	default:
+		return "", fmt.Errorf("model: %T does not carry a title", obj)
+	}
+}
+
+// memberOf answers the field_member_of relationship of the first data element of the supplied JsonApiIslandoraObj or
+// JsonApiCollection
+func memberOf(obj interface{}) (JsonApiData, error) {
+	switch o := obj.(type) {
+	case *JsonApiIslandoraObj:
+		return memberOf(*o)
+	case *JsonApiCollection:
+		return memberOf(*o)
+	case JsonApiIslandoraObj:
+		if len(o.JsonApiData) == 0 {
+			return JsonApiData{}, fmt.Errorf("model: %T has no data elements", o)
+		}
+		return o.JsonApiData[0].JsonApiRelationships.MemberOf.Data, nil
+	case JsonApiCollection:
+		if len(o.JsonApiData) == 0 {
+			return JsonApiData{}, fmt.Errorf("model: %T has no data elements", o)
+		}
+		return o.JsonApiData[0].JsonApiRelationships.MemberOf.Data, nil
+	default:
+		return JsonApiData{}, fmt.Errorf("model: %T does not carry a field_member_of relationship", obj)
+	}
+}
+
+// ChildrenInOrder retrieves every repository object that is a member of (i.e. whose field_member_of references) the
+// repository object or collection with the supplied UUID, and answers them ordered by their field_weight.  Children
+// sharing a weight retain the order in which they were returned by Drupal.
+//
+// This is useful for verifying the order of the pages of paged content (e.g. books or serials).
+func ChildrenInOrder(t *testing.T, parentUuid string) JsonApiIslandoraObj {
+	u := query(t, Node, RepositoryObject)
+	u.Filter = "field_member_of.id"
+	u.Value = parentUuid
+
+	children := JsonApiIslandoraObj{}
+	u.GetAll(&children)
+
+	sort.SliceStable(children.JsonApiData, func(i, j int) bool {
+		return children.JsonApiData[i].JsonApiAttributes.Weight < children.JsonApiData[j].JsonApiAttributes.Weight
+	})
+	return children
+}
+
+// The members of a collection: the child collections and the child repository objects whose field_member_of references
+// the collection
+type Members struct {
+	Collections JsonApiCollection
+	Objects     JsonApiIslandoraObj
+}
+
+// Count answers the total number of member collections and repository objects
+func (m Members) Count() int {
+	return len(m.Collections.JsonApiData) + len(m.Objects.JsonApiData)
+}
+
+// MembersOf retrieves the collections and repository objects that are members of the identified collection, following
+// every page of results.  If `recurse` is true, the members of each member (including repository objects, which may be
+// compound objects) are retrieved in turn, so that the result describes the entire subtree below the collection.  Each
+// member is answered once, even if the hierarchy contains a cycle.
+func MembersOf(t *testing.T, collectionUuid string, recurse bool) Members {
+	members := Members{}
+	visited := map[string]bool{collectionUuid: true}
+	parents := []string{collectionUuid}
+
+	for len(parents) > 0 {
+		parent := parents[0]
+		parents = parents[1:]
+
+		collections := JsonApiCollection{}
+		u := query(t, Node, Collection)
+		u.Filter, u.Value = "field_member_of.id", parent
+		u.GetAll(&collections)
+
+		objects := JsonApiIslandoraObj{}
+		u = query(t, Node, RepositoryObject)
+		u.Filter, u.Value = "field_member_of.id", parent
+		u.GetAll(&objects)
+
+		for _, c := range collections.JsonApiData {
+			if !visited[c.Id] {
+				visited[c.Id] = true
+				members.Collections.JsonApiData = append(members.Collections.JsonApiData, c)
+				parents = append(parents, c.Id)
+			}
+		}
+		for _, o := range objects.JsonApiData {
+			if !visited[o.Id] {
+				visited[o.Id] = true
+				members.Objects.JsonApiData = append(members.Objects.JsonApiData, o)
+				parents = append(parents, o.Id)
+			}
+		}
+
+		if !recurse {
+			break
+		}
+	}
+
+	return members
+}
@@ -0,0 +1,130 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_AncestorsOf(t *testing.T) {
+	root, photos, item := testUuid(1), testUuid(2), testUuid(3)
+	server := newDocumentServer(map[string]string{
+		root:   collectionElement(root, "University Archives", ""),
+		photos: collectionElement(photos, "Photographs", root),
+	})
+	defer server.Close()
+	setBaseUrl(t, server)
+
+	obj := JsonApiIslandoraObj{}
+	require.Nil(t, json.Unmarshal([]byte(fmt.Sprintf(`{"data": [%s]}`, collectionElement(item, "Item", photos))), &obj))
+
+	ancestors := AncestorsOf(t, obj)
+	require.Equal(t, 2, len(ancestors))
+	assert.Equal(t, "University Archives", ancestors[0].JsonApiData[0].JsonApiAttributes.Title)
+	assert.Equal(t, "Photographs", ancestors[1].JsonApiData[0].JsonApiAttributes.Title)
+
+	col := JsonApiCollection{}
+	require.Nil(t, json.Unmarshal([]byte(fmt.Sprintf(`{"data": [%s]}`, collectionElement(root, "University Archives", ""))), &col))
+	assert.Empty(t, AncestorsOf(t, &col))
+}
+
+func Test_AncestorsOfCycle(t *testing.T) {
+	a, b := testUuid(1), testUuid(2)
+	server := newDocumentServer(map[string]string{
+		a: collectionElement(a, "A", b),
+		b: collectionElement(b, "B", a),
+	})
+	defer server.Close()
+	setBaseUrl(t, server)
+
+	col := JsonApiCollection{}
+	require.Nil(t, json.Unmarshal([]byte(fmt.Sprintf(`{"data": [%s]}`, collectionElement(a, "A", b))), &col))
+
+	_, err := AncestorsOfErr(col)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "cycle")
+}
+
+func Test_AssertMemberOfPath(t *testing.T) {
+	root, photos, item := testUuid(1), testUuid(2), testUuid(3)
+	server := newDocumentServer(map[string]string{
+		root:   collectionElement(root, "University Archives", ""),
+		photos: collectionElement(photos, "Photographs", root),
+	})
+	defer server.Close()
+	setBaseUrl(t, server)
+
+	obj := JsonApiIslandoraObj{}
+	require.Nil(t, json.Unmarshal([]byte(fmt.Sprintf(`{"data": [%s]}`, collectionElement(item, "Moonrise", photos))), &obj))
+	assert.True(t, AssertMemberOfPath(t, obj, "University Archives > Photographs > Moonrise"))
+	assert.True(t, AssertMemberOfPath(t, &obj, " University Archives>Photographs >Moonrise "))
+
+	col := JsonApiCollection{}
+	require.Nil(t, json.Unmarshal([]byte(fmt.Sprintf(`{"data": [%s]}`, collectionElement(photos, "Photographs", root))), &col))
+	path, err := MemberOfPathErr(col)
+	assert.Nil(t, err)
+	assert.Equal(t, "University Archives > Photographs", path)
+
+	_, err = MemberOfPathErr(JsonApiSubject{})
+	assert.NotNil(t, err)
+}
+
+func Test_ChildrenInOrder(t *testing.T) {
+	book := testUuid(100)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, book, r.URL.Query().Get("filter[field_member_of.id]"))
+
+		// pages are returned out of order
+		var pages []string
+		for _, weight := range []int{3, 10, 1, 7, 2, 9, 4, 6, 8, 5} {
+			pages = append(pages, fmt.Sprintf(`{"type": "node--islandora_object", "id": "%s",
+				"attributes": {"title": "Page %d", "field_weight": %d}}`, testUuid(weight), weight, weight))
+		}
+		_, _ = fmt.Fprintf(w, `{"data": [%s]}`, strings.Join(pages, ","))
+	}))
+	defer server.Close()
+	setBaseUrl(t, server)
+
+	children := ChildrenInOrder(t, book)
+	require.Equal(t, 10, len(children.JsonApiData))
+	for i, child := range children.JsonApiData {
+		assert.Equal(t, i+1, child.JsonApiAttributes.Weight)
+		assert.Equal(t, fmt.Sprintf("Page %d", i+1), child.JsonApiAttributes.Title)
+	}
+}
+
+func Test_MembersOf(t *testing.T) {
+	// root > [photos > [photo 1, photo 2], letter > [page 1]], with a cycle from photo 2 back to the root
+	root, photos, letter := testUuid(1), testUuid(2), testUuid(3)
+	children := map[string]map[string][]string{
+		Collection:       {root: {photos}},
+		RepositoryObject: {root: {letter}, photos: {testUuid(4), testUuid(5)}, letter: {testUuid(6)}, testUuid(5): {root}},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bundle := strings.TrimPrefix(r.URL.Path, "/jsonapi/node/")
+		var elements []string
+		for _, id := range children[bundle][r.URL.Query().Get("filter[field_member_of.id]")] {
+			elements = append(elements, fmt.Sprintf(`{"type": "node--%s", "id": "%s"}`, bundle, id))
+		}
+		_, _ = fmt.Fprintf(w, `{"data": [%s]}`, strings.Join(elements, ","))
+	}))
+	defer server.Close()
+	setBaseUrl(t, server)
+
+	members := MembersOf(t, root, false)
+	assert.Equal(t, 2, members.Count())
+	require.Equal(t, 1, len(members.Collections.JsonApiData))
+	assert.Equal(t, photos, members.Collections.JsonApiData[0].Id)
+
+	members = MembersOf(t, root, true)
+	assert.Equal(t, 1, len(members.Collections.JsonApiData))
+	assert.Equal(t, 4, len(members.Objects.JsonApiData))
+	assert.Equal(t, 5, members.Count())
+}
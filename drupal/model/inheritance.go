@@ -0,0 +1,113 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// AssertAccessTermsInherited asserts that every member of the identified collection carries the access terms of its
+// parent: the names of the parent's access terms, including the ancestors of each term (see AccessTermNames), are a
+// subset of the names of the member's access terms.  If `recurse` is true, the members of each member are checked in
+// turn against their own parent (see MembersOf), and if `includeMedia` is true, each media of each repository object
+// member is checked against the repository object.  Every member lacking a term of its parent is reported, with the
+// terms it actually carries:
+//
+//	model.AssertAccessTermsInherited(t, collection.JsonApiData[0].Id, true, true)
+func AssertAccessTermsInherited(t *testing.T, collectionUuid string, recurse, includeMedia bool) bool {
+	failures, err := inheritanceFailuresErr(t, collectionUuid, recurse, includeMedia)
+	if !assert.Nil(t, err, "%s", err) {
+		return false
+	}
+	if len(failures) > 0 {
+		return assert.Fail(t, fmt.Sprintf("%d member(s) of collection %s do not inherit access terms:\n\t%s",
+			len(failures), collectionUuid, strings.Join(failures, "\n\t")))
+	}
+	return true
+}
+
+// inheritanceFailuresErr answers a description of each member of the collection that does not carry the access terms
+// of its parent (see AssertAccessTermsInherited).  A member whose parent is not the collection or one of its checked
+// members (e.g. a member of more than one parent) is checked against the collection.
+func inheritanceFailuresErr(t *testing.T, collectionUuid string, recurse, includeMedia bool) ([]string, error) {
+	collection := JsonApiCollection{}
+	u := query(t, Node, Collection)
+	u.Filter, u.Value = "id", collectionUuid
+	if err := u.GetSingleErr(&collection); err != nil {
+		return nil, fmt.Errorf("model: unable to retrieve collection %s: %w", collectionUuid, err)
+	}
+
+	rootTerms, err := accessTermNamesErr(collection.JsonApiData[0].JsonApiRelationships.AccessTerms.Data)
+	if err != nil {
+		return nil, err
+	}
+	// the access term names of the collection and of each checked member, keyed by id
+	terms := map[string][]string{collectionUuid: rootTerms}
+	var failures []string
+
+	check := func(id, member, parentId string, memberTerms []JsonApiData) error {
+		parentTerms, ok := terms[parentId]
+		if !ok {
+			parentId, parentTerms = collectionUuid, rootTerms
+		}
+		names, err := accessTermNamesErr(memberTerms)
+		if err != nil {
+			return err
+		}
+		terms[id] = names
+		if missing := missingNames(parentTerms, names); len(missing) > 0 {
+			failures = append(failures, fmt.Sprintf("%s (member of %s) lacks %q; its access terms are %q", member,
+				parentId, missing, names))
+		}
+		return nil
+	}
+
+	members := MembersOf(t, collectionUuid, recurse)
+	for _, c := range members.Collections.JsonApiData {
+		member := fmt.Sprintf("%s %s '%s'", c.Type, c.Id, c.JsonApiAttributes.Title)
+		if err := check(c.Id, member, c.JsonApiRelationships.MemberOf.Data.Id,
+			c.JsonApiRelationships.AccessTerms.Data); err != nil {
+			return nil, err
+		}
+	}
+	for _, o := range members.Objects.JsonApiData {
+		member := fmt.Sprintf("%s %s '%s'", o.Type, o.Id, o.JsonApiAttributes.Title)
+		if err := check(o.Id, member, o.JsonApiRelationships.MemberOf.Data.Id,
+			o.JsonApiRelationships.AccessTerms.Data); err != nil {
+			return nil, err
+		}
+	}
+	if !includeMedia {
+		return failures, nil
+	}
+
+	for _, o := range members.Objects.JsonApiData {
+		for _, m := range MediaOfObject(t, o.Id) {
+			id, err := createdIdErr(m)
+			if err != nil {
+				return nil, err
+			}
+			if err := check(id, fmt.Sprintf("media %s '%s'", id, m.Name()), o.Id, m.AccessTerms()); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return failures, nil
+}
+
+// missingNames answers the names of expected absent from actual, in the order of expected
+func missingNames(expected, actual []string) []string {
+	present := map[string]bool{}
+	for _, name := range actual {
+		present[name] = true
+	}
+	var missing []string
+	for _, name := range expected {
+		if !present[name] {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
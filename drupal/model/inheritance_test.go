@@ -0,0 +1,77 @@
+package model
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// accessElement answers a JSON API data element of the type with the id, which is a member of the parent (if not empty)
+// and carries the access term
+func accessElement(drupalType, id, parentId, termId string) string {
+	return fmt.Sprintf(`{"type": "%s", "id": "%s", "attributes": {"title": "%s", "name": "%s"}, "relationships": {
+		"field_member_of": {"data": {"type": "node--collection_object", "id": "%s"}},
+		"field_access_terms": {"data": [{"type": "taxonomy_term--islandora_access", "id": "%s"}]}}}`,
+		drupalType, id, id, id, parentId, termId)
+}
+
+func Test_AssertAccessTermsInherited(t *testing.T) {
+	// root (Staff Only) > [sub (Staff Only) > [inherits (Staff Only), with a Public media], stray (Public)]
+	staffOnly, public := testUuid(10), testUuid(11)
+	root, sub, inherits, stray, media := testUuid(1), testUuid(2), testUuid(3), testUuid(4), testUuid(5)
+	members := map[string][]string{
+		"/jsonapi/node/collection_object": {accessElement("node--collection_object", sub, root, staffOnly)},
+		"/jsonapi/node/islandora_object": {accessElement("node--islandora_object", inherits, sub, staffOnly),
+			accessElement("node--islandora_object", stray, root, public)},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		var elements []string
+		switch {
+		case requestedId(r) == staffOnly:
+			elements = append(elements, `{"type": "taxonomy_term--islandora_access", "attributes": {"name": "Staff Only"}}`)
+		case requestedId(r) == public:
+			elements = append(elements, `{"type": "taxonomy_term--islandora_access", "attributes": {"name": "Public"}}`)
+		case requestedId(r) == root:
+			elements = append(elements, accessElement("node--collection_object", root, "", staffOnly))
+		case r.URL.Path == "/jsonapi/media/image" && q.Get("filter[field_media_of.id]") == inherits:
+			elements = append(elements, accessElement("media--image", media, "", public))
+		case q.Get("filter[field_member_of.id]") != "":
+			for _, e := range members[r.URL.Path] {
+				if strings.Contains(e, `"node--collection_object", "id": "`+q.Get("filter[field_member_of.id]")) {
+					elements = append(elements, e)
+				}
+			}
+		}
+		_, _ = fmt.Fprintf(w, `{"data": [%s]}`, strings.Join(elements, ","))
+	}))
+	defer server.Close()
+	setBaseUrl(t, server)
+	ResetTermCache()
+	defer ResetTermCache()
+
+	failures, err := inheritanceFailuresErr(t, root, false, false)
+	require.Nil(t, err, "%s", err)
+	require.Equal(t, 1, len(failures))
+	assert.Equal(t, fmt.Sprintf(`node--islandora_object %s '%s' (member of %s) lacks ["Staff Only"]; `+
+		`its access terms are ["Public"]`, stray, stray, root), failures[0])
+
+	failures, err = inheritanceFailuresErr(t, root, true, true)
+	require.Nil(t, err, "%s", err)
+	require.Equal(t, 2, len(failures))
+	assert.Equal(t, fmt.Sprintf(`media %s '%s' (member of %s) lacks ["Staff Only"]; its access terms are ["Public"]`,
+		media, media, inherits), failures[1])
+
+	_, err = inheritanceFailuresErr(t, testUuid(9), false, false)
+	assert.Contains(t, fmt.Sprint(err), "unable to retrieve collection")
+}
+
+func Test_missingNames(t *testing.T) {
+	assert.Equal(t, []string{"Restricted"}, missingNames([]string{"Public", "Restricted"}, []string{"Public"}))
+	assert.Empty(t, missingNames([]string{"Public"}, []string{"Public", "Staff Only"}))
+}
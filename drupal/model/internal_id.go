@@ -0,0 +1,129 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/stretchr/testify/require"
+)
+
+// The attributes carrying the internal id of each entity type, e.g. the node id of a node
+var internalIdAttributes = map[string]string{
+	Node:         "drupal_internal__nid",
+	TaxonomyTerm: "drupal_internal__tid",
+	MediaEntity:  "drupal_internal__mid",
+	"file":       "drupal_internal__fid",
+	"user":       "drupal_internal__uid",
+}
+
+// Internal ids and UUIDs, keyed by the base url, entity, bundle, and either `nid {id}` or `uuid {uuid}`.  The mapping
+// between them never changes for an environment, so each is only requested once.
+var internalIds = sync.Map{}
+
+// UuidForNid answers the UUID of the entity of the bundle with the internal id, e.g. the node id used by Workbench CSVs
+// and Drupal admin urls (`/node/12/edit`), failing the test immediately if there is no such entity.  The internal id
+// is the `drupal_internal__nid` of a node, the `drupal_internal__tid` of a taxonomy term, or the
+// `drupal_internal__mid` of a media:
+//
+//	uuid := model.UuidForNid(t, model.Node, model.RepositoryObject, 12)
+func UuidForNid(t *testing.T, entity, bundle string, nid int, opts ...Option) string {
+	uuid, err := UuidForNidErr(entity, bundle, nid, opts...)
+	require.Nil(t, err, "%s", err)
+	return uuid
+}
+
+// UuidForNidErr behaves as UuidForNid, but answers an error instead of failing the test.  The error wraps
+// jsonapi.ErrNotFound if there is no such entity.
+func UuidForNidErr(entity, bundle string, nid int, opts ...Option) (string, error) {
+	attribute, err := internalIdAttributeErr(entity)
+	if err != nil {
+		return "", err
+	}
+	key := internalIdKey(opts, entity, bundle, "nid", strconv.Itoa(nid))
+	if cached, ok := internalIds.Load(key); ok {
+		return cached.(string), nil
+	}
+
+	res := struct {
+		Data []JsonApiData
+	}{}
+	u := query(nil, entity, bundle, opts...)
+	u.Filter, u.Value = attribute, strconv.Itoa(nid)
+	if err := u.GetSingleErr(&res); err != nil {
+		return "", fmt.Errorf("model: unable to find the UUID of %s %s %d: %w", entity, bundle, nid, err)
+	}
+
+	uuid := res.Data[0].Id
+	internalIds.Store(key, uuid)
+	internalIds.Store(internalIdKey(opts, entity, bundle, "uuid", uuid), nid)
+	return uuid, nil
+}
+
+// NidFor answers the internal id of the entity identified by the data object, e.g. the node id of a repository object
+// or the term id of a taxonomy term, failing the test immediately if it cannot be retrieved (see UuidForNid)
+func NidFor(t *testing.T, jad JsonApiData, opts ...Option) int {
+	nid, err := NidForErr(jad, opts...)
+	require.Nil(t, err, "%s", err)
+	return nid
+}
+
+// NidForErr behaves as NidFor, but answers an error instead of failing the test
+func NidForErr(jad JsonApiData, opts ...Option) (int, error) {
+	if err := jad.Validate(); err != nil {
+		return 0, fmt.Errorf("model: unable to find the internal id: %w", err)
+	}
+	entity, bundle := jad.Type.Entity(), jad.Type.Bundle()
+	attribute, err := internalIdAttributeErr(entity)
+	if err != nil {
+		return 0, err
+	}
+	key := internalIdKey(opts, entity, bundle, "uuid", jad.Id)
+	if cached, ok := internalIds.Load(key); ok {
+		return cached.(int), nil
+	}
+
+	res := struct {
+		Data []struct {
+			JsonApiAttributes map[string]json.RawMessage `json:"attributes"`
+		}
+	}{}
+	u := jad.url(env.UsernameOr(""), env.PasswordOr(""), opts...)
+	if err := u.GetSingleErr(&res); err != nil {
+		return 0, fmt.Errorf("model: unable to find the internal id of %s %s: %w", jad.Type, jad.Id, err)
+	}
+	nid := 0
+	if err := json.Unmarshal(res.Data[0].JsonApiAttributes[attribute], &nid); err != nil || nid == 0 {
+		return 0, fmt.Errorf("%w: '%s' of %s %s to int", ErrConversion, attribute, jad.Type, jad.Id)
+	}
+
+	internalIds.Store(key, nid)
+	internalIds.Store(internalIdKey(opts, entity, bundle, "nid", strconv.Itoa(nid)), jad.Id)
+	return nid, nil
+}
+
+// ResetInternalIdCache discards the internal ids and UUIDs cached by UuidForNid and NidFor, e.g. after the environment
+// is reinstalled
+func ResetInternalIdCache() {
+	internalIds.Range(func(key, _ interface{}) bool {
+		internalIds.Delete(key)
+		return true
+	})
+}
+
+// internalIdAttributeErr answers the attribute carrying the internal id of the entity type
+func internalIdAttributeErr(entity string) (string, error) {
+	attribute, ok := internalIdAttributes[entity]
+	if !ok {
+		return "", fmt.Errorf("model: the internal id of '%s' entities is unknown", entity)
+	}
+	return attribute, nil
+}
+
+// internalIdKey answers the key of a cached internal id or UUID, on the site the options request (see baseUrlOf)
+func internalIdKey(opts []Option, entity, bundle, kind, id string) string {
+	return fmt.Sprintf("%s %s %s %s %s", baseUrlOf(opts), entity, bundle, kind, id)
+}
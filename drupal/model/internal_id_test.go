@@ -0,0 +1,72 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_UuidForNidAndNidFor(t *testing.T) {
+	t.Cleanup(ResetInternalIdCache)
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch {
+		case collectionPath(r) == "/jsonapi/node/islandora_object" && r.URL.Query().Get("filter[drupal_internal__nid]") == "12":
+			_, _ = fmt.Fprintf(w, `{"data": [{"type": "node--islandora_object", "id": "%s"}]}`, testUuid(1))
+		case r.URL.Path == "/jsonapi/taxonomy_term/subject/"+testUuid(2):
+			_, _ = fmt.Fprintf(w, `{"data": {"type": "taxonomy_term--subject", "id": "%s",
+				"attributes": {"drupal_internal__tid": 7, "name": "moo"}}}`, testUuid(2))
+		default:
+			_, _ = w.Write([]byte(`{"data": []}`))
+		}
+	}))
+	defer server.Close()
+	setBaseUrl(t, server)
+
+	assert.Equal(t, testUuid(1), UuidForNid(t, Node, RepositoryObject, 12))
+	assert.Equal(t, 12, NidFor(t, JsonApiData{Type: "node--islandora_object", Id: testUuid(1)}))
+	assert.Equal(t, 1, requests, "the mapping is expected to be cached in both directions")
+
+	assert.Equal(t, 7, NidFor(t, JsonApiData{Type: "taxonomy_term--subject", Id: testUuid(2)}))
+	assert.Equal(t, testUuid(2), UuidForNid(t, TaxonomyTerm, "subject", 7))
+	assert.Equal(t, 2, requests)
+
+	_, err := UuidForNidErr(Node, RepositoryObject, 13)
+	assert.True(t, errors.Is(err, jsonapi.ErrNotFound), "expected ErrNotFound, got %v", err)
+	_, err = UuidForNidErr("moo", "cow", 1)
+	assert.Contains(t, fmt.Sprint(err), "unknown")
+	_, err = NidForErr(JsonApiData{})
+	assert.True(t, errors.Is(err, ErrRelationshipNotSet), "expected ErrRelationshipNotSet, got %v", err)
+}
+
+func Test_UuidForNidPerSite(t *testing.T) {
+	t.Cleanup(ResetInternalIdCache)
+	site := func(i int) *httptest.Server {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = fmt.Fprintf(w, `{"data": [{"type": "node--islandora_object", "id": "%s"}]}`, testUuid(i))
+		}))
+		t.Cleanup(server.Close)
+		return server
+	}
+	first, second := site(1), site(2)
+	setBaseUrl(t, first)
+
+	assert.Equal(t, testUuid(1), UuidForNid(t, Node, RepositoryObject, 12))
+	assert.Equal(t, testUuid(2), UuidForNid(t, Node, RepositoryObject, 12, WithBaseUrl(second.URL)),
+		"the mapping is expected to be cached per site")
+	assert.Equal(t, testUuid(1), UuidForNid(t, Node, RepositoryObject, 12))
+}
+
+func Test_NodeAttributesNid(t *testing.T) {
+	obj := JsonApiIslandoraObj{}
+	require.Nil(t, json.Unmarshal([]byte(`{"data": [{"attributes": {"drupal_internal__nid": 12, "title": "Moonrise"}}]}`), &obj))
+	assert.Equal(t, 12, obj.JsonApiData[0].JsonApiAttributes.Nid)
+}
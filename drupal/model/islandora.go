@@ -0,0 +1,52 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ModelName answers the name of the Islandora model (field_model) of the first repository object in the response,
+// e.g. `Paged Content`, or the empty string if the object has no model
+func (obj JsonApiIslandoraObj) ModelName(t *testing.T) string {
+	model, ok := obj.model(t)
+	if !ok {
+		return ""
+	}
+	return model.JsonApiData[0].JsonApiAttributes.Name
+}
+
+// ModelExternalUri answers the external uri of the Islandora model (field_model) of the first repository object in the
+// response, e.g. `https://schema.org/Book`, or the empty string if the object has no model
+func (obj JsonApiIslandoraObj) ModelExternalUri(t *testing.T) string {
+	model, ok := obj.model(t)
+	if !ok {
+		return ""
+	}
+	return model.JsonApiData[0].JsonApiAttributes.ExternalUri.Uri
+}
+
+// DisplayHintName answers the name of the Islandora display hint (field_display_hints) of the first repository object
+// in the response, e.g. `Open Seadragon`, or the empty string if the object has no display hint
+func (obj JsonApiIslandoraObj) DisplayHintName(t *testing.T) string {
+	if len(obj.JsonApiData) == 0 || obj.JsonApiData[0].JsonApiRelationships.DisplayHint.Data.IsZero() {
+		return ""
+	}
+
+	display, err := resolveCachedErr[JsonApiIslandoraDisplay](obj.JsonApiData[0].JsonApiRelationships.DisplayHint.Data)
+	if !assert.Nil(t, err, "%s", err) {
+		return ""
+	}
+	return display.JsonApiData[0].JsonApiAttributes.Name
+}
+
+// model resolves the Islandora model of the first repository object in the response, answering false if the object has
+// no model or the model cannot be resolved
+func (obj JsonApiIslandoraObj) model(t *testing.T) (JsonApiIslandoraModel, bool) {
+	if len(obj.JsonApiData) == 0 || obj.JsonApiData[0].JsonApiRelationships.Model.Data.IsZero() {
+		return JsonApiIslandoraModel{}, false
+	}
+
+	model, err := resolveCachedErr[JsonApiIslandoraModel](obj.JsonApiData[0].JsonApiRelationships.Model.Data)
+	return model, assert.Nil(t, err, "%s", err)
+}
@@ -0,0 +1,38 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ModelAndDisplayHint(t *testing.T) {
+	server := newDocumentServer(map[string]string{
+		testUuid(1): fmt.Sprintf(`{"type": "taxonomy_term--islandora_models", "id": "%s", "attributes": {"name": "Paged Content",
+			"field_external_uri": {"uri": "https://schema.org/Book", "title": ""}}}`, testUuid(1)),
+		testUuid(2): fmt.Sprintf(`{"type": "taxonomy_term--islandora_display", "id": "%s", "attributes": {"name": "Open Seadragon"}}`, testUuid(2)),
+	})
+	defer server.Close()
+	setBaseUrl(t, server)
+
+	obj := JsonApiIslandoraObj{}
+	require.Nil(t, json.Unmarshal([]byte(fmt.Sprintf(`{"data": [{"type": "node--islandora_object", "id": "%s",
+		"relationships": {"field_model": {"data": {"type": "taxonomy_term--islandora_models", "id": "%s"}},
+		"field_display_hints": {"data": {"type": "taxonomy_term--islandora_display", "id": "%s"}}}}]}`,
+		testUuid(0), testUuid(1), testUuid(2))), &obj))
+
+	assert.Equal(t, "Paged Content", obj.ModelName(t))
+	assert.Equal(t, "https://schema.org/Book", obj.ModelExternalUri(t))
+	assert.Equal(t, "Open Seadragon", obj.DisplayHintName(t))
+
+	empty := JsonApiIslandoraObj{}
+	require.Nil(t, json.Unmarshal([]byte(fmt.Sprintf(`{"data": [{"type": "node--islandora_object", "id": "%s",
+		"relationships": {"field_model": {"data": null}}}]}`, testUuid(0))), &empty))
+	assert.Equal(t, "", empty.ModelName(t))
+	assert.Equal(t, "", empty.ModelExternalUri(t))
+	assert.Equal(t, "", empty.DisplayHintName(t))
+	assert.Equal(t, "", JsonApiIslandoraObj{}.ModelName(t))
+}
@@ -0,0 +1,87 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/stretchr/testify/require"
+)
+
+// A value string paired with the code of its language, e.g. {"es", "Salida de la luna sobre Hernández"}
+type LangValue struct {
+	Lang  string
+	Value string
+}
+
+// LanguageValues answers the language code and value of each of the supplied values, preserving their order
+func LanguageValues(t *testing.T, values []JsonApiLanguageValue) []LangValue {
+	result := make([]LangValue, len(values))
+	for i, v := range values {
+		result[i] = LangValue{Lang: v.LangCode(t), Value: v.Value()}
+	}
+	return result
+}
+
+// LanguageValueMap answers the supplied values keyed by their language code.  If more than one value shares a
+// language code, the last value wins; use LanguageValueMultiMap when duplicate language codes are expected.
+func LanguageValueMap(t *testing.T, values []JsonApiLanguageValue) map[string]string {
+	result := make(map[string]string, len(values))
+	for _, lv := range LanguageValues(t, values) {
+		result[lv.Lang] = lv.Value
+	}
+	return result
+}
+
+// LanguageValueMultiMap answers the supplied values keyed by their language code.  Values sharing a language code
+// are kept in the order they were supplied.
+func LanguageValueMultiMap(t *testing.T, values []JsonApiLanguageValue) map[string][]string {
+	result := make(map[string][]string)
+	for _, lv := range LanguageValues(t, values) {
+		result[lv.Lang] = append(result[lv.Lang], lv.Value)
+	}
+	return result
+}
+
+// LanguageTermValues answers the supplied values, in order, each in the language identified by the UUID of its Language
+// taxonomy term, so that they may be written to a language-tagged relationship (see jsonapi.ToLanguageValues).  The test
+// fails immediately, naming each language code, if any language code has no term.
+//
+//	titles := model.LanguageTermValues(t, []model.ExpectedLangString{{Value: "Moonrise", LangCode: "en"}})
+//	doc.SetLanguageValues("field_alternative_title", titles...)
+func LanguageTermValues(t *testing.T, values []ExpectedLangString, opts ...Option) []jsonapi.LanguageValue {
+	result, err := LanguageTermValuesErr(values, opts...)
+	require.Nil(t, err, "%s", err)
+	return result
+}
+
+// LanguageTermValuesErr behaves as LanguageTermValues, but answers an error instead of failing the test.  Language codes
+// are resolved using the index of the Language vocabulary, which is retrieved once, and cached (see ResetTermCache).  If
+// any language code has no term, the error names every such code, and wraps jsonapi.ErrNotFound.
+func LanguageTermValuesErr(values []ExpectedLangString, opts ...Option) ([]jsonapi.LanguageValue, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	idx, err := languageCodeIndexErr(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]jsonapi.LanguageValue, 0, len(values))
+	var missing []string
+	for _, v := range values {
+		uuid, ok := idx[v.LangCode]
+		if !ok {
+			missing = append(missing, fmt.Sprintf("'%s'", v.LangCode))
+			continue
+		}
+		result = append(result, jsonapi.LanguageValue{Value: v.Value, LangTermUuid: uuid})
+	}
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("model: unable to find the %s terms with the codes %s: %w", languageVocabulary,
+			strings.Join(missing, ", "), jsonapi.ErrNotFound)
+	}
+	return result, nil
+}
@@ -0,0 +1,100 @@
+package model
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newLanguageServer answers a test server which responds to JSON API queries for language terms, along with a function
+// answering the number of requests received for each language term id
+func newLanguageServer(codes map[string]string) (*httptest.Server, func() map[string]int) {
+	mu := sync.Mutex{}
+	requests := map[string]int{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := requestedId(r)
+		mu.Lock()
+		requests[id]++
+		mu.Unlock()
+		_, _ = fmt.Fprintf(w, `{"data": [{"type": "taxonomy_term--language", "id": "%s",
+			"attributes": {"field_language_code": "%s"}}]}`, id, codes[id])
+	}))
+
+	return server, func() map[string]int {
+		mu.Lock()
+		defer mu.Unlock()
+		result := map[string]int{}
+		for k, v := range requests {
+			result[k] = v
+		}
+		return result
+	}
+}
+
+func Test_LangCodeCache(t *testing.T) {
+	en, es := testUuid(1), testUuid(2)
+	server, requests := newLanguageServer(map[string]string{en: "en", es: "es"})
+	defer server.Close()
+	setBaseUrl(t, server)
+	ResetLangCodeCache()
+	t.Cleanup(ResetLangCodeCache)
+
+	values := []JsonApiLanguageValue{
+		{JsonApiData: JsonApiData{Type: "taxonomy_term--language", Id: en}},
+		{JsonApiData: JsonApiData{Type: "taxonomy_term--language", Id: es}},
+		{JsonApiData: JsonApiData{Type: "taxonomy_term--language", Id: en}},
+		{JsonApiData: JsonApiData{Type: "taxonomy_term--language", Id: es}},
+		{JsonApiData: JsonApiData{Type: "taxonomy_term--language", Id: en}},
+	}
+
+	for _, v := range values {
+		v.LangCode(t)
+	}
+	assert.Equal(t, "en", values[0].LangCode(t))
+	assert.Equal(t, "es", values[1].LangCode(t))
+	assert.Equal(t, map[string]int{en: 1, es: 1}, requests())
+
+	ResetLangCodeCache()
+	assert.Equal(t, "en", values[0].LangCode(t))
+	assert.Equal(t, 2, requests()[en])
+}
+
+func Test_LanguageValueMap(t *testing.T) {
+	en, es := testUuid(1), testUuid(2)
+	server, _ := newLanguageServer(map[string]string{en: "en", es: "es"})
+	defer server.Close()
+	setBaseUrl(t, server)
+	ResetLangCodeCache()
+	t.Cleanup(ResetLangCodeCache)
+
+	value := func(id, value string) JsonApiLanguageValue {
+		lv := JsonApiLanguageValue{JsonApiData: JsonApiData{Type: "taxonomy_term--language", Id: id}}
+		lv.Meta.Value = value
+		return lv
+	}
+	values := []JsonApiLanguageValue{
+		value(en, "Moonrise Over Hernandez"),
+		value(es, "Salida de la luna sobre Hernández"),
+		value(en, "Moonrise, Hernandez"),
+	}
+
+	assert.Equal(t, []LangValue{
+		{"en", "Moonrise Over Hernandez"},
+		{"es", "Salida de la luna sobre Hernández"},
+		{"en", "Moonrise, Hernandez"},
+	}, LanguageValues(t, values))
+
+	m := LanguageValueMap(t, values)
+	assert.Equal(t, "Salida de la luna sobre Hernández", m["es"])
+	assert.Equal(t, "Moonrise, Hernandez", m["en"])
+
+	assert.Equal(t, map[string][]string{
+		"en": {"Moonrise Over Hernandez", "Moonrise, Hernandez"},
+		"es": {"Salida de la luna sobre Hernández"},
+	}, LanguageValueMultiMap(t, values))
+}
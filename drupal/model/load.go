@@ -0,0 +1,77 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// LoadExpected decodes the JSON file at the supplied path (e.g. `testdata/subject.json`) into a new expected struct of
+// type T (e.g. ExpectedSubject), and answers it.  Keys that do not correspond to a field of T fail the test, so that
+// expected files that have drifted from the expected structs are noticed rather than silently ignored:
+//
+//	subject := model.LoadExpected[model.ExpectedSubject](t, "testdata/subject.json")
+func LoadExpected[T any](t *testing.T, path string) T {
+	v, err := LoadExpectedErr[T](path)
+	require.Nil(t, err, "%s", err)
+	return v
+}
+
+// LoadExpectedErr behaves as LoadExpected, but answers an error instead of failing the test.  An error is answered if
+// the file cannot be read, is not valid JSON, or carries a key that is not a field of T.
+func LoadExpectedErr[T any](path string) (T, error) {
+	var v T
+	f, err := os.Open(path)
+	if err != nil {
+		return v, fmt.Errorf("model: unable to load expected %T: %w", v, err)
+	}
+	defer f.Close()
+
+	dec := json.NewDecoder(f)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&v); err != nil {
+		return v, fmt.Errorf("model: unable to decode %s as %T: %w", path, v, err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return v, fmt.Errorf("model: unable to decode %s as %T: unexpected content following the JSON value", path, v)
+	}
+
+	return v, nil
+}
+
+// LoadExpectedDir loads each JSON file (i.e. each file with a `.json` extension) in the supplied directory as an
+// expected struct of type T, and answers them keyed by file name.  Subdirectories are not searched.  The test fails if
+// any file cannot be loaded (see LoadExpected).
+func LoadExpectedDir[T any](t *testing.T, dir string) map[string]T {
+	v, err := LoadExpectedDirErr[T](dir)
+	require.Nil(t, err, "%s", err)
+	return v
+}
+
+// LoadExpectedDirErr behaves as LoadExpectedDir, but answers an error instead of failing the test
+func LoadExpectedDirErr[T any](dir string) (map[string]T, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("model: unable to load expected directory: %w", err)
+	}
+
+	result := make(map[string]T)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(entry.Name()), ".json") {
+			continue
+		}
+		v, err := LoadExpectedErr[T](filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		result[entry.Name()] = v
+	}
+
+	return result, nil
+}
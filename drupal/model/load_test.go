@@ -0,0 +1,39 @@
+package model
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_LoadExpected(t *testing.T) {
+	subject := LoadExpected[ExpectedSubject](t, filepath.Join("testdata", "expected", "subject-analog.json"))
+	assert.Equal(t, "taxonomy_term", subject.Type)
+	assert.Equal(t, "subject", subject.Bundle)
+	assert.Equal(t, "Analog Photography", subject.Name)
+	require.Equal(t, 1, len(subject.Authority))
+	assert.Equal(t, "Google", subject.Authority[0].Title)
+	assert.Equal(t, "basic_html", subject.Description.Format)
+}
+
+func Test_LoadExpectedErrUnknownField(t *testing.T) {
+	_, err := LoadExpectedErr[ExpectedSubject](filepath.Join("testdata", "expected-drift", "subject.json"))
+	assert.NotNil(t, err)
+	assert.Contains(t, fmt.Sprint(err), `"field_authority_link"`)
+
+	_, err = LoadExpectedDirErr[ExpectedSubject](filepath.Join("testdata", "expected-drift"))
+	assert.Contains(t, fmt.Sprint(err), "subject.json")
+
+	_, err = LoadExpectedErr[ExpectedSubject](filepath.Join("testdata", "expected", "moo.json"))
+	assert.NotNil(t, err)
+}
+
+func Test_LoadExpectedDir(t *testing.T) {
+	subjects := LoadExpectedDir[ExpectedSubject](t, filepath.Join("testdata", "expected"))
+	require.Equal(t, 2, len(subjects))
+	assert.Equal(t, "Analog Photography", subjects["subject-analog.json"].Name)
+	assert.Equal(t, "Digital Photography", subjects["subject-digital.json"].Name)
+}
@@ -0,0 +1,147 @@
+package model
+
+import (
+	"testing"
+)
+
+// Media exposes the attributes and relationships common to every media bundle, e.g. JsonApiImageMedia or
+// JsonApiDocumentMedia.  Each method answers the value of the first data element of the media, or the zero value if
+// the media has no data elements.
+type Media interface {
+	Name() string
+	// The name of the file as it was ingested, which may differ from the name of the media
+	OriginalName() string
+	FileSize() int
+	MimeType() string
+	RestrictedAccess() bool
+	// The repository object that the media belongs to
+	MediaOf() JsonApiData
+	// The Islandora Media Use terms of the media, e.g. "Original File" or "Thumbnail Image"
+	MediaUse() []JsonApiData
+	// The Islandora Access terms of the media
+	AccessTerms() []JsonApiData
+	// The file of the media; remote video media do not have a file
+	File() RelData
+}
+
+// Insures each media bundle implements Media
+var (
+	_ Media = JsonApiImageMedia{}
+	_ Media = JsonApiDocumentMedia{}
+	_ Media = JsonApiAudioMedia{}
+	_ Media = JsonApiVideoMedia{}
+	_ Media = JsonApiExtractedTextMedia{}
+	_ Media = JsonApiGenericFileMedia{}
+	_ Media = JsonApiFitsMedia{}
+	_ Media = JsonApiRemoteVideoMedia{}
+)
+
+// commonMedia carries the attributes and relationships shared by every media bundle
+type commonMedia struct {
+	attributes    JsonApiMediaAttributes
+	relationships JsonApiMediaRelationships
+	file          RelData
+}
+
+func (m commonMedia) Name() string {
+	return m.attributes.Name
+}
+
+func (m commonMedia) OriginalName() string {
+	return m.attributes.OriginalName
+}
+
+func (m commonMedia) FileSize() int {
+	return m.attributes.FileSize
+}
+
+func (m commonMedia) MimeType() string {
+	return m.attributes.MimeType
+}
+
+func (m commonMedia) RestrictedAccess() bool {
+	return m.attributes.RestrictedAccess
+}
+
+func (m commonMedia) MediaOf() JsonApiData {
+	return m.relationships.MediaOf.Data
+}
+
+func (m commonMedia) MediaUse() []JsonApiData {
+	return m.relationships.MediaUse.Data
+}
+
+func (m commonMedia) AccessTerms() []JsonApiData {
+	return m.relationships.AccessTerms.Data
+}
+
+func (m commonMedia) File() RelData {
+	return m.file
+}
+
+func (m JsonApiRemoteVideoMedia) common() commonMedia {
+	if len(m.JsonApiData) == 0 {
+		return commonMedia{}
+	}
+	d := m.JsonApiData[0]
+	attributes := JsonApiMediaAttributes{Name: d.JsonApiAttributes.Name, RestrictedAccess: d.JsonApiAttributes.RestrictedAccess}
+	return commonMedia{attributes: attributes, relationships: d.JsonApiRelationships.JsonApiMediaRelationships}
+}
+
+func (m JsonApiRemoteVideoMedia) Name() string {
+	return m.common().Name()
+}
+
+func (m JsonApiRemoteVideoMedia) OriginalName() string {
+	return m.common().OriginalName()
+}
+
+func (m JsonApiRemoteVideoMedia) FileSize() int {
+	return m.common().FileSize()
+}
+
+func (m JsonApiRemoteVideoMedia) MimeType() string {
+	return m.common().MimeType()
+}
+
+func (m JsonApiRemoteVideoMedia) RestrictedAccess() bool {
+	return m.common().RestrictedAccess()
+}
+
+func (m JsonApiRemoteVideoMedia) MediaOf() JsonApiData {
+	return m.common().MediaOf()
+}
+
+func (m JsonApiRemoteVideoMedia) MediaUse() []JsonApiData {
+	return m.common().MediaUse()
+}
+
+func (m JsonApiRemoteVideoMedia) AccessTerms() []JsonApiData {
+	return m.common().AccessTerms()
+}
+
+func (m JsonApiRemoteVideoMedia) File() RelData {
+	return m.common().File()
+}
+
+// VerifyMediaCommon asserts that the attributes and relationships common to every media bundle match the expected
+// media.  Relationships are resolved, so that the names of the expected media use and access terms, and the title of
+// the expected repository object, are compared.  Expected values that are empty (or zero) are not verified, other than
+// the name and restricted access of the media.  Options customize the comparison (see CompareOption).
+func VerifyMediaCommon(t *testing.T, m Media, expected ExpectedMediaGeneric, opts ...CompareOption) bool {
+	return compareMedia(t, expected.Name, nil, mediaFields(m, expected), opts)
+}
+
+// mediaFields answers the fields common to every media bundle, paired with their expected values
+func mediaFields(m Media, e ExpectedMediaGeneric) []comparedField {
+	return []comparedField{
+		field("Name", e.Name, valueOf(m.Name())),
+		field("RestrictedAccess", e.RestrictedAccess, valueOf(m.RestrictedAccess())),
+		field("OriginalName", e.OriginalName, valueOf(m.OriginalName())).ifExpected(),
+		field("MimeType", e.MimeType, valueOf(m.MimeType())).ifExpected(),
+		field("Size", e.Size, valueOf(m.FileSize())).ifExpected(),
+		field("MediaUse", e.MediaUse, labelsOf(m.MediaUse())).ifExpected(),
+		field("AccessTerms", e.AccessTerms, labelsOf(m.AccessTerms())).ifExpected(),
+		field("MediaOf", e.MediaOf, labelOf(m.MediaOf())).ifExpected(),
+	}
+}
@@ -0,0 +1,135 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// The tolerance, in seconds, used when comparing the duration of audio and video media
+const durationTolerance = 0.01
+
+// CompareImageMedia asserts that the image media matches the expected media, including its dimensions and the
+// alternative text of its image.  Expected values that are empty (or zero) are not verified (see VerifyMediaCommon).
+func CompareImageMedia(t *testing.T, expected ExpectedMediaImage, actual JsonApiImageMedia, opts ...CompareOption) bool {
+	if !assert.NotEmpty(t, actual.JsonApiData, "expected image media '%s', but the response is empty", expected.Name) {
+		return false
+	}
+	attrs := actual.JsonApiData[0].JsonApiAttributes
+	golden := func() (interface{}, error) { return expectedImageMediaErr(actual) }
+
+	return compareMedia(t, expected.Name, golden, append(mediaFields(actual, expected.ExpectedMediaGeneric),
+		field("Height", expected.Height, valueOf(attrs.Height)).ifExpected(),
+		field("Width", expected.Width, valueOf(attrs.Width)).ifExpected(),
+		field("AltText", expected.AltText, func() (interface{}, error) { return actual.File().MetaString("alt") }).ifExpected(),
+	), opts)
+}
+
+// CompareDocumentMedia asserts that the document media matches the expected media.  Expected values that are empty (or
+// zero) are not verified (see VerifyMediaCommon).
+func CompareDocumentMedia(t *testing.T, expected ExpectedMediaDocument, actual JsonApiDocumentMedia, opts ...CompareOption) bool {
+	if !assert.NotEmpty(t, actual.JsonApiData, "expected document media '%s', but the response is empty", expected.Name) {
+		return false
+	}
+	golden := func() (interface{}, error) { return expectedDocumentMediaErr(actual) }
+
+	return compareMedia(t, expected.Name, golden, mediaFields(actual, expected.ExpectedMediaGeneric), opts)
+}
+
+// CompareAudioMedia asserts that the audio media matches the expected media, including its duration.  Expected values
+// that are empty (or zero) are not verified (see VerifyMediaCommon).
+func CompareAudioMedia(t *testing.T, expected ExpectedMediaAudio, actual JsonApiAudioMedia, opts ...CompareOption) bool {
+	if !assert.NotEmpty(t, actual.JsonApiData, "expected audio media '%s', but the response is empty", expected.Name) {
+		return false
+	}
+	attrs := actual.JsonApiData[0].JsonApiAttributes
+	golden := func() (interface{}, error) { return expectedAudioMediaErr(actual) }
+
+	return compareMedia(t, expected.Name, golden, append(mediaFields(actual, expected.ExpectedMediaGeneric),
+		durationField(expected.Duration, attrs.DurationSeconds),
+	), opts)
+}
+
+// CompareVideoMedia asserts that the video media matches the expected media, including its duration and dimensions.
+// Expected values that are empty (or zero) are not verified (see VerifyMediaCommon).
+func CompareVideoMedia(t *testing.T, expected ExpectedMediaVideo, actual JsonApiVideoMedia, opts ...CompareOption) bool {
+	if !assert.NotEmpty(t, actual.JsonApiData, "expected video media '%s', but the response is empty", expected.Name) {
+		return false
+	}
+	attrs := actual.JsonApiData[0].JsonApiAttributes
+	golden := func() (interface{}, error) { return expectedVideoMediaErr(actual) }
+
+	return compareMedia(t, expected.Name, golden, append(mediaFields(actual, expected.ExpectedMediaGeneric),
+		durationField(expected.Duration, attrs.DurationSeconds),
+		field("Height", expected.Height, valueOf(attrs.Height)).ifExpected(),
+		field("Width", expected.Width, valueOf(attrs.Width)).ifExpected(),
+	), opts)
+}
+
+// CompareExtractedTextMedia asserts that the extracted text media matches the expected media, including the value of
+// its edited text.  Expected values that are empty (or zero) are not verified (see VerifyMediaCommon).
+func CompareExtractedTextMedia(t *testing.T, expected ExpectedMediaExtractedText, actual JsonApiExtractedTextMedia, opts ...CompareOption) bool {
+	if !assert.NotEmpty(t, actual.JsonApiData, "expected extracted text media '%s', but the response is empty", expected.Name) {
+		return false
+	}
+	attrs := actual.JsonApiData[0].JsonApiAttributes
+	golden := func() (interface{}, error) { return expectedExtractedTextMediaErr(actual) }
+
+	return compareMedia(t, expected.Name, golden, append(mediaFields(actual, expected.ExpectedMediaGeneric),
+		field("ExtractedText", expected.ExtractedText.Value, valueOf(attrs.EditedText.Value)).ifExpected(),
+	), opts)
+}
+
+// CompareFileMedia asserts that the file media matches the expected media.  Expected values that are empty (or zero)
+// are not verified (see VerifyMediaCommon).
+func CompareFileMedia(t *testing.T, expected ExpectedMediaFile, actual JsonApiGenericFileMedia, opts ...CompareOption) bool {
+	if !assert.NotEmpty(t, actual.JsonApiData, "expected file media '%s', but the response is empty", expected.Name) {
+		return false
+	}
+	golden := func() (interface{}, error) { return expectedFileMediaErr(actual) }
+
+	return compareMedia(t, expected.Name, golden, mediaFields(actual, expected.ExpectedMediaGeneric), opts)
+}
+
+// CompareRemoteVideoMedia asserts that the remote video media matches the expected media.  If the expected media
+// carries a video id, the id parsed from the embed url is compared (see ParseEmbedUrl); if it carries an embed url, the
+// urls are compared.
+func CompareRemoteVideoMedia(t *testing.T, expected ExpectedMediaRemoteVideo, actual JsonApiRemoteVideoMedia, opts ...CompareOption) bool {
+	if !assert.NotEmpty(t, actual.JsonApiData, "expected remote video media '%s', but the response is empty", expected.Name) {
+		return false
+	}
+	embedUrl := actual.JsonApiData[0].JsonApiAttributes.EmbedUrl
+	golden := func() (interface{}, error) { return expectedRemoteVideoMediaErr(actual) }
+
+	return compareMedia(t, expected.Name, golden, []comparedField{
+		field("Name", expected.Name, valueOf(actual.Name())),
+		field("RestrictedAccess", expected.RestrictedAccess, valueOf(actual.RestrictedAccess())),
+		field("MediaOf", expected.MediaOf, labelOf(actual.MediaOf())).ifExpected(),
+		field("EmbedUrl", expected.EmbedUrl, valueOf(embedUrl)).ifExpected(),
+		field("VideoId", expected.VideoId, func() (interface{}, error) {
+			info, err := ParseEmbedUrl(embedUrl)
+			return info.VideoId, err
+		}).ifExpected(),
+	}, opts)
+}
+
+// compareMedia asserts that the fields of the named media match their expected values.  If UPDATE_EXPECTED is true and
+// the media has a golden file (see GoldenFile), the expected struct answered by the golden function is written instead.
+func compareMedia(t *testing.T, name string, golden func() (interface{}, error), fields []comparedField, opts []CompareOption) bool {
+	if o := newCompareOptions(opts); golden != nil && o.updating() {
+		return updateExpected(t, o.golden, golden)
+	}
+	return reportDiffs(t, fmt.Sprintf("media '%s'", name), diffFields(fields, newCompareOptions(opts)), opts)
+}
+
+// durationField answers the Duration field, which is compared within durationTolerance
+func durationField(expected float64, actual func() (float64, error)) comparedField {
+	return field("Duration", expected, func() (interface{}, error) { return actual() }).ifExpected().
+		comparedWith(func(expected, actual interface{}) bool {
+			e, eok := expected.(float64)
+			a, aok := actual.(float64)
+			return eok && aok && math.Abs(e-a) <= durationTolerance
+		})
+}
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CompareVideoMedia(t *testing.T) {
+	server := newDocumentServer(map[string]string{
+		"b4b7ae6c-7c6f-4c9a-9e1f-6bb8d9b1b0a2": `{"type": "taxonomy_term--islandora_media_use", "id": "b4b7ae6c-7c6f-4c9a-9e1f-6bb8d9b1b0a2",
+			"attributes": {"name": "Service File"}}`,
+	})
+	defer server.Close()
+	setBaseUrl(t, server)
+
+	video := JsonApiVideoMedia{}
+	unmarshalTestdata(t, "media-video.json", &video)
+
+	expected := ExpectedMediaVideo{Duration: 93.5, Height: 720, Width: 1280}
+	expected.Name = "Moonrise Over Hernandez.mp4"
+	expected.OriginalName = "moonrise.mp4"
+	expected.MediaUse = []string{"Service File"}
+	assert.True(t, CompareVideoMedia(t, expected, video))
+}
+
+func Test_CompareImageAndRemoteVideoMedia(t *testing.T) {
+	image := JsonApiImageMedia{}
+	require.Nil(t, json.Unmarshal([]byte(`{"data": [{"type": "media--image", "id": "00000001-0000-4000-8000-000000000000",
+		"attributes": {"name": "moonrise.tif", "field_height": 3000, "field_width": 4000},
+		"relationships": {"field_media_image": {"data": {"type": "file--file", "id": "00000002-0000-4000-8000-000000000000",
+		"meta": {"alt": "Moonrise over Hernandez, New Mexico"}}}}}]}`), &image))
+
+	expectedImage := ExpectedMediaImage{AltText: "Moonrise over Hernandez, New Mexico", Height: 3000, Width: 4000}
+	expectedImage.Name = "moonrise.tif"
+	assert.True(t, CompareImageMedia(t, expectedImage, image))
+
+	video := JsonApiRemoteVideoMedia{}
+	require.Nil(t, json.Unmarshal([]byte(`{"data": [{"type": "media--remote_video", "id": "00000003-0000-4000-8000-000000000000",
+		"attributes": {"name": "Commencement", "field_media_oembed_video": "https://www.youtube.com/watch?v=dQw4w9WgXcQ"}}]}`), &video))
+
+	expectedVideo := ExpectedMediaRemoteVideo{VideoId: "dQw4w9WgXcQ"}
+	expectedVideo.Name = "Commencement"
+	assert.True(t, CompareRemoteVideoMedia(t, expectedVideo, video))
+}
@@ -0,0 +1,199 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+)
+
+// MediaDocument represents the results of a JSONAPI query for the media of a bundle carrying a file, e.g. Image media.
+// Such bundles differ only in their bundle-specific attributes, A (which embed JsonApiMediaAttributes), and in the
+// field carrying their file, named by the bundle, B.  Each bundle is declared as an alias of an instantiation, e.g.
+// JsonApiImageMedia, so a new bundle carrying a file needs only its attributes, and a bundle naming its file field:
+//
+//	type JsonApiImageMedia = MediaDocument[imageMediaAttributes, imageMedia]
+type MediaDocument[A mediaAttributes, B fileBundle] struct {
+	JsonApiData []MediaData[A, B] `json:"data"`
+}
+
+// MediaData is a data element of a MediaDocument: a media of the bundle B, with the attributes A
+type MediaData[A mediaAttributes, B fileBundle] struct {
+	Type                 jsonapi.DrupalType
+	Id                   string
+	JsonApiAttributes    A
+	JsonApiRelationships JsonApiMediaFileRelationships
+}
+
+// JsonApiMediaFileRelationships are the relationships of a media carrying a file
+type JsonApiMediaFileRelationships struct {
+	JsonApiMediaRelationships
+	// The file of the media, carried by the file field of its bundle, e.g. `field_media_image`
+	File struct {
+		Data RelData
+	}
+}
+
+// mediaAttributes is satisfied by the attributes of a media bundle, which embed JsonApiMediaAttributes
+type mediaAttributes interface {
+	common() JsonApiMediaAttributes
+}
+
+// fileBundle is satisfied by a type naming the field carrying the file of a media bundle, e.g. `imageMedia`
+type fileBundle interface {
+	fileField() string
+}
+
+func (a JsonApiMediaAttributes) common() JsonApiMediaAttributes {
+	return a
+}
+
+// The attributes of the media bundles carrying a file
+type (
+	imageMediaAttributes struct {
+		JsonApiMediaAttributes
+		JsonApiImageMediaAttributes
+	}
+	audioMediaAttributes struct {
+		JsonApiMediaAttributes
+		JsonApiAudioMediaAttributes
+	}
+	videoMediaAttributes struct {
+		JsonApiMediaAttributes
+		JsonApiVideoMediaAttributes
+	}
+	extractedTextMediaAttributes struct {
+		JsonApiMediaAttributes
+		JsonApiExtractedTextMediaAttributes
+	}
+	// The attributes of the bundles carrying only the attributes common to every media, e.g. Document media
+	fileMediaAttributes struct {
+		JsonApiMediaAttributes
+	}
+)
+
+// The media bundles carrying a file, each naming its file field
+type (
+	imageMedia         struct{}
+	documentMedia      struct{}
+	audioMedia         struct{}
+	videoMedia         struct{}
+	extractedTextMedia struct{}
+	genericFileMedia   struct{}
+	fitsMedia          struct{}
+)
+
+func (imageMedia) fileField() string         { return "field_media_image" }
+func (documentMedia) fileField() string      { return "field_media_document" }
+func (audioMedia) fileField() string         { return "field_media_audio_file" }
+func (videoMedia) fileField() string         { return "field_media_video_file" }
+func (extractedTextMedia) fileField() string { return "field_media_file" }
+func (genericFileMedia) fileField() string   { return "field_media_file" }
+func (fitsMedia) fileField() string          { return "field_media_file" }
+
+// mediaDataJson is the JSON representation of a MediaData, whose relationships are decoded according to its bundle
+type mediaDataJson[A mediaAttributes] struct {
+	Type          jsonapi.DrupalType `json:"type"`
+	Id            string             `json:"id"`
+	Attributes    A                  `json:"attributes"`
+	Relationships json.RawMessage    `json:"relationships,omitempty"`
+}
+
+// UnmarshalJSON decodes the data element, reading the file of the media from the file field of the bundle B
+func (d *MediaData[A, B]) UnmarshalJSON(b []byte) error {
+	var raw mediaDataJson[A]
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	*d = MediaData[A, B]{Type: raw.Type, Id: raw.Id, JsonApiAttributes: raw.Attributes}
+	if len(raw.Relationships) == 0 || string(raw.Relationships) == "null" {
+		return nil
+	}
+
+	if err := json.Unmarshal(raw.Relationships, &d.JsonApiRelationships.JsonApiMediaRelationships); err != nil {
+		return err
+	}
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(raw.Relationships, &fields); err != nil {
+		return err
+	}
+	if file, ok := fields[d.fileField()]; ok {
+		if err := json.Unmarshal(file, &d.JsonApiRelationships.File); err != nil {
+			return fmt.Errorf("model: unable to decode %s of %s %s: %w", d.fileField(), d.Type, d.Id, err)
+		}
+	}
+	return nil
+}
+
+// MarshalJSON encodes the data element, writing the file of the media to the file field of the bundle B, so that a
+// decoded media may be encoded again (e.g. to record an expected media)
+func (d MediaData[A, B]) MarshalJSON() ([]byte, error) {
+	common, err := json.Marshal(d.JsonApiRelationships.JsonApiMediaRelationships)
+	if err != nil {
+		return nil, err
+	}
+	relationships := map[string]json.RawMessage{}
+	if err := json.Unmarshal(common, &relationships); err != nil {
+		return nil, err
+	}
+	if relationships[d.fileField()], err = json.Marshal(d.JsonApiRelationships.File); err != nil {
+		return nil, err
+	}
+	raw := mediaDataJson[A]{Type: d.Type, Id: d.Id, Attributes: d.JsonApiAttributes}
+	if raw.Relationships, err = json.Marshal(relationships); err != nil {
+		return nil, err
+	}
+	return json.Marshal(raw)
+}
+
+// fileField answers the name of the field carrying the file of the bundle B
+func (d MediaData[A, B]) fileField() string {
+	var bundle B
+	return bundle.fileField()
+}
+
+// common answers the attributes and relationships of the first data element of the media
+func (m MediaDocument[A, B]) common() commonMedia {
+	if len(m.JsonApiData) == 0 {
+		return commonMedia{}
+	}
+	d := m.JsonApiData[0]
+	return commonMedia{d.JsonApiAttributes.common(), d.JsonApiRelationships.JsonApiMediaRelationships,
+		d.JsonApiRelationships.File.Data}
+}
+
+func (m MediaDocument[A, B]) Name() string {
+	return m.common().Name()
+}
+
+func (m MediaDocument[A, B]) OriginalName() string {
+	return m.common().OriginalName()
+}
+
+func (m MediaDocument[A, B]) FileSize() int {
+	return m.common().FileSize()
+}
+
+func (m MediaDocument[A, B]) MimeType() string {
+	return m.common().MimeType()
+}
+
+func (m MediaDocument[A, B]) RestrictedAccess() bool {
+	return m.common().RestrictedAccess()
+}
+
+func (m MediaDocument[A, B]) MediaOf() JsonApiData {
+	return m.common().MediaOf()
+}
+
+func (m MediaDocument[A, B]) MediaUse() []JsonApiData {
+	return m.common().MediaUse()
+}
+
+func (m MediaDocument[A, B]) AccessTerms() []JsonApiData {
+	return m.common().AccessTerms()
+}
+
+func (m MediaDocument[A, B]) File() RelData {
+	return m.common().File()
+}
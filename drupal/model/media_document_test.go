@@ -0,0 +1,97 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mediaDocument answers a JSONAPI response carrying a media of the bundle, whose file is carried by the file field, and
+// whose attributes include the supplied bundle-specific attributes
+func mediaDocument(bundle, fileField, attributes string) []byte {
+	return []byte(fmt.Sprintf(`{"data": [{"type": "media--%s", "id": "%s",
+		"attributes": {"name": "moo", "field_file_size": 3, "field_mime_type": "text/plain",
+			"field_original_name": "moo.txt", "field_restricted_access": true%s},
+		"relationships": {
+			"%s": {"data": {"type": "file--file", "id": "%s", "meta": {"alt": "moo"}}},
+			"field_media_of": {"data": {"type": "node--islandora_object", "id": "%s"}},
+			"field_media_use": {"data": [{"type": "taxonomy_term--islandora_media_use", "id": "%s"}]},
+			"field_access_terms": {"data": []}}}]}`,
+		bundle, testUuid(1), attributes, fileField, testUuid(2), testUuid(3), testUuid(4)))
+}
+
+func Test_MediaDocumentRoundTrip(t *testing.T) {
+	for _, test := range []struct {
+		bundle, fileField, attributes string
+		media                         Media
+		// asserts the bundle-specific attributes of the decoded media
+		verify func(t *testing.T, m Media)
+	}{
+		{Image, "field_media_image", `, "field_height": 480, "field_width": 640`, &JsonApiImageMedia{},
+			func(t *testing.T, m Media) {
+				attrs := m.(*JsonApiImageMedia).JsonApiData[0].JsonApiAttributes
+				assert.Equal(t, 480, attrs.Height)
+				assert.Equal(t, 640, attrs.Width)
+			}},
+		{Document, "field_media_document", "", &JsonApiDocumentMedia{}, nil},
+		{Audio, "field_media_audio_file", `, "field_duration": "1800"`, &JsonApiAudioMedia{},
+			func(t *testing.T, m Media) {
+				seconds, err := m.(*JsonApiAudioMedia).JsonApiData[0].JsonApiAttributes.DurationSeconds()
+				assert.Nil(t, err)
+				assert.Equal(t, float64(1800), seconds)
+			}},
+		{Video, "field_media_video_file", `, "field_duration": "93.5", "field_height": 720`, &JsonApiVideoMedia{},
+			func(t *testing.T, m Media) {
+				attrs := m.(*JsonApiVideoMedia).JsonApiData[0].JsonApiAttributes
+				seconds, err := attrs.DurationSeconds()
+				assert.Nil(t, err)
+				assert.Equal(t, 93.5, seconds)
+				assert.Equal(t, 720, attrs.Height)
+			}},
+		{ExtractedText, "field_media_file", `, "field_edited_text": {"value": "moo", "format": "plain_text"}`,
+			&JsonApiExtractedTextMedia{},
+			func(t *testing.T, m Media) {
+				attrs := m.(*JsonApiExtractedTextMedia).JsonApiData[0].JsonApiAttributes
+				assert.Equal(t, "moo", attrs.EditedText.Value)
+			}},
+		{File, "field_media_file", "", &JsonApiGenericFileMedia{}, nil},
+		{Fits, "field_media_file", "", &JsonApiFitsMedia{}, nil},
+	} {
+		t.Run(test.bundle, func(t *testing.T) {
+			require.Nil(t, json.Unmarshal(mediaDocument(test.bundle, test.fileField, test.attributes), test.media))
+			m := reflect.ValueOf(test.media).Elem().Interface().(Media)
+			assert.Equal(t, "moo", m.Name())
+			assert.Equal(t, "moo.txt", m.OriginalName())
+			assert.Equal(t, 3, m.FileSize())
+			assert.Equal(t, "text/plain", m.MimeType())
+			assert.True(t, m.RestrictedAccess())
+			assert.Equal(t, testUuid(2), m.File().Id)
+			assert.Equal(t, "moo", m.File().Meta["alt"])
+			assert.Equal(t, testUuid(3), m.MediaOf().Id)
+			require.Equal(t, 1, len(m.MediaUse()))
+			assert.Equal(t, testUuid(4), m.MediaUse()[0].Id)
+			assert.Empty(t, m.AccessTerms())
+			if test.verify != nil {
+				test.verify(t, test.media)
+			}
+
+			encoded, err := json.Marshal(test.media)
+			require.Nil(t, err, "%s", err)
+			assert.Contains(t, string(encoded), `"`+test.fileField+`"`)
+			decoded := reflect.New(reflect.TypeOf(test.media).Elem()).Interface()
+			require.Nil(t, json.Unmarshal(encoded, decoded), "error decoding %s", encoded)
+			assert.Equal(t, test.media, decoded, "the media is expected to survive encoding")
+		})
+	}
+}
+
+func Test_MediaDocumentIgnoresOtherFileFields(t *testing.T) {
+	image := JsonApiImageMedia{}
+	require.Nil(t, json.Unmarshal(mediaDocument(Image, "field_media_file", ""), &image))
+	assert.True(t, image.File().IsZero(), "an image is expected to carry its file in field_media_image only")
+	assert.Equal(t, testUuid(3), image.MediaOf().Id)
+}
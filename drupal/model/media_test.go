@@ -0,0 +1,79 @@
+package model
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// unmarshalTestdata unmarshals the named JSON document from the testdata directory into the supplied pointer
+func unmarshalTestdata(t *testing.T, name string, v interface{}) {
+	b, err := os.ReadFile(filepath.Join("testdata", name))
+	require.Nil(t, err, "error reading testdata %s: %s", name, err)
+	require.Nil(t, json.Unmarshal(b, v), "error unmarshaling testdata %s", name)
+}
+
+func Test_DecodeVideoMedia(t *testing.T) {
+	video := JsonApiVideoMedia{}
+	unmarshalTestdata(t, "media-video.json", &video)
+	require.Equal(t, 1, len(video.JsonApiData))
+
+	attrs := video.JsonApiData[0].JsonApiAttributes
+	assert.Equal(t, "video/mp4", attrs.MimeType)
+	assert.Equal(t, 10485760, attrs.FileSize)
+	assert.Equal(t, 720, attrs.Height)
+	assert.Equal(t, 1280, attrs.Width)
+	duration, err := attrs.DurationSeconds()
+	assert.Nil(t, err)
+	assert.Equal(t, 93.5, duration)
+	assert.Equal(t, "5e0a8b9c-93cf-4a0b-a6b2-d3b8b2a6e2a1", video.JsonApiData[0].JsonApiRelationships.File.Data.Id)
+}
+
+func Test_DecodeAudioMedia(t *testing.T) {
+	audio := JsonApiAudioMedia{}
+	unmarshalTestdata(t, "media-audio.json", &audio)
+	require.Equal(t, 1, len(audio.JsonApiData))
+
+	attrs := audio.JsonApiData[0].JsonApiAttributes
+	assert.Equal(t, "audio/mpeg", attrs.MimeType)
+	assert.True(t, attrs.RestrictedAccess)
+	duration, err := attrs.DurationSeconds()
+	assert.Nil(t, err)
+	assert.Equal(t, float64(1800), duration)
+	assert.Equal(t, "0d9e8f7a-6b5c-4d3e-9f1a-2b3c4d5e6f70", audio.JsonApiData[0].JsonApiRelationships.File.Data.Id)
+}
+
+func Test_VerifyMediaCommon(t *testing.T) {
+	server := newDocumentServer(map[string]string{
+		"b4b7ae6c-7c6f-4c9a-9e1f-6bb8d9b1b0a2": `{"type": "taxonomy_term--islandora_media_use", "id": "b4b7ae6c-7c6f-4c9a-9e1f-6bb8d9b1b0a2",
+			"attributes": {"name": "Service File"}}`,
+		"815a4c04-0be5-44f1-a876-e8ddc11dcf21": `{"type": "node--islandora_object", "id": "815a4c04-0be5-44f1-a876-e8ddc11dcf21",
+			"attributes": {"title": "Moonrise Over Hernandez"}}`,
+	})
+	defer server.Close()
+	setBaseUrl(t, server)
+
+	video := JsonApiVideoMedia{}
+	unmarshalTestdata(t, "media-video.json", &video)
+
+	var m Media = video
+	assert.Equal(t, "Moonrise Over Hernandez.mp4", m.Name())
+	assert.Equal(t, "5e0a8b9c-93cf-4a0b-a6b2-d3b8b2a6e2a1", m.File().Id)
+
+	expected := ExpectedMediaGeneric{
+		Size:     10485760,
+		MimeType: "video/mp4",
+		MediaUse: []string{"Service File"},
+		MediaOf:  "Moonrise Over Hernandez",
+	}
+	expected.Name = "Moonrise Over Hernandez.mp4"
+	assert.True(t, VerifyMediaCommon(t, m, expected))
+
+	var empty Media = JsonApiRemoteVideoMedia{}
+	assert.Equal(t, "", empty.Name())
+	assert.True(t, empty.File().IsZero())
+}
@@ -0,0 +1,160 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Constant for the Drupal custom menu link entity type (which is also its only bundle)
+const MenuLinkContent = "menu_link_content"
+
+// Represents the results of a JSONAPI query for custom menu links, e.g. the `Browse Collections` link of the main menu
+type JsonApiMenuLink struct {
+	JsonApiData []struct {
+		Type              jsonapi.DrupalType
+		Id                string
+		JsonApiAttributes struct {
+			Title string `json:"title"`
+			// The target of the link; its Uri is e.g. `internal:/collections`, `entity:node/12`, or an absolute url
+			Link Link `json:"link"`
+			// The plugin id of the parent link, e.g. `menu_link_content:{uuid}`, or empty for a top-level link
+			Parent string `json:"parent"`
+			Weight int    `json:"weight"`
+			// The machine name of the menu, e.g. `main` or `footer`
+			MenuName string `json:"menu_name"`
+			Enabled  bool   `json:"enabled"`
+		} `json:"attributes"`
+	} `json:"data"`
+}
+
+// MenuNode is a link of a menu, with the links beneath it
+type MenuNode struct {
+	Title    string
+	Url      string
+	Weight   int
+	Enabled  bool
+	Children []MenuNode
+}
+
+// ExpectedMenuLink is the expected link of a menu, with the links expected beneath it, in order
+type ExpectedMenuLink struct {
+	Title string
+	// The uri of the link, e.g. `internal:/collections`; not compared if empty
+	Url      string
+	Children []ExpectedMenuLink
+}
+
+// MenuTree answers the hierarchy of the custom menu links of the menu, e.g. `main`, failing the test immediately if they
+// cannot be retrieved.  Links are ordered by weight, then title, as Drupal renders them.  Links whose parent is not a
+// custom link of the menu (e.g. a link provided by a module) are answered at the top level.
+func MenuTree(t *testing.T, menuName string, opts ...Option) []MenuNode {
+	tree, err := MenuTreeErr(menuName, opts...)
+	require.Nil(t, err, "%s", err)
+	return tree
+}
+
+// MenuTreeErr behaves as MenuTree, but answers an error instead of failing the test
+func MenuTreeErr(menuName string, opts ...Option) ([]MenuNode, error) {
+	links := JsonApiMenuLink{}
+	u := query(nil, MenuLinkContent, MenuLinkContent, opts...)
+	u.Filter, u.Value = "menu_name", menuName
+	if err := u.GetAllErr(&links); err != nil {
+		return nil, fmt.Errorf("model: unable to retrieve the links of menu %s: %w", menuName, err)
+	}
+
+	ids := map[string]bool{}
+	for _, l := range links.JsonApiData {
+		ids[MenuLinkContent+":"+l.Id] = true
+	}
+	children := map[string][]menuEntry{}
+	for _, l := range links.JsonApiData {
+		attrs := l.JsonApiAttributes
+		parent := attrs.Parent
+		if !ids[parent] {
+			parent = ""
+		}
+		children[parent] = append(children[parent], menuEntry{id: MenuLinkContent + ":" + l.Id,
+			node: MenuNode{Title: attrs.Title, Url: attrs.Link.Uri, Weight: attrs.Weight, Enabled: attrs.Enabled}})
+	}
+	return assembleMenu(children, ""), nil
+}
+
+// menuEntry is a link of a menu, identified by its plugin id, e.g. `menu_link_content:{uuid}`
+type menuEntry struct {
+	id   string
+	node MenuNode
+}
+
+// assembleMenu answers the links beneath the parent, each carrying its own children, ordered by weight, then title
+func assembleMenu(children map[string][]menuEntry, parent string) []MenuNode {
+	var nodes []MenuNode
+	for _, e := range children[parent] {
+		e.node.Children = assembleMenu(children, e.id)
+		nodes = append(nodes, e.node)
+	}
+	sort.SliceStable(nodes, func(i, j int) bool {
+		if nodes[i].Weight != nodes[j].Weight {
+			return nodes[i].Weight < nodes[j].Weight
+		}
+		return nodes[i].Title < nodes[j].Title
+	})
+	return nodes
+}
+
+// AssertMenuTree asserts that the custom links of the menu (see MenuTree) form the expected tree, in order, reporting
+// both trees if they differ:
+//
+//	model.AssertMenuTree(t, "main", []model.ExpectedMenuLink{
+//		{Title: "Browse Collections", Url: "internal:/collections"},
+//		{Title: "About", Children: []model.ExpectedMenuLink{{Title: "Contact"}}},
+//	})
+func AssertMenuTree(t *testing.T, menuName string, expected []ExpectedMenuLink, opts ...Option) bool {
+	tree, err := MenuTreeErr(menuName, opts...)
+	if err != nil {
+		return assert.Fail(t, err.Error())
+	}
+	var want, got strings.Builder
+	writeExpectedMenu(&want, expected, 0)
+	writeMenu(&got, tree, expected, 0)
+	return assert.Equal(t, want.String(), got.String(), "the links of menu %s differ from those expected", menuName)
+}
+
+// writeExpectedMenu writes a line per expected link, indented by depth
+func writeExpectedMenu(b *strings.Builder, links []ExpectedMenuLink, depth int) {
+	for _, l := range links {
+		writeMenuLine(b, depth, l.Title, l.Url)
+		writeExpectedMenu(b, l.Children, depth+1)
+	}
+}
+
+// writeMenu writes a line per link, indented by depth.  The url of a link is written only if the url of the link
+// expected at its position is not empty, so that only expected urls are compared.
+func writeMenu(b *strings.Builder, nodes []MenuNode, expected []ExpectedMenuLink, depth int) {
+	for i, n := range nodes {
+		url := n.Url
+		var children []ExpectedMenuLink
+		if i < len(expected) {
+			children = expected[i].Children
+			if expected[i].Url == "" {
+				url = ""
+			}
+		}
+		writeMenuLine(b, depth, n.Title, url)
+		writeMenu(b, n.Children, children, depth+1)
+	}
+}
+
+// writeMenuLine writes the title, and url if not empty, of a link indented by depth
+func writeMenuLine(b *strings.Builder, depth int, title, url string) {
+	b.WriteString(strings.Repeat("  ", depth) + title)
+	if url != "" {
+		b.WriteString(" (" + url + ")")
+	}
+	b.WriteString("\n")
+}
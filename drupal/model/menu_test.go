@@ -0,0 +1,52 @@
+package model
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// menuLinkElement answers a JSON API data element for a custom menu link of the main menu
+func menuLinkElement(id, title, uri, parentId string, weight int) string {
+	parent := ""
+	if parentId != "" {
+		parent = "menu_link_content:" + parentId
+	}
+	return fmt.Sprintf(`{"type": "menu_link_content--menu_link_content", "id": "%s", "attributes": {"title": "%s",
+		"link": {"uri": "%s", "title": null, "options": []}, "parent": "%s", "weight": %d, "menu_name": "main",
+		"enabled": true}}`, id, title, uri, parent, weight)
+}
+
+func Test_MenuTree(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/jsonapi/menu_link_content/menu_link_content", r.URL.Path)
+		assert.Equal(t, "main", r.URL.Query().Get("filter[menu_name]"))
+		_, _ = fmt.Fprintf(w, `{"data": [%s, %s, %s, %s, %s]}`,
+			menuLinkElement(testUuid(1), "About", "internal:/about", "", 5),
+			menuLinkElement(testUuid(2), "Contact", "internal:/contact", testUuid(1), 1),
+			menuLinkElement(testUuid(3), "Browse Collections", "internal:/collections", "", 0),
+			menuLinkElement(testUuid(4), "History", "internal:/history", testUuid(1), 0),
+			menuLinkElement(testUuid(5), "Home", "internal:/", "standard.front_page", 0))
+	}))
+	defer server.Close()
+	setBaseUrl(t, server)
+
+	tree := MenuTree(t, "main")
+	require.Equal(t, 3, len(tree))
+	assert.Equal(t, "Browse Collections", tree[0].Title)
+	assert.Equal(t, "Home", tree[1].Title, "a link beneath a link provided by a module is expected at the top level")
+	assert.Equal(t, "About", tree[2].Title)
+	require.Equal(t, 2, len(tree[2].Children))
+	assert.Equal(t, "History", tree[2].Children[0].Title)
+	assert.Equal(t, "internal:/contact", tree[2].Children[1].Url)
+
+	assert.True(t, AssertMenuTree(t, "main", []ExpectedMenuLink{
+		{Title: "Browse Collections", Url: "internal:/collections"},
+		{Title: "Home"},
+		{Title: "About", Children: []ExpectedMenuLink{{Title: "History"}, {Title: "Contact", Url: "internal:/contact"}}},
+	}))
+}
@@ -7,15 +7,23 @@
 // The 'Expected' structs are simple JSON objects whose values (e.g. title, name, or description) are predetermined
 // by known data that were migrated into Drupal (e.g. via a migration) for the purpose of testing.  To assert whether
 // an expected repository object was actually ingested, the JsonApiIslandoraObj would be compared to the ExpectedRepoObj.
+//
+// Functions accepting a *testing.T report failures to the test.  Outside of tests, e.g. in a command line tool, a
+// Client finds and resolves entities, answering errors instead.
 package model
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"strconv"
+	"sync"
 	"testing"
 
 	"github.com/jhu-idc/idc-golang/drupal/env"
 	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/stretchr/testify/assert"
 )
 
 const (
@@ -52,70 +60,70 @@ type JsonApiData struct {
 }
 
 // Resolve the reference of the data object, useful for references appearing within JSON API `relationships`.  This
-// function formulates a JSON API query based on the type, bundle, and unique identifier of the object, and returns
-// exactly one resource.
-func (jad *JsonApiData) Resolve(t *testing.T, v interface{}) {
-	u := jsonapi.JsonApiUrl{
-		T:            t,
-		// TODO FIXME the BaseUrl won't work as expected. Really the caller wants the BaseUrl that was used to retrieve
-		//   the JsonApiData, which means we really need access to the JSON API 'links' object and use the 'self' href.
-		//   But we can't do that easily right now.
-		BaseUrl:      env.BaseUrlOr("https://islandora-idc.traefik.me"),
-		DrupalEntity: jad.Type.Entity(),
-		DrupalBundle: jad.Type.Bundle(),
-		Filter:       "id",
-		Value:        jad.Id,
+// function requests the canonical path of the object, `/jsonapi/{entity}/{bundle}/{uuid}`, based on its type, bundle,
+// and unique identifier, and returns exactly one resource.  The data object is validated prior to issuing the query (see Validate).
+//
+// If the environment variable 'DRUPAL_USERNAME' is set, the request is issued with HTTP Basic Auth using it and the
+// value of 'DRUPAL_PASSWORD'.  Use ResolveAnonymous for requests that must be unauthenticated.  Options may override
+// the base url, credentials, or timeout of the request (see Option).
+func (jad *JsonApiData) Resolve(t *testing.T, v interface{}, opts ...Option) {
+	if err := jad.Validate(); err != nil {
+		assert.Fail(t, fmt.Sprintf("unable to resolve relationship: %s", err))
+		return
 	}
 
-	u.GetSingle(v)
+	err := jad.ResolveErr(v, opts...)
+	assert.Nil(t, err, "%s", err)
+}
+
+// ResolveAnonymous behaves as Resolve, but always issues an unauthenticated request, regardless of the environment
+func (jad *JsonApiData) ResolveAnonymous(t *testing.T, v interface{}) {
+	jad.Resolve(t, v, WithAnonymous())
 }
 
 // ResolveWithBasicAuth behaves as Resolve, but issues the request with HTTP Basic Auth, using the supplied username and
-// password
+// password.  If the supplied username is empty, the request is unauthenticated.
 func (jad *JsonApiData) ResolveWithBasicAuth(t *testing.T, v interface{}, username string, password string) {
+	jad.Resolve(t, v, WithCredentials(username, password))
+}
+
+// ResolveWithCredentials behaves as ResolveWithBasicAuth, but authenticates using the supplied credentials, e.g.
+// env.AdminCredentials().  Incomplete credentials fail the test with the environment variable that needs to be set,
+// rather than issuing a request that Drupal would refuse.
+func (jad *JsonApiData) ResolveWithCredentials(t *testing.T, v interface{}, c env.Credentials) {
+	if err := c.Validate(); err != nil {
+		assert.Fail(t, fmt.Sprintf("unable to resolve relationship: %s", err))
+		return
+	}
+	jad.Resolve(t, v, WithUser(c))
+}
+
+// url answers the JsonApiUrl used to resolve the data object, authenticating with the supplied username and password,
+// and customized by the supplied options
+func (jad *JsonApiData) url(username, password string, opts ...Option) jsonapi.JsonApiUrl {
 	u := jsonapi.JsonApiUrl{
-		T:            t,
 		// TODO FIXME the BaseUrl won't work as expected. Really the caller wants the BaseUrl that was used to retrieve
 		//   the JsonApiData, which means we really need access to the JSON API 'links' object and use the 'self' href.
 		//   But we can't do that easily right now.
-		BaseUrl:      env.BaseUrlOr("https://islandora-idc.traefik.me"),
+		BaseUrl:      env.BaseUrlOr(defaultBaseUrl),
 		DrupalEntity: jad.Type.Entity(),
 		DrupalBundle: jad.Type.Bundle(),
-		Filter:       "id",
-		Value:        jad.Id,
+		Uuid:         jad.Id,
 		Username:     username,
 		Password:     password,
 	}
-
-	u.GetSingle(v)
+	for _, opt := range opts {
+		opt(&u)
+	}
+	return u
 }
 
 // Represents the results of a JSONAPI query for a single Person from the Person Taxonomy
 type JsonApiPerson struct {
 	JsonApiData []struct {
-		Type              jsonapi.DrupalType
-		Id                string
-		JsonApiAttributes struct {
-			Name        string   `json:"name"`
-			Dates       []string `json:"field_date"`
-			Description struct {
-				Value     string
-				Format    string
-				Processed string
-			}
-			PrimaryPartOfName       string   `json:"field_primary_part_of_name"`
-			PreferredNamePrefix     []string `json:"field_preferred_name_prefix"`
-			PreferredNameRest       []string `json:"field_preferred_name_rest"`
-			PreferredNameSuffix     []string `json:"field_preferred_name_suffix"`
-			PreferredNameFullerForm []string `json:"field_preferred_name_fuller_form"`
-			PreferredNameNumber     []string `json:"field_preferred_name_number"`
-			PersonAlternateName     []string `json:"field_person_alternate_name"`
-			Authority               []struct {
-				Uri    string
-				Title  string
-				Source string
-			} `json:"field_authority_link"`
-		} `json:"attributes"`
+		Type                 jsonapi.DrupalType
+		Id                   string
+		JsonApiAttributes    JsonApiPersonAttributes `json:"attributes"`
 		JsonApiRelationships struct {
 			Relationships struct {
 				Data []struct {
@@ -134,16 +142,8 @@ type JsonApiAccessRights struct {
 		Id                string
 		JsonApiAttributes struct {
 			Name        string
-			Description struct {
-				Value     string
-				Format    string
-				Processed string
-			}
-			Authority []struct {
-				Uri    string
-				Title  string
-				Source string
-			} `json:"field_authority_link"`
+			Description FormattedText
+			Authority   []Authority `json:"field_authority_link"`
 		} `json:"attributes"`
 	} `json:"data"`
 }
@@ -155,11 +155,7 @@ type JsonApiIslandoraAccessTerms struct {
 		Id                string
 		JsonApiAttributes struct {
 			Name        string
-			Description struct {
-				Value     string
-				Format    string
-				Processed string
-			}
+			Description FormattedText
 		} `json:"attributes"`
 		JsonApiRelationships struct {
 			AccessTerms struct {
@@ -176,16 +172,8 @@ type JsonApiCopyrightAndUse struct {
 		Id                string
 		JsonApiAttributes struct {
 			Name        string
-			Description struct {
-				Value     string
-				Format    string
-				Processed string
-			}
-			Authority []struct {
-				Uri    string
-				Title  string
-				Source string
-			} `json:"field_authority_link"`
+			Description FormattedText
+			Authority   []Authority `json:"field_authority_link"`
 		} `json:"attributes"`
 	} `json:"data"`
 }
@@ -200,16 +188,8 @@ type JsonApiFamily struct {
 			Date        []string `json:"field_date"`
 			FamilyName  string   `json:"field_family_name"`
 			Title       string   `json:"field_title_and_other_words"`
-			Description struct {
-				Value     string
-				Format    string
-				Processed string
-			}
-			Authority []struct {
-				Uri    string
-				Title  string
-				Source string
-			} `json:"field_authority_link"`
+			Description FormattedText
+			Authority   []Authority `json:"field_authority_link"`
 		} `json:"attributes"`
 		JsonApiRelationships struct {
 			Relationships struct {
@@ -228,6 +208,8 @@ type JsonApiCollection struct {
 		Type              jsonapi.DrupalType
 		Id                string
 		JsonApiAttributes struct {
+			JsonApiNodeAttributes
+			JsonApiNodeRevisionAttributes
 			Title       string
 			Description struct {
 				Value    string
@@ -236,10 +218,11 @@ type JsonApiCollection struct {
 			ContactEmail     string   `json:"field_collection_contact_email"`
 			ContactName      string   `json:"field_collection_contact_name"`
 			CollectionNumber []string `json:"field_collection_number"`
-			FindingAid       []struct {
-				Uri   string
-				Title string
-			} `json:"field_finding_aid"`
+			FindingAid       []Link   `json:"field_finding_aid"`
+			// Whether the collection is featured on the home page
+			FeaturedItem bool `json:"field_featured_item"`
+			// The position of the collection amongst its siblings
+			Weight int `json:"field_weight"`
 		} `json:"attributes"`
 		JsonApiRelationships struct {
 			AltTitle struct {
@@ -267,6 +250,10 @@ type JsonApiCollection struct {
 			MemberOf struct {
 				Data JsonApiData
 			} `json:"field_member_of"`
+			// The media whose image represents the collection, e.g. as its thumbnail when browsing collections
+			RepresentativeImage struct {
+				Data JsonApiData
+			} `json:"field_representative_image"`
 		} `json:"relationships"`
 	} `json:"data"`
 }
@@ -277,6 +264,8 @@ type JsonApiIslandoraObj struct {
 		Type              jsonapi.DrupalType
 		Id                string
 		JsonApiAttributes struct {
+			JsonApiNodeAttributes
+			JsonApiNodeRevisionAttributes
 			Title             string
 			CollectionNumber  []string `json:"field_collection_number"`
 			DateAvailable     string   `json:"field_date_available"`
@@ -284,37 +273,24 @@ type JsonApiIslandoraObj struct {
 			DateCreated       []string `json:"field_date_created"`
 			DatePublished     []string `json:"field_date_published"`
 			DigitalIdentifier []string `json:"field_digital_identifier"`
-			DspaceIdentifier  struct {
-				Uri   string
-				Title string
-			} `json:"field_dspace_identifier"`
-			DspaceItemid string `json:"field_dspace_item_id"`
-			Description  string
-			Extent       []string `json:"field_extent"`
-			FeaturedItem bool     `json:"field_featured_item"`
-			FindingAid   []struct {
-				Uri   string
-				Title string
-			} `json:"field_finding_aid"`
-			GeoportalLink struct {
-				Uri   string
-				Title string
-			} `json:"field_geoportal_link"`
+			DspaceIdentifier  Link     `json:"field_dspace_identifier"`
+			DspaceItemid      string   `json:"field_dspace_item_id"`
+			Description       string
+			Extent            []string `json:"field_extent"`
+			FeaturedItem      bool     `json:"field_featured_item"`
+			FindingAid        []Link   `json:"field_finding_aid"`
+			GeoportalLink     Link     `json:"field_geoportal_link"`
 			// TODO
 			IsPartOf struct {
 				Uri string
 			} `json:"field_is_part_of"`
-			Issn        string   `json:"field_issn"`
-			ItemBarcode []string `json:"field_item_barcode"`
-			JhirUri     struct {
-				Uri   string
-				Title string
-			} `json:"field_jhir"`
-			LibraryCatalogLink []struct {
-				Uri   string
-				Title string
-			} `json:"field_library_catalog_link"`
-			OclcNumber []string `json:"field_oclc_number"`
+			Issn               string   `json:"field_issn"`
+			ItemBarcode        []string `json:"field_item_barcode"`
+			JhirUri            Link     `json:"field_jhir"`
+			LibraryCatalogLink []Link   `json:"field_library_catalog_link"`
+			OclcNumber         []string `json:"field_oclc_number"`
+			// The position of the object amongst its siblings, e.g. the page number of a page of paged content
+			Weight int `json:"field_weight"`
 		} `json:"attributes"`
 		JsonApiRelationships struct {
 			Abstract struct {
@@ -397,16 +373,8 @@ type JsonApiGenre struct {
 		Id                string
 		JsonApiAttributes struct {
 			Name        string
-			Description struct {
-				Value     string
-				Format    string
-				Processed string
-			}
-			Authority []struct {
-				Uri    string
-				Title  string
-				Source string
-			} `json:"field_authority_link"`
+			Description FormattedText
+			Authority   []Authority `json:"field_authority_link"`
 		} `json:"attributes"`
 	} `json:"data"`
 }
@@ -417,22 +385,12 @@ type JsonApiGeolocation struct {
 		Type              jsonapi.DrupalType
 		Id                string
 		JsonApiAttributes struct {
-			Name    string
-			Broader []struct {
-				Uri   string
-				Title string
-			} `json:"field_broader"`
+			Name        string
+			Broader     []Link   `json:"field_broader"`
 			GeoAltName  []string `json:"field_geo_alt_name"`
-			Description struct {
-				Value     string
-				Format    string
-				Processed string
-			}
-			Authority []struct {
-				Uri    string
-				Title  string
-				Source string
-			} `json:"field_authority_link"`
+			Description FormattedText
+			Authority   []Authority `json:"field_authority_link"`
+			Coordinates *Geofield   `json:"field_coordinates"`
 		} `json:"attributes"`
 	} `json:"data"`
 }
@@ -444,16 +402,8 @@ type JsonApiResourceType struct {
 		Id                string
 		JsonApiAttributes struct {
 			Name        string
-			Description struct {
-				Value     string
-				Format    string
-				Processed string
-			}
-			Authority []struct {
-				Uri    string
-				Title  string
-				Source string
-			} `json:"field_authority_link"`
+			Description FormattedText
+			Authority   []Authority `json:"field_authority_link"`
 		} `json:"attributes"`
 	} `json:"data"`
 }
@@ -465,16 +415,8 @@ type JsonApiSubject struct {
 		Id                string
 		JsonApiAttributes struct {
 			Name        string
-			Description struct {
-				Value     string
-				Format    string
-				Processed string
-			}
-			Authority []struct {
-				Uri    string
-				Title  string
-				Source string
-			} `json:"field_authority_link"`
+			Description FormattedText
+			Authority   []Authority `json:"field_authority_link"`
 		} `json:"attributes"`
 	} `json:"data"`
 }
@@ -487,16 +429,8 @@ type JsonApiLanguage struct {
 		JsonApiAttributes struct {
 			Name         string
 			LanguageCode string `json:"field_language_code"`
-			Description  struct {
-				Value     string
-				Format    string
-				Processed string
-			}
-			Authority []struct {
-				Uri    string
-				Title  string
-				Source string
-			} `json:"field_authority_link"`
+			Description  FormattedText
+			Authority    []Authority `json:"field_authority_link"`
 		} `json:"attributes"`
 	} `json:"data"`
 }
@@ -537,12 +471,49 @@ type JsonApiLanguageValue struct {
 	}
 }
 
+// Caches language codes keyed by the base url and the UUID of their Language Taxonomy entity
+var langCodes = sync.Map{}
+
 // Answers the language code of the value string by resolving the Language Taxonomy entity identified in the
-// JsonApiLanguageValue
+// JsonApiLanguageValue.  Language codes are cached by the base url and the identifier of the Language Taxonomy entity,
+// so only the first lookup for a given language results in a request.
 func (lv JsonApiLanguageValue) LangCode(t *testing.T) string {
-	jsonApiLang := JsonApiLanguage{}
-	lv.Resolve(t, &jsonApiLang)
-	return jsonApiLang.JsonApiData[0].JsonApiAttributes.LanguageCode
+	code, err := lv.langCodeErr()
+	assert.Nil(t, err, "unable to resolve language %s: %s", lv.Id, err)
+	return code
+}
+
+// langCodeErr behaves as LangCode, resolving the Language Taxonomy entity according to the options, but answers an
+// error instead of making assertions
+func (lv JsonApiLanguageValue) langCodeErr(opts ...Option) (string, error) {
+	key := langCodeKey(opts, lv.Id)
+	if code, ok := langCodes.Load(key); ok {
+		loggerOf(opts).Log("model language cache hit", "id", lv.Id, "code", code)
+		return code.(string), nil
+	}
+
+	jsonApiLang, err := ResolveAsErr[JsonApiLanguage](lv.JsonApiData, opts...)
+	if err != nil {
+		return "", err
+	}
+
+	code := jsonApiLang.JsonApiData[0].JsonApiAttributes.LanguageCode
+	langCodes.Store(key, code)
+	return code, nil
+}
+
+// langCodeKey answers the key of the cached language code of the Language Taxonomy entity with the UUID, on the site
+// the options request (see baseUrlOf)
+func langCodeKey(opts []Option, id string) string {
+	return fmt.Sprintf("%s %s", baseUrlOf(opts), id)
+}
+
+// ResetLangCodeCache discards the language codes cached by JsonApiLanguageValue.LangCode
+func ResetLangCodeCache() {
+	langCodes.Range(func(key, _ interface{}) bool {
+		langCodes.Delete(key)
+		return true
+	})
 }
 
 // Answers the value of the string, the language of which is provided by langCode(...)
@@ -556,24 +527,16 @@ type JsonApiCorporateBody struct {
 		Type              jsonapi.DrupalType
 		Id                string
 		JsonApiAttributes struct {
-			Name        string
-			Description struct {
-				Value     string
-				Format    string
-				Processed string
-			}
-			Authority []struct {
-				Uri    string
-				Title  string
-				Source string
-			} `json:"field_authority_link"`
-			PrimaryName     string   `json:"field_primary_name"`
-			SubordinateName []string `json:"field_subordinate_name"`
-			Location        []string `json:"field_location_of_meeting"`
-			NumberOrSection []string `json:"field_num_of_section_or_meet"`
-			DateOfMeeting   []string `json:"field_date_of_meeting_or_treaty"`
-			AltName         []string `json:"field_corporate_body_alt_name"`
-			Date            []string `json:"field_date"`
+			Name            string
+			Description     FormattedText
+			Authority       []Authority `json:"field_authority_link"`
+			PrimaryName     string      `json:"field_primary_name"`
+			SubordinateName []string    `json:"field_subordinate_name"`
+			Location        []string    `json:"field_location_of_meeting"`
+			NumberOrSection []string    `json:"field_num_of_section_or_meet"`
+			DateOfMeeting   []string    `json:"field_date_of_meeting_or_treaty"`
+			AltName         []string    `json:"field_corporate_body_alt_name"`
+			Date            []string    `json:"field_date"`
 		} `json:"attributes"`
 		JsonApiRelationships struct {
 			Relationships struct {
@@ -592,15 +555,8 @@ type JsonApiIslandoraModel struct {
 		Id                string
 		JsonApiAttributes struct {
 			Name        string
-			Description struct {
-				Value     string
-				Format    string
-				Processed string
-			}
-			ExternalUri struct {
-				Uri   string
-				Title string
-			} `json:"field_external_uri"`
+			Description FormattedText
+			ExternalUri Link `json:"field_external_uri"`
 		} `json:"attributes"`
 	} `json:"data"`
 }
@@ -611,15 +567,8 @@ type JsonApiIslandoraDisplay struct {
 		Id                string
 		JsonApiAttributes struct {
 			Name        string
-			Description struct {
-				Value     string
-				Format    string
-				Processed string
-			}
-			ExternalUri struct {
-				Uri   string
-				Title string
-			} `json:"field_external_uri"`
+			Description FormattedText
+			ExternalUri Link `json:"field_external_uri"`
 		} `json:"attributes"`
 	} `json:"data"`
 }
@@ -648,36 +597,78 @@ func (rd RelData) MetaString(field string) (string, error) {
 	return "", fmt.Errorf("%w: %s", ErrMissing, field)
 }
 
+// MetaInt answers the named meta value as an integer.  Because encoding/json decodes JSON numbers as float64 (or as
+// json.Number, if UseNumber is enabled), those values are accepted as long as they are integral.
 func (rd RelData) MetaInt(field string) (int, error) {
 	if value, exists := rd.Meta[field]; exists {
-		if intVal, ok := value.(int); ok {
-			return intVal, nil
+		switch v := value.(type) {
+		case int:
+			return v, nil
+		case float64:
+			if v == math.Trunc(v) && v >= math.MinInt && v < math.MaxInt {
+				return int(v), nil
+			}
+		case json.Number:
+			if intVal, err := strconv.Atoi(v.String()); err == nil {
+				return intVal, nil
+			}
+		}
+		return -1, fmt.Errorf("%w: %v to int", ErrConversion, value)
+	}
+
+	return -1, fmt.Errorf("%w: %s", ErrMissing, field)
+}
+
+func (rd RelData) MetaFloat(field string) (float64, error) {
+	if value, exists := rd.Meta[field]; exists {
+		if floatVal, ok := value.(float64); ok {
+			return floatVal, nil
 		} else {
-			return -1, fmt.Errorf("%w: %v to int", ErrConversion, value)
+			return -1, fmt.Errorf("%w: %v to float64", ErrConversion, value)
 		}
 	}
 
 	return -1, fmt.Errorf("%w: %s", ErrMissing, field)
 }
 
-// https://islandora-idc.traefik.me/jsonapi/media/image?filter[id]=090690a5-4db5-4d72-a94e-3b26a90b516b
-type JsonApiImageMedia struct {
-	JsonApiData []struct {
-		Type              jsonapi.DrupalType
-		Id                string
-		JsonApiAttributes struct {
-			JsonApiMediaAttributes
-			JsonApiImageMediaAttributes
-		} `json:"attributes"`
-		JsonApiRelationships struct {
-			JsonApiMediaRelationships
-			File struct {
-				Data RelData
-			} `json:"field_media_image"`
-		} `json:"relationships"`
-	} `json:"data"`
+func (rd RelData) MetaBool(field string) (bool, error) {
+	if value, exists := rd.Meta[field]; exists {
+		if boolVal, ok := value.(bool); ok {
+			return boolVal, nil
+		} else {
+			return false, fmt.Errorf("%w: %v to bool", ErrConversion, value)
+		}
+	}
+
+	return false, fmt.Errorf("%w: %s", ErrMissing, field)
 }
 
+func (rd RelData) MetaStringSlice(field string) ([]string, error) {
+	if value, exists := rd.Meta[field]; exists {
+		switch values := value.(type) {
+		case []string:
+			return values, nil
+		case []interface{}:
+			result := make([]string, len(values))
+			for i, v := range values {
+				if strVal, ok := v.(string); ok {
+					result[i] = strVal
+				} else {
+					return nil, fmt.Errorf("%w: %v to []string", ErrConversion, value)
+				}
+			}
+			return result, nil
+		default:
+			return nil, fmt.Errorf("%w: %v to []string", ErrConversion, value)
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrMissing, field)
+}
+
+// https://islandora-idc.traefik.me/jsonapi/media/image?filter[id]=090690a5-4db5-4d72-a94e-3b26a90b516b
+type JsonApiImageMedia = MediaDocument[imageMediaAttributes, imageMedia]
+
 type JsonApiMediaAttributes struct {
 	FileSize         int    `json:"field_file_size"`
 	MimeType         string `json:"field_mime_type"`
@@ -703,79 +694,51 @@ type JsonApiImageMediaAttributes struct {
 	Width  int `json:"field_width"`
 }
 
-type JsonApiDocumentMedia struct {
-	JsonApiData []struct {
-		Type              jsonapi.DrupalType
-		Id                string
-		JsonApiAttributes struct {
-			JsonApiMediaAttributes
-		} `json:"attributes"`
-		JsonApiRelationships struct {
-			JsonApiMediaRelationships
-			File struct {
-				Data RelData
-			} `json:"field_media_document"`
-		} `json:"relationships"`
-	} `json:"data"`
+type JsonApiAudioMediaAttributes struct {
+	// The duration of the audio in seconds; Drupal serializes decimal values as strings
+	Duration json.Number `json:"field_duration"`
 }
 
-type JsonApiAudioMedia struct {
-	JsonApiData []struct {
-		Type              jsonapi.DrupalType
-		Id                string
-		JsonApiAttributes struct {
-			JsonApiMediaAttributes
-		} `json:"attributes"`
-		JsonApiRelationships struct {
-			JsonApiMediaRelationships
-			File struct {
-				Data RelData
-			} `json:"field_media_audio_file"`
-		} `json:"relationships"`
-	} `json:"data"`
+type JsonApiVideoMediaAttributes struct {
+	// The duration of the video in seconds; Drupal serializes decimal values as strings
+	Duration json.Number `json:"field_duration"`
+	Height   int         `json:"field_height"`
+	Width    int         `json:"field_width"`
 }
 
-type JsonApiExtractedTextMedia struct {
-	JsonApiData []struct {
-		Type              jsonapi.DrupalType
-		Id                string
-		JsonApiAttributes struct {
-			JsonApiMediaAttributes
-			JsonApiExtractedTextMediaAttributes
-		} `json:"attributes"`
-		JsonApiRelationships struct {
-			JsonApiMediaRelationships
-			File struct {
-				Data RelData
-			} `json:"field_media_file"`
-		} `json:"relationships"`
-	} `json:"data"`
+// DurationSeconds answers the duration of the audio in seconds, or zero if the duration is not present
+func (a JsonApiAudioMediaAttributes) DurationSeconds() (float64, error) {
+	return durationSeconds(a.Duration)
 }
 
-type JsonApiExtractedTextMediaAttributes struct {
-	EditedText struct {
-		Value     string
-		Format    string
-		Processed string
-	} `json:"field_edited_text"`
+// DurationSeconds answers the duration of the video in seconds, or zero if the duration is not present
+func (v JsonApiVideoMediaAttributes) DurationSeconds() (float64, error) {
+	return durationSeconds(v.Duration)
 }
 
-type JsonApiGenericFileMedia struct {
-	JsonApiData []struct {
-		Type              jsonapi.DrupalType
-		Id                string
-		JsonApiAttributes struct {
-			JsonApiMediaAttributes
-		} `json:"attributes"`
-		JsonApiRelationships struct {
-			JsonApiMediaRelationships
-			File struct {
-				Data RelData
-			} `json:"field_media_file"`
-		} `json:"relationships"`
-	} `json:"data"`
+func durationSeconds(duration json.Number) (float64, error) {
+	if duration == "" {
+		return 0, nil
+	}
+	if seconds, err := duration.Float64(); err != nil {
+		return 0, fmt.Errorf("%w: %v to float64", ErrConversion, duration)
+	} else {
+		return seconds, nil
+	}
+}
+
+type JsonApiDocumentMedia = MediaDocument[fileMediaAttributes, documentMedia]
+
+type JsonApiAudioMedia = MediaDocument[audioMediaAttributes, audioMedia]
+
+type JsonApiExtractedTextMedia = MediaDocument[extractedTextMediaAttributes, extractedTextMedia]
+
+type JsonApiExtractedTextMediaAttributes struct {
+	EditedText FormattedText `json:"field_edited_text"`
 }
 
+type JsonApiGenericFileMedia = MediaDocument[fileMediaAttributes, genericFileMedia]
+
 type JsonApiRemoteVideoMedia struct {
 	JsonApiData []struct {
 		Type              jsonapi.DrupalType
@@ -791,37 +754,13 @@ type JsonApiRemoteVideoMedia struct {
 	} `json:"data"`
 }
 
-type JsonApiVideoMedia struct {
-	JsonApiData []struct {
-		Type              jsonapi.DrupalType
-		Id                string
-		JsonApiAttributes struct {
-			JsonApiMediaAttributes
-		} `json:"attributes"`
-		JsonApiRelationships struct {
-			JsonApiMediaRelationships
-			File struct {
-				Data RelData
-			} `json:"field_media_video_file"`
-		} `json:"relationships"`
-	} `json:"data"`
-}
+type JsonApiVideoMedia = MediaDocument[videoMediaAttributes, videoMedia]
 
 type JsonApiFile struct {
 	JsonApiData []struct {
 		Type              jsonapi.DrupalType
 		Id                string
-		JsonApiAttributes struct {
-			Filename string
-			Uri      struct {
-				Url   string
-				Value string
-			}
-			MimeType    string `json:"filemime"`
-			FileSize    int
-			CreatedDate string `json:"created"`
-			ChangedDate string `json:"changed"`
-		} `json:"attributes"`
+		JsonApiAttributes JsonApiFileAttributes `json:"attributes"`
 	} `json:"data"`
 }
 
@@ -831,33 +770,12 @@ type JsonApiMediaUse struct {
 		Id                string
 		JsonApiAttributes struct {
 			Name        string
-			Description struct {
-				Value     string
-				Format    string
-				Processed string
-			}
-			ExternalUri struct {
-				Uri   string
-				Title string
-			} `json:"field_external_uri"`
+			Description FormattedText
+			ExternalUri Link `json:"field_external_uri"`
 		} `json:"attributes"`
 		JsonApiRelationships struct {
 		} `json:"relationships"`
 	} `json:"data"`
 }
 
-type JsonApiFitsMedia struct {
-	JsonApiData []struct {
-		Type              jsonapi.DrupalType
-		Id                string
-		JsonApiAttributes struct {
-			JsonApiMediaAttributes
-		} `json:"attributes"`
-		JsonApiRelationships struct {
-			JsonApiMediaRelationships
-			File struct {
-				Data RelData
-			} `json:"field_media_file"`
-		} `json:"relationships"`
-	} `json:"data"`
-}
+type JsonApiFitsMedia = MediaDocument[fileMediaAttributes, fitsMedia]
@@ -0,0 +1,176 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// relationship meta as decoded from a JSON API document
+const relMeta = `{
+  "type": "taxonomy_term--person",
+  "id": "7397e0c4-df0a-4800-95af-afccc6ff64a5",
+  "meta": {
+    "rel_type": "relators:pht",
+    "display": true,
+    "weight": 2.5,
+    "roles": ["creator", "photographer"],
+    "mixed": ["creator", 1]
+  }
+}`
+
+func decodeRelData(t *testing.T) RelData {
+	rd := RelData{}
+	require.Nil(t, json.Unmarshal([]byte(relMeta), &rd))
+	return rd
+}
+
+func Test_MetaFloat(t *testing.T) {
+	rd := decodeRelData(t)
+	tests := []struct {
+		field    string
+		expected float64
+		err      error
+	}{
+		{"weight", 2.5, nil},
+		{"missing", -1, ErrMissing},
+		{"rel_type", -1, ErrConversion},
+	}
+
+	for _, test := range tests {
+		t.Run(test.field, func(t *testing.T) {
+			actual, err := rd.MetaFloat(test.field)
+			assert.Equal(t, test.expected, actual)
+			assert.True(t, errors.Is(err, test.err), "expected %v, got %v", test.err, err)
+		})
+	}
+}
+
+func Test_MetaBool(t *testing.T) {
+	rd := decodeRelData(t)
+	tests := []struct {
+		field    string
+		expected bool
+		err      error
+	}{
+		{"display", true, nil},
+		{"missing", false, ErrMissing},
+		{"weight", false, ErrConversion},
+	}
+
+	for _, test := range tests {
+		t.Run(test.field, func(t *testing.T) {
+			actual, err := rd.MetaBool(test.field)
+			assert.Equal(t, test.expected, actual)
+			assert.True(t, errors.Is(err, test.err), "expected %v, got %v", test.err, err)
+		})
+	}
+}
+
+func Test_MetaStringSlice(t *testing.T) {
+	rd := decodeRelData(t)
+	tests := []struct {
+		field    string
+		expected []string
+		err      error
+	}{
+		{"roles", []string{"creator", "photographer"}, nil},
+		{"missing", nil, ErrMissing},
+		{"rel_type", nil, ErrConversion},
+		{"mixed", nil, ErrConversion},
+	}
+
+	for _, test := range tests {
+		t.Run(test.field, func(t *testing.T) {
+			actual, err := rd.MetaStringSlice(test.field)
+			assert.Equal(t, test.expected, actual)
+			assert.True(t, errors.Is(err, test.err), "expected %v, got %v", test.err, err)
+		})
+	}
+}
+
+// A field_creator relationship document, as returned by Drupal, carrying a rel_type and an integral weight
+const creatorRelationship = `{
+  "data": [
+    {
+      "type": "taxonomy_term--person",
+      "id": "7397e0c4-df0a-4800-95af-afccc6ff64a5",
+      "meta": {
+        "rel_type": "relators:pht",
+        "weight": 3
+      }
+    }
+  ],
+  "links": {
+    "self": {
+      "href": "http://islandora-idc.traefik.me/jsonapi/node/islandora_object/815a4c04-0be5-44f1-a876-e8ddc11dcf21/relationships/field_creator"
+    }
+  }
+}`
+
+func Test_MetaInt(t *testing.T) {
+	rel := struct {
+		Data []RelData
+	}{}
+	require.Nil(t, json.Unmarshal([]byte(creatorRelationship), &rel))
+	require.Equal(t, 1, len(rel.Data))
+
+	weight, err := rel.Data[0].MetaInt("weight")
+	assert.Nil(t, err)
+	assert.Equal(t, 3, weight)
+
+	rd := decodeRelData(t)
+	tests := []struct {
+		name     string
+		value    interface{}
+		expected int
+		err      error
+	}{
+		{"int", 7, 7, nil},
+		{"integral float", float64(7), 7, nil},
+		{"json number", json.Number("7"), 7, nil},
+		{"fractional float", 2.5, -1, ErrConversion},
+		{"fractional json number", json.Number("2.5"), -1, ErrConversion},
+		{"string", "7", -1, ErrConversion},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rd.Meta["value"] = test.value
+			actual, err := rd.MetaInt("value")
+			assert.Equal(t, test.expected, actual)
+			assert.True(t, errors.Is(err, test.err), "expected %v, got %v", test.err, err)
+		})
+	}
+
+	_, err = rd.MetaInt("missing")
+	assert.True(t, errors.Is(err, ErrMissing))
+}
+
+func Test_DecodeCollection(t *testing.T) {
+	c := JsonApiCollection{}
+	unmarshalTestdata(t, "collection.json", &c)
+	require.Equal(t, 1, len(c.JsonApiData))
+
+	attrs := c.JsonApiData[0].JsonApiAttributes
+	assert.Equal(t, "Sheridan Libraries Photographs", attrs.Title)
+	assert.True(t, attrs.Status)
+	assert.True(t, attrs.FeaturedItem)
+	assert.Equal(t, 3, attrs.Weight)
+	assert.Equal(t, 31, attrs.Nid)
+	assert.Equal(t, []Link{{Uri: "https://aspace.example.edu/repositories/3/resources/406", Title: "Finding aid"}},
+		attrs.FindingAid)
+	created, err := attrs.CreatedTime()
+	require.Nil(t, err, "%s", err)
+	changed, err := attrs.ChangedTime()
+	require.Nil(t, err, "%s", err)
+	assert.True(t, changed.After(created))
+
+	rels := c.JsonApiData[0].JsonApiRelationships
+	assert.Equal(t, JsonApiData{Type: "media--image", Id: "5b6c7d8e-9f0a-4b1c-8d2e-3f4a5b6c7d8e"},
+		rels.RepresentativeImage.Data)
+	assert.False(t, rels.MemberOf.Data.IsPresent())
+}
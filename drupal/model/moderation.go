@@ -0,0 +1,122 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Content moderation states of the editorial workflow
+const (
+	Draft     = "draft"
+	Published = "published"
+	Archived  = "archived"
+)
+
+// AssertPublished asserts that the supplied node (a JsonApiIslandoraObj or JsonApiCollection, or a pointer to either)
+// is in the published moderation state, has a published status, and is visible to anonymous users.
+func AssertPublished(t *testing.T, node interface{}) bool {
+	return assertModerationState(t, node, Published, true)
+}
+
+// AssertDraft asserts that the supplied node (a JsonApiIslandoraObj or JsonApiCollection, or a pointer to either) is
+// in the draft moderation state, has an unpublished status, and is not visible to anonymous users.
+func AssertDraft(t *testing.T, node interface{}) bool {
+	return assertModerationState(t, node, Draft, false)
+}
+
+// SetModerationState moves the node (e.g. the data object of a JsonApiIslandoraObj) to the moderation state, e.g. Draft
+// or Published, by updating its `moderation_state`, so that a test may verify the visibility of the node in each state.
+// Drupal publishes or unpublishes the node according to the transition of its workflow.  The node is updated as the
+// user from the environment (or as supplied by the options), and the test fails immediately if it cannot be updated:
+//
+//	node := model.JsonApiData{Type: obj.JsonApiData[0].Type, Id: obj.JsonApiData[0].Id}
+//	model.SetModerationState(t, node, model.Published)
+func SetModerationState(t *testing.T, node JsonApiData, state string, opts ...Option) {
+	err := SetModerationStateErr(node, state, opts...)
+	require.Nil(t, err, "%s", err)
+}
+
+// SetModerationStateErr behaves as SetModerationState, but answers an error instead of failing the test.  If the node
+// does not exist, the error wraps jsonapi.ErrNotFound.
+func SetModerationStateErr(node JsonApiData, state string, opts ...Option) error {
+	if err := node.Validate(); err != nil {
+		return err
+	}
+	if node.Type.Entity() != Node {
+		return fmt.Errorf("model: %s entities have no moderation state", node.Type)
+	}
+	if state == "" {
+		return fmt.Errorf("model: unable to moderate %s %s: a moderation state is required", node.Type, node.Id)
+	}
+
+	u := query(nil, Node, node.Type.Bundle(), opts...)
+	err := u.UpdateErr(node.Id, jsonapi.Resource{Attributes: map[string]interface{}{"moderation_state": state}},
+		&json.RawMessage{})
+	if err != nil {
+		return fmt.Errorf("model: unable to move %s %s to the %s moderation state: %w", node.Type, node.Id, state, err)
+	}
+	return nil
+}
+
+// assertModerationState asserts the moderation state and status of the node, and that its visibility to anonymous users
+// agrees with its status
+func assertModerationState(t *testing.T, node interface{}, state string, published bool) bool {
+	jad, attrs, err := nodeAttributes(node)
+	if !assert.Nil(t, err, "%s", err) {
+		return false
+	}
+
+	ok := assert.Equal(t, state, attrs.ModerationState, "unexpected moderation state for %s %s", jad.Type, jad.Id)
+	ok = assert.Equal(t, published, attrs.Status, "unexpected status for %s %s", jad.Type, jad.Id) && ok
+
+	visible, err := anonymouslyVisible(jad)
+	if !assert.Nil(t, err, "%s", err) {
+		return false
+	}
+	if published {
+		return assert.True(t, visible, "%s %s is not visible to anonymous users", jad.Type, jad.Id) && ok
+	}
+	return assert.False(t, visible, "%s %s is visible to anonymous users", jad.Type, jad.Id) && ok
+}
+
+// anonymouslyVisible answers whether the data object can be retrieved without credentials.  Drupal omits resources that
+// are not visible to the requesting user, so an unauthenticated request for an invisible resource answers ErrNotFound,
+// or ErrForbidden if requested by its canonical path.
+func anonymouslyVisible(jad JsonApiData) (bool, error) {
+	u := jad.url("", "")
+	err := u.GetSingleErr(&labeled{})
+	if errors.Is(err, jsonapi.ErrNotFound) || errors.Is(err, jsonapi.ErrForbidden) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// nodeAttributes answers the identity and node attributes of the first data element of the supplied node
+func nodeAttributes(node interface{}) (JsonApiData, JsonApiNodeAttributes, error) {
+	switch n := node.(type) {
+	case *JsonApiIslandoraObj:
+		return nodeAttributes(*n)
+	case *JsonApiCollection:
+		return nodeAttributes(*n)
+	case JsonApiIslandoraObj:
+		if len(n.JsonApiData) == 0 {
+			return JsonApiData{}, JsonApiNodeAttributes{}, fmt.Errorf("model: %T has no data elements", n)
+		}
+		d := n.JsonApiData[0]
+		return JsonApiData{Type: d.Type, Id: d.Id}, d.JsonApiAttributes.JsonApiNodeAttributes, nil
+	case JsonApiCollection:
+		if len(n.JsonApiData) == 0 {
+			return JsonApiData{}, JsonApiNodeAttributes{}, fmt.Errorf("model: %T has no data elements", n)
+		}
+		d := n.JsonApiData[0]
+		return JsonApiData{Type: d.Type, Id: d.Id}, d.JsonApiAttributes.JsonApiNodeAttributes, nil
+	default:
+		return JsonApiData{}, JsonApiNodeAttributes{}, fmt.Errorf("model: %T is not a node", node)
+	}
+}
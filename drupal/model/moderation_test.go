@@ -0,0 +1,69 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_AssertModerationState(t *testing.T) {
+	draftId, publishedId := testUuid(1), testUuid(2)
+
+	// anonymous requests for the draft answer an empty response, as Drupal does for resources the user cannot view
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := requestedId(r)
+		if _, _, authenticated := r.BasicAuth(); id == draftId && !authenticated {
+			_, _ = w.Write([]byte(`{"data": []}`))
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"data": [{"type": "node--islandora_object", "id": "%s"}]}`, id)
+	}))
+	defer server.Close()
+	setBaseUrl(t, server)
+	t.Setenv("DRUPAL_USERNAME", "admin")
+	t.Setenv("DRUPAL_PASSWORD", "moo")
+
+	draft := JsonApiIslandoraObj{}
+	require.Nil(t, json.Unmarshal([]byte(fmt.Sprintf(`{"data": [{"type": "node--islandora_object", "id": "%s",
+		"attributes": {"status": false, "moderation_state": "draft"}}]}`, draftId)), &draft))
+	published := JsonApiIslandoraObj{}
+	require.Nil(t, json.Unmarshal([]byte(fmt.Sprintf(`{"data": [{"type": "node--islandora_object", "id": "%s",
+		"attributes": {"status": true, "moderation_state": "published"}}]}`, publishedId)), &published))
+
+	assert.True(t, AssertDraft(t, draft))
+	assert.True(t, AssertPublished(t, &published))
+
+	visible, err := anonymouslyVisible(JsonApiData{Type: "node--islandora_object", Id: draftId})
+	assert.Nil(t, err)
+	assert.False(t, visible)
+
+	visible, err = anonymouslyVisible(JsonApiData{Type: "node--islandora_object", Id: publishedId})
+	assert.Nil(t, err)
+	assert.True(t, visible)
+}
+
+func Test_SetModerationState(t *testing.T) {
+	d := newFakeDrupal(t, map[string]string{"islandora_models name Image": testUuid(1)})
+	obj := CreateObject(t, ObjectSpec{Title: "Moonrise", ModelName: "Image"})
+	node := JsonApiData{Type: obj.JsonApiData[0].Type, Id: obj.JsonApiData[0].Id}
+
+	SetModerationState(t, node, Published)
+	d.mu.Lock()
+	assert.Equal(t, Published, d.created[node.Id]["attributes"].(map[string]interface{})["moderation_state"])
+	assert.Equal(t, "Moonrise", d.created[node.Id]["attributes"].(map[string]interface{})["title"],
+		"attributes other than the moderation state are expected to be unchanged")
+	d.mu.Unlock()
+
+	err := SetModerationStateErr(JsonApiData{Type: node.Type, Id: testUuid(9)}, Draft)
+	assert.True(t, errors.Is(err, jsonapi.ErrNotFound), "expected ErrNotFound, got %v", err)
+	err = SetModerationStateErr(JsonApiData{Type: "media--image", Id: node.Id}, Draft)
+	assert.Contains(t, fmt.Sprint(err), "have no moderation state")
+	assert.NotNil(t, SetModerationStateErr(node, ""))
+}
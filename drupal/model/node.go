@@ -0,0 +1,84 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+)
+
+// Attributes common to all node entities, e.g. JsonApiIslandoraObj and JsonApiCollection
+type JsonApiNodeAttributes struct {
+	// The node id, e.g. `12` of `/node/12` (see UuidForNid)
+	Nid int `json:"drupal_internal__nid"`
+	// Whether the node is published
+	Status bool `json:"status"`
+	// RFC 3339 timestamp of the node's creation
+	Created string `json:"created"`
+	// RFC 3339 timestamp of the node's last update
+	Changed  string `json:"changed"`
+	Langcode string `json:"langcode"`
+	// Whether the node is promoted to the front page
+	Promote bool `json:"promote"`
+	// The content moderation state of the node, e.g. `draft` or `published`; empty if the node's bundle is not moderated
+	ModerationState string `json:"moderation_state"`
+}
+
+// CreatedTime answers the parsed creation timestamp of the node
+func (na JsonApiNodeAttributes) CreatedTime() (time.Time, error) {
+	return parseTimestamp("created", na.Created)
+}
+
+// ChangedTime answers the parsed timestamp of the node's last update
+func (na JsonApiNodeAttributes) ChangedTime() (time.Time, error) {
+	return parseTimestamp("changed", na.Changed)
+}
+
+// parseTimestamp parses the RFC 3339 value of the named attribute
+func parseTimestamp(attribute, value string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return t, fmt.Errorf("%w: '%s' value %s to time", ErrConversion, attribute, value)
+	}
+	return t, nil
+}
+
+// NodeIdErr answers the node id (`drupal_internal__nid`) of the repository object or collection with the uuid, e.g. to
+// build the urls Drupal addresses by node id, such as `/node/12/manifest`.  The error wraps jsonapi.ErrNotFound if no
+// such node exists.
+func NodeIdErr(nodeUuid string, opts ...Option) (int, error) {
+	for _, bundle := range []string{RepositoryObject, Collection} {
+		node := struct {
+			Data []struct {
+				JsonApiAttributes struct {
+					Nid int `json:"drupal_internal__nid"`
+				} `json:"attributes"`
+			}
+		}{}
+		u := query(nil, Node, bundle, opts...)
+		u.Filter, u.Value = "id", nodeUuid
+		err := u.GetSingleErr(&node)
+		if err == nil {
+			return node.Data[0].JsonApiAttributes.Nid, nil
+		}
+		if !errors.Is(err, jsonapi.ErrNotFound) {
+			return 0, fmt.Errorf("model: unable to find the node id of %s: %w", nodeUuid, err)
+		}
+	}
+	return 0, fmt.Errorf("%w: no repository object or collection %s", jsonapi.ErrNotFound, nodeUuid)
+}
+
+// CanonicalUrlErr answers the canonical url of the repository object or collection with the uuid, e.g.
+// `https://islandora.example.edu/node/12`, which identifies the node in the documents Islandora derives from it (e.g.
+// the JSON-LD indexed into the triplestore)
+func CanonicalUrlErr(nodeUuid string, opts ...Option) (string, error) {
+	nid, err := NodeIdErr(nodeUuid, opts...)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(env.BaseUrlOr(defaultBaseUrl), "/") + "/node/" + strconv.Itoa(nid), nil
+}
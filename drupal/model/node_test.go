@@ -0,0 +1,59 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NodeAttributes(t *testing.T) {
+	obj := JsonApiIslandoraObj{}
+	require.Nil(t, json.Unmarshal([]byte(`{"data": [{"type": "node--islandora_object", "id": "815a4c04-0be5-44f1-a876-e8ddc11dcf21",
+		"attributes": {"title": "Moonrise", "status": true, "promote": false, "langcode": "en",
+		"created": "2021-06-01T12:00:00+00:00", "changed": "2021-06-02T08:30:00-04:00"}}]}`), &obj))
+
+	attrs := obj.JsonApiData[0].JsonApiAttributes
+	assert.True(t, attrs.Status)
+	assert.False(t, attrs.Promote)
+	assert.Equal(t, "en", attrs.Langcode)
+
+	created, err := attrs.CreatedTime()
+	assert.Nil(t, err)
+	assert.True(t, created.Equal(time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)))
+
+	changed, err := attrs.ChangedTime()
+	assert.Nil(t, err)
+	assert.True(t, changed.Equal(time.Date(2021, 6, 2, 12, 30, 0, 0, time.UTC)))
+
+	_, err = JsonApiNodeAttributes{Created: "yesterday"}.CreatedTime()
+	assert.True(t, errors.Is(err, ErrConversion))
+}
+
+func Test_CanonicalUrlErr(t *testing.T) {
+	// the collection is only answered when its bundle is queried
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/jsonapi/node/collection_object" || requestedId(r) != testUuid(1) {
+			_, _ = w.Write([]byte(`{"data": []}`))
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"data": [{"type": "node--collection_object", "id": "%s",
+			"attributes": {"drupal_internal__nid": 12}}]}`, testUuid(1))
+	}))
+	defer server.Close()
+	setBaseUrl(t, server)
+
+	u, err := CanonicalUrlErr(testUuid(1))
+	assert.Nil(t, err, "%s", err)
+	assert.Equal(t, server.URL+"/node/12", u)
+
+	_, err = NodeIdErr(testUuid(2))
+	assert.True(t, errors.Is(err, jsonapi.ErrNotFound), "expected ErrNotFound, got %v", err)
+}
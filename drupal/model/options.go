@@ -0,0 +1,120 @@
+package model
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+)
+
+// An Option customizes the JSONAPI request made on behalf of a single call, e.g. to authenticate as a different user.
+// Options are accepted by the finders (e.g. FindObjectByTitle), the Resolve variants (e.g. Resolve and ResolveAs), and
+// Query, and override the environment, or the Config of the request, for that call only.  Tests need not change the
+// environment of the process to make a different request, so they remain safe to run in parallel:
+//
+//	obj := model.FindObjectByTitle(t, "Moonrise", model.WithAnonymous(), model.WithTimeout(5*time.Second))
+type Option func(u *jsonapi.JsonApiUrl)
+
+// Query answers a JsonApiUrl for the supplied entity and bundle, authenticated using the credentials from the
+// environment, and customized by the supplied options.  The caller supplies the filter:
+//
+//	u := model.Query(t, model.Node, model.RepositoryObject, model.WithConfig(&config))
+//	u.Filter, u.Value = "title", "Moonrise"
+func Query(t *testing.T, entity, bundle string, opts ...Option) jsonapi.JsonApiUrl {
+	return query(t, entity, bundle, opts...)
+}
+
+// WithConfig makes requests according to the supplied Config (see env.Load), e.g. one shared by a suite, in place of
+// the base url and credentials from the environment.  Options following WithConfig override the Config.
+func WithConfig(c *env.Config) Option {
+	return func(u *jsonapi.JsonApiUrl) {
+		u.Config = c
+	}
+}
+
+// WithContext bounds requests by the deadline and cancellation of the context, e.g. the context of a command line tool
+// interrupted by its user
+func WithContext(ctx context.Context) Option {
+	return func(u *jsonapi.JsonApiUrl) {
+		u.Context = ctx
+	}
+}
+
+// WithLogger records the diagnostic events of requests (e.g. their status and duration) and of the model (e.g. cache
+// hits) using the Logger, in place of the Logger set by jsonapi.SetLogger
+func WithLogger(l jsonapi.Logger) Option {
+	return func(u *jsonapi.JsonApiUrl) {
+		u.Logger = l
+	}
+}
+
+// loggerOf answers the Logger supplied by the options, or the Logger set by jsonapi.SetLogger
+func loggerOf(opts []Option) jsonapi.Logger {
+	u := jsonapi.JsonApiUrl{}
+	for _, opt := range opts {
+		opt(&u)
+	}
+	if u.Logger == nil {
+		return jsonapi.DefaultLogger()
+	}
+	return u.Logger
+}
+
+// baseUrlOf answers the base url requests are made to according to the options: the base url supplied by WithBaseUrl,
+// otherwise that of the Config supplied by WithConfig, otherwise the base url from the environment
+func baseUrlOf(opts []Option) string {
+	u := query(nil, "", "", opts...)
+	if !u.ExplicitBaseUrl && u.Config != nil {
+		return u.Config.BaseUrl
+	}
+	return u.BaseUrl
+}
+
+// WithTimeout bounds the time of each request, in place of the timeout of the Config, if any
+func WithTimeout(d time.Duration) Option {
+	return func(u *jsonapi.JsonApiUrl) {
+		u.Timeout = d
+	}
+}
+
+// WithBaseUrl requests resources from the supplied Drupal base url, e.g. `https://islandora-idc.traefik.me`, instead
+// of the base url from the environment
+func WithBaseUrl(baseUrl string) Option {
+	return func(u *jsonapi.JsonApiUrl) {
+		u.BaseUrl = baseUrl
+		u.ExplicitBaseUrl = true
+	}
+}
+
+// WithCredentials authenticates requests using the supplied username and password, instead of the credentials from
+// the environment or the Config.  If the username is empty, requests are anonymous (see WithAnonymous).
+func WithCredentials(username, password string) Option {
+	return func(u *jsonapi.JsonApiUrl) {
+		u.Username = username
+		u.Password = password
+		u.Credentials = nil
+		u.Anonymous = username == ""
+		if u.Config != nil {
+			c := *u.Config
+			c.Credentials = env.Credentials{Username: username, Password: password}
+			u.Config = &c
+		}
+	}
+}
+
+// WithUser authenticates requests using the supplied credentials, e.g. env.AdminCredentials() or
+// env.CredentialsFor("collection_creator").  If the credentials are incomplete, the request fails with the environment
+// variable that needs to be set.
+func WithUser(c env.Credentials) Option {
+	return func(u *jsonapi.JsonApiUrl) {
+		u.Credentials = &c
+		u.Anonymous = false
+	}
+}
+
+// WithAnonymous sends requests without credentials, so that only the resources visible to anonymous users are answered
+func WithAnonymous() Option {
+	return WithCredentials("", "")
+}
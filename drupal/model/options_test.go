@@ -0,0 +1,50 @@
+package model
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_OptionsOverrideConfig(t *testing.T) {
+	t.Parallel()
+	var user string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, _, _ = r.BasicAuth()
+		if requestedId(r) == "slow" {
+			time.Sleep(200 * time.Millisecond)
+		}
+		_, _ = fmt.Fprintf(w, `{"data": [%s]}`, collectionElement(testUuid(0), "Collection", ""))
+	}))
+	defer server.Close()
+
+	config := env.Config{BaseUrl: server.URL, Timeout: time.Second,
+		Credentials: env.Credentials{Username: "suite", Password: "moo"}}
+	require.Nil(t, config.Validate())
+	jad := JsonApiData{Type: "node--collection_object", Id: testUuid(0)}
+
+	collection := ResolveAs[JsonApiCollection](t, jad, WithConfig(&config))
+	assert.Equal(t, "Collection", collection.JsonApiData[0].JsonApiAttributes.Title)
+	assert.Equal(t, "suite", user)
+
+	ResolveAs[JsonApiCollection](t, jad, WithConfig(&config), WithCredentials("admin", "moo"))
+	assert.Equal(t, "admin", user)
+	assert.Equal(t, "suite", config.Credentials.Username, "the shared Config is not expected to change")
+
+	ResolveAs[JsonApiCollection](t, jad, WithConfig(&config), WithAnonymous())
+	assert.Equal(t, "", user)
+
+	jad.Resolve(t, &JsonApiCollection{}, WithConfig(&config), WithUser(env.Credentials{Username: "creator",
+		Password: "moo"}))
+	assert.Equal(t, "creator", user)
+
+	u := Query(t, Node, Collection, WithConfig(&config), WithTimeout(50*time.Millisecond))
+	u.Filter, u.Value = "id", "slow"
+	assert.Contains(t, fmt.Sprint(u.GetSingleErr(&JsonApiCollection{})), "Client.Timeout")
+}
@@ -0,0 +1,100 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// The attributes of a Person taxonomy term
+type JsonApiPersonAttributes struct {
+	Name                    string   `json:"name"`
+	Dates                   []string `json:"field_date"`
+	Description             FormattedText
+	PrimaryPartOfName       string      `json:"field_primary_part_of_name"`
+	PreferredNamePrefix     []string    `json:"field_preferred_name_prefix"`
+	PreferredNameRest       []string    `json:"field_preferred_name_rest"`
+	PreferredNameSuffix     []string    `json:"field_preferred_name_suffix"`
+	PreferredNameFullerForm []string    `json:"field_preferred_name_fuller_form"`
+	PreferredNameNumber     []string    `json:"field_preferred_name_number"`
+	PersonAlternateName     []string    `json:"field_person_alternate_name"`
+	Authority               []Authority `json:"field_authority_link"`
+}
+
+// DisplayName answers the preferred name of the person as it is displayed by the site, assembled from the parts of
+// the name (see displayName)
+func (pa JsonApiPersonAttributes) DisplayName() string {
+	return displayName(pa.PreferredNamePrefix, pa.PrimaryPartOfName, pa.PreferredNameRest, pa.PreferredNameNumber,
+		pa.PreferredNameFullerForm, pa.PreferredNameSuffix)
+}
+
+// DisplayName answers the expected preferred name of the person, assembled from the expected parts of the name using
+// the same rules as JsonApiPersonAttributes.DisplayName
+func (ep ExpectedPerson) DisplayName() string {
+	return displayName(ep.Prefix, ep.PrimaryName, ep.RestOfName, ep.Number, ep.FullerForm, ep.Suffix)
+}
+
+// AssertPerson asserts that the Person taxonomy term carries the display name, alternate names, and dates of the
+// expected person.  Display names are compared rather than the individual parts of the name, so that a migration which
+// distributes the source name across the parts differently, but displays the same name, is not considered a failure.
+func AssertPerson(t *testing.T, expected ExpectedPerson, actual JsonApiPerson) bool {
+	if !assert.Equal(t, 1, len(actual.JsonApiData), "expected exactly one person data element") {
+		return false
+	}
+	attrs := actual.JsonApiData[0].JsonApiAttributes
+
+	ok := assert.Equal(t, expected.DisplayName(), attrs.DisplayName(), "display name of person '%s'", attrs.Name)
+	ok = assert.ElementsMatch(t, expected.AltName, attrs.PersonAlternateName, "alternate names of person '%s'", attrs.Name) && ok
+	return assert.ElementsMatch(t, expected.Date, attrs.Dates, "dates of person '%s'", attrs.Name) && ok
+}
+
+// displayName assembles the parts of a person's name as the site's display formatter does:
+//
+//	[prefixes] primary[, rest] [numbers] [(fuller forms)][, suffix]...
+//
+// e.g. `Dr. King, Martin Luther (Martin Luther King), Jr.` or `Henry VIII`.  Multiple values of a part are joined
+// with a space, except suffixes, which are each preceded by a comma.  Empty values are ignored.
+func displayName(prefix []string, primary string, rest, number, fullerForm, suffix []string) string {
+	sb := strings.Builder{}
+	sb.WriteString(joinNonEmpty(append(nonEmpty(prefix), strings.TrimSpace(primary)), " "))
+
+	if r := joinNonEmpty(rest, " "); r != "" {
+		writeSeparated(&sb, ", ", r)
+	}
+	if n := joinNonEmpty(number, " "); n != "" {
+		writeSeparated(&sb, " ", n)
+	}
+	if f := joinNonEmpty(fullerForm, " "); f != "" {
+		writeSeparated(&sb, " ", "("+f+")")
+	}
+	for _, s := range nonEmpty(suffix) {
+		writeSeparated(&sb, ", ", s)
+	}
+
+	return sb.String()
+}
+
+// writeSeparated writes the value to the builder, preceded by the separator if the builder is not empty
+func writeSeparated(sb *strings.Builder, separator, value string) {
+	if sb.Len() > 0 {
+		sb.WriteString(separator)
+	}
+	sb.WriteString(value)
+}
+
+// nonEmpty answers the trimmed values, omitting those that are empty
+func nonEmpty(values []string) []string {
+	var result []string
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// joinNonEmpty joins the non-empty trimmed values with the separator
+func joinNonEmpty(values []string, separator string) string {
+	return strings.Join(nonEmpty(values), separator)
+}
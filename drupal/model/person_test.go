@@ -0,0 +1,46 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DisplayName(t *testing.T) {
+	tests := []struct {
+		name     string
+		attrs    JsonApiPersonAttributes
+		expected string
+	}{
+		{"primary only", JsonApiPersonAttributes{PrimaryPartOfName: "Plato"}, "Plato"},
+		{"empty rest of name", JsonApiPersonAttributes{PrimaryPartOfName: "Plato", PreferredNameRest: []string{"", " "}}, "Plato"},
+		{"rest of name", JsonApiPersonAttributes{PrimaryPartOfName: "Hopkins", PreferredNameRest: []string{"Johns"}}, "Hopkins, Johns"},
+		{"number", JsonApiPersonAttributes{PrimaryPartOfName: "Henry", PreferredNameNumber: []string{"VIII"}}, "Henry VIII"},
+		{"multiple suffixes", JsonApiPersonAttributes{PrimaryPartOfName: "King", PreferredNameRest: []string{"Martin", "Luther"},
+			PreferredNameSuffix: []string{"Jr.", "Rev."}}, "King, Martin Luther, Jr., Rev."},
+		{"all parts", JsonApiPersonAttributes{PreferredNamePrefix: []string{"Dr."}, PrimaryPartOfName: "King",
+			PreferredNameRest: []string{"Martin Luther"}, PreferredNameFullerForm: []string{"Michael King"},
+			PreferredNameSuffix: []string{"Jr."}}, "Dr. King, Martin Luther (Michael King), Jr."},
+		{"no primary", JsonApiPersonAttributes{PreferredNameRest: []string{"Johns"}}, "Johns"},
+		{"empty", JsonApiPersonAttributes{}, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.attrs.DisplayName())
+		})
+	}
+}
+
+func Test_AssertPerson(t *testing.T) {
+	actual := JsonApiPerson{}
+	require.Nil(t, json.Unmarshal([]byte(`{"data": [{"type": "taxonomy_term--person", "id": "00000001-0000-4000-8000-000000000000",
+		"attributes": {"name": "Hopkins, Johns", "field_primary_part_of_name": "Hopkins",
+		"field_preferred_name_rest": ["Johns"], "field_date": ["1795-05-19/1873-12-24"]}}]}`), &actual))
+
+	expected := ExpectedPerson{PrimaryName: "Hopkins", RestOfName: []string{"Johns"}, Date: []string{"1795-05-19/1873-12-24"}}
+	assert.Equal(t, "Hopkins, Johns", expected.DisplayName())
+	assert.True(t, AssertPerson(t, expected, actual))
+}
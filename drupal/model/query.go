@@ -0,0 +1,29 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+)
+
+// The base url of Drupal used when the environment variable 'DRUPAL_BASE_URL' is unset
+const defaultBaseUrl = env.DefaultBaseUrl
+
+// query answers a JsonApiUrl for the supplied entity and bundle, authenticated using the credentials from the
+// environment, if present (see Resolve), and customized by the supplied options.  Callers are expected to supply the
+// filter.
+func query(t *testing.T, entity, bundle string, opts ...Option) jsonapi.JsonApiUrl {
+	u := jsonapi.JsonApiUrl{
+		T:            t,
+		BaseUrl:      env.BaseUrlOr(defaultBaseUrl),
+		DrupalEntity: entity,
+		DrupalBundle: bundle,
+		Username:     env.UsernameOr(""),
+		Password:     env.PasswordOr(""),
+	}
+	for _, opt := range opts {
+		opt(&u)
+	}
+	return u
+}
@@ -0,0 +1,103 @@
+package model
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+)
+
+const (
+	// Constant for the redirect entity type (which is also its only bundle), provided by the Redirect module
+	Redirect = "redirect"
+	// Constant for the path alias entity type (which is also its only bundle)
+	PathAlias = "path_alias"
+)
+
+// Represents the results of a JSONAPI query for a single redirect
+type JsonApiRedirect struct {
+	JsonApiData []struct {
+		Type              jsonapi.DrupalType
+		Id                string
+		JsonApiAttributes struct {
+			// The path being redirected, e.g. `handle/1774.2/12345`; Drupal stores the path without a leading slash
+			Source struct {
+				Path  string
+				Query interface{}
+			} `json:"redirect_source"`
+			// The target of the redirect, e.g. `entity:node/12` or `internal:/node/12`
+			Target     Link `json:"redirect_redirect"`
+			StatusCode int  `json:"status_code"`
+		} `json:"attributes"`
+	} `json:"data"`
+}
+
+// Represents the results of a JSONAPI query for a single path alias
+type JsonApiPathAlias struct {
+	JsonApiData []struct {
+		Type              jsonapi.DrupalType
+		Id                string
+		JsonApiAttributes struct {
+			// The alias, e.g. `/collections/sheridan-photos`
+			Alias string `json:"alias"`
+			// The system path being aliased, e.g. `/node/12`
+			Path     string `json:"path"`
+			Langcode string `json:"langcode"`
+		} `json:"attributes"`
+	} `json:"data"`
+}
+
+// matches the node id of a link to a node, e.g. `entity:node/12`, `internal:/node/12`, or `/node/12`
+var nodePathPattern = regexp.MustCompile(`^(?:entity:|internal:)?/?node/(\d+)$`)
+
+// FindRedirect retrieves the redirect from the supplied source path, asserting that exactly one redirect matches.  A
+// leading slash on the source path is ignored.
+func FindRedirect(t *testing.T, sourcePath string) JsonApiRedirect {
+	u := query(t, Redirect, Redirect)
+	u.Filter = "redirect_source.path"
+	u.Value = strings.TrimPrefix(sourcePath, "/")
+
+	redirect := JsonApiRedirect{}
+	u.GetSingle(&redirect)
+	return redirect
+}
+
+// FindPathAlias retrieves the path alias with the supplied alias (e.g. `/collections/sheridan-photos`), asserting that
+// exactly one path alias matches.
+func FindPathAlias(t *testing.T, alias string) JsonApiPathAlias {
+	u := query(t, PathAlias, PathAlias)
+	u.Filter = "alias"
+	u.Value = alias
+
+	pathAlias := JsonApiPathAlias{}
+	u.GetSingle(&pathAlias)
+	return pathAlias
+}
+
+// TargetNid answers the node id targeted by the first redirect in the response, and whether the redirect targets a node
+func (r JsonApiRedirect) TargetNid() (int, bool) {
+	if len(r.JsonApiData) == 0 {
+		return 0, false
+	}
+	return nodeId(r.JsonApiData[0].JsonApiAttributes.Target.Uri)
+}
+
+// Nid answers the node id aliased by the first path alias in the response, and whether the alias is of a node
+func (pa JsonApiPathAlias) Nid() (int, bool) {
+	if len(pa.JsonApiData) == 0 {
+		return 0, false
+	}
+	return nodeId(pa.JsonApiData[0].JsonApiAttributes.Path)
+}
+
+// nodeId answers the node id present in the supplied link, and whether the link is to a node
+func nodeId(link string) (int, bool) {
+	m := nodePathPattern.FindStringSubmatch(link)
+	if m == nil {
+		return 0, false
+	}
+	nid, err := strconv.Atoi(m[1])
+	return nid, err == nil
+}
@@ -0,0 +1,55 @@
+package model
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_FindRedirect(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/jsonapi/redirect/redirect":
+			assert.Equal(t, "handle/1774.2/12345", r.URL.Query().Get("filter[redirect_source.path]"))
+			_, _ = w.Write([]byte(`{"data": [{"type": "redirect--redirect", "id": "00000001-0000-4000-8000-000000000000",
+				"attributes": {"redirect_source": {"path": "handle/1774.2/12345", "query": []},
+				"redirect_redirect": {"uri": "entity:node/12", "title": ""}, "status_code": 301}}]}`))
+		case "/jsonapi/path_alias/path_alias":
+			assert.Equal(t, "/collections/sheridan-photos", r.URL.Query().Get("filter[alias]"))
+			_, _ = w.Write([]byte(`{"data": [{"type": "path_alias--path_alias", "id": "00000002-0000-4000-8000-000000000000",
+				"attributes": {"alias": "/collections/sheridan-photos", "path": "/node/12", "langcode": "en"}}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	setBaseUrl(t, server)
+
+	redirect := FindRedirect(t, "/handle/1774.2/12345")
+	require.Equal(t, 1, len(redirect.JsonApiData))
+	assert.Equal(t, 301, redirect.JsonApiData[0].JsonApiAttributes.StatusCode)
+	nid, ok := redirect.TargetNid()
+	assert.True(t, ok)
+	assert.Equal(t, 12, nid)
+
+	alias := FindPathAlias(t, "/collections/sheridan-photos")
+	nid, ok = alias.Nid()
+	assert.True(t, ok)
+	assert.Equal(t, 12, nid)
+}
+
+func Test_NodeId(t *testing.T) {
+	for link, expected := range map[string]int{"entity:node/12": 12, "internal:/node/7": 7, "/node/3": 3} {
+		nid, ok := nodeId(link)
+		assert.True(t, ok, link)
+		assert.Equal(t, expected, nid, link)
+	}
+
+	_, ok := nodeId("https://example.org/node/12")
+	assert.False(t, ok)
+	_, ok = nodeId("/taxonomy/term/12")
+	assert.False(t, ok)
+}
@@ -0,0 +1,213 @@
+package model
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/stretchr/testify/assert"
+)
+
+// RecordedFixture describes an entity created, or deleted, by a fixture (e.g. CreateCollection or CreateMedia), as
+// recorded in the file named by the environment variable 'IDC_RECORD_FIXTURES'.  The file carries one JSON record per
+// line, and is only ever appended to while tests run, so that it survives an aborted run, and may be shared by the
+// test binaries of several packages.
+type RecordedFixture struct {
+	Entity string `json:"entity"`
+	Bundle string `json:"bundle"`
+	Id     string `json:"id"`
+	// The name of the test that created the entity
+	Test string `json:"test"`
+	// The base url of the Drupal site the entity was created on
+	BaseUrl string `json:"base_url"`
+	// Whether the record notes the deletion of the entity, rather than its creation
+	Deleted bool `json:"deleted,omitempty"`
+}
+
+// The order in which the entities of each type are purged, so that an entity is deleted before the entities it
+// references: media before their files, and files before the nodes and terms media and nodes reference.  Entities of
+// other types are purged last.
+var purgeRank = map[string]int{
+	MediaEntity:  0,
+	fileEntity:   1,
+	Node:         2,
+	TaxonomyTerm: 3,
+}
+
+// Serializes access to the record file by the tests of this binary
+var recordMu sync.Mutex
+
+// PurgeRecorded deletes every entity recorded in the file named by 'IDC_RECORD_FIXTURES' that has not already been
+// deleted, e.g. the fixtures orphaned by an aborted run, asserting that no error occurs.  The test is skipped if the
+// environment variable is not set.  Invoke PurgeRecordedErr from TestMain, where no test is available:
+//
+//	func Test_PurgeFixtures(t *testing.T) {
+//		model.PurgeRecorded(t)
+//	}
+func PurgeRecorded(t *testing.T, opts ...Option) {
+	if env.RecordFixturesOr("") == "" {
+		t.Skip("IDC_RECORD_FIXTURES is not set; there are no recorded fixtures to purge")
+	}
+	err := PurgeRecordedErr(opts...)
+	assert.Nil(t, err, "%s", err)
+}
+
+// PurgeRecordedErr deletes every entity recorded in the file named by 'IDC_RECORD_FIXTURES' that has not already been
+// deleted.  Media are deleted before files, and files before nodes and terms; entities of the same type are deleted
+// in the reverse of the order they were created, so that members are deleted before their collections.  Entities
+// that no longer exist are ignored.  The file is rewritten to carry only the entities that could not be deleted, and is
+// removed if every entity was deleted.  Nothing is done if the environment variable is not set.
+func PurgeRecordedErr(opts ...Option) error {
+	path := env.RecordFixturesOr("")
+	if path == "" {
+		return nil
+	}
+
+	recordMu.Lock()
+	defer recordMu.Unlock()
+
+	outstanding, err := readRecordedErr(path)
+	if err != nil {
+		return err
+	}
+
+	var remaining []RecordedFixture
+	var problems []string
+	for _, f := range purgeOrder(outstanding) {
+		fOpts := opts
+		if f.BaseUrl != "" {
+			fOpts = append(append([]Option{}, opts...), WithBaseUrl(f.BaseUrl))
+		}
+		u := query(nil, f.Entity, f.Bundle, fOpts...)
+		if err := u.DeleteErr(f.Id); err != nil && !errors.Is(err, jsonapi.ErrNotFound) {
+			remaining = append(remaining, f)
+			problems = append(problems, fmt.Sprintf("%s/%s %s (created by %s): %s", f.Entity, f.Bundle, f.Id, f.Test,
+				err))
+		}
+	}
+
+	if err := rewriteRecordedErr(path, remaining); err != nil {
+		problems = append(problems, err.Error())
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("model: unable to purge %d recorded fixtures:\n\t%s", len(remaining),
+			strings.Join(problems, "\n\t"))
+	}
+	return nil
+}
+
+// recordFixture appends the record of the entity's creation, or deletion, to the file named by 'IDC_RECORD_FIXTURES',
+// if it is set, asserting that no error occurs
+func recordFixture(t *testing.T, entity, bundle, id string, deleted bool, opts []Option) {
+	path := env.RecordFixturesOr("")
+	if path == "" {
+		return
+	}
+
+	u := query(t, entity, bundle, opts...)
+	f := RecordedFixture{Entity: entity, Bundle: bundle, Id: id, Test: t.Name(),
+		BaseUrl: strings.TrimSuffix(u.String(), fmt.Sprintf("/jsonapi/%s/%s", entity, bundle)), Deleted: deleted}
+
+	recordMu.Lock()
+	defer recordMu.Unlock()
+	err := appendRecordedErr(path, []RecordedFixture{f})
+	assert.Nil(t, err, "unable to record fixture %s/%s %s: %s", entity, bundle, id, err)
+}
+
+// readRecordedErr answers the entities recorded in the file that have not been deleted, in the order they were
+// created.  A missing file records no entities.
+func readRecordedErr(path string) ([]RecordedFixture, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("model: unable to read recorded fixtures: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	var created []RecordedFixture
+	deleted := map[string]bool{}
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		f := RecordedFixture{}
+		if err := json.Unmarshal(scanner.Bytes(), &f); err != nil {
+			return nil, fmt.Errorf("model: unable to read recorded fixtures: %s line %d: %w", path, line, err)
+		}
+		if f.Deleted {
+			deleted[f.Id] = true
+		} else {
+			created = append(created, f)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("model: unable to read recorded fixtures: %w", err)
+	}
+
+	var outstanding []RecordedFixture
+	for _, f := range created {
+		if !deleted[f.Id] {
+			outstanding = append(outstanding, f)
+		}
+	}
+	return outstanding, nil
+}
+
+// appendRecordedErr appends a line to the file for each of the records
+func appendRecordedErr(path string, records []RecordedFixture) error {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("model: unable to open recorded fixtures: %w", err)
+	}
+	for _, f := range records {
+		line, err := json.Marshal(f)
+		if err != nil {
+			_ = file.Close()
+			return fmt.Errorf("model: unable to marshal recorded fixture: %w", err)
+		}
+		if _, err := file.Write(append(line, '\n')); err != nil {
+			_ = file.Close()
+			return fmt.Errorf("model: unable to write recorded fixtures: %w", err)
+		}
+	}
+	return file.Close()
+}
+
+// rewriteRecordedErr replaces the content of the file with the records, removing the file if there are none
+func rewriteRecordedErr(path string, records []RecordedFixture) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("model: unable to rewrite recorded fixtures: %w", err)
+	}
+	if len(records) == 0 {
+		return nil
+	}
+	return appendRecordedErr(path, records)
+}
+
+// purgeOrder answers the fixtures in the order they are to be deleted (see purgeRank)
+func purgeOrder(fixtures []RecordedFixture) []RecordedFixture {
+	ordered := make([]RecordedFixture, len(fixtures))
+	for i, f := range fixtures {
+		ordered[len(fixtures)-1-i] = f
+	}
+	rank := func(entity string) int {
+		if r, ok := purgeRank[entity]; ok {
+			return r
+		}
+		return len(purgeRank)
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return rank(ordered[i].Entity) < rank(ordered[j].Entity)
+	})
+	return ordered
+}
@@ -0,0 +1,70 @@
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_RecordFixtures(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fixtures.jsonl")
+	t.Setenv("IDC_RECORD_FIXTURES", path)
+	d := newFakeDrupal(t, map[string]string{})
+
+	t.Run("cleaned up", func(t *testing.T) {
+		CreateCollection(t, CollectionSpec{Title: "Transient Collection"})
+	})
+	outstanding, err := readRecordedErr(path)
+	assert.Nil(t, err, "%s", err)
+	assert.Empty(t, outstanding, "fixtures deleted by their test are not expected to be outstanding")
+
+	collection := EnsureCollection(t, CollectionSpec{Title: "Orphaned Collection"})
+	outstanding, err = readRecordedErr(path)
+	assert.Nil(t, err, "%s", err)
+	require.Equal(t, 1, len(outstanding))
+	assert.Equal(t, RecordedFixture{Entity: Node, Bundle: Collection, Id: collection.JsonApiData[0].Id,
+		Test: t.Name(), BaseUrl: d.URL}, outstanding[0])
+}
+
+func Test_PurgeRecorded(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fixtures.jsonl")
+	t.Setenv("IDC_RECORD_FIXTURES", path)
+	d := newFakeDrupal(t, map[string]string{})
+
+	collection := EnsureCollection(t, CollectionSpec{Title: "Orphaned Collection"})
+	for _, id := range []string{testUuid(1), testUuid(2)} {
+		d.created[id] = map[string]interface{}{}
+	}
+	require.Nil(t, appendRecordedErr(path, []RecordedFixture{
+		{Entity: fileEntity, Bundle: fileEntity, Id: testUuid(2), BaseUrl: d.URL},
+		{Entity: MediaEntity, Bundle: Image, Id: testUuid(1), BaseUrl: d.URL},
+		{Entity: Node, Bundle: RepositoryObject, Id: testUuid(3), BaseUrl: d.URL},
+	}))
+
+	PurgeRecorded(t)
+	assert.Equal(t, []string{"/jsonapi/media/image/" + testUuid(1), "/jsonapi/file/file/" + testUuid(2),
+		"/jsonapi/node/collection_object/" + collection.JsonApiData[0].Id}, d.deleted,
+		"media are expected to be purged before files, and files before nodes")
+	_, err := os.Stat(path)
+	assert.True(t, os.IsNotExist(err), "the record file is expected to be removed once purged")
+}
+
+func Test_PurgeOrder(t *testing.T) {
+	ordered := purgeOrder([]RecordedFixture{
+		{Entity: Node, Id: "collection"},
+		{Entity: TaxonomyTerm, Id: "term"},
+		{Entity: Node, Id: "member"},
+		{Entity: fileEntity, Id: "file"},
+		{Entity: MediaEntity, Id: "media"},
+		{Entity: "user", Id: "user"},
+	})
+
+	var ids []string
+	for _, f := range ordered {
+		ids = append(ids, f.Id)
+	}
+	assert.Equal(t, []string{"media", "file", "member", "collection", "term", "user"}, ids)
+}
@@ -0,0 +1,156 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Agent bundles of the taxonomy_term entity, which may be the subject or target of a field_relationships relationship
+const (
+	Person        = "person"
+	Family        = "family"
+	CorporateBody = "corporate_body"
+)
+
+// The key of the field_relationships meta object that carries the type of the relationship, e.g. `schema:knows`
+const relTypeKey = "rel_type"
+
+// A typed relationship from one agent (a person, family, or corporate body) to another
+type Relationship struct {
+	// The type of the relationship, e.g. `schema:knows` or `relators:rsp`
+	RelType string
+	// The target of the relationship
+	Target JsonApiData
+	// The name of the target, e.g. `Hopkins, Johns`
+	TargetName string
+	// The bundle of the target: one of Person, Family, or CorporateBody
+	TargetKind string
+}
+
+// Relationships resolves the field_relationships of the first person in the response, answering each typed
+// relationship with the name of its target
+func (p JsonApiPerson) Relationships(t *testing.T) []Relationship {
+	if !assert.NotEmpty(t, p.JsonApiData, "unable to resolve relationships of an empty person response") {
+		return nil
+	}
+	return relationships(t, p.JsonApiData[0].JsonApiRelationships.Relationships.Data)
+}
+
+// Relationships resolves the field_relationships of the first family in the response, answering each typed
+// relationship with the name of its target
+func (f JsonApiFamily) Relationships(t *testing.T) []Relationship {
+	if !assert.NotEmpty(t, f.JsonApiData, "unable to resolve relationships of an empty family response") {
+		return nil
+	}
+	return relationships(t, f.JsonApiData[0].JsonApiRelationships.Relationships.Data)
+}
+
+// Relationships resolves the field_relationships of the first corporate body in the response, answering each typed
+// relationship with the name of its target
+func (cb JsonApiCorporateBody) Relationships(t *testing.T) []Relationship {
+	if !assert.NotEmpty(t, cb.JsonApiData, "unable to resolve relationships of an empty corporate body response") {
+		return nil
+	}
+	return relationships(t, cb.JsonApiData[0].JsonApiRelationships.Relationships.Data)
+}
+
+// relationships resolves the name of each target.  Targets that are not agents, or that cannot be resolved, are
+// reported as failures and omitted from the result.
+func relationships(t *testing.T, data []struct {
+	JsonApiData
+	Meta map[string]string
+}) []Relationship {
+	var result []Relationship
+	for _, rel := range data {
+		r, err := resolveRelationship(rel.JsonApiData, rel.Meta[relTypeKey])
+		if !assert.Nil(t, err, "%s", err) {
+			continue
+		}
+		result = append(result, r)
+	}
+	return result
+}
+
+// resolveRelationship resolves the name of the relationship's target, which must be an agent
+func resolveRelationship(target JsonApiData, relType string) (Relationship, error) {
+	if relType == "" {
+		return Relationship{}, fmt.Errorf("model: relationship to %s %s has no '%s'", target.Type, target.Id, relTypeKey)
+	}
+	if !target.Type.Is(TaxonomyTerm, Person) && !target.Type.Is(TaxonomyTerm, Family) &&
+		!target.Type.Is(TaxonomyTerm, CorporateBody) {
+		return Relationship{}, fmt.Errorf("model: target of '%s' relationship is %s %s, which is not a person, family, or corporate body",
+			relType, target.Type, target.Id)
+	}
+
+	name, err := resolveLabelErr(target)
+	if err != nil {
+		return Relationship{}, fmt.Errorf("model: unable to resolve target of '%s' relationship: %w", relType, err)
+	}
+
+	return Relationship{RelType: relType, Target: target, TargetName: name, TargetKind: target.Type.Bundle()}, nil
+}
+
+// An agent (a person, family, or corporate body) credited by a repository object, e.g. its creator
+type Agent struct {
+	// The name of the agent, e.g. `Adams, Ansel`
+	Name string
+	// The MARC relator of the agent, e.g. `relators:pht`
+	Role string
+	// The bundle of the agent: one of Person, Family, or CorporateBody
+	Kind string
+}
+
+// String answers the agent in the form `Adams, Ansel (relators:pht)`
+func (a Agent) String() string {
+	return fmt.Sprintf("%s (%s)", a.Name, a.Role)
+}
+
+// Agents resolves the agents of the first repository object in the response referenced by the named field, which must
+// be `field_creator` or `field_contributor`.  The role of each agent is read from the `rel_type` meta value of the
+// relationship.  Agents are sorted by name, role, and kind, so the result may be compared to an expected slice.
+//
+// Targets that are not persons, families, or corporate bodies, or that cannot be resolved, are reported as failures
+// and omitted from the result.
+func (obj JsonApiIslandoraObj) Agents(t *testing.T, field string) []Agent {
+	if !assert.NotEmpty(t, obj.JsonApiData, "unable to resolve agents of an empty repository object response") {
+		return nil
+	}
+
+	var data []RelData
+	switch rels := obj.JsonApiData[0].JsonApiRelationships; field {
+	case "field_creator":
+		data = rels.Creator.Data
+	case "field_contributor":
+		data = rels.Contributor.Data
+	default:
+		assert.Fail(t, fmt.Sprintf("'%s' is not a field of agents; expected field_creator or field_contributor", field))
+		return nil
+	}
+
+	var agents []Agent
+	for _, rd := range data {
+		role, err := rd.MetaString(relTypeKey)
+		if !assert.Nil(t, err, "unable to read the role of %s %s: %s", rd.Type, rd.Id, err) {
+			continue
+		}
+		r, err := resolveRelationship(rd.JsonApiData, role)
+		if !assert.Nil(t, err, "%s", err) {
+			continue
+		}
+		agents = append(agents, Agent{Name: r.TargetName, Role: r.RelType, Kind: r.TargetKind})
+	}
+
+	sort.Slice(agents, func(i, j int) bool {
+		if agents[i].Name != agents[j].Name {
+			return agents[i].Name < agents[j].Name
+		}
+		if agents[i].Role != agents[j].Role {
+			return agents[i].Role < agents[j].Role
+		}
+		return agents[i].Kind < agents[j].Kind
+	})
+	return agents
+}
@@ -0,0 +1,65 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Relationships(t *testing.T) {
+	server := newDocumentServer(map[string]string{
+		testUuid(1): fmt.Sprintf(`{"type": "taxonomy_term--person", "id": "%s", "attributes": {"name": "Hopkins, Johns"}}`, testUuid(1)),
+		testUuid(2): fmt.Sprintf(`{"type": "taxonomy_term--family", "id": "%s", "attributes": {"name": "Gilman family"}}`, testUuid(2)),
+	})
+	defer server.Close()
+	setBaseUrl(t, server)
+
+	person := JsonApiPerson{}
+	require.Nil(t, json.Unmarshal([]byte(fmt.Sprintf(`{"data": [{"type": "taxonomy_term--person", "id": "%s",
+		"relationships": {"field_relationships": {"data": [
+			{"type": "taxonomy_term--person", "id": "%s", "meta": {"rel_type": "schema:knows"}},
+			{"type": "taxonomy_term--family", "id": "%s", "meta": {"rel_type": "relators:asn"}}]}}}]}`,
+		testUuid(0), testUuid(1), testUuid(2))), &person))
+
+	rels := person.Relationships(t)
+	assert.Equal(t, []Relationship{
+		{RelType: "schema:knows", Target: JsonApiData{Type: "taxonomy_term--person", Id: testUuid(1)}, TargetName: "Hopkins, Johns", TargetKind: Person},
+		{RelType: "relators:asn", Target: JsonApiData{Type: "taxonomy_term--family", Id: testUuid(2)}, TargetName: "Gilman family", TargetKind: Family},
+	}, rels)
+
+	_, err := resolveRelationship(JsonApiData{Type: "taxonomy_term--corporate_body", Id: testUuid(3)}, "schema:knows")
+	assert.Contains(t, fmt.Sprint(err), "unable to resolve target of 'schema:knows' relationship")
+
+	_, err = resolveRelationship(JsonApiData{Type: "taxonomy_term--subject", Id: testUuid(1)}, "schema:knows")
+	assert.Contains(t, fmt.Sprint(err), "not a person, family, or corporate body")
+
+	_, err = resolveRelationship(JsonApiData{Type: "taxonomy_term--person", Id: testUuid(1)}, "")
+	assert.Contains(t, fmt.Sprint(err), "has no 'rel_type'")
+}
+
+func Test_Agents(t *testing.T) {
+	server := newDocumentServer(map[string]string{
+		testUuid(1): fmt.Sprintf(`{"type": "taxonomy_term--person", "id": "%s", "attributes": {"name": "Adams, Ansel"}}`, testUuid(1)),
+		testUuid(2): fmt.Sprintf(`{"type": "taxonomy_term--corporate_body", "id": "%s", "attributes": {"name": "Sierra Club"}}`, testUuid(2)),
+	})
+	defer server.Close()
+	setBaseUrl(t, server)
+
+	obj := JsonApiIslandoraObj{}
+	require.Nil(t, json.Unmarshal([]byte(fmt.Sprintf(`{"data": [{"type": "node--islandora_object", "id": "%s",
+		"relationships": {"field_creator": {"data": [
+			{"type": "taxonomy_term--corporate_body", "id": "%s", "meta": {"rel_type": "relators:pbl"}},
+			{"type": "taxonomy_term--person", "id": "%s", "meta": {"rel_type": "relators:pht"}}]}}}]}`,
+		testUuid(0), testUuid(2), testUuid(1))), &obj))
+
+	agents := obj.Agents(t, "field_creator")
+	assert.Equal(t, []Agent{
+		{Name: "Adams, Ansel", Role: "relators:pht", Kind: Person},
+		{Name: "Sierra Club", Role: "relators:pbl", Kind: CorporateBody},
+	}, agents)
+	assert.Equal(t, "Adams, Ansel (relators:pht)", agents[0].String())
+	assert.Empty(t, obj.Agents(t, "field_contributor"))
+}
@@ -0,0 +1,152 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// MediaFileState is the file carried by a media at a point in time, e.g. before and after its file is replaced by a
+// re-ingest of a corrected scan (see AssertFileReplaced)
+type MediaFileState struct {
+	Media JsonApiData
+	// The revision id of the media (`drupal_internal__vid`), or zero if Drupal does not expose it
+	RevisionId int
+	// The file entity referenced by the file field of the media, e.g. `field_media_image`
+	File           JsonApiData
+	FileAttributes JsonApiFileAttributes
+	// The SHA256 digest of the content of the file
+	Checksum string
+}
+
+// MediaFileStateOf answers the file carried by the media, and the digest of its content, failing the test immediately if
+// the media or file cannot be retrieved.  Only media carrying an uploaded file (e.g. Image or Document media) are
+// supported.
+func MediaFileStateOf(t *testing.T, media JsonApiData, opts ...Option) MediaFileState {
+	state, err := MediaFileStateErr(media, opts...)
+	require.Nil(t, err, "%s", err)
+	return state
+}
+
+// MediaFileStateErr behaves as MediaFileStateOf, but answers an error instead of failing the test
+func MediaFileStateErr(media JsonApiData, opts ...Option) (MediaFileState, error) {
+	field, err := mediaFileFieldErr(media)
+	if err != nil {
+		return MediaFileState{}, err
+	}
+
+	res := struct {
+		Data []struct {
+			Attributes struct {
+				RevisionId int `json:"drupal_internal__vid"`
+			}
+			Relationships map[string]json.RawMessage
+		}
+	}{}
+	if err := media.ResolveErr(&res, opts...); err != nil {
+		return MediaFileState{}, fmt.Errorf("model: unable to resolve %s %s: %w", media.Type, media.Id, err)
+	}
+	state := MediaFileState{Media: media, RevisionId: res.Data[0].Attributes.RevisionId}
+	rel := struct{ Data JsonApiData }{}
+	if err := json.Unmarshal(res.Data[0].Relationships[field], &rel); err != nil {
+		return MediaFileState{}, fmt.Errorf("model: %s %s carries no file in %s: %w", media.Type, media.Id, field, err)
+	}
+	state.File = rel.Data
+	if err := state.File.Validate(); err != nil {
+		return MediaFileState{}, fmt.Errorf("model: %s %s carries no file in %s: %w", media.Type, media.Id, field, err)
+	}
+
+	file, err := ResolveAsErr[JsonApiFile](state.File, opts...)
+	if err != nil {
+		return MediaFileState{}, err
+	}
+	state.FileAttributes = file.JsonApiData[0].JsonApiAttributes
+	if state.Checksum, err = state.FileAttributes.ChecksumErr(SHA256); err != nil {
+		return MediaFileState{}, err
+	}
+	return state, nil
+}
+
+// ReplaceMediaFile uploads the content as a new file, and updates the file field of the media to reference it, e.g. to
+// re-ingest a corrected scan, answering the identifier of the new file.  The new file is deleted when the test
+// completes; the old file is left to the policy of the site.  The test fails immediately if the file cannot be
+// uploaded, or the media cannot be updated:
+//
+//	before := model.MediaFileStateOf(t, media)
+//	model.ReplaceMediaFile(t, media, "moo-corrected.tiff", f)
+//	model.AssertFileReplaced(t, before, model.MediaFileStateOf(t, media), true)
+func ReplaceMediaFile(t *testing.T, media JsonApiData, filename string, content io.Reader,
+	opts ...Option) jsonapi.ResourceIdentifier {
+	field, err := mediaFileFieldErr(media)
+	require.Nil(t, err, "%s", err)
+
+	bundle := media.Type.Bundle()
+	file := upload(t, bundle, field, filename, content, opts)
+	if bundle == Image {
+		file.Meta = map[string]string{"alt": filename}
+	}
+	u := query(t, MediaEntity, bundle, opts...)
+	err = u.UpdateErr(media.Id, jsonapi.Resource{Relationships: map[string]interface{}{field: jsonapi.ToOne(&file)}},
+		&json.RawMessage{})
+	require.Nil(t, err, "unable to replace the file of %s %s: %s", media.Type, media.Id, err)
+	return file
+}
+
+// AssertFileReplaced asserts that the media references a different file after than before, whose size or content
+// differs, and that a new revision of the media was saved, if its revision ids are exposed.  If retainOld, the old file
+// must still exist; otherwise it must have been deleted, which is waited for (see WaitForEntity for the time waited),
+// as Drupal may delete unused files after the update completes.
+func AssertFileReplaced(t *testing.T, before, after MediaFileState, retainOld bool, opts ...Option) bool {
+	err := replacementErr(before, after)
+	ok := assert.Nil(t, err, "%s", err)
+
+	u := query(t, fileEntity, fileEntity, opts...)
+	if retainOld {
+		exists, err := u.ResourceExistsErr(before.File.Id)
+		return assert.Nil(t, err, "%s", err) && assert.True(t, exists, "the replaced file %s (%s) was deleted",
+			before.File.Id, before.FileAttributes.Filename) && ok
+	}
+	return WaitFor(t, WaitTimeout(t), WaitInterval(t), func() (bool, error) {
+		exists, err := u.ResourceExistsErr(before.File.Id)
+		if err == nil && exists {
+			err = fmt.Errorf("the replaced file %s (%s) still exists", before.File.Id, before.FileAttributes.Filename)
+		}
+		return err == nil, err
+	}) && ok
+}
+
+// replacementErr answers an error describing each way in which the media after does not carry a replacement of the
+// file it carried before (see AssertFileReplaced), or nil if it does
+func replacementErr(before, after MediaFileState) error {
+	var errs []error
+	if before.File.Id == after.File.Id {
+		errs = append(errs, fmt.Errorf("%s %s still references file %s", after.Media.Type, after.Media.Id,
+			before.File.Id))
+	}
+	if before.Checksum == after.Checksum && before.FileAttributes.FileSize == after.FileAttributes.FileSize {
+		errs = append(errs, fmt.Errorf("the replacement file of %s %s has the same size (%d) and checksum (%s)",
+			after.Media.Type, after.Media.Id, after.FileAttributes.FileSize, after.Checksum))
+	}
+	if before.RevisionId != 0 && after.RevisionId != 0 && after.RevisionId <= before.RevisionId {
+		errs = append(errs, fmt.Errorf("no new revision of %s %s was saved", after.Media.Type, after.Media.Id))
+	}
+	return errors.Join(errs...)
+}
+
+// mediaFileFieldErr answers the field of the media carrying its uploaded file, e.g. `field_media_image`
+func mediaFileFieldErr(media JsonApiData) (string, error) {
+	if err := media.Validate(); err != nil {
+		return "", err
+	}
+	bundle, ok := mediaFileFields[media.Type.Bundle()]
+	if media.Type.Entity() != MediaEntity || !ok {
+		return "", fmt.Errorf("model: %s entities do not carry an uploaded file", media.Type)
+	}
+	return bundle.field, nil
+}
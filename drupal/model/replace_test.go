@@ -0,0 +1,66 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ReplaceMediaFile(t *testing.T) {
+	d := newFakeDrupal(t, map[string]string{})
+	t.Setenv("IDC_WAIT_INTERVAL", "1ms")
+
+	media := CreateMedia(t, MediaSpec{Bundle: Image, Reader: strings.NewReader("moo"), Filename: "moo.tiff",
+		MediaOfUuid: testUuid(3)})
+	mediaData := JsonApiData{Type: "media--image", Id: media.(JsonApiImageMedia).JsonApiData[0].Id}
+
+	before := MediaFileStateOf(t, mediaData)
+	assert.Equal(t, media.File().Id, before.File.Id)
+	assert.Equal(t, 3, before.FileAttributes.FileSize)
+
+	file := ReplaceMediaFile(t, mediaData, "moo-corrected.tiff", strings.NewReader("mooo"))
+	assert.Equal(t, []string{file.Id}, d.relationshipIds(mediaData.Id, "field_media_image"))
+
+	after := MediaFileStateOf(t, mediaData)
+	assert.Equal(t, file.Id, after.File.Id)
+	assert.Equal(t, "moo-corrected.tiff", after.FileAttributes.Filename)
+	assert.Equal(t, 4, after.FileAttributes.FileSize)
+	assert.NotEqual(t, before.Checksum, after.Checksum)
+	assert.True(t, AssertFileReplaced(t, before, after, true))
+
+	err := replacementErr(before, before)
+	require.NotNil(t, err, "a media still referencing the same file is expected not to be a replacement")
+	assert.Contains(t, err.Error(), "still references file "+before.File.Id)
+	assert.Contains(t, err.Error(), "has the same size (3)")
+
+	before.RevisionId, after.RevisionId = 2, 2
+	assert.Contains(t, fmt.Sprint(replacementErr(before, after)), "no new revision")
+	after.RevisionId = 3
+	assert.Nil(t, replacementErr(before, after))
+}
+
+func Test_AssertFileReplacedDeletingOld(t *testing.T) {
+	newFakeDrupal(t, map[string]string{})
+	t.Setenv("IDC_WAIT_INTERVAL", "1ms")
+
+	media := CreateMedia(t, MediaSpec{Bundle: Image, Reader: strings.NewReader("moo"), Filename: "moo.tiff",
+		MediaOfUuid: testUuid(3)})
+	mediaData := JsonApiData{Type: "media--image", Id: media.(JsonApiImageMedia).JsonApiData[0].Id}
+	before := MediaFileStateOf(t, mediaData)
+	ReplaceMediaFile(t, mediaData, "moo-corrected.tiff", strings.NewReader("mooo"))
+	after := MediaFileStateOf(t, mediaData)
+
+	u := query(t, fileEntity, fileEntity)
+	err := u.DeleteErr(before.File.Id)
+	require.Nil(t, err, "%s", err)
+	assert.True(t, AssertFileReplaced(t, before, after, false), "the old file is expected to have been deleted")
+}
+
+func Test_MediaFileStateErrUnsupported(t *testing.T) {
+	_, err := MediaFileStateErr(JsonApiData{Type: "media--remote_video", Id: testUuid(1)})
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "do not carry an uploaded file")
+}
@@ -0,0 +1,217 @@
+package model
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/stretchr/testify/assert"
+)
+
+// ResolveErr behaves as Resolve, but answers an error instead of making assertions.  It is safe to invoke from
+// goroutines, and may be used outside of tests.
+func (jad *JsonApiData) ResolveErr(v interface{}, opts ...Option) error {
+	if err := jad.Validate(); err != nil {
+		return fmt.Errorf("model: unable to resolve relationship: %w", err)
+	}
+
+	u := jad.url(env.UsernameOr(""), env.PasswordOr(""), opts...)
+	return u.GetSingleErr(v)
+}
+
+// ResolveConcurrently resolves each of the supplied items, overlapping the requests using at most `workers` concurrent
+// requests.  The `out` parameter must be a pointer to a slice of JSON API structs (e.g. `*[]JsonApiSubject`); it will
+// be populated with one resolved element per item, in the same order as the supplied items.
+//
+// Each failure is reported with the index and identifier of the item that could not be resolved.  A `workers` value
+// less than one is treated as one.  The options apply to the request of each item.
+func ResolveConcurrently(t *testing.T, items []JsonApiData, out interface{}, workers int, opts ...Option) {
+	outVal := reflect.ValueOf(out)
+	if outVal.Kind() != reflect.Ptr || outVal.Elem().Kind() != reflect.Slice {
+		assert.Fail(t, fmt.Sprintf("ResolveConcurrently requires a pointer to a slice, but was supplied %T", out))
+		return
+	}
+
+	for _, err := range resolveConcurrently(items, outVal.Elem(), workers, opts...) {
+		assert.Nil(t, err, "%s", err)
+	}
+}
+
+// resolveConcurrently resolves the items into a newly allocated slice which is assigned to `slice`, and answers the
+// errors encountered, ordered by the index of the item that failed.
+func resolveConcurrently(items []JsonApiData, slice reflect.Value, workers int, opts ...Option) []error {
+	if workers < 1 {
+		workers = 1
+	}
+
+	logger := loggerOf(opts)
+	logger.Log("model resolve fan-out", "items", len(items), "workers", workers)
+	resolved := reflect.MakeSlice(slice.Type(), len(items), len(items))
+	errs := make([]error, len(items))
+	sem := make(chan struct{}, workers)
+	wg := sync.WaitGroup{}
+
+	for i := range items {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer func() { <-sem; wg.Done() }()
+			item := items[i]
+			if err := item.ResolveErr(resolved.Index(i).Addr().Interface(), opts...); err != nil {
+				errs[i] = fmt.Errorf("model: unable to resolve item %d (%s %s): %w", i, item.Type, item.Id, err)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+	slice.Set(resolved)
+
+	var result []error
+	for _, err := range errs {
+		if err != nil {
+			result = append(result, err)
+		}
+	}
+	logger.Log("model resolve fan-out complete", "items", len(items), "failed", len(result))
+
+	return result
+}
+
+// ResolveAs resolves the supplied data object into a new JSON API struct of type T (e.g. JsonApiSubject), and answers
+// it.  The typed struct is asserted to carry a resolved element in its `JsonApiData` slice, so callers may safely
+// index the first element of the result:
+//
+//	subject := model.ResolveAs[model.JsonApiSubject](t, jad)
+//	name := subject.JsonApiData[0].JsonApiAttributes.Name
+func ResolveAs[T any](t *testing.T, jad JsonApiData, opts ...Option) T {
+	v, err := ResolveAsErr[T](jad, opts...)
+	assert.Nil(t, err, "%s", err)
+	return v
+}
+
+// ResolveAsErr behaves as ResolveAs, but answers an error instead of making assertions.  An error is returned if the
+// data object cannot be resolved, or if the resolved struct does not contain at least one data element.
+func ResolveAsErr[T any](jad JsonApiData, opts ...Option) (T, error) {
+	var v T
+	if err := jad.ResolveErr(&v, opts...); err != nil {
+		return v, fmt.Errorf("model: unable to resolve %s %s as %T: %w", jad.Type, jad.Id, v, err)
+	}
+
+	if n, ok := dataLen(v); !ok {
+		return v, fmt.Errorf("model: %T has no 'JsonApiData' slice to resolve %s %s into", v, jad.Type, jad.Id)
+	} else if n == 0 {
+		return v, fmt.Errorf("model: resolving %s %s as %T resulted in an empty 'JsonApiData' slice", jad.Type, jad.Id, v)
+	}
+
+	return v, nil
+}
+
+// dataLen answers the length of the `JsonApiData` slice carried by the supplied JSON API struct, and whether such a
+// slice exists
+func dataLen(v interface{}) (int, bool) {
+	val := reflect.Indirect(reflect.ValueOf(v))
+	if val.Kind() != reflect.Struct {
+		return 0, false
+	}
+
+	data := val.FieldByName("JsonApiData")
+	if !data.IsValid() || data.Kind() != reflect.Slice {
+		return 0, false
+	}
+
+	return data.Len(), true
+}
+
+// Minimally represents a resolved entity that carries a name (e.g. a taxonomy term) or a title (e.g. a node)
+type labeled struct {
+	JsonApiData []struct {
+		JsonApiAttributes struct {
+			Name  string
+			Title string
+		} `json:"attributes"`
+	} `json:"data"`
+}
+
+// resolveLabelErr resolves the data object, and answers its name, or its title if it has no name.  Labels of taxonomy
+// terms are cached (see resolveCachedErr).
+func resolveLabelErr(jad JsonApiData) (string, error) {
+	res, err := resolveCachedErr[labeled](jad)
+	if err != nil {
+		return "", err
+	}
+
+	if attrs := res.JsonApiData[0].JsonApiAttributes; attrs.Name != "" {
+		return attrs.Name, nil
+	} else {
+		return attrs.Title, nil
+	}
+}
+
+// resolveLabel behaves as resolveLabelErr, but asserts that no error occurs
+func resolveLabel(t *testing.T, jad JsonApiData) string {
+	label, err := resolveLabelErr(jad)
+	assert.Nil(t, err, "%s", err)
+	return label
+}
+
+// ResolveLabelsErr resolves each of the supplied data objects, and answers its name, or its title if it has no name, in
+// order, e.g. the names of the creators of a repository object.  Labels of taxonomy terms are cached.
+func ResolveLabelsErr(data []JsonApiData) ([]string, error) {
+	labels := make([]string, len(data))
+	for i, jad := range data {
+		label, err := resolveLabelErr(jad)
+		if err != nil {
+			return nil, err
+		}
+		labels[i] = label
+	}
+	return labels, nil
+}
+
+// resolveLabels answers the label of each of the supplied data objects, in order
+func resolveLabels(t *testing.T, data []JsonApiData) []string {
+	labels := make([]string, len(data))
+	for i, jad := range data {
+		labels[i] = resolveLabel(t, jad)
+	}
+	return labels
+}
+
+// Resolved taxonomy terms, keyed by the base url, the type and identifier of the term, and the type it was resolved to
+var termCache = sync.Map{}
+
+// resolveCachedErr behaves as ResolveAsErr, but caches the resolved struct if the data object is a taxonomy term.  Terms
+// (e.g. access terms, media use terms, or Islandora models) are referenced by many entities and rarely change, so each
+// is only requested once per base url.  Other entities are always requested.  Cache hits are logged to the Logger of
+// the options.
+func resolveCachedErr[T any](jad JsonApiData, opts ...Option) (T, error) {
+	if jad.Type.Entity() != TaxonomyTerm {
+		return ResolveAsErr[T](jad, opts...)
+	}
+
+	var v T
+	key := fmt.Sprintf("%s %s %s %T", baseUrlOf(opts), jad.Type, jad.Id, v)
+	if cached, ok := termCache.Load(key); ok {
+		loggerOf(opts).Log("model term cache hit", "type", jad.Type, "id", jad.Id)
+		return cached.(T), nil
+	}
+
+	v, err := ResolveAsErr[T](jad, opts...)
+	if err != nil {
+		return v, err
+	}
+	termCache.Store(key, v)
+	return v, nil
+}
+
+// ResetTermCache empties the cache of resolved taxonomy terms, and of term indexes (see TermIndex), e.g. after a test
+// modifies a term
+func ResetTermCache() {
+	termCache.Range(func(key, _ interface{}) bool {
+		termCache.Delete(key)
+		return true
+	})
+	resetTermIndexes()
+}
@@ -0,0 +1,225 @@
+package model
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTermServer answers a test server which responds to the canonical path of a term with the subject term named after
+// its id.  Ids beginning with "ffffffff" result in a 404 response.  The returned function answers the
+// maximum number of requests observed in flight at once.
+func newTermServer() (*httptest.Server, func() int) {
+	mu := sync.Mutex{}
+	inFlight, maxInFlight := 0, 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+		defer func() { mu.Lock(); inFlight--; mu.Unlock() }()
+
+		id := requestedId(r)
+		if strings.HasPrefix(id, "ffffffff") {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"data": {"type": "taxonomy_term--subject", "id": "%s", "attributes": {"name": "name-%s"}}}`, id, id)
+	}))
+
+	return server, func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return maxInFlight
+	}
+}
+
+// an identifier which the term server does not know about
+const missingUuid = "ffffffff-0000-4000-8000-000000000000"
+
+// testUuid answers a well-formed UUID derived from the supplied integer
+func testUuid(i int) string {
+	return fmt.Sprintf("%08d-0000-4000-8000-000000000000", i)
+}
+
+// requestedId answers the id of the entity requested by its canonical path, `/jsonapi/{entity}/{bundle}/{uuid}`, or
+// filtered by its id
+func requestedId(r *http.Request) string {
+	if segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/"); len(segments) == 4 {
+		return segments[3]
+	}
+	return r.URL.Query().Get("filter[id]")
+}
+
+// collectionPath answers the path of the collection of the request, without the UUID of a canonical path, e.g.
+// `/jsonapi/file/file`
+func collectionPath(r *http.Request) string {
+	if segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/"); len(segments) == 4 {
+		return "/" + strings.Join(segments[:3], "/")
+	}
+	return r.URL.Path
+}
+
+// setBaseUrl points the Drupal base url at the supplied server for the duration of the test
+func setBaseUrl(t *testing.T, server *httptest.Server) {
+	t.Setenv("DRUPAL_BASE_URL", server.URL)
+}
+
+func Test_ResolveConcurrentlyPreservesOrder(t *testing.T) {
+	server, maxInFlight := newTermServer()
+	defer server.Close()
+	setBaseUrl(t, server)
+
+	var items []JsonApiData
+	for i := 0; i < 20; i++ {
+		items = append(items, JsonApiData{Type: "taxonomy_term--subject", Id: testUuid(i)})
+	}
+
+	var out []JsonApiSubject
+	ResolveConcurrently(t, items, &out, 4)
+
+	assert.Equal(t, len(items), len(out))
+	for i, subject := range out {
+		assert.Equal(t, "name-"+testUuid(i), subject.JsonApiData[0].JsonApiAttributes.Name)
+	}
+	assert.LessOrEqual(t, maxInFlight(), 4)
+}
+
+func Test_ResolveConcurrentlyReportsFailures(t *testing.T) {
+	server, _ := newTermServer()
+	defer server.Close()
+	setBaseUrl(t, server)
+
+	items := []JsonApiData{
+		{Type: "taxonomy_term--subject", Id: testUuid(0)},
+		{Type: "taxonomy_term--subject", Id: missingUuid},
+	}
+
+	var out []JsonApiSubject
+	errs := resolveConcurrently(items, reflect.ValueOf(&out).Elem(), 2)
+
+	assert.Equal(t, 1, len(errs))
+	assert.Contains(t, errs[0].Error(), "item 1")
+	assert.Contains(t, errs[0].Error(), missingUuid)
+	assert.Equal(t, "name-"+testUuid(0), out[0].JsonApiData[0].JsonApiAttributes.Name)
+}
+
+func Test_ResolveLogsToLoggerOfOptions(t *testing.T) {
+	server, _ := newTermServer()
+	defer server.Close()
+	ResetTermCache()
+	t.Cleanup(ResetTermCache)
+
+	var events []string
+	opts := []Option{WithBaseUrl(server.URL), WithLogger(eventLogger(func(msg string) {
+		if strings.HasPrefix(msg, "model") {
+			events = append(events, msg)
+		}
+	}))}
+	items := []JsonApiData{{Type: "taxonomy_term--subject", Id: testUuid(0)}}
+
+	var out []JsonApiSubject
+	ResolveConcurrently(t, items, &out, 1, opts...)
+	assert.Equal(t, "name-"+testUuid(0), out[0].JsonApiData[0].JsonApiAttributes.Name)
+
+	for i := 0; i < 2; i++ {
+		label, err := resolveCachedErr[labeled](items[0], opts...)
+		require.Nil(t, err, "%s", err)
+		assert.Equal(t, "name-"+testUuid(0), label.JsonApiData[0].JsonApiAttributes.Name)
+	}
+	assert.Equal(t, []string{"model resolve fan-out", "model resolve fan-out complete", "model term cache hit"}, events)
+}
+
+func Test_ResolveAs(t *testing.T) {
+	server, _ := newTermServer()
+	defer server.Close()
+	setBaseUrl(t, server)
+
+	subject := ResolveAs[JsonApiSubject](t, JsonApiData{Type: "taxonomy_term--subject", Id: testUuid(0)})
+	assert.Equal(t, "name-"+testUuid(0), subject.JsonApiData[0].JsonApiAttributes.Name)
+
+	_, err := ResolveAsErr[JsonApiSubject](JsonApiData{Type: "taxonomy_term--subject", Id: missingUuid})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), missingUuid)
+
+	_, err = ResolveAsErr[string](JsonApiData{Type: "taxonomy_term--subject", Id: testUuid(0)})
+	assert.NotNil(t, err)
+}
+
+func Test_ResolveRequestsCanonicalPath(t *testing.T) {
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.RequestURI())
+		_, _ = fmt.Fprintf(w, `{"data": {"type": "taxonomy_term--subject", "id": "%s", "attributes": {"name": "moo"}}}`,
+			requestedId(r))
+	}))
+	defer server.Close()
+	setBaseUrl(t, server)
+
+	subject := JsonApiSubject{}
+	jad := JsonApiData{Type: "taxonomy_term--subject", Id: testUuid(1)}
+	jad.Resolve(t, &subject)
+	assert.Equal(t, []string{"/jsonapi/taxonomy_term/subject/" + testUuid(1)}, requested)
+	require.Len(t, subject.JsonApiData, 1)
+	assert.Equal(t, "moo", subject.JsonApiData[0].JsonApiAttributes.Name)
+}
+
+// newDocumentServer answers a test server which responds to the canonical path of an entity, or JSON API queries
+// filtered by its id, with the data element keyed by that id, or an empty response if no such element exists.
+func newDocumentServer(elements map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if element, ok := elements[requestedId(r)]; ok {
+			_, _ = fmt.Fprintf(w, `{"data": [%s]}`, element)
+		} else {
+			_, _ = w.Write([]byte(`{"data": []}`))
+		}
+	}))
+}
+
+// collectionElement answers a JSON API data element for a collection with the supplied id and title, which is a member
+// of the collection identified by parentId (if not empty)
+func collectionElement(id, title, parentId string) string {
+	memberOf := `null`
+	if parentId != "" {
+		memberOf = fmt.Sprintf(`{"type": "node--collection_object", "id": "%s"}`, parentId)
+	}
+	return fmt.Sprintf(`{"type": "node--collection_object", "id": "%s", "attributes": {"title": "%s"},
+		"relationships": {"field_member_of": {"data": %s}}}`, id, title, memberOf)
+}
+
+func Test_ResolveUsesEnvironmentCredentials(t *testing.T) {
+	var user, pass string
+	var authn bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, authn = r.BasicAuth()
+		_, _ = fmt.Fprintf(w, `{"data": [%s]}`, collectionElement(testUuid(0), "Collection", ""))
+	}))
+	defer server.Close()
+	setBaseUrl(t, server)
+	t.Setenv("DRUPAL_USERNAME", "admin")
+	t.Setenv("DRUPAL_PASSWORD", "moo")
+
+	jad := JsonApiData{Type: "node--collection_object", Id: testUuid(0)}
+
+	jad.Resolve(t, &JsonApiCollection{})
+	assert.True(t, authn)
+	assert.Equal(t, "admin", user)
+	assert.Equal(t, "moo", pass)
+
+	jad.ResolveAnonymous(t, &JsonApiCollection{})
+	assert.False(t, authn)
+
+	jad.ResolveWithBasicAuth(t, &JsonApiCollection{}, "other", "secret")
+	assert.Equal(t, "other", user)
+	assert.Equal(t, "secret", pass)
+}
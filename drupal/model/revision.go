@@ -0,0 +1,66 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	// Identifies the latest revision of a resource when retrieving a revision
+	LatestVersion = "rel:latest-version"
+	// Identifies the working copy (i.e. the latest, possibly unpublished, revision) of a resource when retrieving a
+	// revision
+	WorkingCopy = "rel:working-copy"
+)
+
+// Revision attributes carried by node entities, e.g. JsonApiIslandoraObj and JsonApiCollection
+type JsonApiNodeRevisionAttributes struct {
+	// The revision identifier; Drupal increments it each time a new revision of the node is saved
+	Vid             int    `json:"drupal_internal__vid"`
+	RevisionCreated string `json:"revision_timestamp"`
+	RevisionLog     string `json:"revision_log"`
+}
+
+// RevisionVersion answers the version identifying the revision with the supplied identifier (i.e. the value of
+// `drupal_internal__vid`), for use with GetNodeRevision
+func RevisionVersion(vid int) string {
+	return fmt.Sprintf("id:%d", vid)
+}
+
+// GetNodeRevision retrieves the identified revision of the node with the supplied bundle and UUID, and unmarshals it
+// into the supplied interface (which must be a pointer), e.g. a JsonApiIslandoraObj.  The version is one of
+// LatestVersion, WorkingCopy, or the value answered by RevisionVersion.
+//
+// Drupal's JSON API only exposes individual revisions (by way of the `resourceVersion` parameter); it does not offer a
+// listing of every revision of a node.  Tests wishing to observe a new revision should record the `Vid` of the node
+// before it is updated, and compare it to the `Vid` of the latest version afterwards.
+func GetNodeRevision(t *testing.T, bundle, uuid, version string, v interface{}) {
+	err := GetNodeRevisionErr(bundle, uuid, version, v)
+	assert.Nil(t, err, "%s", err)
+}
+
+// GetNodeRevisionErr behaves as GetNodeRevision, but answers an error instead of making assertions.
+func GetNodeRevisionErr(bundle, uuid, version string, v interface{}) error {
+	u := fmt.Sprintf("%s/jsonapi/%s/%s/%s?resourceVersion=%s", strings.TrimSuffix(env.BaseUrlOr(defaultBaseUrl), "/"),
+		Node, url.PathEscape(bundle), url.PathEscape(uuid), url.QueryEscape(version))
+
+	_, body, err := jsonapi.GetResourceErr(u, env.UsernameOr(""), env.PasswordOr(""))
+	if err != nil {
+		return fmt.Errorf("model: unable to retrieve revision %s of %s %s: %w", version, bundle, uuid, err)
+	}
+
+	res := &jsonapi.JsonApiResponse{}
+	if err := json.Unmarshal(body, res); err != nil {
+		return fmt.Errorf("model: unable to unmarshal revision %s of %s %s: %w", version, bundle, uuid, err)
+	}
+
+	res.To(v)
+	return nil
+}
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_GetNodeRevision(t *testing.T) {
+	uuid := testUuid(1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/jsonapi/node/islandora_object/"+uuid, r.URL.Path)
+		vid := 48
+		if r.URL.Query().Get("resourceVersion") == LatestVersion {
+			vid = 49
+		}
+		// individual resources are returned with 'data' as an object rather than an array
+		_, _ = fmt.Fprintf(w, `{"data": {"type": "node--islandora_object", "id": "%s", "attributes": {"title": "Moonrise",
+			"drupal_internal__vid": %d, "revision_timestamp": "2021-06-01T12:00:00+00:00", "revision_log": "Updated by migration"}}}`,
+			uuid, vid)
+	}))
+	defer server.Close()
+	setBaseUrl(t, server)
+
+	before := JsonApiIslandoraObj{}
+	GetNodeRevision(t, RepositoryObject, uuid, RevisionVersion(48), &before)
+	require.Equal(t, 1, len(before.JsonApiData))
+	assert.Equal(t, "Moonrise", before.JsonApiData[0].JsonApiAttributes.Title)
+	assert.Equal(t, "Updated by migration", before.JsonApiData[0].JsonApiAttributes.RevisionLog)
+
+	after := JsonApiIslandoraObj{}
+	GetNodeRevision(t, RepositoryObject, uuid, LatestVersion, &after)
+	require.Equal(t, 1, len(after.JsonApiData))
+	assert.Greater(t, after.JsonApiData[0].JsonApiAttributes.Vid, before.JsonApiData[0].JsonApiAttributes.Vid)
+}
@@ -0,0 +1,180 @@
+package model
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/stretchr/testify/assert"
+)
+
+// The attributes and relationships that Drupal manages on every entity, and which are deliberately not modeled.  They
+// are never reported as unmodeled.
+var drupalManagedKeys = map[string]bool{
+	"bundle":                        true,
+	"content_translation_changed":   true,
+	"content_translation_created":   true,
+	"content_translation_outdated":  true,
+	"content_translation_source":    true,
+	"content_translation_status":    true,
+	"content_translation_uid":       true,
+	"default_langcode":              true,
+	"drupal_internal__fid":          true,
+	"drupal_internal__id":           true,
+	"drupal_internal__mid":          true,
+	"drupal_internal__nid":          true,
+	"drupal_internal__revision_id":  true,
+	"drupal_internal__tid":          true,
+	"drupal_internal__vid":          true,
+	"menu_link":                     true,
+	"node_type":                     true,
+	"parent":                        true,
+	"path":                          true,
+	"revision_created":              true,
+	"revision_log":                  true,
+	"revision_log_message":          true,
+	"revision_timestamp":            true,
+	"revision_translation_affected": true,
+	"revision_uid":                  true,
+	"revision_user":                 true,
+	"sticky":                        true,
+	"uid":                           true,
+	"vid":                           true,
+	"weight":                        true,
+}
+
+// SchemaDrift describes the differences between the attributes and relationships of a Drupal bundle and the fields
+// declared by the struct that models it
+type SchemaDrift struct {
+	// The keys present in Drupal, but not modeled, e.g. a newly added or renamed field
+	Unmodeled []string
+	// The keys modeled, but absent from Drupal, e.g. a removed or renamed field
+	Missing []string
+}
+
+// IsZero answers whether there is no drift between Drupal and the model
+func (d SchemaDrift) IsZero() bool {
+	return len(d.Unmodeled) == 0 && len(d.Missing) == 0
+}
+
+// String answers a readable description of the drift, listing the unmodeled and missing keys
+func (d SchemaDrift) String() string {
+	return fmt.Sprintf("present in Drupal but unmodeled: %q\nmodeled but absent from Drupal : %q", d.Unmodeled, d.Missing)
+}
+
+// AssertSchema asserts that the attributes and relationships of the entity bundle in Drupal match the json tags
+// declared by its model T (e.g. JsonApiIslandoraObj), so that a field added, removed, or renamed in Drupal is caught
+// immediately, rather than surfacing as a zero value in an unrelated assertion.  A single resource of the bundle is
+// retrieved, and its keys are compared with the tags of T's attributes and relationships.  Keys managed by Drupal (e.g.
+// `drupal_internal__nid` or `path`) are not reported as unmodeled.  Each bundle is best checked by a test of its own:
+//
+//	func Test_RepositoryObjectSchema(t *testing.T) {
+//		model.AssertSchema[model.JsonApiIslandoraObj](t, model.Node, model.RepositoryObject)
+//	}
+func AssertSchema[T any](t *testing.T, entity, bundle string, opts ...Option) bool {
+	drift, err := schemaDriftErr[T](query(t, entity, bundle, opts...))
+	if !assert.Nil(t, err, "%s", err) {
+		return false
+	}
+	return assert.True(t, drift.IsZero(), "the schema of %s/%s has drifted from %T:\n%s", entity, bundle, *new(T),
+		drift)
+}
+
+// schemaDriftErr retrieves a single resource from the url, and answers the drift between its keys and the json tags of
+// the model T
+func schemaDriftErr[T any](u jsonapi.JsonApiUrl) (SchemaDrift, error) {
+	modeled, err := modeledKeysErr(reflect.TypeOf(*new(T)))
+	if err != nil {
+		return SchemaDrift{}, err
+	}
+
+	raw := struct {
+		Data []struct {
+			Attributes    map[string]interface{}
+			Relationships map[string]interface{}
+		}
+	}{}
+	u.RawFilter = "page[limit]=1"
+	if err := u.GetSingleErr(&raw); err != nil {
+		return SchemaDrift{}, fmt.Errorf("model: unable to retrieve a %s/%s to check its schema: %w", u.DrupalEntity,
+			u.DrupalBundle, err)
+	}
+
+	actual := map[string]bool{}
+	for key := range raw.Data[0].Attributes {
+		actual[key] = true
+	}
+	for key := range raw.Data[0].Relationships {
+		actual[key] = true
+	}
+
+	return schemaDrift(modeled, actual), nil
+}
+
+// schemaDrift compares the modeled keys with the actual keys.  Keys are compared case-insensitively, as they are when
+// unmarshaled.
+func schemaDrift(modeled, actual map[string]bool) SchemaDrift {
+	lower := func(keys map[string]bool) map[string]bool {
+		result := make(map[string]bool, len(keys))
+		for key := range keys {
+			result[strings.ToLower(key)] = true
+		}
+		return result
+	}
+	modeledLower, actualLower := lower(modeled), lower(actual)
+
+	drift := SchemaDrift{}
+	for key := range actual {
+		if !modeledLower[strings.ToLower(key)] && !drupalManagedKeys[key] {
+			drift.Unmodeled = append(drift.Unmodeled, key)
+		}
+	}
+	for key := range modeled {
+		if !actualLower[strings.ToLower(key)] {
+			drift.Missing = append(drift.Missing, key)
+		}
+	}
+	sort.Strings(drift.Unmodeled)
+	sort.Strings(drift.Missing)
+	return drift
+}
+
+// modeledKeysErr answers the keys of the attributes and relationships modeled by the supplied JSONAPI struct, i.e. the
+// json tags of the fields of its JsonApiAttributes and JsonApiRelationships
+func modeledKeysErr(model reflect.Type) (map[string]bool, error) {
+	data, ok := model.FieldByName("JsonApiData")
+	if model.Kind() != reflect.Struct || !ok || data.Type.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("model: %s does not model a JSONAPI response", model)
+	}
+
+	keys := map[string]bool{}
+	for _, name := range []string{"JsonApiAttributes", "JsonApiRelationships"} {
+		if f, ok := data.Type.Elem().FieldByName(name); ok {
+			collectJsonKeys(f.Type, keys)
+		}
+	}
+	return keys, nil
+}
+
+// collectJsonKeys adds the json key of each exported field of the struct to keys, flattening embedded structs as
+// encoding/json does
+func collectJsonKeys(s reflect.Type, keys map[string]bool) {
+	for i := 0; i < s.NumField(); i++ {
+		f := s.Field(i)
+		tag := f.Tag.Get("json")
+		name := strings.Split(tag, ",")[0]
+		switch {
+		case tag == "-" || !f.IsExported():
+			continue
+		case f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct:
+			collectJsonKeys(f.Type, keys)
+		case name != "":
+			keys[name] = true
+		default:
+			keys[f.Name] = true
+		}
+	}
+}
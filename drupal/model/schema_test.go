@@ -0,0 +1,64 @@
+package model
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSchemaServer answers a server responding to every request with a single language term carrying the supplied
+// attributes
+func newSchemaServer(attributes string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, `{"data": [{"type": "taxonomy_term--language", "id": "%s", "attributes": %s,
+			"relationships": {"parent": {"data": []}}}]}`, testUuid(0), attributes)
+	}))
+}
+
+func Test_SchemaDriftNone(t *testing.T) {
+	server := newSchemaServer(`{"drupal_internal__tid": 1, "name": "English", "field_language_code": "en",
+		"description": null, "field_authority_link": []}`)
+	defer server.Close()
+	setBaseUrl(t, server)
+
+	assert.True(t, AssertSchema[JsonApiLanguage](t, TaxonomyTerm, "language"))
+}
+
+func Test_SchemaDriftReported(t *testing.T) {
+	server := newSchemaServer(`{"name": "English", "field_lang_code": "en", "description": null,
+		"field_authority_link": []}`)
+	defer server.Close()
+	setBaseUrl(t, server)
+
+	drift, err := schemaDriftErr[JsonApiLanguage](query(t, TaxonomyTerm, "language"))
+	require.Nil(t, err, "%s", err)
+	assert.Equal(t, []string{"field_lang_code"}, drift.Unmodeled)
+	assert.Equal(t, []string{"field_language_code"}, drift.Missing)
+	assert.Contains(t, drift.String(), `present in Drupal but unmodeled: ["field_lang_code"]`)
+}
+
+func Test_SchemaDriftEmptyBundle(t *testing.T) {
+	server := newDocumentServer(map[string]string{})
+	defer server.Close()
+	setBaseUrl(t, server)
+
+	_, err := schemaDriftErr[JsonApiLanguage](query(t, TaxonomyTerm, "language"))
+	assert.Contains(t, fmt.Sprint(err), "unable to retrieve a taxonomy_term/language")
+}
+
+func Test_ModeledKeysFlattensEmbeddedStructs(t *testing.T) {
+	keys, err := modeledKeysErr(reflect.TypeOf(JsonApiIslandoraObj{}))
+	require.Nil(t, err, "%s", err)
+	for _, key := range []string{"status", "moderation_state", "Title", "field_jhir", "field_member_of"} {
+		assert.True(t, keys[key], "expected %s to be modeled", key)
+	}
+	assert.False(t, keys["JsonApiNodeAttributes"])
+
+	_, err = modeledKeysErr(reflect.TypeOf(Link{}))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,165 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// The taxonomy comparers assert that every modeled field of a taxonomy term matches the expected term, reporting a
+// failure for each mismatched field, in the manner of CompareRepoObj.  Names, descriptions, alternate names, and dates
+// are compared, along with the full list of authority links.  Authority links are compared as a set of
+// `title <uri> (source)` values, so that each missing or unexpected link is reported individually.
+
+// CompareSubject asserts that the Subject taxonomy term matches the expected subject
+func CompareSubject(t *testing.T, expected ExpectedSubject, actual JsonApiSubject, opts ...CompareOption) bool {
+	if !assertTermPresent(t, "subject", expected.Name, len(actual.JsonApiData)) {
+		return false
+	}
+	attrs := actual.JsonApiData[0].JsonApiAttributes
+
+	return compareTerm(t, "subject", expected.Name, termFields(expected.Name, attrs.Name, expected.Description,
+		attrs.Description, expected.Authority, attrs.Authority), opts)
+}
+
+// CompareGenre asserts that the Genre taxonomy term matches the expected genre
+func CompareGenre(t *testing.T, expected ExpectedGenre, actual JsonApiGenre, opts ...CompareOption) bool {
+	if !assertTermPresent(t, "genre", expected.Name, len(actual.JsonApiData)) {
+		return false
+	}
+	attrs := actual.JsonApiData[0].JsonApiAttributes
+
+	return compareTerm(t, "genre", expected.Name, termFields(expected.Name, attrs.Name, expected.Description,
+		attrs.Description, expected.Authority, attrs.Authority), opts)
+}
+
+// CompareLanguage asserts that the Language taxonomy term matches the expected language, including its language code
+func CompareLanguage(t *testing.T, expected ExpectedLanguage, actual JsonApiLanguage, opts ...CompareOption) bool {
+	if !assertTermPresent(t, "language", expected.Name, len(actual.JsonApiData)) {
+		return false
+	}
+	attrs := actual.JsonApiData[0].JsonApiAttributes
+
+	return compareTerm(t, "language", expected.Name, append(termFields(expected.Name, attrs.Name, expected.Description,
+		attrs.Description, expected.Authority, attrs.Authority),
+		field("LanguageCode", expected.LanguageCode, valueOf(attrs.LanguageCode)),
+	), opts)
+}
+
+// CompareGeolocation asserts that the Geolocation taxonomy term matches the expected geolocation.  Coordinates are
+// compared within CoordinateTolerance, and only if they are expected.
+func CompareGeolocation(t *testing.T, expected ExpectedGeolocation, actual JsonApiGeolocation, opts ...CompareOption) bool {
+	if !assertTermPresent(t, "geolocation", expected.Name, len(actual.JsonApiData)) {
+		return false
+	}
+	attrs := actual.JsonApiData[0].JsonApiAttributes
+
+	return compareTerm(t, "geolocation", expected.Name, append(termFields(expected.Name, attrs.Name,
+		expected.Description, attrs.Description, expected.Authority, attrs.Authority),
+		field("GeoAltName", expected.GeoAltName, valueOf(attrs.GeoAltName)),
+		field("Broader", expected.Broader, valueOf(attrs.Broader)),
+		field("Coordinates", expected.Coordinates, func() (interface{}, error) {
+			if attrs.Coordinates == nil {
+				return nil, fmt.Errorf("model: geolocation '%s' has no coordinates", attrs.Name)
+			}
+			return attrs.Coordinates.Coordinates()
+		}).ifExpected().comparedWith(func(expected, actual interface{}) bool {
+			e, eok := expected.(*Coordinates)
+			a, aok := actual.(Coordinates)
+			return eok && aok && e.Near(a, CoordinateTolerance)
+		}),
+	), opts)
+}
+
+// CompareCorporateBody asserts that the Corporate Body taxonomy term matches the expected corporate body.  The
+// relationships of the corporate body are not compared (see JsonApiCorporateBody.Relationships).
+func CompareCorporateBody(t *testing.T, expected ExpectedCorporateBody, actual JsonApiCorporateBody, opts ...CompareOption) bool {
+	if !assertTermPresent(t, "corporate body", expected.Name, len(actual.JsonApiData)) {
+		return false
+	}
+	attrs := actual.JsonApiData[0].JsonApiAttributes
+
+	return compareTerm(t, "corporate body", expected.Name, append(termFields(expected.Name, attrs.Name,
+		expected.Description, attrs.Description, expected.Authority, attrs.Authority),
+		field("PrimaryName", expected.PrimaryName, valueOf(attrs.PrimaryName)),
+		field("SubordinateName", expected.SubordinateName, valueOf(attrs.SubordinateName)),
+		field("DateOfMeeting", expected.DateOfMeeting, valueOf(attrs.DateOfMeeting)),
+		field("Location", expected.Location, valueOf(attrs.Location)),
+		field("NumberOrSection", expected.NumberOrSection, valueOf(attrs.NumberOrSection)),
+		field("AltName", expected.AltName, valueOf(attrs.AltName)),
+		field("Date", expected.Date, valueOf(attrs.Date)),
+	), opts)
+}
+
+// CompareFamily asserts that the Family taxonomy term matches the expected family.  The relationships of the family
+// are not compared (see JsonApiFamily.Relationships).
+func CompareFamily(t *testing.T, expected ExpectedFamily, actual JsonApiFamily, opts ...CompareOption) bool {
+	if !assertTermPresent(t, "family", expected.Name, len(actual.JsonApiData)) {
+		return false
+	}
+	attrs := actual.JsonApiData[0].JsonApiAttributes
+
+	return compareTerm(t, "family", expected.Name, append(termFields(expected.Name, attrs.Name, expected.Description,
+		attrs.Description, expected.Authority, attrs.Authority),
+		field("Date", expected.Date, valueOf(attrs.Date)),
+		field("FamilyName", expected.FamilyName, valueOf(attrs.FamilyName)),
+		field("Title", expected.Title, valueOf(attrs.Title)),
+	), opts)
+}
+
+// ComparePerson asserts that the Person taxonomy term matches the expected person.  As with AssertPerson, the display
+// name is compared rather than the individual parts of the name.  The relationships of the person are not compared
+// (see JsonApiPerson.Relationships).
+func ComparePerson(t *testing.T, expected ExpectedPerson, actual JsonApiPerson, opts ...CompareOption) bool {
+	if !assertTermPresent(t, "person", expected.Name, len(actual.JsonApiData)) {
+		return false
+	}
+	attrs := actual.JsonApiData[0].JsonApiAttributes
+
+	return compareTerm(t, "person", expected.Name, append(termFields(expected.Name, attrs.Name, expected.Description,
+		attrs.Description, personAuthorities(expected), attrs.Authority),
+		field("DisplayName", expected.DisplayName(), valueOf(attrs.DisplayName())),
+		field("AltName", expected.AltName, valueOf(attrs.PersonAlternateName)),
+		field("Date", expected.Date, valueOf(attrs.Dates)),
+	), opts)
+}
+
+// assertTermPresent asserts that the response carries the described taxonomy term
+func assertTermPresent(t *testing.T, kind, name string, n int) bool {
+	return assert.NotEqual(t, 0, n, "expected %s '%s', but the response is empty", kind, name)
+}
+
+// compareTerm asserts that the fields of the described taxonomy term match their expected values
+func compareTerm(t *testing.T, kind, name string, fields []comparedField, opts []CompareOption) bool {
+	return reportDiffs(t, fmt.Sprintf("%s '%s'", kind, name), diffFields(fields, newCompareOptions(opts)), opts)
+}
+
+// termFields answers the fields common to taxonomy terms: the name, the value of the description, and the authority
+// links
+func termFields(expectedName, actualName string, expectedDescription, actualDescription FormattedText,
+	expectedAuthority, actualAuthority []Authority) []comparedField {
+	return []comparedField{
+		field("Name", expectedName, valueOf(actualName)),
+		field("Description", expectedDescription.Value, valueOf(actualDescription.Value)),
+		field("Authority", authorityStrings(expectedAuthority), valueOf(authorityStrings(actualAuthority))),
+	}
+}
+
+// authorityStrings answers each authority link in the form `title <uri> (source)`, in order
+func authorityStrings(links []Authority) []string {
+	var result []string
+	for _, a := range links {
+		result = append(result, fmt.Sprintf("%s <%s> (%s)", a.Title, a.Uri, a.Source))
+	}
+	return result
+}
+
+// personAuthorities answers the expected authority links of the person as Authority values
+func personAuthorities(e ExpectedPerson) []Authority {
+	var result []Authority
+	for _, a := range e.Authority {
+		result = append(result, Authority{Uri: a.Uri, Title: a.Name, Source: a.Type})
+	}
+	return result
+}
@@ -0,0 +1,70 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CompareSubjectAuthority(t *testing.T) {
+	actual := JsonApiSubject{}
+	require.Nil(t, json.Unmarshal([]byte(`{"data": [{"type": "taxonomy_term--subject", "id": "00000001-0000-4000-8000-000000000000",
+		"attributes": {"name": "Analog Photography", "description": {"value": "<p>Analog photography.</p>"},
+		"field_authority_link": [
+			{"uri": "http://www.ford.com", "title": "Ford", "source": "iso19115"},
+			{"uri": "http://www.google.com", "title": "Google", "source": "other"}]}}]}`), &actual))
+
+	expected := ExpectedSubject{
+		Authority: []Authority{
+			{Uri: "http://www.google.com", Title: "Google", Source: "other"},
+			{Uri: "http://www.ford.com", Title: "Ford", Source: "iso19115"},
+		},
+		Description: FormattedText{Value: "<p>Analog photography.</p>"},
+	}
+	expected.Name = "Analog Photography"
+	assert.True(t, CompareSubject(t, expected, actual))
+
+	expected.Authority[1].Source = "lcnaf"
+	fields := termFields(expected.Name, actual.JsonApiData[0].JsonApiAttributes.Name, expected.Description,
+		actual.JsonApiData[0].JsonApiAttributes.Description, expected.Authority, actual.JsonApiData[0].JsonApiAttributes.Authority)
+	diffs := diffFields(fields, newCompareOptions(nil))
+	require.Equal(t, 1, len(diffs), "%v", diffs)
+	assert.Equal(t, "Authority", diffs[0].Field)
+	assert.Equal(t, []string{"Ford <http://www.ford.com> (lcnaf)"}, diffs[0].Missing)
+	assert.Equal(t, []string{"Ford <http://www.ford.com> (iso19115)"}, diffs[0].Unexpected)
+}
+
+func Test_CompareTerms(t *testing.T) {
+	geo := JsonApiGeolocation{}
+	require.Nil(t, json.Unmarshal([]byte(`{"data": [{"type": "taxonomy_term--geo_location", "id": "00000001-0000-4000-8000-000000000000",
+		"attributes": {"name": "Baltimore", "field_geo_alt_name": ["Charm City"],
+		"field_coordinates": {"value": "POINT (-76.6121893 39.2903848)", "geo_type": "Point"}}}]}`), &geo))
+
+	expectedGeo := ExpectedGeolocation{GeoAltName: []string{"Charm City"}, Coordinates: &Coordinates{Lat: 39.2903848, Lon: -76.6121893}}
+	expectedGeo.Name = "Baltimore"
+	assert.True(t, CompareGeolocation(t, expectedGeo, geo))
+
+	person := JsonApiPerson{}
+	require.Nil(t, json.Unmarshal([]byte(`{"data": [{"type": "taxonomy_term--person", "id": "00000002-0000-4000-8000-000000000000",
+		"attributes": {"name": "Hopkins, Johns", "field_primary_part_of_name": "Hopkins", "field_preferred_name_rest": ["Johns"],
+		"field_authority_link": [{"uri": "http://id.loc.gov/authorities/names/n50043364", "title": "LCNAF", "source": "lcnaf"}]}}]}`), &person))
+
+	expectedPerson := ExpectedPerson{PrimaryName: "Hopkins", RestOfName: []string{"Johns"}}
+	expectedPerson.Authority = append(expectedPerson.Authority, struct {
+		Uri  string
+		Name string
+		Type string
+	}{"http://id.loc.gov/authorities/names/n50043364", "LCNAF", "lcnaf"})
+	expectedPerson.Name = "Hopkins, Johns"
+	assert.True(t, ComparePerson(t, expectedPerson, person))
+
+	language := JsonApiLanguage{}
+	require.Nil(t, json.Unmarshal([]byte(`{"data": [{"type": "taxonomy_term--language", "id": "00000003-0000-4000-8000-000000000000",
+		"attributes": {"name": "English", "field_language_code": "en"}}]}`), &language))
+
+	expectedLanguage := ExpectedLanguage{LanguageCode: "en"}
+	expectedLanguage.Name = "English"
+	assert.True(t, CompareLanguage(t, expectedLanguage, language))
+}
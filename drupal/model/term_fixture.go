@@ -0,0 +1,173 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/stretchr/testify/require"
+)
+
+// The vocabulary of Genre terms
+const genreVocabulary = "genre"
+
+// Describes a term, e.g. a subject or genre, to be created by CreateSubject or CreateGenre.  Only the Name is required.
+type TermSpec struct {
+	Name        string
+	Description string
+	Authorities []Authority
+}
+
+// Describes a typed relationship from a new person or corporate body to an existing agent
+type RelationshipSpec struct {
+	// The type of the relationship, e.g. `schema:knows`
+	RelType string
+	// The uuid of the target of the relationship
+	TargetUuid string
+	// The bundle of the target: Person, Family, or CorporateBody.  Defaults to Person.
+	TargetKind string
+}
+
+// Describes a person to be created by CreatePerson.  Either the Name or the PrimaryName is required.
+type PersonSpec struct {
+	// The name of the person; defaults to the display name assembled from the parts of the name
+	Name          string
+	PrimaryName   string
+	RestOfName    []string
+	FullerForm    []string
+	Prefix        []string
+	Suffix        []string
+	Number        []string
+	AltNames      []string
+	Dates         []string
+	Description   string
+	Authorities   []Authority
+	Relationships []RelationshipSpec
+}
+
+// Describes a corporate body to be created by CreateCorporateBody.  Only the Name is required.
+type CorporateBodySpec struct {
+	Name             string
+	PrimaryName      string
+	SubordinateNames []string
+	AltNames         []string
+	Dates            []string
+	Description      string
+	Authorities      []Authority
+	Relationships    []RelationshipSpec
+}
+
+// CreatePerson creates a person taxonomy term described by the spec, and answers the created person.  The test fails
+// immediately if the person cannot be created.  Relationships to other agents carry their type as the `rel_type` meta
+// value, so they may be read back using JsonApiPerson.Relationships.  The person is created as the user from the
+// environment (or as supplied by the options), and is deleted when the test completes:
+//
+//	hopkins := model.CreatePerson(t, model.PersonSpec{PrimaryName: "Hopkins", RestOfName: []string{"Johns"}})
+//	model.CreatePerson(t, model.PersonSpec{Name: "Moo, Cow", Relationships: []model.RelationshipSpec{
+//		{RelType: "schema:knows", TargetUuid: hopkins.JsonApiData[0].Id}}})
+func CreatePerson(t *testing.T, spec PersonSpec, opts ...Option) JsonApiPerson {
+	name := spec.Name
+	if name == "" {
+		name = displayName(spec.Prefix, spec.PrimaryName, spec.RestOfName, spec.Number, spec.FullerForm, spec.Suffix)
+	}
+	require.NotEmpty(t, name, "unable to create person: a Name or PrimaryName is required")
+
+	r := termResource(TermSpec{Name: name, Description: spec.Description, Authorities: spec.Authorities})
+	setNonEmpty(r.Attributes, map[string]interface{}{
+		"field_primary_part_of_name":       spec.PrimaryName,
+		"field_preferred_name_rest":        spec.RestOfName,
+		"field_preferred_name_fuller_form": spec.FullerForm,
+		"field_preferred_name_prefix":      spec.Prefix,
+		"field_preferred_name_suffix":      spec.Suffix,
+		"field_preferred_name_number":      spec.Number,
+		"field_person_alternate_name":      spec.AltNames,
+		"field_date":                       spec.Dates,
+	})
+	setRelationships(r, spec.Relationships)
+	return create[JsonApiPerson](t, TaxonomyTerm, Person, r, opts)
+}
+
+// CreateCorporateBody creates a corporate body taxonomy term described by the spec, and answers the created corporate
+// body.  It otherwise behaves as CreatePerson.
+func CreateCorporateBody(t *testing.T, spec CorporateBodySpec, opts ...Option) JsonApiCorporateBody {
+	require.NotEmpty(t, spec.Name, "unable to create corporate body: a Name is required")
+
+	r := termResource(TermSpec{Name: spec.Name, Description: spec.Description, Authorities: spec.Authorities})
+	setNonEmpty(r.Attributes, map[string]interface{}{
+		"field_primary_name":            spec.PrimaryName,
+		"field_subordinate_name":        spec.SubordinateNames,
+		"field_corporate_body_alt_name": spec.AltNames,
+		"field_date":                    spec.Dates,
+	})
+	setRelationships(r, spec.Relationships)
+	return create[JsonApiCorporateBody](t, TaxonomyTerm, CorporateBody, r, opts)
+}
+
+// CreateSubject creates a subject taxonomy term described by the spec, and answers the created subject.  The test
+// fails immediately if the subject cannot be created.  The subject is created as the user from the environment (or as
+// supplied by the options), and is deleted when the test completes.
+func CreateSubject(t *testing.T, spec TermSpec, opts ...Option) JsonApiSubject {
+	require.NotEmpty(t, spec.Name, "unable to create subject: a Name is required")
+	return create[JsonApiSubject](t, TaxonomyTerm, subjectVocabulary, termResource(spec), opts)
+}
+
+// CreateGenre creates a genre taxonomy term described by the spec, and answers the created genre.  It otherwise
+// behaves as CreateSubject.
+func CreateGenre(t *testing.T, spec TermSpec, opts ...Option) JsonApiGenre {
+	require.NotEmpty(t, spec.Name, "unable to create genre: a Name is required")
+	return create[JsonApiGenre](t, TaxonomyTerm, genreVocabulary, termResource(spec), opts)
+}
+
+// termResource answers the resource carrying the name, description, and authority links common to every term
+func termResource(spec TermSpec) jsonapi.Resource {
+	r := jsonapi.Resource{
+		Attributes: map[string]interface{}{
+			"name": spec.Name,
+		},
+		Relationships: map[string]interface{}{},
+	}
+	if spec.Description != "" {
+		r.Attributes["description"] = map[string]string{"value": spec.Description}
+	}
+	if len(spec.Authorities) > 0 {
+		links := make([]map[string]string, len(spec.Authorities))
+		for i, a := range spec.Authorities {
+			links[i] = map[string]string{"uri": a.Uri, "title": a.Title, "source": a.Source}
+		}
+		r.Attributes["field_authority_link"] = links
+	}
+	return r
+}
+
+// setRelationships sets the field_relationships of the resource to the typed relationships, if any
+func setRelationships(r jsonapi.Resource, rels []RelationshipSpec) {
+	if len(rels) == 0 {
+		return
+	}
+	ids := make([]jsonapi.ResourceIdentifier, len(rels))
+	for i, rel := range rels {
+		kind := rel.TargetKind
+		if kind == "" {
+			kind = Person
+		}
+		ids[i] = jsonapi.ResourceIdentifier{Type: jsonapi.NewDrupalType(TaxonomyTerm, kind), Id: rel.TargetUuid,
+			Meta: map[string]string{relTypeKey: rel.RelType}}
+	}
+	r.Relationships["field_relationships"] = jsonapi.ToMany(ids...)
+}
+
+// setNonEmpty sets each of the values that is not empty, i.e. not the empty string or an empty slice
+func setNonEmpty(attributes map[string]interface{}, values map[string]interface{}) {
+	for field, value := range values {
+		switch v := value.(type) {
+		case string:
+			if v == "" {
+				continue
+			}
+		case []string:
+			if len(v) == 0 {
+				continue
+			}
+		}
+		attributes[field] = value
+	}
+}
@@ -0,0 +1,57 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CreatePersonRelationships(t *testing.T) {
+	d := newFakeDrupal(t, map[string]string{})
+
+	var ids []string
+	t.Run("create", func(t *testing.T) {
+		hopkins := CreatePerson(t, PersonSpec{PrimaryName: "Hopkins", RestOfName: []string{"Johns"},
+			Authorities: []Authority{{Uri: "http://id.loc.gov/authorities/names/n79065573", Source: "lcnaf"}}})
+		require.Equal(t, 1, len(hopkins.JsonApiData))
+		attrs := hopkins.JsonApiData[0].JsonApiAttributes
+		assert.Equal(t, "Hopkins, Johns", attrs.Name)
+		assert.Equal(t, "Hopkins", attrs.PrimaryPartOfName)
+		require.Equal(t, 1, len(attrs.Authority))
+		assert.True(t, attrs.Authority[0].IsLCNAF())
+
+		club := CreateCorporateBody(t, CorporateBodySpec{Name: "Sierra Club", AltNames: []string{"SC"}})
+		assert.Equal(t, []string{"SC"}, club.JsonApiData[0].JsonApiAttributes.AltName)
+
+		cow := CreatePerson(t, PersonSpec{Name: "Moo, Cow", Relationships: []RelationshipSpec{
+			{RelType: "schema:knows", TargetUuid: hopkins.JsonApiData[0].Id},
+			{RelType: "schema:memberOf", TargetUuid: club.JsonApiData[0].Id, TargetKind: CorporateBody},
+		}})
+		assert.Equal(t, []Relationship{
+			{RelType: "schema:knows", Target: JsonApiData{Type: "taxonomy_term--person", Id: hopkins.JsonApiData[0].Id},
+				TargetName: "Hopkins, Johns", TargetKind: Person},
+			{RelType: "schema:memberOf", Target: JsonApiData{Type: "taxonomy_term--corporate_body",
+				Id: club.JsonApiData[0].Id}, TargetName: "Sierra Club", TargetKind: CorporateBody},
+		}, cow.Relationships(t))
+		ids = []string{cow.JsonApiData[0].Id, club.JsonApiData[0].Id, hopkins.JsonApiData[0].Id}
+	})
+
+	assert.Equal(t, []string{"/jsonapi/taxonomy_term/person/" + ids[0], "/jsonapi/taxonomy_term/corporate_body/" + ids[1],
+		"/jsonapi/taxonomy_term/person/" + ids[2]}, d.deleted)
+}
+
+func Test_CreateSubjectAndGenre(t *testing.T) {
+	d := newFakeDrupal(t, map[string]string{})
+
+	t.Run("create", func(t *testing.T) {
+		subject := CreateSubject(t, TermSpec{Name: "Analog Photography", Description: "Film"})
+		assert.Equal(t, "Analog Photography", subject.JsonApiData[0].JsonApiAttributes.Name)
+		assert.Equal(t, "Film", subject.JsonApiData[0].JsonApiAttributes.Description.Value)
+
+		genre := CreateGenre(t, TermSpec{Name: "Landscapes"})
+		assert.Equal(t, "taxonomy_term--genre", string(genre.JsonApiData[0].Type))
+	})
+
+	assert.Equal(t, 2, len(d.deleted))
+}
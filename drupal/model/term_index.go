@@ -0,0 +1,200 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/stretchr/testify/require"
+)
+
+// Answered (wrapped) by FindTermErr when several terms of the vocabulary have the name
+var ErrAmbiguousTerm = fmt.Errorf("%w: several terms have the name", ErrInvalidData)
+
+// The term indexes built by TermIndex, keyed by the base url and vocabulary
+var termIndexes = sync.Map{}
+
+// TermNameIndex is the index of the terms of a vocabulary by name
+type TermNameIndex struct {
+	Vocabulary string
+	terms      map[string][]JsonApiData
+}
+
+// Lookup answers the terms with the name, in the order they were retrieved, or nil if there are none.  Names are
+// matched exactly.
+func (idx TermNameIndex) Lookup(name string) []JsonApiData {
+	return idx.terms[name]
+}
+
+// Names answers the names of the terms of the vocabulary, in order
+func (idx TermNameIndex) Names() []string {
+	names := make([]string, 0, len(idx.terms))
+	for name := range idx.terms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// nearMisses answers the names of the vocabulary that differ from the name only by case or whitespace
+func (idx TermNameIndex) nearMisses(name string) []string {
+	var misses []string
+	for _, n := range idx.Names() {
+		if n != name && termNameKey(n) == termNameKey(name) {
+			misses = append(misses, n)
+		}
+	}
+	return misses
+}
+
+// TermIndex answers the index of the terms of the vocabulary by name, failing the test immediately if the vocabulary
+// cannot be retrieved.  The vocabulary is retrieved once, a page at a time, carrying only the names of its terms; the
+// index is cached for the remainder of the run (see ResetTermCache) unless options are supplied:
+//
+//	subjects := model.TermIndex(t, "subject")
+//	moon := subjects.Lookup("Moon")
+func TermIndex(t *testing.T, vocabulary string, opts ...Option) TermNameIndex {
+	idx, err := TermIndexErr(vocabulary, opts...)
+	require.Nil(t, err, "%s", err)
+	return idx
+}
+
+// TermIndexErr behaves as TermIndex, but answers an error instead of failing the test
+func TermIndexErr(vocabulary string, opts ...Option) (TermNameIndex, error) {
+	key := fmt.Sprintf("%s %s", env.BaseUrlOr(defaultBaseUrl), vocabulary)
+	if len(opts) == 0 {
+		if cached, ok := termIndexes.Load(key); ok {
+			return cached.(TermNameIndex), nil
+		}
+	}
+
+	idx := TermNameIndex{Vocabulary: vocabulary, terms: map[string][]JsonApiData{}}
+	u := query(nil, TaxonomyTerm, vocabulary, opts...)
+	u.RawFilter = fmt.Sprintf("fields[%s]=name", jsonapi.NewDrupalType(TaxonomyTerm, vocabulary))
+	page := struct {
+		Data []struct {
+			JsonApiData
+			Attributes struct {
+				Name string
+			}
+		}
+	}{}
+	err := u.EachPageErr(&page, func() error {
+		for _, term := range page.Data {
+			idx.terms[term.Attributes.Name] = append(idx.terms[term.Attributes.Name], term.JsonApiData)
+		}
+		return nil
+	})
+	if err != nil {
+		return TermNameIndex{}, fmt.Errorf("model: unable to index the %s vocabulary: %w", vocabulary, err)
+	}
+
+	if len(opts) == 0 {
+		termIndexes.Store(key, idx)
+	}
+	return idx, nil
+}
+
+// languageCodeIndexErr answers the UUIDs of the Language taxonomy terms keyed by their language code.  The vocabulary is
+// retrieved once, a page at a time, carrying only the codes of its terms, and cached as TermIndexErr caches the index of a
+// vocabulary.  The code of each term is also cached by its UUID (see JsonApiLanguageValue.LangCode).
+func languageCodeIndexErr(opts ...Option) (map[string]string, error) {
+	key := fmt.Sprintf("%s %s field_language_code", env.BaseUrlOr(defaultBaseUrl), languageVocabulary)
+	if len(opts) == 0 {
+		if cached, ok := termIndexes.Load(key); ok {
+			return cached.(map[string]string), nil
+		}
+	}
+
+	idx := map[string]string{}
+	u := query(nil, TaxonomyTerm, languageVocabulary, opts...)
+	u.RawFilter = fmt.Sprintf("fields[%s]=field_language_code", jsonapi.NewDrupalType(TaxonomyTerm, languageVocabulary))
+	page := struct {
+		Data []struct {
+			JsonApiData
+			Attributes struct {
+				LanguageCode string `json:"field_language_code"`
+			}
+		}
+	}{}
+	err := u.EachPageErr(&page, func() error {
+		for _, term := range page.Data {
+			idx[term.Attributes.LanguageCode] = term.Id
+			langCodes.Store(langCodeKey(opts, term.Id), term.Attributes.LanguageCode)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("model: unable to index the %s vocabulary: %w", languageVocabulary, err)
+	}
+
+	if len(opts) == 0 {
+		termIndexes.Store(key, idx)
+	}
+	return idx, nil
+}
+
+// FindTerm answers the data object of the term of the vocabulary with the name, using the index of the vocabulary
+// (see TermIndex), failing the test immediately if there is no such term, or several.  If there is no such term, the
+// failure suggests the names that differ from it only by case or whitespace:
+//
+//	moon := model.FindTerm(t, "subject", "Moon")
+func FindTerm(t *testing.T, vocabulary, name string, opts ...Option) JsonApiData {
+	term, err := FindTermErr(vocabulary, name, opts...)
+	require.Nil(t, err, "%s", err)
+	return term
+}
+
+// FindTermErr behaves as FindTerm, but answers an error instead of failing the test.  The error wraps
+// jsonapi.ErrNotFound if there is no such term, or ErrAmbiguousTerm if there are several.
+func FindTermErr(vocabulary, name string, opts ...Option) (JsonApiData, error) {
+	idx, err := TermIndexErr(vocabulary, opts...)
+	if err != nil {
+		return JsonApiData{}, err
+	}
+
+	terms := idx.Lookup(name)
+	switch len(terms) {
+	case 1:
+		return terms[0], nil
+	case 0:
+		if misses := idx.nearMisses(name); len(misses) > 0 {
+			return JsonApiData{}, fmt.Errorf("%w: no %s term is named '%s'; did you mean %s?", jsonapi.ErrNotFound,
+				vocabulary, name, quoteAll(misses))
+		}
+		return JsonApiData{}, fmt.Errorf("%w: no %s term is named '%s'", jsonapi.ErrNotFound, vocabulary, name)
+	}
+
+	ids := make([]string, len(terms))
+	for i, term := range terms {
+		ids[i] = term.Id
+	}
+	return JsonApiData{}, fmt.Errorf("%w: %d %s terms are named '%s': %s", ErrAmbiguousTerm, len(terms), vocabulary,
+		name, strings.Join(ids, ", "))
+}
+
+// resetTermIndexes discards the cached term indexes
+func resetTermIndexes() {
+	termIndexes.Range(func(key, _ interface{}) bool {
+		termIndexes.Delete(key)
+		return true
+	})
+}
+
+// termNameKey answers the name without regard to case or whitespace
+func termNameKey(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), ""))
+}
+
+// quoteAll answers the values, each quoted, separated by `or`
+func quoteAll(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = "'" + v + "'"
+	}
+	return strings.Join(quoted, " or ")
+}
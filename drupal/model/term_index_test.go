@@ -0,0 +1,51 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TermIndexAndFindTerm(t *testing.T) {
+	t.Cleanup(ResetTermCache)
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "/jsonapi/taxonomy_term/subject", r.URL.Path)
+		if r.URL.Query().Get("page[offset]") == "" {
+			assert.Equal(t, "name", r.URL.Query().Get("fields[taxonomy_term--subject]"))
+			_, _ = fmt.Fprintf(w, `{"data": [
+				{"type": "taxonomy_term--subject", "id": "%s", "attributes": {"name": "Moon"}},
+				{"type": "taxonomy_term--subject", "id": "%s", "attributes": {"name": "Cows"}}],
+				"links": {"next": {"href": "http://%s%s?page[offset]=2"}}}`, testUuid(1), testUuid(2), r.Host, r.URL.Path)
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"data": [{"type": "taxonomy_term--subject", "id": "%s", "attributes": {"name": "Cows"}},
+			{"type": "taxonomy_term--subject", "id": "%s", "attributes": {"name": "Sea  Shells"}}]}`, testUuid(3), testUuid(4))
+	}))
+	defer server.Close()
+	setBaseUrl(t, server)
+
+	idx := TermIndex(t, "subject")
+	assert.Equal(t, []string{"Cows", "Moon", "Sea  Shells"}, idx.Names())
+	assert.Len(t, idx.Lookup("Cows"), 2)
+	assert.Equal(t, testUuid(1), FindTerm(t, "subject", "Moon").Id)
+	assert.Equal(t, 2, requests, "the vocabulary is expected to be indexed once")
+
+	_, err := FindTermErr("subject", "Cows")
+	assert.True(t, errors.Is(err, ErrAmbiguousTerm), "expected ErrAmbiguousTerm, got %v", err)
+	_, err = FindTermErr("subject", "sea shells")
+	assert.True(t, errors.Is(err, jsonapi.ErrNotFound), "expected ErrNotFound, got %v", err)
+	assert.Contains(t, fmt.Sprint(err), "did you mean 'Sea  Shells'?")
+	_, err = FindTermErr("subject", "Sun")
+	assert.NotContains(t, fmt.Sprint(err), "did you mean")
+
+	ResetTermCache()
+	TermIndex(t, "subject")
+	assert.Equal(t, 4, requests)
+}
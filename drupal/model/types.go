@@ -0,0 +1,45 @@
+package model
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+// Represents an authority link, e.g. the value of `field_authority_link` on a taxonomy term
+type Authority struct {
+	Uri    string
+	Title  string
+	Source string
+}
+
+// Represents a link field, e.g. the value of `field_finding_aid` or `field_external_uri`
+type Link struct {
+	Uri   string
+	Title string
+}
+
+// Represents a formatted text field, e.g. the `description` of a taxonomy term
+type FormattedText struct {
+	Value     string
+	Format    string
+	Processed string
+}
+
+// The authority source used by Drupal for Library of Congress Name Authority File links
+const lcnafSource = "lcnaf"
+
+// IsLCNAF answers true if the authority link refers to the Library of Congress Name Authority File, either by its
+// source or by its URI
+func (a Authority) IsLCNAF() bool {
+	return strings.EqualFold(a.Source, lcnafSource) || strings.Contains(a.Uri, "id.loc.gov/authorities/names")
+}
+
+// matches markup tags in processed text
+var markupPattern = regexp.MustCompile(`<[^>]*>`)
+
+// Plain answers the processed text with its markup stripped and HTML entities unescaped, e.g.
+// `<p>Analog photography &amp; film</p>` becomes `Analog photography & film`
+func (ft FormattedText) Plain() string {
+	return strings.TrimSpace(html.UnescapeString(markupPattern.ReplaceAllString(ft.Processed, "")))
+}
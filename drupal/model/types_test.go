@@ -0,0 +1,20 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_AuthorityIsLCNAF(t *testing.T) {
+	assert.True(t, Authority{Uri: "http://id.loc.gov/authorities/names/n79022889", Source: "lcnaf"}.IsLCNAF())
+	assert.True(t, Authority{Uri: "https://id.loc.gov/authorities/names/n79022889", Source: "other"}.IsLCNAF())
+	assert.False(t, Authority{Uri: "http://id.loc.gov/authorities/subjects/sh85100849", Source: "lcsh"}.IsLCNAF())
+	assert.False(t, Authority{Uri: "http://www.google.com", Source: "other"}.IsLCNAF())
+}
+
+func Test_FormattedTextPlain(t *testing.T) {
+	assert.Equal(t, "Analog photography & film", FormattedText{Processed: "<p>Analog photography &amp; film</p>\n"}.Plain())
+	assert.Equal(t, "Line one\nLine two", FormattedText{Processed: "<p>Line one<br />\nLine two</p>"}.Plain())
+	assert.Equal(t, "", FormattedText{}.Plain())
+}
@@ -0,0 +1,78 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	// Constant for the Drupal user entity type (which is also its only bundle)
+	User = "user"
+	// Constant for the Drupal user role entity type (which is also its only bundle)
+	UserRole = "user_role"
+)
+
+// Represents the results of a JSONAPI query for a single Drupal user account
+type JsonApiUser struct {
+	JsonApiData []struct {
+		Type              jsonapi.DrupalType
+		Id                string
+		JsonApiAttributes struct {
+			Name    string `json:"name"`
+			Mail    string `json:"mail"`
+			Status  bool   `json:"status"`
+			Created string `json:"created"`
+		} `json:"attributes"`
+		JsonApiRelationships struct {
+			Roles struct {
+				Data []JsonApiData
+			} `json:"roles"`
+		} `json:"relationships"`
+	} `json:"data"`
+}
+
+// Represents the results of a JSONAPI query for a single Drupal user role
+type JsonApiUserRole struct {
+	JsonApiData []struct {
+		Type              jsonapi.DrupalType
+		Id                string
+		JsonApiAttributes struct {
+			// The machine name of the role, e.g. `collection_manager`
+			MachineName string `json:"drupal_internal__id"`
+			Label       string `json:"label"`
+		} `json:"attributes"`
+	} `json:"data"`
+}
+
+// FindUserByName retrieves the user account with the supplied name, asserting that exactly one account matches.
+// Listing user accounts typically requires authentication, so the request is issued using the credentials from the
+// environment (see Resolve).
+func FindUserByName(t *testing.T, name string) JsonApiUser {
+	u := query(t, User, User)
+	u.Filter = "name"
+	u.Value = name
+
+	user := JsonApiUser{}
+	u.GetSingle(&user)
+	return user
+}
+
+// RoleNames resolves the roles of the first user account in the response, and answers their machine names, e.g.
+// `collection_manager`
+func (user JsonApiUser) RoleNames(t *testing.T) []string {
+	if !assert.NotEmpty(t, user.JsonApiData, "unable to resolve roles of an empty user response") {
+		return nil
+	}
+
+	var names []string
+	for _, roleData := range user.JsonApiData[0].JsonApiRelationships.Roles.Data {
+		role := JsonApiUserRole{}
+		roleData.Resolve(t, &role)
+		if len(role.JsonApiData) > 0 {
+			names = append(names, role.JsonApiData[0].JsonApiAttributes.MachineName)
+		}
+	}
+	return names
+}
@@ -0,0 +1,38 @@
+package model
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_FindUserByName(t *testing.T) {
+	role := testUuid(1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch collectionPath(r) {
+		case "/jsonapi/user/user":
+			assert.Equal(t, "collection_manager_user", r.URL.Query().Get("filter[name]"))
+			_, _ = fmt.Fprintf(w, `{"data": [{"type": "user--user", "id": "%s",
+				"attributes": {"name": "collection_manager_user", "mail": "cm@example.org", "status": true, "created": "2021-06-01T12:00:00+00:00"},
+				"relationships": {"roles": {"data": [{"type": "user_role--user_role", "id": "%s"}]}}}]}`, testUuid(0), role)
+		case "/jsonapi/user_role/user_role":
+			assert.Equal(t, role, requestedId(r))
+			_, _ = fmt.Fprintf(w, `{"data": [{"type": "user_role--user_role", "id": "%s",
+				"attributes": {"drupal_internal__id": "collection_manager", "label": "Collection Manager"}}]}`, role)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	setBaseUrl(t, server)
+
+	user := FindUserByName(t, "collection_manager_user")
+	require.Equal(t, 1, len(user.JsonApiData))
+	assert.Equal(t, "cm@example.org", user.JsonApiData[0].JsonApiAttributes.Mail)
+	assert.True(t, user.JsonApiData[0].JsonApiAttributes.Status)
+	assert.Equal(t, []string{"collection_manager"}, user.RoleNames(t))
+}
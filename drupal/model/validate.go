@@ -0,0 +1,67 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+)
+
+var ErrInvalidData = errors.New("invalid JSON API data")
+
+// Answered (wrapped) when a zero data object is validated or resolved, i.e. the relationship carrying it was not set;
+// it also wraps ErrInvalidData
+var ErrRelationshipNotSet = fmt.Errorf("%w: relationship not set", ErrInvalidData)
+
+// matches a well-formed RFC 4122 UUID (versions 1 through 5), which excludes the nil UUID
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[1-5][0-9a-fA-F]{3}-[89abAB][0-9a-fA-F]{3}-[0-9a-fA-F]{12}$`)
+
+// Validate answers an error wrapping ErrInvalidData if the identifier of the data object is not a well-formed RFC 4122
+// UUID, or if its type does not carry both an entity and a bundle, e.g. `taxonomy_term--subject`.
+//
+// Migrations may emit relationships which point to malformed or placeholder identifiers (e.g. "missing"); validating
+// the data object prior to resolving it surfaces those problems clearly.
+func (jad JsonApiData) Validate() error {
+	if jad.IsZero() {
+		return ErrRelationshipNotSet
+	}
+
+	parts := strings.Split(string(jad.Type), "--")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return fmt.Errorf("%w: type '%s' (id '%s') must have the form 'entity--bundle'", ErrInvalidData, jad.Type, jad.Id)
+	}
+
+	if !uuidPattern.MatchString(jad.Id) {
+		return fmt.Errorf("%w: id '%s' (type '%s') is not a well-formed UUID", ErrInvalidData, jad.Id, jad.Type)
+	}
+
+	return nil
+}
+
+// IsZero answers true if neither the type nor the identifier of the data object are set, i.e. the relationship
+// carrying this data object was not set.  A data object that carries a type but an empty identifier is not zero.
+func (jad JsonApiData) IsZero() bool {
+	return jad.Type == "" && jad.Id == ""
+}
+
+// IsPresent answers true if the relationship carrying the data object was set.  Drupal answers `"data": null` for an
+// empty single-valued relationship (e.g. the `field_member_of` of a root collection, or an unset
+// `field_copyright_and_use`), which decodes to the zero data object; check IsPresent before resolving such a
+// relationship, as resolving it fails with ErrRelationshipNotSet.
+func (jad JsonApiData) IsPresent() bool {
+	return !jad.IsZero()
+}
+
+// Equal answers true if the supplied data object has the same type and identifier as this data object.  Identifiers
+// are compared case-insensitively.
+func (jad JsonApiData) Equal(other JsonApiData) bool {
+	return jad.Type == other.Type && strings.EqualFold(jad.Id, other.Id)
+}
+
+// Identifier answers the identifier of the data object, e.g. to set a relationship of a resource to it (see
+// jsonapi.DocumentBuilder)
+func (jad JsonApiData) Identifier() jsonapi.ResourceIdentifier {
+	return jsonapi.ResourceIdentifier{Type: jad.Type, Id: jad.Id}
+}
@@ -0,0 +1,102 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Validate(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  JsonApiData
+		valid bool
+	}{
+		{"valid", JsonApiData{Type: "taxonomy_term--subject", Id: "7397e0c4-df0a-4800-95af-afccc6ff64a5"}, true},
+		{"upper case", JsonApiData{Type: "node--islandora_object", Id: "7397E0C4-DF0A-4800-95AF-AFCCC6FF64A5"}, true},
+		{"zero", JsonApiData{}, false},
+		{"placeholder id", JsonApiData{Type: "taxonomy_term--subject", Id: "missing"}, false},
+		{"empty id", JsonApiData{Type: "taxonomy_term--subject"}, false},
+		{"nil uuid", JsonApiData{Type: "taxonomy_term--subject", Id: "00000000-0000-0000-0000-000000000000"}, false},
+		{"missing bundle", JsonApiData{Type: "taxonomy_term", Id: "7397e0c4-df0a-4800-95af-afccc6ff64a5"}, false},
+		{"empty bundle", JsonApiData{Type: "taxonomy_term--", Id: "7397e0c4-df0a-4800-95af-afccc6ff64a5"}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.data.Validate()
+			if test.valid {
+				assert.Nil(t, err)
+			} else {
+				assert.True(t, errors.Is(err, ErrInvalidData), "expected ErrInvalidData, got %v", err)
+			}
+		})
+	}
+}
+
+func Test_IsZero(t *testing.T) {
+	assert.True(t, JsonApiData{}.IsZero())
+	assert.False(t, JsonApiData{Type: "taxonomy_term--subject"}.IsZero())
+	assert.False(t, JsonApiData{Id: "7397e0c4-df0a-4800-95af-afccc6ff64a5"}.IsZero())
+}
+
+func Test_NullRelationships(t *testing.T) {
+	obj := JsonApiIslandoraObj{}
+	require.Nil(t, json.Unmarshal([]byte(`{"data": [{"type": "node--islandora_object", "id": "moo", "relationships": {
+		"field_member_of": {"data": null},
+		"field_copyright_and_use": {"data": null},
+		"field_model": {"data": null},
+		"field_display_hints": {"data": null},
+		"field_title_language": {"data": null},
+		"field_subject": {"data": []},
+		"field_creator": {"data": null}}}]}`), &obj))
+	rels := obj.JsonApiData[0].JsonApiRelationships
+	for name, jad := range map[string]JsonApiData{"member of": rels.MemberOf.Data,
+		"copyright and use": rels.CopyrightAndUse.Data, "model": rels.Model.Data, "display hint": rels.DisplayHint.Data,
+		"title language": rels.TitleLanguage.Data} {
+		assert.False(t, jad.IsPresent(), "%s is not expected to be present", name)
+		err := jad.ResolveErr(&labeled{})
+		assert.True(t, errors.Is(err, ErrRelationshipNotSet), "%s: expected ErrRelationshipNotSet, got %v", name, err)
+		assert.Contains(t, fmt.Sprint(err), "relationship not set")
+	}
+	assert.Empty(t, rels.Subject.Data)
+	assert.Empty(t, rels.Creator.Data)
+
+	col := JsonApiCollection{}
+	require.Nil(t, json.Unmarshal([]byte(`{"data": [{"type": "node--collection_object", "id": "moo", "relationships": {
+		"field_member_of": {"data": null},
+		"field_title_language": {"data": null},
+		"field_access_terms": {"data": null}}}]}`), &col))
+	colRels := col.JsonApiData[0].JsonApiRelationships
+	assert.False(t, colRels.MemberOf.Data.IsPresent())
+	assert.False(t, colRels.TitleLanguage.Data.IsPresent())
+	assert.Empty(t, colRels.AccessTerms.Data)
+
+	assert.True(t, JsonApiData{Type: "taxonomy_term--subject", Id: testUuid(1)}.IsPresent())
+	_, err := ResolveAsErr[JsonApiSubject](JsonApiData{})
+	assert.True(t, errors.Is(err, ErrInvalidData), "expected ErrInvalidData, got %v", err)
+}
+
+func Test_Equal(t *testing.T) {
+	a := JsonApiData{Type: "taxonomy_term--subject", Id: "7397e0c4-df0a-4800-95af-afccc6ff64a5"}
+
+	assert.True(t, a.Equal(a))
+	assert.True(t, a.Equal(JsonApiData{Type: "taxonomy_term--subject", Id: "7397E0C4-DF0A-4800-95AF-AFCCC6FF64A5"}))
+	assert.False(t, a.Equal(JsonApiData{Type: "taxonomy_term--genre", Id: a.Id}))
+	assert.False(t, a.Equal(JsonApiData{Type: a.Type, Id: "bacfc5b6-b4b9-4239-8744-46dca6a91f0e"}))
+}
+
+func Test_Identifier(t *testing.T) {
+	a := JsonApiData{Type: "taxonomy_term--subject", Id: "7397e0c4-df0a-4800-95af-afccc6ff64a5"}
+	b, err := json.Marshal(jsonapi.NewDocumentBuilder("node--islandora_object").
+		SetRelationship("field_subject", a.Identifier()))
+	require.Nil(t, err, "%s", err)
+	assert.JSONEq(t, `{"data": {"type": "node--islandora_object", "relationships": {"field_subject": {"data": [
+		{"type": "taxonomy_term--subject", "id": "7397e0c4-df0a-4800-95af-afccc6ff64a5"}]}}}}`, string(b))
+}
@@ -0,0 +1,92 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/stretchr/testify/assert"
+)
+
+// AssertAnonymousCannotSee asserts that an unauthenticated request of the url answers no resource, either because
+// Drupal answers a 404 status or omits the resource from the result, or because it answers a 403 status.  The test
+// fails if the resource is answered, or if its visibility cannot be determined (e.g. because of a network error).
+func AssertAnonymousCannotSee(t *testing.T, u jsonapi.JsonApiUrl) bool {
+	u.Credentials, u.Anonymous = nil, true
+	return assertVisibility(t, "anonymous", false, describeQuery(u), u.ExistsErr)
+}
+
+// AssertAuthenticatedCanSee asserts that a request of the url authenticated by the credentials answers at least one
+// resource.  The failure distinguishes a resource that is absent (a 404 status, or an empty result) from one that is
+// forbidden (a 403 status), and from a network error.
+func AssertAuthenticatedCanSee(t *testing.T, u jsonapi.JsonApiUrl, c env.Credentials) bool {
+	u.Credentials, u.Anonymous = &c, false
+	return assertVisibility(t, c.Username, true, describeQuery(u), u.ExistsErr)
+}
+
+// AssertRestrictedVisibility asserts that the entity of the bundle identified by the uuid cannot be seen anonymously,
+// but can be seen by the user authenticated by the credentials.  Drupal behaves differently when a resource is hidden
+// from the user: a filtered query (e.g. `/jsonapi/node/islandora_object?filter[id]={uuid}`) omits it from the result,
+// while its canonical url (e.g. `/jsonapi/node/islandora_object/{uuid}`) answers a 403 status, so both are checked:
+//
+//	model.AssertRestrictedVisibility(t, model.Node, model.RepositoryObject, obj.JsonApiData[0].Id,
+//		env.CredentialsFor("collection_creator"))
+func AssertRestrictedVisibility(t *testing.T, entity, bundle, uuid string, c env.Credentials, opts ...Option) bool {
+	u := query(t, entity, bundle, opts...)
+	u.Filter, u.Value = "id", uuid
+	anonymous, authenticated := u, u
+	anonymous.Credentials, anonymous.Anonymous = nil, true
+	authenticated.Credentials, authenticated.Anonymous = &c, false
+
+	canonical := fmt.Sprintf("canonical url of %s/%s %s", entity, bundle, uuid)
+	ok := AssertAnonymousCannotSee(t, u)
+	ok = assertVisibility(t, "anonymous", false, canonical, func() (bool, error) {
+		return anonymous.ResourceExistsErr(uuid)
+	}) && ok
+	ok = AssertAuthenticatedCanSee(t, u, c) && ok
+	return assertVisibility(t, c.Username, true, canonical, func() (bool, error) {
+		return authenticated.ResourceExistsErr(uuid)
+	}) && ok
+}
+
+// assertVisibility asserts that the existence check answers whether the user is expected to see the resource
+// described by target.  A 403 status satisfies an expectation that the resource cannot be seen.
+func assertVisibility(t *testing.T, user string, expected bool, target string, exists func() (bool, error)) bool {
+	visible, err := exists()
+	if err != nil && !errors.Is(err, jsonapi.ErrForbidden) {
+		return assert.Fail(t, fmt.Sprintf("unable to determine whether %s can see %s: %s", user, target,
+			describeVisibility(visible, err)))
+	}
+	if visible != expected {
+		return assert.Fail(t, fmt.Sprintf("%s is expected to %s %s, but the response was %s", user,
+			map[bool]string{true: "see", false: "not see"}[expected], target, describeVisibility(visible, err)))
+	}
+	t.Logf("%s: %s is %s", target, user, describeVisibility(visible, err))
+	return true
+}
+
+// describeVisibility answers a readable description of the outcome of an existence check, e.g.
+// `not found (404 or empty result)`
+func describeVisibility(visible bool, err error) string {
+	switch {
+	case err == nil && visible:
+		return "visible"
+	case err == nil:
+		return "not found (404 or empty result)"
+	case errors.Is(err, jsonapi.ErrForbidden):
+		return "forbidden (403)"
+	default:
+		return fmt.Sprintf("network error (%s)", err)
+	}
+}
+
+// describeQuery answers a readable description of the resources requested by the url, e.g.
+// `node/islandora_object filtered by id=...`
+func describeQuery(u jsonapi.JsonApiUrl) string {
+	if u.Filter == "" {
+		return fmt.Sprintf("%s/%s", u.DrupalEntity, u.DrupalBundle)
+	}
+	return fmt.Sprintf("%s/%s filtered by %s=%s", u.DrupalEntity, u.DrupalBundle, u.Filter, u.Value)
+}
@@ -0,0 +1,64 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/stretchr/testify/assert"
+)
+
+// newRestrictedObjectServer answers a server whose repository object may only be seen by authenticated users: it is
+// omitted from filtered queries, and forbidden at its canonical url, for anonymous requests
+func newRestrictedObjectServer(t *testing.T, id string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _, authenticated := r.BasicAuth()
+		switch r.URL.Path {
+		case "/jsonapi/node/islandora_object":
+			if !authenticated || requestedId(r) != id {
+				_, _ = w.Write([]byte(`{"data": []}`))
+				return
+			}
+			_, _ = fmt.Fprintf(w, `{"data": [{"type": "node--islandora_object", "id": "%s"}]}`, id)
+		case "/jsonapi/node/islandora_object/" + id:
+			if !authenticated {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			_, _ = fmt.Fprintf(w, `{"data": {"type": "node--islandora_object", "id": "%s"}}`, id)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	setBaseUrl(t, server)
+	return server
+}
+
+func Test_AssertRestrictedVisibility(t *testing.T) {
+	newRestrictedObjectServer(t, testUuid(1))
+	staff := env.Credentials{Username: "staff", Password: "moo"}
+
+	assert.True(t, AssertRestrictedVisibility(t, Node, RepositoryObject, testUuid(1), staff))
+
+	u := query(t, Node, RepositoryObject)
+	u.Filter, u.Value = "id", testUuid(1)
+	assert.True(t, AssertAnonymousCannotSee(t, u))
+	assert.True(t, AssertAuthenticatedCanSee(t, u, staff))
+
+	u.Value, u.Credentials = testUuid(2), &staff
+	visible, err := u.ExistsErr()
+	assert.Nil(t, err, "%s", err)
+	assert.Equal(t, "not found (404 or empty result)", describeVisibility(visible, err))
+}
+
+func Test_describeVisibility(t *testing.T) {
+	assert.Equal(t, "visible", describeVisibility(true, nil))
+	assert.Equal(t, "not found (404 or empty result)", describeVisibility(false, nil))
+	assert.Equal(t, "forbidden (403)", describeVisibility(false, fmt.Errorf("%w: 403", jsonapi.ErrForbidden)))
+	assert.Equal(t, "network error (connection refused)", describeVisibility(false, errors.New("connection refused")))
+}
@@ -0,0 +1,63 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	// Constant for the Drupal taxonomy term entity type
+	TaxonomyTerm = "taxonomy_term"
+	// Constant for the Drupal taxonomy vocabulary entity type (which is also its only bundle)
+	TaxonomyVocabulary = "taxonomy_vocabulary"
+)
+
+// Represents the results of a JSONAPI query for a single taxonomy vocabulary
+type JsonApiVocabulary struct {
+	JsonApiData []struct {
+		Type              jsonapi.DrupalType
+		Id                string
+		JsonApiAttributes struct {
+			// The machine name of the vocabulary, e.g. `genre` or `language`
+			MachineName string `json:"drupal_internal__vid"`
+			Name        string `json:"name"`
+			Description string `json:"description"`
+		} `json:"attributes"`
+	} `json:"data"`
+}
+
+// Minimally represents a taxonomy term of any vocabulary
+type Term struct {
+	JsonApiData
+	Name string
+}
+
+// TermsOf retrieves every term of the named vocabulary (e.g. `language`), following pagination until all terms have
+// been retrieved.
+func TermsOf(t *testing.T, vocabulary string) []Term {
+	u := query(t, TaxonomyTerm, vocabulary)
+
+	res := struct {
+		JsonApiData []struct {
+			Type              jsonapi.DrupalType
+			Id                string
+			JsonApiAttributes struct {
+				Name string
+			} `json:"attributes"`
+		} `json:"data"`
+	}{}
+	u.GetAll(&res)
+
+	terms := make([]Term, len(res.JsonApiData))
+	for i, data := range res.JsonApiData {
+		terms[i] = Term{JsonApiData: JsonApiData{Type: data.Type, Id: data.Id}, Name: data.JsonApiAttributes.Name}
+	}
+	return terms
+}
+
+// AssertTermCount asserts that the named vocabulary contains exactly the expected number of terms
+func AssertTermCount(t *testing.T, vocabulary string, expected int) bool {
+	return assert.Equal(t, expected, len(TermsOf(t, vocabulary)), "unexpected number of terms in vocabulary '%s'", vocabulary)
+}
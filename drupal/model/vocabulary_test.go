@@ -0,0 +1,33 @@
+package model
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TermsOf(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/jsonapi/taxonomy_term/language", r.URL.Path)
+		switch r.URL.Query().Get("page[offset]") {
+		case "":
+			_, _ = fmt.Fprintf(w, `{"data": [{"type": "taxonomy_term--language", "id": "%s", "attributes": {"name": "English"}}],
+				"links": {"next": {"href": "%s/jsonapi/taxonomy_term/language?page%%5Boffset%%5D=1"}}}`, testUuid(1), server.URL)
+		case "1":
+			_, _ = fmt.Fprintf(w, `{"data": [{"type": "taxonomy_term--language", "id": "%s", "attributes": {"name": "Spanish"}}]}`, testUuid(2))
+		}
+	}))
+	defer server.Close()
+	setBaseUrl(t, server)
+
+	terms := TermsOf(t, "language")
+	assert.Equal(t, []Term{
+		{JsonApiData: JsonApiData{Type: "taxonomy_term--language", Id: testUuid(1)}, Name: "English"},
+		{JsonApiData: JsonApiData{Type: "taxonomy_term--language", Id: testUuid(2)}, Name: "Spanish"},
+	}, terms)
+	AssertTermCount(t, "language", 2)
+}
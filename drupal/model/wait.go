@@ -0,0 +1,180 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	// The environment variable overriding the time waited by the WaitForX functions, e.g. `5m`
+	waitTimeout = "IDC_WAIT_TIMEOUT"
+	// The environment variable overriding the interval between the polls of the WaitForX functions, e.g. `5s`
+	waitInterval        = "IDC_WAIT_INTERVAL"
+	defaultWaitTimeout  = 2 * time.Minute
+	defaultWaitInterval = 2 * time.Second
+	// The length of the description of the last observed state reported by WaitForCondition
+	maxObservedLen = 500
+)
+
+// WaitFor polls the condition every interval until it answers true, rather than sleeping for a fixed time after an
+// entity is created or migrated, while search indexes, caches, and derivatives catch up.  An error answered by the
+// condition does not end the wait: it is taken as the last observed state (e.g. "no Thumbnail Image media yet"), and
+// reported if the condition is not met before the timeout.  Answers whether the condition was met; the test fails,
+// reporting how long was waited and the last observed state, if it was not:
+//
+//	model.WaitFor(t, time.Minute, time.Second, func() (bool, error) {
+//		return len(model.MediaOfObject(t, objUuid)) > 0, nil
+//	})
+func WaitFor(t *testing.T, timeout, interval time.Duration, condition func() (bool, error)) bool {
+	waited, polls, err := waitFor(timeout, interval, condition)
+	if err == nil {
+		return true
+	}
+	return assert.Fail(t, fmt.Sprintf("condition not met after waiting %s (%d polls); last observed: %s",
+		waited.Round(time.Millisecond), polls, err))
+}
+
+// WaitForEntity waits until the url answers exactly one resource, e.g. a repository object filtered by its title after
+// a migration.  Polls are conditional requests, so an unchanged response is not unmarshaled again.  The time waited,
+// and the interval between polls, default to two minutes and two seconds, and may be overridden by the environment
+// variables 'IDC_WAIT_TIMEOUT' and 'IDC_WAIT_INTERVAL'.
+func WaitForEntity(t *testing.T, u jsonapi.JsonApiUrl) bool {
+	var validators jsonapi.Validators
+	var observed error
+	return WaitFor(t, WaitTimeout(t), WaitInterval(t), func() (bool, error) {
+		v := struct {
+			Data []JsonApiData
+		}{}
+		next, err := u.GetSingleIfChangedErr(validators, &v)
+		validators = next
+		if errors.Is(err, jsonapi.ErrNotModified) {
+			return false, observed
+		}
+		observed = err
+		return err == nil, err
+	})
+}
+
+// WaitForMediaUse waits until the repository object has a media with the named media use (e.g. ThumbnailImage), and
+// answers it, or nil if the wait times out.  See WaitForEntity for the time waited.
+func WaitForMediaUse(t *testing.T, objUuid, mediaUseName string) Media {
+	var found Media
+	WaitFor(t, WaitTimeout(t), WaitInterval(t), func() (bool, error) {
+		m, err := mediaWithUseErr(t, objUuid, mediaUseName)
+		found = m
+		return err == nil, err
+	})
+	return found
+}
+
+// WaitForCondition waits until the entity identified by the data object, resolved as a T (e.g. a
+// JsonApiIslandoraObj), meets the condition, and answers it.  The last resolved value is reported if the condition is
+// not met.  Polls are conditional requests, so an unchanged entity is neither unmarshaled nor tested again.  See
+// WaitForEntity for the time waited.
+//
+//	obj := model.WaitForCondition(t, data, func(obj model.JsonApiIslandoraObj) bool {
+//		return obj.JsonApiData[0].JsonApiAttributes.Title == "Moonrise"
+//	})
+func WaitForCondition[T any](t *testing.T, jad JsonApiData, condition func(T) bool, opts ...Option) T {
+	var last T
+	if err := jad.Validate(); err != nil {
+		assert.Fail(t, fmt.Sprintf("unable to wait for %s %s: %s", jad.Type, jad.Id, err))
+		return last
+	}
+
+	u := jad.url(env.UsernameOr(""), env.PasswordOr(""), opts...)
+	var validators jsonapi.Validators
+	var observed error
+	WaitFor(t, WaitTimeout(t), WaitInterval(t), func() (bool, error) {
+		var v T
+		next, err := u.GetSingleIfChangedErr(validators, &v)
+		validators = next
+		switch {
+		case errors.Is(err, jsonapi.ErrNotModified):
+			return false, observed
+		case err != nil:
+			observed = err
+			return false, err
+		}
+
+		last = v
+		if condition(v) {
+			return true, nil
+		}
+		observed = fmt.Errorf("%s", truncate(fmt.Sprintf("%+v", v), maxObservedLen))
+		return false, observed
+	})
+	return last
+}
+
+// waitFor polls the condition every interval until it answers true, or the timeout elapses.  Answers the time waited,
+// the number of polls, and, if the condition was not met, the last observed state.
+func waitFor(timeout, interval time.Duration, condition func() (bool, error)) (time.Duration, int, error) {
+	start := time.Now()
+	deadline := start.Add(timeout)
+	for polls := 1; ; polls++ {
+		met, err := condition()
+		if met {
+			return time.Since(start), polls, nil
+		}
+		if err == nil {
+			err = errors.New("condition not met")
+		}
+		if !time.Now().Add(interval).Before(deadline) {
+			return time.Since(start), polls, err
+		}
+		time.Sleep(interval)
+	}
+}
+
+// mediaWithUseErr answers the media of the repository object with the named media use, or an error describing the
+// media use of each media the object has
+func mediaWithUseErr(t *testing.T, objUuid, mediaUseName string) (Media, error) {
+	var observed []string
+	for _, b := range mediaBundles {
+		media, err := b.mediaOf(t, b.bundle, objUuid)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range media {
+			for _, use := range m.MediaUse() {
+				label, err := resolveLabelErr(use)
+				if err != nil {
+					return nil, err
+				}
+				if label == mediaUseName {
+					return m, nil
+				}
+				observed = append(observed, label)
+			}
+		}
+	}
+	return nil, fmt.Errorf("repository object %s has no '%s' media; its media uses are %q", objUuid, mediaUseName,
+		observed)
+}
+
+// WaitTimeout answers the time waited by the WaitForX functions: two minutes, unless overridden by the environment
+// variable 'IDC_WAIT_TIMEOUT'.  The test fails if the variable is not a duration.
+func WaitTimeout(t *testing.T) time.Duration {
+	return env.MustDurationOr(t, waitTimeout, defaultWaitTimeout)
+}
+
+// WaitInterval answers the interval between the polls of the WaitForX functions: two seconds, unless overridden by
+// the environment variable 'IDC_WAIT_INTERVAL'.  The test fails if the variable is not a duration.
+func WaitInterval(t *testing.T) time.Duration {
+	return env.MustDurationOr(t, waitInterval, defaultWaitInterval)
+}
+
+// truncate answers at most the first n bytes of s, marking the omission of the remainder
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "…"
+}
@@ -0,0 +1,111 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_waitFor(t *testing.T) {
+	polls := 0
+	_, n, err := waitFor(time.Second, time.Millisecond, func() (bool, error) {
+		polls++
+		return polls == 3, nil
+	})
+	assert.Nil(t, err, "%s", err)
+	assert.Equal(t, 3, n)
+
+	waited, n, err := waitFor(20*time.Millisecond, 5*time.Millisecond, func() (bool, error) {
+		return false, errors.New("no thumbnail yet")
+	})
+	assert.Equal(t, "no thumbnail yet", fmt.Sprint(err))
+	assert.True(t, n > 1, "expected more than one poll, got %d", n)
+	assert.True(t, waited < 20*time.Millisecond, "expected the wait to end before the timeout, waited %s", waited)
+
+	_, _, err = waitFor(0, time.Millisecond, func() (bool, error) { return false, nil })
+	assert.Equal(t, "condition not met", fmt.Sprint(err))
+}
+
+// newPollServer answers a server responding with no data elements until it has been polled the supplied number of
+// times, and with the data element afterwards.  Each response carries an ETag identifying its content, and conditional
+// requests for unchanged content are answered with a 304 status, counted by notModified.
+func newPollServer(t *testing.T, polls int32, data string, notModified *int32) *httptest.Server {
+	var n int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, etag := `{"data": []}`, `"empty"`
+		if atomic.AddInt32(&n, 1) > polls {
+			body, etag = fmt.Sprintf(`{"data": [%s]}`, data), `"data"`
+		}
+		if r.Header.Get("If-None-Match") == etag {
+			atomic.AddInt32(notModified, 1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	setBaseUrl(t, server)
+	t.Setenv("IDC_WAIT_INTERVAL", "1ms")
+	return server
+}
+
+func Test_WaitTimeoutAndInterval(t *testing.T) {
+	t.Setenv("IDC_WAIT_TIMEOUT", "")
+	t.Setenv("IDC_WAIT_INTERVAL", "")
+	os.Unsetenv("IDC_WAIT_TIMEOUT")
+	os.Unsetenv("IDC_WAIT_INTERVAL")
+	assert.Equal(t, 2*time.Minute, WaitTimeout(t))
+	assert.Equal(t, 2*time.Second, WaitInterval(t))
+
+	t.Setenv("IDC_WAIT_TIMEOUT", "5m")
+	t.Setenv("IDC_WAIT_INTERVAL", "5s")
+	assert.Equal(t, 5*time.Minute, WaitTimeout(t))
+	assert.Equal(t, 5*time.Second, WaitInterval(t))
+}
+
+func Test_WaitForEntity(t *testing.T) {
+	var notModified int32
+	newPollServer(t, 3, collectionElement(testUuid(1), "Moonrise", ""), &notModified)
+
+	u := query(t, Node, RepositoryObject)
+	u.Filter, u.Value = "title", "Moonrise"
+	assert.True(t, WaitForEntity(t, u))
+	assert.Equal(t, int32(2), notModified, "unchanged responses are expected to be answered with 304")
+}
+
+func Test_WaitForCondition(t *testing.T) {
+	var notModified int32
+	newPollServer(t, 2, collectionElement(testUuid(1), "Moonrise", ""), &notModified)
+
+	c := WaitForCondition(t, JsonApiData{Type: "node--collection_object", Id: testUuid(1)},
+		func(c JsonApiCollection) bool {
+			return len(c.JsonApiData) == 1 && c.JsonApiData[0].JsonApiAttributes.Title == "Moonrise"
+		})
+	assert.Equal(t, testUuid(1), c.JsonApiData[0].Id)
+	assert.Equal(t, int32(1), notModified)
+}
+
+func Test_WaitForMediaUse(t *testing.T) {
+	objId, thumbnailUse := testUuid(0), testUuid(2)
+	server := newMediaServer(t, objId, map[string]string{
+		"/jsonapi/media/image": mediaElement(Image, testUuid(5), "image/jpeg", thumbnailUse, objId),
+	}, map[string]string{thumbnailUse: ThumbnailImage})
+	defer server.Close()
+	setBaseUrl(t, server)
+
+	m := WaitForMediaUse(t, objId, ThumbnailImage)
+	if assert.NotNil(t, m) {
+		assert.Equal(t, "image/jpeg", m.MimeType())
+	}
+
+	_, err := mediaWithUseErr(t, objId, ServiceFile)
+	assert.Contains(t, fmt.Sprint(err), `has no 'Service File' media; its media uses are ["Thumbnail Image"]`)
+}
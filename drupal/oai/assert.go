@@ -0,0 +1,68 @@
+package oai
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+// AssertDublinCore asserts that the simple Dublin Core of the record agrees with the repository object it describes,
+// so that drift in the OAI-PMH metadata mapping is caught: the dc:title values include the title of the object, the
+// dc:creator values include the name of each of its creators, and the dc:date values include each of its
+// field_date_created values.  Every disagreement is reported.
+func AssertDublinCore(t *testing.T, record Record, obj model.JsonApiIslandoraObj) bool {
+	if !assert.NotEmpty(t, obj.JsonApiData, "unable to compare record %s with an empty repository object response",
+		record.Header.Identifier) {
+		return false
+	}
+	data := obj.JsonApiData[0]
+
+	var creators []model.JsonApiData
+	for _, c := range data.JsonApiRelationships.Creator.Data {
+		creators = append(creators, c.JsonApiData)
+	}
+	creatorNames, err := model.ResolveLabelsErr(creators)
+	if !assert.Nil(t, err, "%s", err) {
+		return false
+	}
+
+	dc := record.Metadata.Dc
+	var drift []string
+	for _, c := range []struct {
+		element  string
+		expected []string
+		actual   []string
+	}{
+		{"dc:title", []string{data.JsonApiAttributes.Title}, dc.Title},
+		{"dc:creator", creatorNames, dc.Creator},
+		{"dc:date", data.JsonApiAttributes.DateCreated, dc.Date},
+	} {
+		if missing := missingValues(c.expected, c.actual); len(missing) > 0 {
+			drift = append(drift, fmt.Sprintf("%s lacks %q; its values are %q", c.element, missing, c.actual))
+		}
+	}
+
+	if len(drift) > 0 {
+		return assert.Fail(t, fmt.Sprintf("record %s does not agree with %s %s:\n\t%s", record.Header.Identifier,
+			data.Type, data.Id, strings.Join(drift, "\n\t")))
+	}
+	return true
+}
+
+// missingValues answers the values of expected absent from actual, ignoring surrounding whitespace
+func missingValues(expected, actual []string) []string {
+	present := map[string]bool{}
+	for _, v := range actual {
+		present[strings.TrimSpace(v)] = true
+	}
+	var missing []string
+	for _, v := range expected {
+		if !present[strings.TrimSpace(v)] {
+			missing = append(missing, v)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,36 @@
+package oai
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_AssertDublinCore(t *testing.T) {
+	newFakeFeed(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, `{"data": [{"type": "taxonomy_term--person", "id": "%s",
+			"attributes": {"name": "Adams, Ansel"}}]}`, r.URL.Query().Get("filter[id]"))
+	})
+	model.ResetTermCache()
+	defer model.ResetTermCache()
+
+	obj := model.JsonApiIslandoraObj{}
+	require.Nil(t, json.Unmarshal([]byte(`{"data": [{"type": "node--islandora_object", "id": "1",
+		"attributes": {"title": "Moonrise", "field_date_created": ["1941"]},
+		"relationships": {"field_creator": {"data": [{"type": "taxonomy_term--person",
+			"id": "815a4c04-0be5-44f1-a876-e8ddc11dcf21",
+			"meta": {"rel_type": "relators:pht"}}]}}}]}`), &obj))
+
+	record := GetRecord(t, "oai:islandora.example.edu:node-12", OaiDc)
+	assert.True(t, AssertDublinCore(t, record, obj))
+}
+
+func Test_missingValues(t *testing.T) {
+	assert.Empty(t, missingValues([]string{"Moonrise"}, []string{" Moonrise\n"}))
+	assert.Equal(t, []string{"1942"}, missingValues([]string{"1941", "1942"}, []string{"1941"}))
+}
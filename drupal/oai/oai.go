@@ -0,0 +1,183 @@
+// Provides helpers that harvest the OAI-PMH feed of the IDC site, so that tests may verify the metadata offered to
+// harvesting partners, e.g. that the Dublin Core of a record agrees with the repository object it describes.
+//
+// Requests are issued to the OAI-PMH endpoint named by the environment variable 'IDC_OAI_URL' (by default
+// {base url}/oai/request), using the Config loaded by env.Load.
+package oai
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	oaiUrl         = "IDC_OAI_URL"
+	defaultOaiPath = "/oai/request"
+	// The metadata prefix of simple Dublin Core
+	OaiDc = "oai_dc"
+	// The maximum number of pages followed by ListIdentifiers, guarding against a resumption token that never ends
+	maxPages = 1000
+)
+
+// Answered (wrapped) when the OAI-PMH endpoint answers that the requested record does not exist
+var ErrNotFound = errors.New("oai: record not found")
+
+// Header identifies a record of the feed
+type Header struct {
+	// The OAI identifier of the record, e.g. `oai:islandora.example.edu:node-12`
+	Identifier string `xml:"identifier"`
+	// The date the record was last modified
+	Datestamp string `xml:"datestamp"`
+	// The sets the record belongs to
+	SetSpecs []string `xml:"setSpec"`
+	// `deleted` if the record was deleted
+	Status string `xml:"status,attr"`
+}
+
+// DublinCore is the simple Dublin Core metadata of a record, whose metadata prefix is OaiDc
+type DublinCore struct {
+	Title       []string `xml:"http://purl.org/dc/elements/1.1/ title"`
+	Creator     []string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	Subject     []string `xml:"http://purl.org/dc/elements/1.1/ subject"`
+	Description []string `xml:"http://purl.org/dc/elements/1.1/ description"`
+	Publisher   []string `xml:"http://purl.org/dc/elements/1.1/ publisher"`
+	Contributor []string `xml:"http://purl.org/dc/elements/1.1/ contributor"`
+	Date        []string `xml:"http://purl.org/dc/elements/1.1/ date"`
+	Type        []string `xml:"http://purl.org/dc/elements/1.1/ type"`
+	Format      []string `xml:"http://purl.org/dc/elements/1.1/ format"`
+	Identifier  []string `xml:"http://purl.org/dc/elements/1.1/ identifier"`
+	Source      []string `xml:"http://purl.org/dc/elements/1.1/ source"`
+	Language    []string `xml:"http://purl.org/dc/elements/1.1/ language"`
+	Relation    []string `xml:"http://purl.org/dc/elements/1.1/ relation"`
+	Coverage    []string `xml:"http://purl.org/dc/elements/1.1/ coverage"`
+	Rights      []string `xml:"http://purl.org/dc/elements/1.1/ rights"`
+}
+
+// Record is a record of the feed
+type Record struct {
+	Header Header `xml:"header"`
+	// The metadata of the record in the format of its metadata prefix
+	Metadata struct {
+		// The metadata as XML, e.g. for metadata prefixes other than OaiDc
+		Xml string `xml:",innerxml"`
+		// The metadata decoded as simple Dublin Core; empty for other metadata prefixes
+		Dc DublinCore `xml:"http://www.openarchives.org/OAI/2.0/oai_dc/ dc"`
+	} `xml:"metadata"`
+}
+
+// The OAI-PMH responses decoded by this package
+type response struct {
+	Error *struct {
+		Code    string `xml:"code,attr"`
+		Message string `xml:",chardata"`
+	} `xml:"error"`
+	GetRecord struct {
+		Record Record `xml:"record"`
+	} `xml:"GetRecord"`
+	ListIdentifiers struct {
+		Headers         []Header `xml:"header"`
+		ResumptionToken string   `xml:"resumptionToken"`
+	} `xml:"ListIdentifiers"`
+}
+
+// GetRecord retrieves the record with the OAI identifier in the format of the metadata prefix (e.g. OaiDc), failing the
+// test immediately if it cannot:
+//
+//	record := oai.GetRecord(t, "oai:islandora.example.edu:node-12", oai.OaiDc)
+//	assert.Equal(t, []string{"Moonrise"}, record.Metadata.Dc.Title)
+func GetRecord(t *testing.T, identifier, metadataPrefix string) Record {
+	r, err := GetRecordErr(identifier, metadataPrefix)
+	require.Nil(t, err, "%s", err)
+	return r
+}
+
+// GetRecordErr behaves as GetRecord, but answers an error instead of failing the test.  The error wraps ErrNotFound if
+// no record has the identifier.
+func GetRecordErr(identifier, metadataPrefix string) (Record, error) {
+	res, err := requestErr(url.Values{
+		"verb":           {"GetRecord"},
+		"identifier":     {identifier},
+		"metadataPrefix": {metadataPrefix},
+	})
+	if err != nil {
+		return Record{}, err
+	}
+	return res.GetRecord.Record, nil
+}
+
+// ListIdentifiers answers the headers of every record with the metadata prefix, belonging to the set if it is not
+// empty, following resumption tokens until the list is complete, and failing the test immediately if it cannot
+func ListIdentifiers(t *testing.T, metadataPrefix, set string) []Header {
+	headers, err := ListIdentifiersErr(metadataPrefix, set)
+	require.Nil(t, err, "%s", err)
+	return headers
+}
+
+// ListIdentifiersErr behaves as ListIdentifiers, but answers an error instead of failing the test.  An empty list is
+// answered if no records match.
+func ListIdentifiersErr(metadataPrefix, set string) ([]Header, error) {
+	params := url.Values{"verb": {"ListIdentifiers"}, "metadataPrefix": {metadataPrefix}}
+	if set != "" {
+		params.Set("set", set)
+	}
+
+	var headers []Header
+	for pages := 0; pages < maxPages; pages++ {
+		res, err := requestErr(params)
+		if err != nil {
+			return nil, err
+		}
+		headers = append(headers, res.ListIdentifiers.Headers...)
+
+		token := strings.TrimSpace(res.ListIdentifiers.ResumptionToken)
+		if token == "" {
+			return headers, nil
+		}
+		// a resumption token is exclusive of every argument other than the verb
+		params = url.Values{"verb": {"ListIdentifiers"}, "resumptionToken": {token}}
+	}
+	return nil, fmt.Errorf("oai: more than %d pages of identifiers encountered; resumption loop suspected", maxPages)
+}
+
+// requestErr issues the OAI-PMH request carrying the parameters, and answers the decoded response.  An OAI-PMH error is
+// answered as an error, except `noRecordsMatch`, which answers an empty response.
+func requestErr(params url.Values) (response, error) {
+	res := response{}
+	c, err := env.Load()
+	if err != nil {
+		return res, err
+	}
+	u := env.StringOr(oaiUrl, strings.TrimSuffix(c.BaseUrl, "/")+defaultOaiPath) + "?" + params.Encode()
+
+	// the OAI-PMH endpoint is public, so the request carries no credentials
+	httpRes, err := (&jsonapi.Client{Config: &c, Anonymous: true}).Do(context.Background(), http.MethodGet, u, nil, nil)
+	if err != nil {
+		return res, err
+	}
+	if err := xml.Unmarshal(httpRes.Body, &res); err != nil {
+		return res, fmt.Errorf("oai: unable to unmarshal the response from %s: %w", env.RedactUrl(u), err)
+	}
+
+	if res.Error != nil {
+		switch res.Error.Code {
+		case "noRecordsMatch":
+			return response{}, nil
+		case "idDoesNotExist":
+			return res, fmt.Errorf("%w: %s: %s", ErrNotFound, env.RedactUrl(u), strings.TrimSpace(res.Error.Message))
+		default:
+			return res, fmt.Errorf("oai: %s error encountered retrieving %s: %s", res.Error.Code, env.RedactUrl(u),
+				strings.TrimSpace(res.Error.Message))
+		}
+	}
+	return res, nil
+}
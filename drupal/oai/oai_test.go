@@ -0,0 +1,106 @@
+package oai
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const oaiRecord = `<?xml version="1.0" encoding="UTF-8"?>
+<OAI-PMH xmlns="http://www.openarchives.org/OAI/2.0/">
+  <request verb="GetRecord">%[1]s</request>
+  <GetRecord><record>
+    <header><identifier>oai:islandora.example.edu:node-12</identifier><datestamp>2021-06-01T12:00:00Z</datestamp>
+      <setSpec>photographs</setSpec></header>
+    <metadata>
+      <oai_dc:dc xmlns:oai_dc="http://www.openarchives.org/OAI/2.0/oai_dc/" xmlns:dc="http://purl.org/dc/elements/1.1/">
+        <dc:title>Moonrise</dc:title>
+        <dc:creator>Adams, Ansel</dc:creator>
+        <dc:date>1941</dc:date>
+        <dc:subject>Photography</dc:subject>
+      </oai_dc:dc>
+    </metadata>
+  </record></GetRecord>
+</OAI-PMH>`
+
+const oaiError = `<OAI-PMH xmlns="http://www.openarchives.org/OAI/2.0/"><error code="%s">%s</error></OAI-PMH>`
+
+const oaiIdentifiers = `<OAI-PMH xmlns="http://www.openarchives.org/OAI/2.0/"><ListIdentifiers>
+  <header><identifier>oai:islandora.example.edu:node-%d</identifier></header>
+  <header status="deleted"><identifier>oai:islandora.example.edu:node-%d</identifier></header>
+  <resumptionToken completeListSize="4">%s</resumptionToken>
+</ListIdentifiers></OAI-PMH>`
+
+// newFakeFeed answers a server serving the OAI-PMH feed and, for other paths, the handler (if not nil), and configures
+// the environment to use it
+func newFakeFeed(t *testing.T, drupal http.HandlerFunc) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/oai/request" {
+			if drupal == nil {
+				http.NotFound(w, r)
+				return
+			}
+			drupal(w, r)
+			return
+		}
+
+		q := r.URL.Query()
+		switch {
+		case q.Get("verb") == "GetRecord" && q.Get("identifier") == "oai:islandora.example.edu:node-12":
+			_, _ = fmt.Fprintf(w, oaiRecord, q.Get("identifier"))
+		case q.Get("verb") == "GetRecord":
+			_, _ = fmt.Fprintf(w, oaiError, "idDoesNotExist", "No matching identifier")
+		case q.Get("resumptionToken") == "page2":
+			assert.Empty(t, q.Get("metadataPrefix"), "a resumption token is exclusive")
+			_, _ = fmt.Fprintf(w, oaiIdentifiers, 3, 4, "")
+		case q.Get("set") == "empty":
+			_, _ = fmt.Fprintf(w, oaiError, "noRecordsMatch", "")
+		case q.Get("verb") == "ListIdentifiers" && q.Get("metadataPrefix") == OaiDc:
+			_, _ = fmt.Fprintf(w, oaiIdentifiers, 1, 2, "page2")
+		default:
+			_, _ = fmt.Fprintf(w, oaiError, "badArgument", "Unsupported")
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	t.Cleanup(func() { _, _ = env.Reload() })
+	t.Setenv("DRUPAL_BASE_URL", server.URL)
+	_, err := env.Reload()
+	require.Nil(t, err, "%s", err)
+	return server
+}
+
+func Test_GetRecord(t *testing.T) {
+	newFakeFeed(t, nil)
+
+	r := GetRecord(t, "oai:islandora.example.edu:node-12", OaiDc)
+	assert.Equal(t, "oai:islandora.example.edu:node-12", r.Header.Identifier)
+	assert.Equal(t, []string{"photographs"}, r.Header.SetSpecs)
+	assert.Equal(t, []string{"Moonrise"}, r.Metadata.Dc.Title)
+	assert.Equal(t, []string{"Adams, Ansel"}, r.Metadata.Dc.Creator)
+	assert.Equal(t, []string{"Photography"}, r.Metadata.Dc.Subject)
+	assert.Contains(t, r.Metadata.Xml, "<dc:date>1941</dc:date>")
+
+	_, err := GetRecordErr("oai:islandora.example.edu:node-13", OaiDc)
+	assert.True(t, errors.Is(err, ErrNotFound), "expected ErrNotFound, got %v", err)
+}
+
+func Test_ListIdentifiers(t *testing.T) {
+	newFakeFeed(t, nil)
+
+	headers := ListIdentifiers(t, OaiDc, "")
+	require.Equal(t, 4, len(headers))
+	assert.Equal(t, "oai:islandora.example.edu:node-1", headers[0].Identifier)
+	assert.Equal(t, "deleted", headers[3].Status)
+
+	assert.Empty(t, ListIdentifiers(t, OaiDc, "empty"))
+
+	_, err := ListIdentifiersErr("mods", "")
+	assert.Contains(t, fmt.Sprint(err), "badArgument error encountered")
+}
@@ -0,0 +1,311 @@
+package search
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// The environment variable overriding the url of the CSV export of search results, whose placeholder `{query}` is
+	// replaced by the escaped full text sought; a path is resolved against the base url
+	exportPattern        = "IDC_CSV_EXPORT_PATTERN"
+	defaultExportPattern = "/idc/search/csv?search_api_fulltext={query}"
+	// The environment variable overriding the delimiter separating the values of a multi-valued cell
+	exportDelimiter        = "IDC_CSV_DELIMITER"
+	defaultExportDelimiter = "|"
+)
+
+// Export is the CSV export of search results: the names of its columns, and its rows, each holding a cell per column
+type Export struct {
+	Header []string
+	Rows   [][]string
+	// The delimiter separating the values of a multi-valued cell, e.g. `|`
+	Delimiter string
+}
+
+// Column answers the index of the named column, or -1 if the export has no such column.  Names are matched without
+// regard to case, and spaces and hyphens are taken as underscores, so that `Date Created` names `date_created`.
+func (e Export) Column(name string) int {
+	for i, h := range e.Header {
+		if columnKey(h) == columnKey(name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Value answers the cell of the named column of the row, trimmed of surrounding space, or the empty string if the export
+// has no such column.  The cell of a single-valued column (e.g. `title`) is not split, as its value may carry the
+// Delimiter.
+func (e Export) Value(row []string, column string) string {
+	i := e.Column(column)
+	if i < 0 || i >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[i])
+}
+
+// Values answers the values of the named column of the row: the cell split by the Delimiter, with each value trimmed of
+// surrounding space, and empty values omitted.  Answers nil if the export has no such column.
+func (e Export) Values(row []string, column string) []string {
+	i := e.Column(column)
+	if i < 0 || i >= len(row) {
+		return nil
+	}
+	var values []string
+	for _, v := range strings.Split(row[i], e.Delimiter) {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// RowsWhere answers the rows whose named column holds the value, either as its whole cell, or as one of its values
+func (e Export) RowsWhere(column, value string) [][]string {
+	var rows [][]string
+	for _, row := range e.Rows {
+		if e.Value(row, column) == value {
+			rows = append(rows, row)
+			continue
+		}
+		for _, v := range e.Values(row, column) {
+			if v == value {
+				rows = append(rows, row)
+				break
+			}
+		}
+	}
+	return rows
+}
+
+// ExportCsv answers the CSV export of the search results for the full text, failing the test immediately if it cannot
+// be retrieved or parsed.  The url of the export defaults to `{base url}/idc/search/csv?search_api_fulltext={query}`,
+// and may be overridden by the environment variable 'IDC_CSV_EXPORT_PATTERN'.  The values of a multi-valued cell are
+// separated by `|`, unless overridden by the environment variable 'IDC_CSV_DELIMITER':
+//
+//	export := search.ExportCsv(t, "Moonrise")
+//	search.AssertExportedRow(t, export, expected)
+func ExportCsv(t *testing.T, query string) Export {
+	e, err := ExportCsvErr(query)
+	require.Nil(t, err, "%s", err)
+	return e
+}
+
+// ExportCsvErr behaves as ExportCsv, but answers an error instead of failing the test
+func ExportCsvErr(query string) (Export, error) {
+	c, err := env.Load()
+	if err != nil {
+		return Export{}, err
+	}
+
+	u := strings.ReplaceAll(env.StringOr(exportPattern, defaultExportPattern), "{query}", url.QueryEscape(query))
+	if !strings.Contains(u, "://") {
+		u = strings.TrimSuffix(c.BaseUrl, "/") + "/" + strings.TrimPrefix(u, "/")
+	}
+	base, rawQuery, _ := strings.Cut(u, "?")
+	params, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return Export{}, fmt.Errorf("search: unable to parse the query of the CSV export url %s: %w", u, err)
+	}
+
+	body, err := getErr(c, base, params, "text/csv")
+	if err != nil {
+		return Export{}, err
+	}
+	e, err := parseExportErr(body, env.StringOr(exportDelimiter, defaultExportDelimiter))
+	if err != nil {
+		return Export{}, fmt.Errorf("search: unable to parse the CSV export answered by %s: %w", u, err)
+	}
+	return e, nil
+}
+
+// parseExportErr parses the CSV document: its first record is the header, and each record must have a cell per column.
+// Quoted cells may carry commas, newlines, and (doubled) quotes.  A leading byte order mark, as written for
+// spreadsheets, is ignored.
+func parseExportErr(body []byte, delimiter string) (Export, error) {
+	r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(body, []byte("\ufeff"))))
+	header, err := r.Read()
+	if err == io.EOF {
+		return Export{}, fmt.Errorf("the export is empty: it has no header")
+	}
+	if err != nil {
+		return Export{}, err
+	}
+
+	e := Export{Header: header, Delimiter: delimiter}
+	for {
+		row, err := r.Read()
+		if err == io.EOF {
+			return e, nil
+		}
+		if err != nil {
+			return Export{}, err
+		}
+		e.Rows = append(e.Rows, row)
+	}
+}
+
+// A column of the export, and the values expected of it by a repository object
+type exportColumn struct {
+	name     string
+	expected func(e model.ExpectedRepoObj) []string
+}
+
+// The columns of the export compared by AssertExportedRow, if present in its header
+var exportColumns = []exportColumn{
+	{"title", func(e model.ExpectedRepoObj) []string { return nonEmpty(e.Title) }},
+	{"abstract", func(e model.ExpectedRepoObj) []string { return langValues(e.Abstract) }},
+	{"access_terms", func(e model.ExpectedRepoObj) []string { return e.AccessTerms }},
+	{"collection_number", func(e model.ExpectedRepoObj) []string { return e.CollectionNumber }},
+	{"contributor", func(e model.ExpectedRepoObj) []string {
+		var names []string
+		for _, c := range e.Contributor {
+			names = append(names, c.Name)
+		}
+		return names
+	}},
+	{"creator", func(e model.ExpectedRepoObj) []string {
+		var names []string
+		for _, c := range e.Creator {
+			names = append(names, c.Name)
+		}
+		return names
+	}},
+	{"date_created", func(e model.ExpectedRepoObj) []string { return e.DateCreated }},
+	{"date_published", func(e model.ExpectedRepoObj) []string { return e.DatePublished }},
+	{"digital_identifier", func(e model.ExpectedRepoObj) []string { return e.DigitalIdentifier }},
+	{"extent", func(e model.ExpectedRepoObj) []string { return e.Extent }},
+	{"genre", func(e model.ExpectedRepoObj) []string { return e.Genre }},
+	{"member_of", func(e model.ExpectedRepoObj) []string { return nonEmpty(e.MemberOf) }},
+	{"publisher", func(e model.ExpectedRepoObj) []string { return e.Publisher }},
+	{"resource_type", func(e model.ExpectedRepoObj) []string { return e.ResourceType }},
+	{"spatial_coverage", func(e model.ExpectedRepoObj) []string { return e.SpatialCoverage }},
+	{"subject", func(e model.ExpectedRepoObj) []string { return e.Subject }},
+}
+
+// The columns of the export holding a single value, whose cell is not split by the delimiter
+var singleValuedColumns = map[string]bool{"title": true, "member_of": true}
+
+// AssertExportedRow asserts that the export has a row for the repository object, found by its title (and, if several
+// rows share the title, by its digital identifier), whose cells carry the expected values.  Each column of the export
+// named `title`, `abstract`, `access_terms`, `collection_number`, `contributor`, `creator`, `date_created`,
+// `date_published`, `digital_identifier`, `extent`, `genre`, `member_of`, `publisher`, `resource_type`,
+// `spatial_coverage`, or `subject` is compared; the cells of `title` and `member_of` are compared whole, and the
+// values of other cells are compared as a set.  Every mismatched column is reported.
+func AssertExportedRow(t *testing.T, e Export, expected model.ExpectedRepoObj) bool {
+	diffs, err := exportedRowDiffsErr(e, expected)
+	if err != nil {
+		return assert.Fail(t, err.Error())
+	}
+	if len(diffs) > 0 {
+		lines := make([]string, len(diffs))
+		for i, d := range diffs {
+			lines[i] = d.String()
+		}
+		return assert.Fail(t, fmt.Sprintf("the exported row of '%s' does not match %d column(s):\n%s", expected.Title,
+			len(diffs), strings.Join(lines, "\n")))
+	}
+	return true
+}
+
+// exportedRowDiffsErr answers the mismatched columns of the row of the repository object, or an error if the export
+// has no (single) row for it, or none of the compared columns
+func exportedRowDiffsErr(e Export, expected model.ExpectedRepoObj) ([]model.FieldDiff, error) {
+	if e.Column("title") < 0 {
+		return nil, fmt.Errorf("the export has no title column: its columns are %q", e.Header)
+	}
+	rows := e.RowsWhere("title", expected.Title)
+	if len(rows) > 1 && len(expected.DigitalIdentifier) > 0 {
+		var identified [][]string
+		for _, row := range rows {
+			if sameValues(expected.DigitalIdentifier, e.Values(row, "digital_identifier")) {
+				identified = append(identified, row)
+			}
+		}
+		rows = identified
+	}
+	if len(rows) != 1 {
+		return nil, fmt.Errorf("exactly one exported row titled '%s' is expected, but found %d", expected.Title,
+			len(rows))
+	}
+
+	var diffs []model.FieldDiff
+	for _, c := range exportColumns {
+		if e.Column(c.name) < 0 {
+			continue
+		}
+		want, got := c.expected(expected), e.Values(rows[0], c.name)
+		if singleValuedColumns[c.name] {
+			got = nonEmpty(e.Value(rows[0], c.name))
+		}
+		if !sameValues(want, got) {
+			missing, unexpected := setDiff(want, got)
+			diffs = append(diffs, model.FieldDiff{Field: c.name, Expected: want, Actual: got, Missing: missing,
+				Unexpected: unexpected})
+		}
+	}
+	return diffs, nil
+}
+
+// sameValues answers whether the lists hold the same values, without regard to order
+func sameValues(expected, actual []string) bool {
+	missing, unexpected := setDiff(expected, actual)
+	return len(missing) == 0 && len(unexpected) == 0
+}
+
+// setDiff answers the expected values missing from the actual values, and the actual values that are not expected,
+// counting duplicates
+func setDiff(expected, actual []string) (missing, unexpected []string) {
+	counts := map[string]int{}
+	for _, v := range actual {
+		counts[v]++
+	}
+	for _, v := range expected {
+		if counts[v] > 0 {
+			counts[v]--
+			continue
+		}
+		missing = append(missing, v)
+	}
+	for v, n := range counts {
+		for ; n > 0; n-- {
+			unexpected = append(unexpected, v)
+		}
+	}
+	sort.Strings(unexpected)
+	return missing, unexpected
+}
+
+// columnKey answers the name of a column normalized for comparison
+func columnKey(name string) string {
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(strings.ToLower(strings.TrimSpace(name)))
+}
+
+// nonEmpty answers the value as a list, or nil if it is empty
+func nonEmpty(v string) []string {
+	if v == "" {
+		return nil
+	}
+	return []string{v}
+}
+
+// langValues answers the values of the language-tagged strings
+func langValues(strs []model.ExpectedLangString) []string {
+	var values []string
+	for _, s := range strs {
+		values = append(values, s.Value)
+	}
+	return values
+}
@@ -0,0 +1,126 @@
+package search
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// An export whose cells carry commas, quotes, newlines, and multiple values, preceded by a byte order mark
+const exportCsv = "\ufeff" + `Title,Creator,Date Created,Subject,Extent,Abstract,digital_identifier
+"Moonrise, Hernandez",Ansel Adams,1941-11-01,Photography|Landscapes,"1 print | 1 negative","A moon ""rising"" over
+a village|Un paysage",idc:1
+Winter Sunrise,Ansel Adams,1944,,,,idc:2
+Winter Sunrise,Ansel Adams,1944,Mountains,,,idc:3
+`
+
+// expectedMoonrise answers the expected repository object of the first row of the export
+func expectedMoonrise(t *testing.T) model.ExpectedRepoObj {
+	e := model.ExpectedRepoObj{}
+	err := json.Unmarshal([]byte(`{
+		"title": "Moonrise, Hernandez",
+		"creator": [{"rel_type": "relators:pht", "name": "Ansel Adams"}],
+		"date_created": ["1941-11-01"],
+		"subject": ["Landscapes", "Photography"],
+		"extent": ["1 print", "1 negative"],
+		"abstract": [{"value": "A moon \"rising\" over\na village", "language": "en"}, {"value": "Un paysage", "language": "fr"}],
+		"digital_identifier": ["idc:1"]
+	}`), &e)
+	require.Nil(t, err, "%s", err)
+	return e
+}
+
+func Test_ExportCsvErr(t *testing.T) {
+	var accept, query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/idc/search/csv" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		accept, query = r.Header.Get("Accept"), r.URL.Query().Get("search_api_fulltext")
+		w.Header().Set("Content-Type", "text/csv")
+		_, _ = w.Write([]byte(exportCsv))
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { _, _ = env.Reload() })
+	t.Setenv("DRUPAL_BASE_URL", server.URL)
+	_, err := env.Reload()
+	require.Nil(t, err, "%s", err)
+
+	e, err := ExportCsvErr("Moonrise & Sunrise")
+	require.Nil(t, err, "%s", err)
+	assert.Equal(t, "text/csv", accept)
+	assert.Equal(t, "Moonrise & Sunrise", query)
+	assert.Equal(t, []string{"Title", "Creator", "Date Created", "Subject", "Extent", "Abstract", "digital_identifier"},
+		e.Header)
+	require.Equal(t, 3, len(e.Rows))
+
+	row := e.Rows[0]
+	assert.Equal(t, "Moonrise, Hernandez", e.Value(row, "title"))
+	assert.Equal(t, []string{"1941-11-01"}, e.Values(row, "date_created"))
+	assert.Equal(t, []string{"Photography", "Landscapes"}, e.Values(row, "Subject"))
+	assert.Equal(t, []string{"1 print", "1 negative"}, e.Values(row, "extent"))
+	assert.Equal(t, []string{"A moon \"rising\" over\na village", "Un paysage"}, e.Values(row, "abstract"))
+	assert.Nil(t, e.Values(e.Rows[1], "subject"))
+	assert.Nil(t, e.Values(row, "genre"))
+
+	t.Setenv("IDC_CSV_EXPORT_PATTERN", "/export.csv?q={query}")
+	_, err = ExportCsvErr("Moonrise")
+	assert.Contains(t, fmt.Sprint(err), "404 status")
+}
+
+func Test_ExportCsvErrDelimiter(t *testing.T) {
+	e, err := parseExportErr([]byte("title,subject\nMoonrise,Photography; Landscapes\n"), ";")
+	require.Nil(t, err, "%s", err)
+	assert.Equal(t, []string{"Photography", "Landscapes"}, e.Values(e.Rows[0], "subject"))
+
+	_, err = parseExportErr([]byte(""), "|")
+	assert.Contains(t, fmt.Sprint(err), "it has no header")
+
+	_, err = parseExportErr([]byte("title,subject\nMoonrise\n"), "|")
+	assert.Contains(t, fmt.Sprint(err), "wrong number of fields")
+
+	_, err = parseExportErr([]byte("title\n\"Moonrise\n"), "|")
+	assert.Contains(t, fmt.Sprint(err), "extraneous or missing \" in quoted-field")
+}
+
+func Test_AssertExportedRow(t *testing.T) {
+	e, err := parseExportErr([]byte(exportCsv), "|")
+	require.Nil(t, err, "%s", err)
+
+	expected := expectedMoonrise(t)
+	diffs, err := exportedRowDiffsErr(e, expected)
+	require.Nil(t, err, "%s", err)
+	assert.Empty(t, diffs)
+	assert.True(t, AssertExportedRow(t, e, expected))
+
+	expected.Subject = []string{"Photography", "Astronomy"}
+	expected.DateCreated = nil
+	diffs, err = exportedRowDiffsErr(e, expected)
+	require.Nil(t, err, "%s", err)
+	require.Equal(t, 2, len(diffs))
+	assert.Equal(t, "date_created", diffs[0].Field)
+	assert.Equal(t, []string{"1941-11-01"}, diffs[0].Unexpected)
+	assert.Equal(t, "subject", diffs[1].Field)
+	assert.Equal(t, []string{"Astronomy"}, diffs[1].Missing)
+	assert.Equal(t, []string{"Landscapes"}, diffs[1].Unexpected)
+
+	sunrise := model.ExpectedRepoObj{DigitalIdentifier: []string{"idc:3"}, Subject: []string{"Mountains"}}
+	sunrise.Title = "Winter Sunrise"
+	sunrise.Creator = expectedMoonrise(t).Creator
+	sunrise.DateCreated = []string{"1944"}
+	diffs, err = exportedRowDiffsErr(e, sunrise)
+	require.Nil(t, err, "%s", err)
+	assert.Empty(t, diffs)
+
+	sunrise.DigitalIdentifier = nil
+	_, err = exportedRowDiffsErr(e, sunrise)
+	assert.Contains(t, fmt.Sprint(err), "exactly one exported row titled 'Winter Sunrise' is expected, but found 2")
+}
@@ -0,0 +1,51 @@
+package search
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/model"
+)
+
+// WaitForIndexed polls the search index until it answers a document for the entity with the uuid, and answers it, so
+// that its title and collection may be asserted.  The test fails, reporting the last observed state, if the entity is
+// not indexed before the timeout.  The time waited, and the interval between polls, default to two minutes and two
+// seconds, and may be overridden by the environment variables 'IDC_WAIT_TIMEOUT' and 'IDC_WAIT_INTERVAL':
+//
+//	hit := search.WaitForIndexed(t, obj.JsonApiData[0].Id)
+//	assert.Equal(t, "Moonrise", hit.Title)
+//	assert.Equal(t, "Photographs", hit.Collection)
+func WaitForIndexed(t *testing.T, uuid string) Hit {
+	var found Hit
+	model.WaitFor(t, model.WaitTimeout(t), model.WaitInterval(t), func() (bool, error) {
+		hits, err := SearchQueryErr(Query{Uuid: uuid})
+		if err != nil {
+			return false, err
+		}
+		for _, h := range hits {
+			if h.Uuid == uuid {
+				found = h
+				return true, nil
+			}
+		}
+		return false, fmt.Errorf("%s is not indexed", uuid)
+	})
+	return found
+}
+
+// AssertDeindexed polls the search index until it answers no document for the entity with the uuid, e.g. after the
+// entity is deleted, and answers whether it was removed.  See WaitForIndexed for the time waited.
+func AssertDeindexed(t *testing.T, uuid string) bool {
+	return model.WaitFor(t, model.WaitTimeout(t), model.WaitInterval(t), func() (bool, error) {
+		hits, err := SearchQueryErr(Query{Uuid: uuid})
+		if err != nil {
+			return false, err
+		}
+		for _, h := range hits {
+			if h.Uuid == uuid {
+				return false, fmt.Errorf("%s is still indexed, titled '%s'", uuid, h.Title)
+			}
+		}
+		return true, nil
+	})
+}
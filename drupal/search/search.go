@@ -0,0 +1,204 @@
+// Provides helpers that query the search index of the IDC site, so that acceptance tests may verify that migrated
+// content is findable, e.g. that an object is indexed with the right title and collection.
+//
+// The index is queried using one of two backends, named by the environment variable 'IDC_SEARCH_BACKEND':
+//
+//	search_api  (the default) the Search API REST export of the site, at {base url}/idc/search, which answers a JSON
+//	            array of rows carrying `uuid`, `title`, `type`, and `collection`, and accepts the query parameters
+//	            `search_api_fulltext` and `uuid`
+//	solr        the select handler of the Solr core backing the index, whose documents carry the fields `ss_uuid`,
+//	            `ss_title`, `ss_type`, and `sm_collection`
+//
+// The url of the endpoint may be overridden by the environment variable 'IDC_SEARCH_URL', and must be supplied when
+// querying Solr directly, e.g. `http://solr:8983/solr/ISLANDORA/select`.  Requests are issued using the Config loaded by
+// env.Load, and are authenticated by its credentials, if any.
+//
+// The CSV export of search results, relied upon by metadata librarians, is retrieved by ExportCsv (see its
+// documentation for its url and the delimiter of multi-valued cells), and its rows compared with the expected values of
+// repository objects by AssertExportedRow.
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/stretchr/testify/assert"
+)
+
+// The backends named by 'IDC_SEARCH_BACKEND'
+const (
+	SearchApiBackend = "search_api"
+	SolrBackend      = "solr"
+)
+
+const (
+	searchBackend     = "IDC_SEARCH_BACKEND"
+	searchUrl         = "IDC_SEARCH_URL"
+	defaultSearchPath = "/idc/search"
+	defaultRows       = 10
+	// The fields of the documents of the Solr core
+	solrUuidField       = "ss_uuid"
+	solrTitleField      = "ss_title"
+	solrBundleField     = "ss_type"
+	solrCollectionField = "sm_collection"
+)
+
+// A document of the search index
+type Hit struct {
+	// The UUID of the indexed entity
+	Uuid string `json:"uuid"`
+	// The title of the indexed entity
+	Title string `json:"title"`
+	// The bundle of the indexed entity, e.g. `islandora_object`
+	Bundle string `json:"type"`
+	// The label of the collection the entity is a member of, as faceted by the index
+	Collection string `json:"collection"`
+}
+
+// Query describes the documents sought from the search index
+type Query struct {
+	// Full text sought, e.g. a title; empty matches every document
+	Text string
+	// The UUID of the entity sought, if not empty
+	Uuid string
+	// The maximum number of hits answered; defaults to ten
+	Rows int
+}
+
+// SearchQuery answers the hits of the search index matching the query, failing the test if the index cannot be queried:
+//
+//	hits := search.SearchQuery(t, search.Query{Uuid: obj.JsonApiData[0].Id})
+func SearchQuery(t *testing.T, q Query) []Hit {
+	hits, err := SearchQueryErr(q)
+	assert.Nil(t, err, "%s", err)
+	return hits
+}
+
+// SearchQueryErr behaves as SearchQuery, but answers an error instead of failing the test
+func SearchQueryErr(q Query) ([]Hit, error) {
+	c, err := env.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	switch backend := env.StringOr(searchBackend, SearchApiBackend); backend {
+	case SearchApiBackend:
+		u := env.StringOr(searchUrl, strings.TrimSuffix(c.BaseUrl, "/")+defaultSearchPath)
+		return searchApiErr(c, u, q)
+	case SolrBackend:
+		u := env.StringOr(searchUrl, "")
+		if u == "" {
+			return nil, fmt.Errorf("search: querying Solr requires the url of its select handler: set %s", searchUrl)
+		}
+		return solrErr(c, u, q)
+	default:
+		return nil, fmt.Errorf("search: unknown backend '%s' named by %s: expected '%s' or '%s'", backend,
+			searchBackend, SearchApiBackend, SolrBackend)
+	}
+}
+
+// searchApiErr queries the Search API REST export at the url
+func searchApiErr(c env.Config, u string, q Query) ([]Hit, error) {
+	params := url.Values{}
+	if q.Text != "" {
+		params.Set("search_api_fulltext", q.Text)
+	}
+	if q.Uuid != "" {
+		params.Set("uuid", q.Uuid)
+	}
+	params.Set("items_per_page", strconv.Itoa(rows(q)))
+
+	body, err := getErr(c, u, params, "application/json")
+	if err != nil {
+		return nil, err
+	}
+	var hits []Hit
+	if err := json.Unmarshal(body, &hits); err != nil {
+		return nil, fmt.Errorf("search: unable to unmarshal the hits answered by %s: %w", env.RedactUrl(u), err)
+	}
+	return hits, nil
+}
+
+// solrErr queries the Solr select handler at the url
+func solrErr(c env.Config, u string, q Query) ([]Hit, error) {
+	params := url.Values{}
+	params.Set("wt", "json")
+	params.Set("rows", strconv.Itoa(rows(q)))
+	params.Set("fl", strings.Join([]string{solrUuidField, solrTitleField, solrBundleField, solrCollectionField}, ","))
+	params.Set("q", "*:*")
+	if q.Text != "" {
+		params.Set("q", q.Text)
+	}
+	if q.Uuid != "" {
+		params.Set("fq", fmt.Sprintf("%s:%q", solrUuidField, q.Uuid))
+	}
+
+	body, err := getErr(c, u, params, "application/json")
+	if err != nil {
+		return nil, err
+	}
+	res := struct {
+		Response struct {
+			Docs []map[string]interface{}
+		}
+	}{}
+	if err := json.Unmarshal(body, &res); err != nil {
+		return nil, fmt.Errorf("search: unable to unmarshal the documents answered by %s: %w", env.RedactUrl(u), err)
+	}
+
+	hits := make([]Hit, len(res.Response.Docs))
+	for i, doc := range res.Response.Docs {
+		hits[i] = Hit{
+			Uuid:       solrValue(doc[solrUuidField]),
+			Title:      solrValue(doc[solrTitleField]),
+			Bundle:     solrValue(doc[solrBundleField]),
+			Collection: solrValue(doc[solrCollectionField]),
+		}
+	}
+	return hits, nil
+}
+
+// solrValue answers the value of a field of a Solr document: the value of a single-valued field, or the first value of
+// a multi-valued field
+func solrValue(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case []interface{}:
+		if len(value) == 0 {
+			return ""
+		}
+		return solrValue(value[0])
+	case string:
+		return value
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// rows answers the maximum number of hits sought by the query
+func rows(q Query) int {
+	if q.Rows > 0 {
+		return q.Rows
+	}
+	return defaultRows
+}
+
+// getErr retrieves the url with the query parameters, accepting the media type, authenticated by the credentials of the
+// Config (if any), and answers the body of the response
+func getErr(c env.Config, u string, params url.Values, accept string) ([]byte, error) {
+	res, err := jsonapi.NewClient(c).Do(context.Background(), http.MethodGet, u+"?"+params.Encode(), nil,
+		http.Header{"Accept": {accept}})
+	if err != nil {
+		return nil, err
+	}
+	return res.Body, nil
+}
@@ -0,0 +1,155 @@
+package search
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// A fake index serving the Search API REST export and a Solr select handler, whose documents are keyed by uuid
+type fakeIndex struct {
+	*httptest.Server
+	mu   sync.Mutex
+	docs map[string]Hit
+	// the number of polls of each uuid answered without its document, as if it were not yet indexed
+	pending map[string]int
+	// the headers of the last request
+	header http.Header
+}
+
+// newFakeIndex answers a fake index, and configures the environment to use it with the backend
+func newFakeIndex(t *testing.T, backend string, docs ...Hit) *fakeIndex {
+	idx := &fakeIndex{docs: map[string]Hit{}, pending: map[string]int{}}
+	for _, d := range docs {
+		idx.docs[d.Uuid] = d
+	}
+	idx.Server = httptest.NewServer(http.HandlerFunc(idx.serve))
+	t.Cleanup(idx.Close)
+
+	t.Cleanup(func() { _, _ = env.Reload() })
+	t.Setenv("DRUPAL_BASE_URL", idx.URL)
+	t.Setenv("IDC_SEARCH_BACKEND", backend)
+	t.Setenv("IDC_WAIT_INTERVAL", "1ms")
+	t.Setenv("IDC_WAIT_TIMEOUT", "1s")
+	if backend == SolrBackend {
+		t.Setenv("IDC_SEARCH_URL", idx.URL+"/solr/ISLANDORA/select")
+	}
+	_, err := env.Reload()
+	require.Nil(t, err, "%s", err)
+	return idx
+}
+
+// matching answers the documents with the uuid, counting down the polls of a pending document
+func (idx *fakeIndex) matching(uuid string) []Hit {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if idx.pending[uuid] > 0 {
+		idx.pending[uuid]--
+		return nil
+	}
+	if d, ok := idx.docs[uuid]; ok {
+		return []Hit{d}
+	}
+	return nil
+}
+
+func (idx *fakeIndex) serve(w http.ResponseWriter, r *http.Request) {
+	idx.mu.Lock()
+	idx.header = r.Header.Clone()
+	idx.mu.Unlock()
+	q := r.URL.Query()
+	switch r.URL.Path {
+	case "/idc/search":
+		hits := idx.matching(q.Get("uuid"))
+		body := "["
+		for i, h := range hits {
+			if i > 0 {
+				body += ","
+			}
+			body += fmt.Sprintf(`{"uuid": "%s", "title": "%s", "type": "%s", "collection": "%s"}`, h.Uuid, h.Title,
+				h.Bundle, h.Collection)
+		}
+		_, _ = w.Write([]byte(body + "]"))
+	case "/solr/ISLANDORA/select":
+		var uuid string
+		_, _ = fmt.Sscanf(q.Get("fq"), "ss_uuid:%q", &uuid)
+		body := `{"response": {"docs": [`
+		for i, h := range idx.matching(uuid) {
+			if i > 0 {
+				body += ","
+			}
+			body += fmt.Sprintf(`{"ss_uuid": "%s", "ss_title": "%s", "ss_type": "%s", "sm_collection": ["%s"]}`,
+				h.Uuid, h.Title, h.Bundle, h.Collection)
+		}
+		_, _ = w.Write([]byte(body + "]}}"))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+var moonrise = Hit{Uuid: "1", Title: "Moonrise", Bundle: "islandora_object", Collection: "Photographs"}
+
+func Test_SearchQuery(t *testing.T) {
+	for _, backend := range []string{SearchApiBackend, SolrBackend} {
+		t.Run(backend, func(t *testing.T) {
+			newFakeIndex(t, backend, moonrise)
+
+			assert.Equal(t, []Hit{moonrise}, SearchQuery(t, Query{Uuid: "1"}))
+			assert.Empty(t, SearchQuery(t, Query{Uuid: "2"}))
+		})
+	}
+}
+
+func Test_SearchQueryErr(t *testing.T) {
+	newFakeIndex(t, "elasticsearch")
+	_, err := SearchQueryErr(Query{})
+	assert.Contains(t, fmt.Sprint(err), "unknown backend 'elasticsearch'")
+
+	t.Setenv("IDC_SEARCH_BACKEND", SolrBackend)
+	_, err = SearchQueryErr(Query{})
+	assert.Contains(t, fmt.Sprint(err), "set IDC_SEARCH_URL")
+
+	t.Setenv("IDC_SEARCH_URL", "http://127.0.0.1:1/solr/ISLANDORA/select")
+	_, err = SearchQueryErr(Query{})
+	assert.Contains(t, fmt.Sprint(err), "encountered error sending GET")
+}
+
+func Test_SearchQueryIdentified(t *testing.T) {
+	idx := newFakeIndex(t, SolrBackend, moonrise)
+	t.Setenv("IDC_USER_AGENT", "moo/1.0")
+	_, err := env.Reload()
+	require.Nil(t, err, "%s", err)
+
+	_, err = SearchQueryErr(Query{Uuid: "1"})
+	require.Nil(t, err, "%s", err)
+	assert.Equal(t, "moo/1.0", idx.header.Get("User-Agent"))
+	assert.Equal(t, jsonapi.TestRunId(), idx.header.Get(jsonapi.TestRunHeader))
+}
+
+func Test_WaitForIndexed(t *testing.T) {
+	idx := newFakeIndex(t, SearchApiBackend, moonrise)
+	idx.pending["1"] = 3
+
+	assert.Equal(t, moonrise, WaitForIndexed(t, "1"))
+	assert.Equal(t, 0, idx.pending["1"])
+}
+
+func Test_AssertDeindexed(t *testing.T) {
+	newFakeIndex(t, SolrBackend)
+
+	assert.True(t, AssertDeindexed(t, "1"))
+}
+
+func Test_solrValue(t *testing.T) {
+	assert.Equal(t, "", solrValue(nil))
+	assert.Equal(t, "", solrValue([]interface{}{}))
+	assert.Equal(t, "Photographs", solrValue([]interface{}{"Photographs", "Letters"}))
+	assert.Equal(t, "412", solrValue(412.0))
+}
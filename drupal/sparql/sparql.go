@@ -0,0 +1,174 @@
+// Provides helpers that query the triplestore Islandora indexes nodes and media into (e.g. Blazegraph), so that tests
+// may verify that the JSON-LD of a migrated object was indexed.
+//
+// Queries are POSTed to the SPARQL endpoint named by the environment variable 'IDC_SPARQL_URL', e.g.
+// `http://blazegraph:8080/bigdata/namespace/islandora/sparql`, using the timeout and TLS settings of the Config loaded
+// by env.Load.  Islandora identifies each node in the triplestore by its canonical url (see model.CanonicalUrlErr).
+package sparql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/jhu-idc/idc-golang/drupal/jsonapi"
+	"github.com/jhu-idc/idc-golang/drupal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sparqlUrl = "IDC_SPARQL_URL"
+
+// The SPARQL 1.1 Query Results JSON Format
+type results struct {
+	Boolean *bool `json:"boolean"`
+	Results struct {
+		Bindings []map[string]struct {
+			Type  string `json:"type"`
+			Value string `json:"value"`
+		} `json:"bindings"`
+	} `json:"results"`
+}
+
+// Ask answers the result of the SPARQL ASK query, failing the test immediately if the query cannot be answered:
+//
+//	sparql.Ask(t, `ASK { <https://islandora.example.edu/node/12> ?p ?o }`)
+func Ask(t *testing.T, query string) bool {
+	result, err := AskErr(query)
+	require.Nil(t, err, "%s", err)
+	return result
+}
+
+// AskErr behaves as Ask, but answers an error instead of failing the test
+func AskErr(query string) (bool, error) {
+	res, err := queryErr(query)
+	if err != nil {
+		return false, err
+	}
+	if res.Boolean == nil {
+		return false, fmt.Errorf("sparql: the response to an ASK query carries no boolean: %s", query)
+	}
+	return *res.Boolean, nil
+}
+
+// Select answers the solutions of the SPARQL SELECT query, each mapping the name of a variable to the value bound to
+// it, failing the test immediately if the query cannot be answered.  Variables without a binding in a solution are
+// absent from its map.
+//
+//	titles := sparql.Select(t, `SELECT ?title WHERE { <https://islandora.example.edu/node/12> dcterms:title ?title }`)
+func Select(t *testing.T, query string) []map[string]string {
+	solutions, err := SelectErr(query)
+	require.Nil(t, err, "%s", err)
+	return solutions
+}
+
+// SelectErr behaves as Select, but answers an error instead of failing the test
+func SelectErr(query string) ([]map[string]string, error) {
+	res, err := queryErr(query)
+	if err != nil {
+		return nil, err
+	}
+
+	solutions := make([]map[string]string, len(res.Results.Bindings))
+	for i, binding := range res.Results.Bindings {
+		solutions[i] = map[string]string{}
+		for name, value := range binding {
+			solutions[i][name] = value.Value
+		}
+	}
+	return solutions, nil
+}
+
+// AssertTripleExists asserts that the triplestore holds a triple with the subject and predicate (both IRIs, e.g. the
+// canonical url of a node and `http://purl.org/dc/terms/title`), whose object is the supplied value: the lexical form
+// of a literal, regardless of its datatype or language, or an IRI.  The failure reports the objects the triplestore
+// holds for the subject and predicate.
+//
+//	subject, _ := model.CanonicalUrlErr(obj.JsonApiData[0].Id)
+//	sparql.AssertTripleExists(t, subject, "http://purl.org/dc/terms/title", "Moonrise")
+func AssertTripleExists(t *testing.T, subjectUri, predicate, object string) bool {
+	exists, err := AskErr(askTriple(subjectUri, predicate, object))
+	if !assert.Nil(t, err, "%s", err) {
+		return false
+	}
+	if exists {
+		return true
+	}
+	return assert.Fail(t, fmt.Sprintf("no triple <%s> <%s> %s; %s", subjectUri, predicate,
+		literal(object), describeObjects(subjectUri, predicate)))
+}
+
+// WaitForTriple polls the triplestore until it holds the triple described by AssertTripleExists, since nodes are
+// indexed asynchronously, and answers whether it did.  The test fails, reporting the objects the triplestore holds for
+// the subject and predicate, if the triple is not indexed before the timeout.  The time waited, and the interval
+// between polls, default to two minutes and two seconds, and may be overridden by the environment variables
+// 'IDC_WAIT_TIMEOUT' and 'IDC_WAIT_INTERVAL'.
+func WaitForTriple(t *testing.T, subjectUri, predicate, object string) bool {
+	return model.WaitFor(t, model.WaitTimeout(t), model.WaitInterval(t), func() (bool, error) {
+		exists, err := AskErr(askTriple(subjectUri, predicate, object))
+		if err != nil || exists {
+			return exists, err
+		}
+		return false, fmt.Errorf("%s", describeObjects(subjectUri, predicate))
+	})
+}
+
+// askTriple answers an ASK query for a triple with the subject and predicate whose object has the lexical form
+func askTriple(subjectUri, predicate, object string) string {
+	return fmt.Sprintf("ASK { <%s> <%s> ?o . FILTER(STR(?o) = %s) }", subjectUri, predicate, literal(object))
+}
+
+// describeObjects answers a description of the objects the triplestore holds for the subject and predicate
+func describeObjects(subjectUri, predicate string) string {
+	solutions, err := SelectErr(fmt.Sprintf("SELECT ?o WHERE { <%s> <%s> ?o }", subjectUri, predicate))
+	if err != nil {
+		return fmt.Sprintf("unable to retrieve its objects: %s", err)
+	}
+	if len(solutions) == 0 {
+		return "the subject has no such predicate"
+	}
+	objects := make([]string, len(solutions))
+	for i, s := range solutions {
+		objects[i] = s["o"]
+	}
+	return fmt.Sprintf("its objects are %q", objects)
+}
+
+// literal answers the value as a SPARQL string literal
+func literal(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`).Replace(value) + `"`
+}
+
+// queryErr POSTs the query to the SPARQL endpoint, and answers its decoded results
+func queryErr(query string) (results, error) {
+	res := results{}
+	c, err := env.Load()
+	if err != nil {
+		return res, err
+	}
+	u := env.StringOr(sparqlUrl, "")
+	if u == "" {
+		return res, fmt.Errorf("sparql: the SPARQL endpoint is unknown: set %s", sparqlUrl)
+	}
+
+	// the endpoint is not Drupal, so the request carries no credentials
+	log.Printf("Querying %s: %s", env.RedactUrl(u), query)
+	httpRes, err := (&jsonapi.Client{Config: &c, Anonymous: true}).Do(context.Background(), http.MethodPost, u,
+		[]byte(url.Values{"query": {query}}.Encode()), http.Header{
+			"Content-Type": {"application/x-www-form-urlencoded"},
+			"Accept":       {"application/sparql-results+json"},
+		})
+	if err != nil {
+		return res, err
+	}
+	if err := json.Unmarshal(httpRes.Body, &res); err != nil {
+		return res, fmt.Errorf("sparql: unable to unmarshal the results answered by %s: %w", env.RedactUrl(u), err)
+	}
+	return res, nil
+}
@@ -0,0 +1,126 @@
+package sparql
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	askPattern    = regexp.MustCompile(`^ASK \{ <(.*)> <(.*)> \?o \. FILTER\(STR\(\?o\) = "(.*)"\) }$`)
+	selectPattern = regexp.MustCompile(`^SELECT \?o WHERE \{ <(.*)> <(.*)> \?o }$`)
+)
+
+// A fake triplestore answering the queries issued by this package, whose objects are keyed by subject and predicate
+type fakeStore struct {
+	mu      sync.Mutex
+	objects map[string][]string
+	// the number of ASK queries answered negatively before the triples are held
+	pending int
+}
+
+// newFakeStore answers a fake triplestore, and configures the environment to use it
+func newFakeStore(t *testing.T, objects map[string][]string) *fakeStore {
+	s := &fakeStore{objects: objects}
+	server := httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(server.Close)
+
+	t.Cleanup(func() { _, _ = env.Reload() })
+	t.Setenv("IDC_SPARQL_URL", server.URL+"/bigdata/namespace/islandora/sparql")
+	t.Setenv("IDC_WAIT_INTERVAL", "1ms")
+	t.Setenv("IDC_WAIT_TIMEOUT", "1s")
+	_, err := env.Reload()
+	require.Nil(t, err, "%s", err)
+	return s
+}
+
+func (s *fakeStore) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if r.Method != http.MethodPost || r.Header.Get("Accept") != "application/sparql-results+json" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	query := r.FormValue("query")
+	if m := askPattern.FindStringSubmatch(query); m != nil {
+		held := false
+		if s.pending > 0 {
+			s.pending--
+		} else {
+			for _, o := range s.objects[m[1]+" "+m[2]] {
+				held = held || o == m[3]
+			}
+		}
+		_, _ = fmt.Fprintf(w, `{"head": {}, "boolean": %s}`, strconv.FormatBool(held))
+	} else if m := selectPattern.FindStringSubmatch(query); m != nil {
+		var bindings []string
+		for _, o := range s.objects[m[1]+" "+m[2]] {
+			bindings = append(bindings, fmt.Sprintf(`{"o": {"type": "literal", "value": "%s"}}`, o))
+		}
+		_, _ = fmt.Fprintf(w, `{"head": {"vars": ["o"]}, "results": {"bindings": [%s]}}`, strings.Join(bindings, ","))
+	} else {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("unsupported query: " + query))
+	}
+}
+
+const (
+	node  = "https://islandora.example.edu/node/12"
+	title = "http://purl.org/dc/terms/title"
+)
+
+func Test_AskAndSelect(t *testing.T) {
+	newFakeStore(t, map[string][]string{node + " " + title: {"Moonrise"}})
+
+	assert.True(t, Ask(t, askTriple(node, title, "Moonrise")))
+	assert.False(t, Ask(t, askTriple(node, title, "Sunset")))
+	assert.Equal(t, []map[string]string{{"o": "Moonrise"}}, Select(t, fmt.Sprintf("SELECT ?o WHERE { <%s> <%s> ?o }",
+		node, title)))
+
+	_, err := SelectErr("DESCRIBE <" + node + ">")
+	assert.Contains(t, fmt.Sprint(err), "400 status encountered")
+	_, err = AskErr(fmt.Sprintf("SELECT ?o WHERE { <%s> <%s> ?o }", node, title))
+	assert.Contains(t, fmt.Sprint(err), "carries no boolean")
+}
+
+func Test_AssertTripleExists(t *testing.T) {
+	newFakeStore(t, map[string][]string{node + " " + title: {"Moonrise"}})
+
+	assert.True(t, AssertTripleExists(t, node, title, "Moonrise"))
+	assert.Equal(t, `its objects are ["Moonrise"]`, describeObjects(node, title))
+	assert.Equal(t, "the subject has no such predicate", describeObjects(node, "http://purl.org/dc/terms/creator"))
+}
+
+func Test_WaitForTriple(t *testing.T) {
+	s := newFakeStore(t, map[string][]string{node + " " + title: {"Moonrise"}})
+	s.pending = 3
+
+	assert.True(t, WaitForTriple(t, node, title, "Moonrise"))
+	assert.Equal(t, 0, s.pending)
+}
+
+func Test_queryErr(t *testing.T) {
+	t.Cleanup(func() { _, _ = env.Reload() })
+	t.Setenv("IDC_SPARQL_URL", "")
+	_, err := env.Reload()
+	require.Nil(t, err, "%s", err)
+
+	_, err = AskErr("ASK { ?s ?p ?o }")
+	assert.Contains(t, fmt.Sprint(err), "set IDC_SPARQL_URL")
+}
+
+func Test_literal(t *testing.T) {
+	assert.Equal(t, `"Moonrise"`, literal("Moonrise"))
+	assert.Equal(t, `"\"Moonrise\",\n\tHernandez, New Mexico \\ 1941"`,
+		literal("\"Moonrise\",\n\tHernandez, New Mexico \\ 1941"))
+}
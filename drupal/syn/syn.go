@@ -0,0 +1,273 @@
+// Provides helpers that sign the JSON Web Tokens (JWTs) verified by Syn, so that tests may call the endpoints of
+// Islandora microservices (e.g. Houdini, Homarus, or the health check of Milliner), which authenticate requests by a
+// token signed with the private key shared with Drupal rather than by Drupal credentials.
+//
+// Tokens are signed using RS256, as configured by environment variables:
+//
+//	IDC_SYN_KEY       the path of the PEM encoded RSA private key (PKCS #1 or PKCS #8) shared with Syn; required
+//	IDC_SYN_ISSUER    the issuer (`iss`) of the token; by default the base url of Drupal
+//	IDC_SYN_AUDIENCE  the audience (`aud`) of the token; omitted by default
+//	IDC_SYN_SUBJECT   the subject (`sub`) of the token; by default the admin username, or `admin`
+//	IDC_SYN_ROLES     the comma-separated roles of the subject; by default `administrator`
+//	IDC_SYN_TTL       the time a token is valid, e.g. `10m`; by default one hour
+//
+// A Signer answers a token until it nears its expiry, and signs a new one thereafter, so that it may be supplied as the
+// bearer token of a jsonapi.JsonApiUrl used for the duration of a suite:
+//
+//	signer := syn.NewSigner(t)
+//	_, body, err := jsonapi.GetResourceWithBearerErr(houdiniUrl, signer.Token(t))
+package syn
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jhu-idc/idc-golang/drupal/env"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	synKey          = "IDC_SYN_KEY"
+	synIssuer       = "IDC_SYN_ISSUER"
+	synAudience     = "IDC_SYN_AUDIENCE"
+	synSubject      = "IDC_SYN_SUBJECT"
+	synRoles        = "IDC_SYN_ROLES"
+	synTtl          = "IDC_SYN_TTL"
+	defaultSubject  = "admin"
+	defaultRoles    = "administrator"
+	defaultTtl      = time.Hour
+	defaultIssuer   = "http://islandora-idc.traefik.me"
+	maxRefreshAhead = time.Minute
+)
+
+// Answered (wrapped) when a token has expired, or is not yet valid
+var ErrExpired = errors.New("syn: token expired")
+
+// Answered (wrapped) when the signature of a token does not verify against the public key
+var ErrInvalidSignature = errors.New("syn: invalid token signature")
+
+// The time used to issue, and verify, tokens; replaced by tests
+var now = time.Now
+
+// Claims are the claims of a token verified by Syn
+type Claims struct {
+	// The issuer of the token, e.g. the base url of Drupal
+	Issuer string `json:"iss"`
+	// The intended audience of the token, if any
+	Audience string `json:"aud,omitempty"`
+	// The name of the user the token authenticates
+	Subject string `json:"sub"`
+	// The url of the user the token authenticates, if any
+	WebId string `json:"webid,omitempty"`
+	// The roles of the user, e.g. `administrator`
+	Roles []string `json:"roles"`
+	// The time the token was issued, in seconds since the epoch
+	IssuedAt int64 `json:"iat"`
+	// The time the token expires, in seconds since the epoch
+	ExpiresAt int64 `json:"exp"`
+}
+
+// Expires answers the time the token expires
+func (c Claims) Expires() time.Time {
+	return time.Unix(c.ExpiresAt, 0)
+}
+
+// Signer signs tokens carrying the claims configured by the environment, answering the same token until it nears its
+// expiry.  A Signer is safe for concurrent use.
+type Signer struct {
+	key    *rsa.PrivateKey
+	claims Claims
+	ttl    time.Duration
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// NewSigner answers a Signer configured by the environment, failing the test immediately if it cannot be configured,
+// e.g. because 'IDC_SYN_KEY' is not set, or does not name a readable RSA private key
+func NewSigner(t *testing.T) *Signer {
+	s, err := NewSignerErr()
+	require.Nil(t, err, "%s", err)
+	return s
+}
+
+// NewSignerErr behaves as NewSigner, but answers an error instead of failing the test
+func NewSignerErr() (*Signer, error) {
+	path := strings.TrimSpace(env.StringOr(synKey, ""))
+	if path == "" {
+		return nil, fmt.Errorf("syn: signing a token requires a private key: set %s to the path of its PEM file", synKey)
+	}
+	key, err := ReadKeyErr(path)
+	if err != nil {
+		return nil, err
+	}
+	ttl, err := env.DurationOr(synTtl, defaultTtl)
+	if err != nil {
+		return nil, err
+	}
+	if ttl <= 0 {
+		return nil, fmt.Errorf("syn: %s must be positive, but was %s", synTtl, ttl)
+	}
+
+	var roles []string
+	for _, role := range strings.Split(env.StringOr(synRoles, defaultRoles), ",") {
+		if role = strings.TrimSpace(role); role != "" {
+			roles = append(roles, role)
+		}
+	}
+
+	return &Signer{
+		key: key,
+		ttl: ttl,
+		claims: Claims{
+			Issuer:   env.StringOr(synIssuer, env.BaseUrlOr(defaultIssuer)),
+			Audience: env.StringOr(synAudience, ""),
+			Subject:  env.StringOr(synSubject, env.AdminUsernameOr(defaultSubject)),
+			Roles:    roles,
+		},
+	}, nil
+}
+
+// Token answers a valid token, failing the test immediately if it cannot be signed
+func (s *Signer) Token(t *testing.T) string {
+	token, err := s.TokenErr()
+	require.Nil(t, err, "%s", err)
+	return token
+}
+
+// TokenErr answers the token last signed, unless it expires within a minute (or a tenth of its lifetime, if shorter),
+// in which case a new token is signed and answered.  TokenErr may be supplied as the BearerToken of a
+// jsonapi.JsonApiUrl.
+func (s *Signer) TokenErr() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ahead := s.ttl / 10
+	if ahead > maxRefreshAhead {
+		ahead = maxRefreshAhead
+	}
+	issued := now()
+	if s.token != "" && issued.Add(ahead).Before(s.expires) {
+		return s.token, nil
+	}
+
+	claims := s.claims
+	claims.IssuedAt = issued.Unix()
+	claims.ExpiresAt = issued.Add(s.ttl).Unix()
+	token, err := Sign(s.key, claims)
+	if err != nil {
+		return "", err
+	}
+	s.token, s.expires = token, claims.Expires()
+	return token, nil
+}
+
+// Sign answers the claims as a token signed by the key using RS256
+func Sign(key *rsa.PrivateKey, claims Claims) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", fmt.Errorf("syn: unable to marshal the token header: %w", err)
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("syn: unable to marshal the token claims: %w", err)
+	}
+
+	signed := encode(header) + "." + encode(payload)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("syn: unable to sign the token: %w", err)
+	}
+	return signed + "." + encode(signature), nil
+}
+
+// VerifyErr answers the claims of the token if it is signed by the private key of the public key using RS256, and is
+// currently valid.  An invalid signature wraps ErrInvalidSignature, and an expired token wraps ErrExpired.
+func VerifyErr(key *rsa.PublicKey, token string) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Claims{}, fmt.Errorf("syn: a token has 3 parts, but found %d", len(parts))
+	}
+
+	header := struct{ Alg string }{}
+	if err := decodeErr(parts[0], &header); err != nil {
+		return Claims{}, fmt.Errorf("syn: unable to decode the token header: %w", err)
+	}
+	if header.Alg != "RS256" {
+		return Claims{}, fmt.Errorf("syn: tokens are signed using RS256, but found '%s'", header.Alg)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return Claims{}, fmt.Errorf("syn: unable to decode the token signature: %w", err)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return Claims{}, fmt.Errorf("%w: %s", ErrInvalidSignature, err)
+	}
+
+	claims := Claims{}
+	if err := decodeErr(parts[1], &claims); err != nil {
+		return Claims{}, fmt.Errorf("syn: unable to decode the token claims: %w", err)
+	}
+	at := now().Unix()
+	if at >= claims.ExpiresAt || at < claims.IssuedAt {
+		return claims, fmt.Errorf("%w: valid from %s until %s", ErrExpired, time.Unix(claims.IssuedAt, 0).UTC(),
+			claims.Expires().UTC())
+	}
+	return claims, nil
+}
+
+// ReadKeyErr answers the RSA private key of the PEM file at the path, encoded as PKCS #1 or PKCS #8
+func ReadKeyErr(path string) (*rsa.PrivateKey, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("syn: unable to read the private key %s: %w", path, err)
+	}
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, fmt.Errorf("syn: no PEM data found in the private key %s", path)
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("syn: unable to parse the private key %s: %w", path, err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("syn: the private key %s is a %T, but Syn requires an RSA key", path, parsed)
+	}
+	return key, nil
+}
+
+// encode answers the unpadded base64url encoding of b
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodeErr unmarshals the unpadded base64url encoded JSON into v
+func decodeErr(part string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
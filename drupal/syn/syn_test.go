@@ -0,0 +1,116 @@
+package syn
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestKey generates an RSA key, writes it as a PKCS #8 PEM file, and sets IDC_SYN_KEY to its path
+func writeTestKey(t *testing.T) *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err, "%s", err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.Nil(t, err, "%s", err)
+
+	path := filepath.Join(t.TempDir(), "private.key")
+	err = os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600)
+	require.Nil(t, err, "%s", err)
+	t.Setenv(synKey, path)
+	return key
+}
+
+// setNow replaces the time used to issue and verify tokens for the duration of the test
+func setNow(t *testing.T, at *time.Time) {
+	t.Cleanup(func() { now = time.Now })
+	now = func() time.Time { return *at }
+}
+
+func Test_TokenClaimsAndSignature(t *testing.T) {
+	key := writeTestKey(t)
+	t.Setenv("DRUPAL_BASE_URL", "https://idc.example.org")
+	t.Setenv(synAudience, "houdini")
+	t.Setenv(synSubject, "idc-admin")
+	t.Setenv(synRoles, "administrator, fedoraadmin")
+	t.Setenv(synTtl, "10m")
+	at := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	setNow(t, &at)
+
+	signer, err := NewSignerErr()
+	require.Nil(t, err, "%s", err)
+	token, err := signer.TokenErr()
+	require.Nil(t, err, "%s", err)
+
+	claims, err := VerifyErr(&key.PublicKey, token)
+	require.Nil(t, err, "%s", err)
+	assert.Equal(t, Claims{
+		Issuer:    "https://idc.example.org",
+		Audience:  "houdini",
+		Subject:   "idc-admin",
+		Roles:     []string{"administrator", "fedoraadmin"},
+		IssuedAt:  at.Unix(),
+		ExpiresAt: at.Add(10 * time.Minute).Unix(),
+	}, claims)
+
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err, "%s", err)
+	_, err = VerifyErr(&other.PublicKey, token)
+	assert.True(t, errors.Is(err, ErrInvalidSignature), "%s", err)
+
+	at = at.Add(10 * time.Minute)
+	_, err = VerifyErr(&key.PublicKey, token)
+	assert.True(t, errors.Is(err, ErrExpired), "%s", err)
+}
+
+func Test_TokenErrRefreshesNearExpiry(t *testing.T) {
+	key := writeTestKey(t)
+	t.Setenv(synTtl, "10m")
+	at := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	setNow(t, &at)
+
+	signer, err := NewSignerErr()
+	require.Nil(t, err, "%s", err)
+	first, err := signer.TokenErr()
+	require.Nil(t, err, "%s", err)
+
+	at = at.Add(8 * time.Minute)
+	second, err := signer.TokenErr()
+	require.Nil(t, err, "%s", err)
+	assert.Equal(t, first, second, "a token expiring in 2 minutes is expected to be reused")
+
+	at = at.Add(time.Minute + 30*time.Second)
+	third, err := signer.TokenErr()
+	require.Nil(t, err, "%s", err)
+	assert.NotEqual(t, first, third, "a token expiring in 30 seconds is expected to be replaced")
+
+	claims, err := VerifyErr(&key.PublicKey, third)
+	require.Nil(t, err, "%s", err)
+	assert.Equal(t, at.Add(10*time.Minute).Unix(), claims.ExpiresAt)
+}
+
+func Test_NewSignerErr(t *testing.T) {
+	t.Setenv(synKey, "")
+	_, err := NewSignerErr()
+	assert.Contains(t, fmt.Sprint(err), "set IDC_SYN_KEY")
+
+	path := filepath.Join(t.TempDir(), "private.key")
+	require.Nil(t, os.WriteFile(path, []byte("not a key"), 0600))
+	t.Setenv(synKey, path)
+	_, err = NewSignerErr()
+	assert.Contains(t, fmt.Sprint(err), "no PEM data found")
+
+	writeTestKey(t)
+	t.Setenv(synTtl, "0s")
+	_, err = NewSignerErr()
+	assert.Contains(t, fmt.Sprint(err), "IDC_SYN_TTL must be positive")
+}